@@ -23,6 +23,7 @@ import (
 	sqliterepo "github.com/sainaif/holy-home/internal/repository/sqlite"
 	"github.com/sainaif/holy-home/internal/services"
 	"github.com/sainaif/holy-home/internal/static"
+	"github.com/sainaif/holy-home/internal/utils"
 )
 
 func main() {
@@ -32,6 +33,11 @@ func main() {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
+	// Schema migration subcommand: api migrate status|up|verify
+	if len(os.Args) > 1 && os.Args[1] == "migrate" {
+		os.Exit(runMigrateCommand(cfg, os.Args[2:]))
+	}
+
 	// Validate configuration security
 	if err := validateConfig(cfg); err != nil {
 		log.Fatalf("Configuration validation failed: %v", err)
@@ -62,6 +68,8 @@ func main() {
 		EnableTrustedProxyCheck: true,
 		TrustedProxies:          []string{"172.20.0.0/16", "10.0.0.0/8", "127.0.0.1"},
 		ProxyHeader:             fiber.HeaderXForwardedFor,
+		// Leave room for chore completion photos above the default 4 MB
+		BodyLimit: max(4*1024*1024, cfg.Chores.PhotoMaxBytes+1024*1024),
 	})
 
 	// Global Middleware
@@ -93,8 +101,8 @@ func main() {
 	app.Use(cors.New(cors.Config{
 		AllowOrigins:  cfg.App.AllowedOrigins,
 		AllowMethods:  "GET,POST,PUT,PATCH,DELETE,OPTIONS",
-		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, Idempotency-Key, X-Request-ID",
-		ExposeHeaders: "Cache-Control, Pragma, Expires",
+		AllowHeaders:  "Origin, Content-Type, Accept, Authorization, Idempotency-Key, X-Request-ID, X-Backup-Passphrase",
+		ExposeHeaders: "Cache-Control, Pragma, Expires, Idempotent-Replayed",
 	}))
 
 	if cfg.Logging.Format == "json" {
@@ -114,27 +122,41 @@ func main() {
 	})
 
 	// Initialize services with repositories
-	userService := services.NewUserService(repos.Users, repos.Groups, repos.Roles, repos.PasswordResetTokens, cfg)
-	groupService := services.NewGroupService(repos.Groups, repos.Users, repos.Allocations)
+	userService := services.NewUserService(repos.Users, repos.Groups, repos.Residencies, repos.GroupMembershipChanges, repos.Roles, repos.PasswordResetTokens, cfg)
+	groupService := services.NewGroupService(repos.Groups, repos.GroupWeightChanges, repos.Users, repos.Allocations)
 	eventService := services.NewEventService()
 	webPushService := services.NewWebPushService(repos.WebPushSubscriptions)
 	notificationPreferenceService := services.NewNotificationPreferenceService(repos.NotificationPreferences)
 	notificationService := services.NewNotificationService(repos.Notifications, eventService, webPushService, notificationPreferenceService, cfg)
-	billService := services.NewBillService(repos.Bills, repos.Consumptions, repos.Allocations, repos.Payments, repos.Users, repos.Groups, notificationService)
-	consumptionService := services.NewConsumptionService(repos.Consumptions, repos.Bills, repos.Users)
-	allocationService := services.NewAllocationService(repos.Users, repos.Groups, repos.Consumptions, repos.Allocations, repos.Bills)
-	loanService := services.NewLoanService(repos.Loans, repos.LoanPayments, repos.Users, repos.Groups, notificationService)
-	choreService := services.NewChoreService(repos.Chores, repos.ChoreAssignments, repos.ChoreSwapRequests, repos.Users, notificationService)
-	supplyService := services.NewSupplyService(repos.SupplySettings, repos.SupplyItems, repos.SupplyContributions, repos.Users, notificationService)
-	recurringBillService := services.NewRecurringBillService(repos.RecurringBillTemplates, repos.RecurringBillAllocations, repos.Bills, repos.Allocations, repos.Payments, repos.Users, cfg)
-	paymentService := services.NewPaymentService(repos.Payments, repos.Bills, recurringBillService)
+	txManager := sqliterepo.NewTxManager(sqliteDB.DB)
+	currencyService := services.NewCurrencyService(repos.ExchangeRates, repos.AppSettings, txManager)
+	billService := services.NewBillService(repos.Bills, repos.Consumptions, repos.Meters, repos.MeterReplacements, repos.Tariffs, repos.Allocations, repos.Payments, repos.Settlements, repos.Users, repos.Groups, repos.Residencies, repos.GroupWeightChanges, repos.GroupMembershipChanges, currencyService, notificationService, txManager)
+	consumptionService := services.NewConsumptionService(repos.Consumptions, repos.Meters, repos.MeterReplacements, repos.Bills, repos.Users)
+	meterService := services.NewMeterService(repos.Meters, repos.MeterReplacements, repos.Consumptions, repos.Users, repos.Groups)
+	tariffService := services.NewTariffService(repos.Tariffs)
+	allocationService := services.NewAllocationService(repos.Users, repos.Groups, repos.Residencies, repos.GroupWeightChanges, repos.GroupMembershipChanges, repos.Consumptions, repos.Meters, repos.MeterReplacements, repos.Tariffs, repos.Allocations, repos.Bills)
+	loanService := services.NewLoanService(repos.Loans, repos.LoanPayments, repos.Users, repos.Groups, repos.LedgerEntries, currencyService, notificationService, txManager)
+	ledgerService := services.NewLedgerService(repos.LedgerEntries, repos.Users, repos.Groups, txManager)
+	settlementService := services.NewSettlementService(repos.Users, repos.Groups, repos.Residencies, repos.GroupWeightChanges, repos.GroupMembershipChanges, repos.Bills, repos.Consumptions, repos.Meters, repos.MeterReplacements, repos.Tariffs, repos.Allocations, repos.Payments, repos.Loans, repos.LoanPayments, repos.Settlements, txManager)
+	choreService := services.NewChoreService(repos.Chores, repos.ChoreAssignments, repos.ChoreSwapRequests, repos.UserAbsences, repos.ChorePreferences, repos.ChoreCompletions, repos.ChoreSettings, repos.SentReminders, repos.Users, notificationService, cfg)
+	supplyService := services.NewSupplyService(repos.SupplySettings, repos.SupplyItems, repos.SupplyContributions, repos.Users, repos.LedgerEntries, currencyService, notificationService, txManager)
+	recurringBillService := services.NewRecurringBillService(repos.RecurringBillTemplates, repos.RecurringBillAllocations, repos.Bills, repos.Allocations, repos.Payments, repos.Settlements, repos.Users, cfg)
+	paymentService := services.NewPaymentService(repos.Payments, repos.Bills, currencyService, recurringBillService, txManager)
+	calendarService := services.NewCalendarService(repos.CalendarFeedTokens, repos.Users, repos.Chores, repos.ChoreAssignments, repos.Bills, repos.Allocations, repos.Payments, repos.Settlements, repos.Loans, cfg)
 	exportService := services.NewExportService(repos.Bills, repos.Consumptions, repos.Loans, repos.LoanPayments, repos.Chores, repos.ChoreAssignments, repos.Users, repos.Groups)
-	backupService := services.NewBackupService(sqliteDB.DB, repos.Users, repos.Groups, repos.Bills, repos.Consumptions, repos.Allocations, repos.Payments, repos.Loans, repos.LoanPayments, repos.Chores, repos.ChoreAssignments, repos.ChoreSettings, repos.Notifications, repos.SupplySettings, repos.SupplyItems, repos.SupplyContributions, repos.RecurringBillTemplates, repos.RecurringBillAllocations, repos.PasskeyCredentials)
+	statementService := services.NewStatementService(repos.Users, repos.Groups, repos.Bills, repos.Payments, repos.Loans, repos.LoanPayments, allocationService, ledgerService)
+	backupService := services.NewBackupService(sqliteDB.DB, repos.Users, repos.Groups, repos.Residencies, repos.GroupWeightChanges, repos.GroupMembershipChanges, repos.Bills, repos.Consumptions, repos.Meters, repos.MeterReplacements, repos.Tariffs, repos.Allocations, repos.Payments, repos.Loans, repos.LoanPayments, repos.Chores, repos.ChoreAssignments, repos.ChoreSettings, repos.Notifications, repos.SupplySettings, repos.SupplyItems, repos.SupplyContributions, repos.RecurringBillTemplates, repos.RecurringBillAllocations, repos.PasskeyCredentials, repos.Roles, repos.Permissions, repos.AuditLogs, repos.ApprovalRequests, repos.ApprovalPolicies, repos.ChoreSwapRequests, repos.UserAbsences, repos.ChorePreferences, repos.ChoreCompletions, repos.AppSettings, repos.SupplyItemHistory, repos.NotificationPreferences, repos.WebPushSubscriptions, repos.SentReminders, repos.ExchangeRates, repos.LedgerEntries, repos.Settlements, ledgerService)
+	backupArchiveService := services.NewBackupArchiveService(backupService, cfg)
 	auditService := services.NewAuditService(repos.AuditLogs)
 	permissionService := services.NewPermissionService(repos.Permissions)
 	roleService := services.NewRoleService(repos.Roles, repos.Users, repos.Permissions)
-	approvalService := services.NewApprovalService(repos.ApprovalRequests)
-	appSettingsService := services.NewAppSettingsService(repos.AppSettings)
+	approvalService := services.NewApprovalService(repos.ApprovalRequests, repos.ApprovalPolicies, repos.Users, roleService, notificationService, txManager, auditService)
+	approvalService.RegisterExecutor("chore.delete", "chore", choreService.ExecuteApprovedDelete)
+	approvalService.RegisterExecutor("bill.delete", "bill", billService.ExecuteApprovedDelete)
+	approvalService.RegisterExecutor("loan.delete", "loan", loanService.ExecuteApprovedDelete)
+	approvalService.RegisterExecutor("supply.budget_adjust", "supply_settings", supplyService.ExecuteApprovedBudgetAdjustment)
+	approvalService.RegisterExecutor("supply.refund", "supply_item", supplyService.ExecuteApprovedRefund)
+	appSettingsService := services.NewAppSettingsService(repos.AppSettings, repos.Bills, repos.LedgerEntries)
 	reminderService := services.NewReminderService(
 		repos.SentReminders,
 		repos.AppSettings,
@@ -165,22 +187,35 @@ func main() {
 	if err := roleService.InitializeDefaultRoles(context.Background()); err != nil {
 		log.Printf("Warning: Failed to initialize roles: %v", err)
 	}
+	if err := approvalService.InitializeDefaultPolicies(context.Background()); err != nil {
+		log.Printf("Warning: Failed to initialize approval policies: %v", err)
+	}
 	log.Println("Permissions and roles initialized")
 
+	// Post records created before the ledger existed
+	if err := ledgerService.Backfill(context.Background()); err != nil {
+		log.Printf("Warning: Failed to backfill ledger: %v", err)
+	}
+
 	// Initialize handlers
 	authHandler := handlers.NewAuthHandler(authService, userService, auditService, cfg)
 	sessionHandler := handlers.NewSessionHandler(sessionService)
+	calendarHandler := handlers.NewCalendarHandler(calendarService)
 	userHandler := handlers.NewUserHandler(userService, auditService, roleService, cfg)
 	groupHandler := handlers.NewGroupHandler(groupService, auditService)
 	billHandler := handlers.NewBillHandler(billService, consumptionService, allocationService, auditService, eventService)
+	meterHandler := handlers.NewMeterHandler(meterService, auditService)
+	tariffHandler := handlers.NewTariffHandler(tariffService, auditService)
 	recurringBillHandler := handlers.NewRecurringBillHandler(recurringBillService, auditService)
 	loanHandler := handlers.NewLoanHandler(loanService, eventService, auditService)
-	choreHandler := handlers.NewChoreHandler(choreService, approvalService, roleService, auditService, eventService)
+	ledgerHandler := handlers.NewLedgerHandler(ledgerService)
+	settlementHandler := handlers.NewSettlementHandler(settlementService, eventService, auditService)
+	choreHandler := handlers.NewChoreHandler(choreService, auditService, eventService)
 	supplyHandler := handlers.NewSupplyHandler(supplyService, auditService, eventService)
-	backupHandler := handlers.NewBackupHandler(backupService)
+	backupHandler := handlers.NewBackupHandler(backupService, backupArchiveService)
 	eventHandler := handlers.NewEventHandler(eventService)
 	wsHandler := handlers.NewWebSocketHandler(eventService, cfg)
-	exportHandler := handlers.NewExportHandler(exportService)
+	exportHandler := handlers.NewExportHandler(exportService, statementService)
 	auditHandler := handlers.NewAuditHandler(auditService)
 	roleHandler := handlers.NewRoleHandler(roleService, permissionService, auditService, eventService, userService)
 	approvalHandler := handlers.NewApprovalHandler(approvalService)
@@ -188,6 +223,7 @@ func main() {
 	webPushHandler := handlers.NewWebPushHandler(webPushService)
 	notificationPreferenceHandler := handlers.NewNotificationPreferenceHandler(notificationPreferenceService)
 	appSettingsHandler := handlers.NewAppSettingsHandler(appSettingsService)
+	exchangeRateHandler := handlers.NewExchangeRateHandler(currencyService)
 	paymentHandler := handlers.NewPaymentHandler(paymentService, auditService)
 	reminderHandler := handlers.NewReminderHandler(reminderService)
 
@@ -196,6 +232,7 @@ func main() {
 
 	// API routes group - all API endpoints under /api
 	api := app.Group("/api")
+	api.Use(middleware.IdempotencyMiddleware(cfg, repos.IdempotencyKeys))
 
 	// Authentication routes
 	auth := api.Group("/auth")
@@ -224,9 +261,15 @@ func main() {
 	sessions := api.Group("/sessions")
 	sessions.Get("/", middleware.AuthMiddleware(cfg), sessionHandler.GetSessions)
 	sessions.Delete("/", middleware.AuthMiddleware(cfg), sessionHandler.DeleteAllSessions)
+	sessions.Get("/calendar-feeds", middleware.AuthMiddleware(cfg), calendarHandler.GetFeedTokens)
+	sessions.Post("/calendar-feeds", middleware.AuthMiddleware(cfg), calendarHandler.CreateFeedToken)
+	sessions.Delete("/calendar-feeds/:id", middleware.AuthMiddleware(cfg), calendarHandler.DeleteFeedToken)
 	sessions.Patch("/:id", middleware.AuthMiddleware(cfg), sessionHandler.RenameSession)
 	sessions.Delete("/:id", middleware.AuthMiddleware(cfg), sessionHandler.DeleteSession)
 
+	// Calendar feed (public, authenticated by the feed token)
+	api.Get("/calendar.ics", middleware.RateLimitMiddleware(60, 15*time.Minute), calendarHandler.GetFeed)
+
 	// User routes
 	users := api.Group("/users")
 	users.Get("/", middleware.AuthMiddleware(cfg), middleware.RequirePermission("users.read", getRoleService), userHandler.GetUsers)
@@ -235,6 +278,11 @@ func main() {
 	users.Get("/:id", middleware.AuthMiddleware(cfg), userHandler.GetUser)
 	users.Patch("/:id", middleware.AuthMiddleware(cfg), userHandler.UpdateUser)
 	users.Delete("/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("users.delete", getRoleService), userHandler.DeleteUser)
+	users.Get("/:id/group-history", middleware.AuthMiddleware(cfg), middleware.RequirePermission("users.read", getRoleService), userHandler.GetGroupHistory)
+	users.Get("/:id/residencies", middleware.AuthMiddleware(cfg), middleware.RequirePermission("users.read", getRoleService), userHandler.GetResidencies)
+	users.Post("/:id/residencies", middleware.AuthMiddleware(cfg), middleware.RequirePermission("users.update", getRoleService), userHandler.CreateResidency)
+	users.Patch("/:id/residencies/:residencyId", middleware.AuthMiddleware(cfg), middleware.RequirePermission("users.update", getRoleService), userHandler.UpdateResidency)
+	users.Delete("/:id/residencies/:residencyId", middleware.AuthMiddleware(cfg), middleware.RequirePermission("users.update", getRoleService), userHandler.DeleteResidency)
 	users.Post("/change-password", middleware.AuthMiddleware(cfg), userHandler.ChangePassword)
 	users.Post("/:id/force-password-change", middleware.AuthMiddleware(cfg), middleware.RequirePermission("users.update", getRoleService), userHandler.ForcePasswordChange)
 	users.Post("/:id/generate-reset-link", middleware.AuthMiddleware(cfg), middleware.RequirePermission("users.update", getRoleService), userHandler.GeneratePasswordResetLink)
@@ -244,6 +292,7 @@ func main() {
 	groups.Get("/", middleware.AuthMiddleware(cfg), groupHandler.GetGroups)
 	groups.Post("/", middleware.AuthMiddleware(cfg), middleware.RequirePermission("groups.create", getRoleService), groupHandler.CreateGroup)
 	groups.Get("/:id", middleware.AuthMiddleware(cfg), groupHandler.GetGroup)
+	groups.Get("/:id/weight-history", middleware.AuthMiddleware(cfg), groupHandler.GetWeightHistory)
 	groups.Patch("/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("groups.update", getRoleService), groupHandler.UpdateGroup)
 	groups.Delete("/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("groups.delete", getRoleService), groupHandler.DeleteGroup)
 
@@ -255,9 +304,10 @@ func main() {
 	bills.Post("/:id/post", middleware.AuthMiddleware(cfg), middleware.RequirePermission("bills.post", getRoleService), billHandler.PostBill)
 	bills.Post("/:id/close", middleware.AuthMiddleware(cfg), middleware.RequirePermission("bills.close", getRoleService), billHandler.CloseBill)
 	bills.Post("/:id/reopen", middleware.AuthMiddleware(cfg), middleware.RequirePermission("bills.update", getRoleService), billHandler.ReopenBill)
-	bills.Delete("/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("bills.delete", getRoleService), billHandler.DeleteBill)
+	bills.Delete("/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("bills.delete", getRoleService, middleware.WithApproval(approvalService, middleware.ApprovalRoute{Action: "bill.delete", ResourceType: "bill"})), billHandler.DeleteBill)
 	bills.Get("/:id/allocation", middleware.AuthMiddleware(cfg), billHandler.GetBillAllocation)
 	bills.Get("/:id/payment-status", middleware.AuthMiddleware(cfg), billHandler.GetBillPaymentStatus)
+	bills.Get("/:id/suspicious-readings", middleware.AuthMiddleware(cfg), billHandler.GetSuspiciousReadings)
 
 	// Consumption routes
 	consumptions := api.Group("/consumptions")
@@ -265,6 +315,26 @@ func main() {
 	consumptions.Get("/", middleware.AuthMiddleware(cfg), billHandler.GetConsumptions)
 	consumptions.Delete("/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("readings.delete", getRoleService), billHandler.DeleteConsumption)
 	consumptions.Post("/:id/mark-invalid", middleware.AuthMiddleware(cfg), billHandler.MarkConsumptionInvalid)
+	consumptions.Post("/:id/confirm", middleware.AuthMiddleware(cfg), billHandler.ConfirmConsumption)
+
+	// Meter routes
+	meters := api.Group("/meters")
+	meters.Get("/", middleware.AuthMiddleware(cfg), meterHandler.GetMeters)
+	meters.Post("/", middleware.AuthMiddleware(cfg), middleware.RequirePermission("meters.manage", getRoleService), meterHandler.CreateMeter)
+	meters.Get("/:id", middleware.AuthMiddleware(cfg), meterHandler.GetMeter)
+	meters.Patch("/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("meters.manage", getRoleService), meterHandler.UpdateMeter)
+	meters.Delete("/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("meters.manage", getRoleService), meterHandler.DeleteMeter)
+	meters.Get("/:id/readings", middleware.AuthMiddleware(cfg), meterHandler.GetReadings)
+	meters.Get("/:id/replacements", middleware.AuthMiddleware(cfg), meterHandler.GetReplacements)
+	meters.Post("/:id/replacements", middleware.AuthMiddleware(cfg), middleware.RequirePermission("meters.manage", getRoleService), meterHandler.ReplaceMeter)
+
+	// Tariff routes
+	tariffs := api.Group("/tariffs")
+	tariffs.Get("/", middleware.AuthMiddleware(cfg), tariffHandler.GetTariffs)
+	tariffs.Post("/", middleware.AuthMiddleware(cfg), middleware.RequirePermission("tariffs.manage", getRoleService), tariffHandler.CreateTariff)
+	tariffs.Get("/:id", middleware.AuthMiddleware(cfg), tariffHandler.GetTariff)
+	tariffs.Patch("/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("tariffs.manage", getRoleService), tariffHandler.UpdateTariff)
+	tariffs.Delete("/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("tariffs.manage", getRoleService), tariffHandler.DeleteTariff)
 
 	// Recurring bill routes
 	recurringBills := api.Group("/recurring-bills")
@@ -289,25 +359,42 @@ func main() {
 	loans.Get("/balances", middleware.AuthMiddleware(cfg), middleware.RequirePermission("loans.read", getRoleService), loanHandler.GetBalances)
 	loans.Get("/balances/me", middleware.AuthMiddleware(cfg), middleware.RequirePermission("loans.read", getRoleService), loanHandler.GetMyBalance)
 	loans.Get("/balances/user/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("loans.read", getRoleService), loanHandler.GetUserBalance)
+	loans.Get("/settlement", middleware.AuthMiddleware(cfg), middleware.RequirePermission("loans.read", getRoleService), settlementHandler.GetPlan)
+	loans.Post("/settlement/apply", middleware.AuthMiddleware(cfg), middleware.RequirePermission("loan-payments.create", getRoleService), settlementHandler.ApplyPlan)
 	loans.Get("/:id/payments", middleware.AuthMiddleware(cfg), middleware.RequirePermission("loans.read", getRoleService), loanHandler.GetLoanPayments)
-	loans.Delete("/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("loans.delete", getRoleService), loanHandler.DeleteLoan)
+	loans.Delete("/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("loans.delete", getRoleService, middleware.WithApproval(approvalService, middleware.ApprovalRoute{Action: "loan.delete", ResourceType: "loan"})), loanHandler.DeleteLoan)
 
 	// Loan payment routes
 	loanPayments := api.Group("/loan-payments")
 	loanPayments.Post("/", middleware.AuthMiddleware(cfg), middleware.RequirePermission("loan-payments.create", getRoleService), loanHandler.CreateLoanPayment)
 
+	// Ledger routes
+	ledger := api.Group("/ledger")
+	ledger.Get("/balances", middleware.AuthMiddleware(cfg), middleware.RequirePermission("loans.read", getRoleService), ledgerHandler.GetBalances)
+	ledger.Get("/statement", middleware.AuthMiddleware(cfg), middleware.RequirePermission("loans.read", getRoleService), ledgerHandler.GetStatement)
+
 	// Chore routes
 	chores := api.Group("/chores")
 	chores.Post("/", middleware.AuthMiddleware(cfg), middleware.RequirePermission("chores.create", getRoleService), choreHandler.CreateChore)
 	chores.Get("/", middleware.AuthMiddleware(cfg), choreHandler.GetChores)
 	chores.Get("/with-assignments", middleware.AuthMiddleware(cfg), choreHandler.GetChoresWithAssignments)
+	chores.Get("/settings", middleware.AuthMiddleware(cfg), choreHandler.GetChoreSettings)
+	chores.Patch("/settings", middleware.AuthMiddleware(cfg), middleware.RequirePermission("chores.update", getRoleService), choreHandler.UpdateChoreSettings)
 	chores.Put("/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("chores.update", getRoleService), choreHandler.UpdateChore)
-	chores.Delete("/:id", middleware.AuthMiddleware(cfg), choreHandler.DeleteChore)
+	chores.Delete("/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("chores.delete", getRoleService, middleware.WithApproval(approvalService, middleware.ApprovalRoute{Action: "chore.delete", ResourceType: "chore"})), choreHandler.DeleteChore)
 	chores.Post("/assign", middleware.AuthMiddleware(cfg), middleware.RequirePermission("chores.assign", getRoleService), choreHandler.AssignChore)
 	chores.Post("/swap", middleware.AuthMiddleware(cfg), middleware.RequirePermission("chores.assign", getRoleService), choreHandler.SwapChoreAssignment)
 	chores.Post("/:id/rotate", middleware.AuthMiddleware(cfg), middleware.RequirePermission("chores.assign", getRoleService), choreHandler.RotateChore)
 	chores.Post("/:id/auto-assign", middleware.AuthMiddleware(cfg), middleware.RequirePermission("chores.assign", getRoleService), choreHandler.AutoAssignChore)
 	chores.Post("/:id/random-assign", middleware.AuthMiddleware(cfg), middleware.RequirePermission("chores.assign", getRoleService), choreHandler.RandomAssignChore)
+	chores.Post("/:id/fair-assign", middleware.AuthMiddleware(cfg), middleware.RequirePermission("chores.assign", getRoleService), choreHandler.FairAssignChore)
+	chores.Get("/:id/fair-assign", middleware.AuthMiddleware(cfg), choreHandler.PreviewFairAssignment)
+	chores.Get("/absences", middleware.AuthMiddleware(cfg), choreHandler.GetAbsences)
+	chores.Post("/absences", middleware.AuthMiddleware(cfg), choreHandler.CreateAbsence)
+	chores.Delete("/absences/:id", middleware.AuthMiddleware(cfg), choreHandler.DeleteAbsence)
+	chores.Get("/:id/preferences", middleware.AuthMiddleware(cfg), choreHandler.GetChorePreferences)
+	chores.Put("/:id/preferences/:userId", middleware.AuthMiddleware(cfg), choreHandler.SetChorePreference)
+	chores.Delete("/:id/preferences/:userId", middleware.AuthMiddleware(cfg), choreHandler.RemoveChorePreference)
 
 	// Chore assignment routes
 	choreAssignments := api.Group("/chore-assignments")
@@ -315,6 +402,12 @@ func main() {
 	choreAssignments.Get("/me", middleware.AuthMiddleware(cfg), choreHandler.GetMyChoreAssignments)
 	choreAssignments.Patch("/:id", middleware.AuthMiddleware(cfg), choreHandler.UpdateChoreAssignment)
 	choreAssignments.Patch("/:id/reassign", middleware.AuthMiddleware(cfg), middleware.RequirePermission("chores.assign", getRoleService), choreHandler.ReassignChoreAssignment)
+	choreAssignments.Get("/:id/explanation", middleware.AuthMiddleware(cfg), choreHandler.ExplainChoreAssignment)
+	choreAssignments.Post("/:id/complete", middleware.AuthMiddleware(cfg), choreHandler.SubmitCompletion)
+	choreAssignments.Post("/:id/confirm", middleware.AuthMiddleware(cfg), choreHandler.ConfirmCompletion)
+	choreAssignments.Post("/:id/dispute", middleware.AuthMiddleware(cfg), choreHandler.DisputeCompletion)
+	choreAssignments.Get("/:id/completions", middleware.AuthMiddleware(cfg), choreHandler.GetCompletions)
+	choreAssignments.Get("/:id/completions/:completionId/photo", middleware.AuthMiddleware(cfg), choreHandler.GetCompletionPhoto)
 
 	// Chore leaderboard
 	api.Get("/chores/leaderboard", middleware.AuthMiddleware(cfg), choreHandler.GetUserLeaderboard)
@@ -334,7 +427,7 @@ func main() {
 	// Settings
 	supplies.Get("/settings", middleware.AuthMiddleware(cfg), supplyHandler.GetSettings)
 	supplies.Patch("/settings", middleware.AuthMiddleware(cfg), middleware.RequirePermission("supplies.update", getRoleService), supplyHandler.UpdateSettings)
-	supplies.Post("/settings/adjust", middleware.AuthMiddleware(cfg), middleware.RequirePermission("supplies.update", getRoleService), supplyHandler.AdjustBudget)
+	supplies.Post("/settings/adjust", middleware.AuthMiddleware(cfg), middleware.RequirePermission("supplies.update", getRoleService, middleware.WithApproval(approvalService, middleware.ApprovalRoute{Action: "supply.budget_adjust", ResourceType: "supply_settings", AmountField: "adjustment"})), supplyHandler.AdjustBudget)
 	supplies.Patch("/settings/holder", middleware.AuthMiddleware(cfg), middleware.RequirePermission("supplies.update", getRoleService), supplyHandler.SetBudgetHolder)
 
 	// Items
@@ -344,7 +437,7 @@ func main() {
 	supplies.Post("/items/:id/restock", middleware.AuthMiddleware(cfg), supplyHandler.RestockItem)
 	supplies.Post("/items/:id/consume", middleware.AuthMiddleware(cfg), supplyHandler.ConsumeItem)
 	supplies.Patch("/items/:id/quantity", middleware.AuthMiddleware(cfg), supplyHandler.SetQuantity)
-	supplies.Post("/items/:id/refund", middleware.AuthMiddleware(cfg), middleware.RequirePermission("supplies.update", getRoleService), supplyHandler.MarkAsRefunded)
+	supplies.Post("/items/:id/refund", middleware.AuthMiddleware(cfg), middleware.RequirePermission("supplies.update", getRoleService, middleware.WithApproval(approvalService, middleware.ApprovalRoute{Action: "supply.refund", ResourceType: "supply_item"})), supplyHandler.MarkAsRefunded)
 	supplies.Delete("/items/:id", middleware.AuthMiddleware(cfg), supplyHandler.DeleteItem)
 
 	// Contributions
@@ -369,6 +462,7 @@ func main() {
 	exports.Get("/balances", middleware.AuthMiddleware(cfg), exportHandler.ExportBalances)
 	exports.Get("/chores", middleware.AuthMiddleware(cfg), exportHandler.ExportChores)
 	exports.Get("/consumptions", middleware.AuthMiddleware(cfg), exportHandler.ExportConsumptions)
+	exports.Get("/statement", middleware.AuthMiddleware(cfg), exportHandler.ExportStatement)
 
 	// Audit log routes
 	audit := api.Group("/audit")
@@ -390,6 +484,10 @@ func main() {
 	approvals.Get("/", middleware.AuthMiddleware(cfg), middleware.RequirePermission("approvals.review", getRoleService), approvalHandler.GetAllRequests)
 	approvals.Post("/:id/approve", middleware.AuthMiddleware(cfg), middleware.RequirePermission("approvals.review", getRoleService), approvalHandler.ApproveRequest)
 	approvals.Post("/:id/reject", middleware.AuthMiddleware(cfg), middleware.RequirePermission("approvals.review", getRoleService), approvalHandler.RejectRequest)
+	approvals.Get("/policies", middleware.AuthMiddleware(cfg), middleware.RequirePermission("approvals.review", getRoleService), approvalHandler.GetPolicies)
+	approvals.Post("/policies", middleware.AuthMiddleware(cfg), middleware.RequirePermission("approvals.manage", getRoleService), approvalHandler.CreatePolicy)
+	approvals.Patch("/policies/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("approvals.manage", getRoleService), approvalHandler.UpdatePolicy)
+	approvals.Delete("/policies/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("approvals.manage", getRoleService), approvalHandler.DeletePolicy)
 
 	// Notification routes
 	notifications := api.Group("/notifications")
@@ -409,6 +507,10 @@ func main() {
 	backup := api.Group("/backup")
 	backup.Get("/export", middleware.AuthMiddleware(cfg), middleware.RequirePermission("backup.export", getRoleService), backupHandler.ExportBackup)
 	backup.Post("/import", middleware.AuthMiddleware(cfg), middleware.RequirePermission("backup.import", getRoleService), backupHandler.ImportBackup)
+	backup.Get("/archives", middleware.AuthMiddleware(cfg), middleware.RequirePermission("backup.export", getRoleService), backupHandler.ListArchives)
+	backup.Post("/archives", middleware.AuthMiddleware(cfg), middleware.RequirePermission("backup.export", getRoleService), backupHandler.CreateArchive)
+	backup.Get("/archives/:name", middleware.AuthMiddleware(cfg), middleware.RequirePermission("backup.export", getRoleService), backupHandler.DownloadArchive)
+	backup.Delete("/archives/:name", middleware.AuthMiddleware(cfg), middleware.RequirePermission("backup.manage", getRoleService), backupHandler.DeleteArchive)
 
 	// App settings routes
 	appSettings := api.Group("/app-settings")
@@ -416,6 +518,13 @@ func main() {
 	appSettings.Get("/languages", appSettingsHandler.GetSupportedLanguages) // Public - get supported languages
 	appSettings.Patch("/", middleware.AuthMiddleware(cfg), middleware.RequirePermission("settings.app.update", getRoleService), appSettingsHandler.UpdateSettings)
 
+	// Exchange rate routes
+	exchangeRates := api.Group("/exchange-rates")
+	exchangeRates.Get("/", middleware.AuthMiddleware(cfg), exchangeRateHandler.GetRates)
+	exchangeRates.Post("/", middleware.AuthMiddleware(cfg), middleware.RequirePermission("settings.app.update", getRoleService), exchangeRateHandler.SetRate)
+	exchangeRates.Post("/import", middleware.AuthMiddleware(cfg), middleware.RequirePermission("settings.app.update", getRoleService), exchangeRateHandler.ImportRates)
+	exchangeRates.Delete("/:id", middleware.AuthMiddleware(cfg), middleware.RequirePermission("settings.app.update", getRoleService), exchangeRateHandler.DeleteRate)
+
 	// Reminder routes
 	reminders := api.Group("/reminders")
 	reminders.Post("/debt/:userId", middleware.AuthMiddleware(cfg), middleware.RequirePermission("reminders.send", getRoleService), reminderHandler.SendDebtReminder)
@@ -489,6 +598,34 @@ func main() {
 		}
 	}()
 
+	// Start scheduled backup job (writes a backup when the newest one is older than BACKUP_INTERVAL)
+	go func() {
+		ticker := time.NewTicker(1 * time.Hour)
+		defer ticker.Stop()
+
+		if err := backupArchiveService.RunScheduled(context.Background()); err != nil {
+			log.Printf("Error during scheduled backup: %v", err)
+		}
+
+		for range ticker.C {
+			if err := backupArchiveService.RunScheduled(context.Background()); err != nil {
+				log.Printf("Error during scheduled backup: %v", err)
+			}
+		}
+	}()
+
+	// Start idempotency key cleanup job
+	go func() {
+		ticker := time.NewTicker(1 * time.Hour)
+		defer ticker.Stop()
+
+		for range ticker.C {
+			if err := repos.IdempotencyKeys.DeleteExpired(context.Background()); err != nil {
+				log.Printf("Error during idempotency key cleanup: %v", err)
+			}
+		}
+	}()
+
 	// Start supply contribution processing job
 	go func() {
 		ticker := time.NewTicker(24 * time.Hour)
@@ -559,6 +696,30 @@ func main() {
 		}
 	}()
 
+	// Start chore schedule and overdue handling (runs every hour)
+	go func() {
+		ticker := time.NewTicker(1 * time.Hour)
+		defer ticker.Stop()
+
+		// Run initial check after 2 minutes to let the system stabilize
+		time.Sleep(2 * time.Minute)
+		if _, err := choreService.GenerateUpcomingAssignments(context.Background(), time.Now()); err != nil {
+			log.Printf("Error during chore scheduling: %v", err)
+		}
+		if _, err := choreService.ProcessOverdueAssignments(context.Background(), time.Now()); err != nil {
+			log.Printf("Error during overdue chore check: %v", err)
+		}
+
+		for range ticker.C {
+			if _, err := choreService.GenerateUpcomingAssignments(context.Background(), time.Now()); err != nil {
+				log.Printf("Error during chore scheduling: %v", err)
+			}
+			if _, err := choreService.ProcessOverdueAssignments(context.Background(), time.Now()); err != nil {
+				log.Printf("Error during overdue chore check: %v", err)
+			}
+		}
+	}()
+
 	// Start server
 	addr := fmt.Sprintf("%s:%s", cfg.App.Host, cfg.App.Port)
 	go func() {
@@ -661,6 +822,11 @@ func validateConfig(cfg *config.Config) error {
 		}
 	}
 
+	// Validate backup archive passphrase if provided
+	if cfg.Backup.Passphrase != "" && len([]rune(cfg.Backup.Passphrase)) < utils.MinPassphraseLength {
+		return fmt.Errorf("BACKUP_PASSPHRASE is too short (minimum %d characters required)", utils.MinPassphraseLength)
+	}
+
 	// Validate TOTP encryption key if provided
 	if cfg.Auth.TOTPEncryptionKey != "" {
 		if len(cfg.Auth.TOTPEncryptionKey) != 32 {
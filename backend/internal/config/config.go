@@ -3,6 +3,8 @@ package config
 import (
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"time"
 )
 
@@ -14,6 +16,8 @@ type Config struct {
 	SQLite  SQLiteConfig
 	Logging LogConfig
 	VAPID   VAPIDConfig
+	Backup  BackupConfig
+	Chores  ChoreConfig
 }
 
 type VAPIDConfig struct {
@@ -27,8 +31,9 @@ type AppConfig struct {
 	Host           string
 	Port           string
 	BaseURL        string
-	Domain         string // For WebAuthn (e.g., "localhost" or "holyhome.app")
-	AllowedOrigins string // CORS allowed origins, defaults to "*" if not set
+	Domain         string        // For WebAuthn (e.g., "localhost" or "holyhome.app")
+	AllowedOrigins string        // CORS allowed origins, defaults to "*" if not set
+	IdempotencyTTL time.Duration // How long responses to Idempotency-Key requests are replayed
 }
 
 type JWTConfig struct {
@@ -57,6 +62,23 @@ type SQLiteConfig struct {
 	DatabasePath string // Path to SQLite database file
 }
 
+type BackupConfig struct {
+	Dir         string        // Directory for scheduled backup archives
+	Interval    time.Duration // Time between scheduled backups, 0 disables them
+	KeepDaily   int           // Number of most recent days to keep one backup for
+	KeepWeekly  int           // Number of most recent weeks to keep one backup for
+	KeepMonthly int           // Number of most recent months to keep one backup for
+	Passphrase  string        // Encrypts scheduled backup archives when set
+}
+
+type ChoreConfig struct {
+	ScheduleDays     int    // Days ahead to create assignments for scheduled chores, 0 disables it
+	FairWindowDays   int    // Days before a due date whose completed chores count towards fair assignment, 0 counts all
+	FairHalfLifeDays int    // Days after which an assignment counts half towards fair assignment, 0 disables decay
+	PhotoDir         string // Directory for photos proving chore completion
+	PhotoMaxBytes    int    // Largest accepted completion photo
+}
+
 type LogConfig struct {
 	Level  string
 	Format string
@@ -73,6 +95,48 @@ func Load() (*Config, error) {
 		return nil, fmt.Errorf("invalid JWT_REFRESH_TTL: %w", err)
 	}
 
+	idempotencyTTL, err := time.ParseDuration(getEnv("IDEMPOTENCY_TTL", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid IDEMPOTENCY_TTL: %w", err)
+	}
+
+	backupInterval, err := time.ParseDuration(getEnv("BACKUP_INTERVAL", "24h"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid BACKUP_INTERVAL: %w", err)
+	}
+
+	keepDaily, err := getEnvCount("BACKUP_KEEP_DAILY", 7)
+	if err != nil {
+		return nil, err
+	}
+	keepWeekly, err := getEnvCount("BACKUP_KEEP_WEEKLY", 4)
+	if err != nil {
+		return nil, err
+	}
+	keepMonthly, err := getEnvCount("BACKUP_KEEP_MONTHLY", 6)
+	if err != nil {
+		return nil, err
+	}
+
+	choreScheduleDays, err := getEnvCount("CHORE_SCHEDULE_DAYS", 7)
+	if err != nil {
+		return nil, err
+	}
+	choreFairWindowDays, err := getEnvCount("CHORE_FAIR_WINDOW_DAYS", 56)
+	if err != nil {
+		return nil, err
+	}
+	choreFairHalfLifeDays, err := getEnvCount("CHORE_FAIR_HALF_LIFE_DAYS", 14)
+	if err != nil {
+		return nil, err
+	}
+	chorePhotoMaxKB, err := getEnvCount("CHORE_PHOTO_MAX_KB", 5120)
+	if err != nil {
+		return nil, err
+	}
+
+	databasePath := getEnv("DATABASE_PATH", "./holyhome.db")
+
 	return &Config{
 		App: AppConfig{
 			Name:           getEnv("APP_NAME", "Holy Home"),
@@ -82,6 +146,7 @@ func Load() (*Config, error) {
 			BaseURL:        getEnv("APP_BASE_URL", "http://localhost:8080"),
 			Domain:         getEnv("APP_DOMAIN", "localhost"),
 			AllowedOrigins: getEnv("ALLOWED_ORIGINS", "*"),
+			IdempotencyTTL: idempotencyTTL,
 		},
 		JWT: JWTConfig{
 			AccessTTL:     accessTTL,
@@ -101,7 +166,7 @@ func Load() (*Config, error) {
 			RequireUsername:    getEnv("AUTH_REQUIRE_USERNAME", "false") == "true",
 		},
 		SQLite: SQLiteConfig{
-			DatabasePath: getEnv("DATABASE_PATH", "./holyhome.db"),
+			DatabasePath: databasePath,
 		},
 		Logging: LogConfig{
 			Level:  getEnv("LOG_LEVEL", "info"),
@@ -111,6 +176,21 @@ func Load() (*Config, error) {
 			PublicKey:  getEnv("VAPID_PUBLIC_KEY", ""),
 			PrivateKey: getEnv("VAPID_PRIVATE_KEY", ""),
 		},
+		Backup: BackupConfig{
+			Dir:         getEnv("BACKUP_DIR", filepath.Join(filepath.Dir(databasePath), "backups")),
+			Interval:    backupInterval,
+			KeepDaily:   keepDaily,
+			KeepWeekly:  keepWeekly,
+			KeepMonthly: keepMonthly,
+			Passphrase:  getEnv("BACKUP_PASSPHRASE", ""),
+		},
+		Chores: ChoreConfig{
+			ScheduleDays:     choreScheduleDays,
+			FairWindowDays:   choreFairWindowDays,
+			FairHalfLifeDays: choreFairHalfLifeDays,
+			PhotoDir:         getEnv("CHORE_PHOTO_DIR", filepath.Join(filepath.Dir(databasePath), "chore-photos")),
+			PhotoMaxBytes:    chorePhotoMaxKB * 1024,
+		},
 	}, nil
 }
 
@@ -120,3 +200,16 @@ func getEnv(key, defaultValue string) string {
 	}
 	return defaultValue
 }
+
+// getEnvCount reads a non-negative integer from the environment
+func getEnvCount(key string, defaultValue int) (int, error) {
+	value := os.Getenv(key)
+	if value == "" {
+		return defaultValue, nil
+	}
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid %s: must be a non-negative integer", key)
+	}
+	return n, nil
+}
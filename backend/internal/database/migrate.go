@@ -0,0 +1,287 @@
+package database
+
+import (
+	"context"
+	"crypto/sha256"
+	"embed"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"log"
+	"path"
+	"regexp"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jmoiron/sqlx"
+)
+
+// Migrations are embedded SQL files named NNNN_description.sql. Each one runs once, in
+// version order, inside its own transaction. Applied migrations must never be edited:
+// their checksum is recorded and checked on every start. Schema changes go in a new file.
+//
+//go:embed migrations/*.sql
+var migrationsFS embed.FS
+
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.sql$`)
+
+// Migration is an embedded up-migration
+type Migration struct {
+	Version  int
+	Name     string
+	SQL      string
+	Checksum string // SHA-256 of SQL
+}
+
+// MigrationStatus describes a migration known to the binary or recorded in the database
+type MigrationStatus struct {
+	Version          int
+	Name             string
+	Applied          bool
+	AppliedAt        *time.Time
+	ChecksumMismatch bool // Applied file differs from the embedded one
+	Unknown          bool // Recorded in the database but not embedded in this binary
+}
+
+type appliedMigration struct {
+	Version   int    `db:"version"`
+	Name      string `db:"name"`
+	Checksum  string `db:"checksum"`
+	AppliedAt string `db:"applied_at"`
+}
+
+// LoadMigrations returns the embedded migrations ordered by version
+func LoadMigrations() ([]Migration, error) {
+	entries, err := fs.ReadDir(migrationsFS, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	var migrations []Migration
+	seen := make(map[int]string)
+	for _, entry := range entries {
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("invalid migration file name %q, expected NNNN_description.sql", entry.Name())
+		}
+		version, _ := strconv.Atoi(match[1])
+		if other, ok := seen[version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d: %s and %s", version, other, entry.Name())
+		}
+		seen[version] = entry.Name()
+
+		data, err := migrationsFS.ReadFile(path.Join("migrations", entry.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read migration %s: %w", entry.Name(), err)
+		}
+		sum := sha256.Sum256(data)
+		migrations = append(migrations, Migration{
+			Version:  version,
+			Name:     match[2],
+			SQL:      string(data),
+			Checksum: hex.EncodeToString(sum[:]),
+		})
+	}
+
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// MigrationStatus lists every embedded migration and whether it has been applied,
+// followed by any applied versions this binary does not know about
+func (s *SQLiteDB) MigrationStatus(ctx context.Context) ([]MigrationStatus, error) {
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := s.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []MigrationStatus
+	for _, m := range migrations {
+		status := MigrationStatus{Version: m.Version, Name: m.Name}
+		if a, ok := applied[m.Version]; ok {
+			appliedAt, _ := time.Parse(time.RFC3339, a.AppliedAt)
+			status.Applied = true
+			status.AppliedAt = &appliedAt
+			status.ChecksumMismatch = a.Checksum != m.Checksum
+			delete(applied, m.Version)
+		}
+		statuses = append(statuses, status)
+	}
+
+	var unknown []MigrationStatus
+	for _, a := range applied {
+		appliedAt, _ := time.Parse(time.RFC3339, a.AppliedAt)
+		unknown = append(unknown, MigrationStatus{Version: a.Version, Name: a.Name, Applied: true, AppliedAt: &appliedAt, Unknown: true})
+	}
+	sort.Slice(unknown, func(i, j int) bool { return unknown[i].Version < unknown[j].Version })
+
+	return append(statuses, unknown...), nil
+}
+
+// VerifyMigrations checks that every applied migration matches the embedded file
+// and that the database was not migrated by a newer binary
+func (s *SQLiteDB) VerifyMigrations(ctx context.Context) error {
+	statuses, err := s.MigrationStatus(ctx)
+	if err != nil {
+		return err
+	}
+	for _, status := range statuses {
+		if status.ChecksumMismatch {
+			return fmt.Errorf("migration %04d_%s was modified after it was applied", status.Version, status.Name)
+		}
+		if status.Unknown {
+			return fmt.Errorf("migration %04d_%s is applied but unknown to this version of the application", status.Version, status.Name)
+		}
+	}
+	return nil
+}
+
+// Migrate applies all pending migrations in version order and returns the ones applied.
+// It refuses to run if VerifyMigrations fails.
+func (s *SQLiteDB) Migrate(ctx context.Context) ([]Migration, error) {
+	if err := s.ensureMigrationsTable(ctx); err != nil {
+		return nil, err
+	}
+	if err := s.VerifyMigrations(ctx); err != nil {
+		return nil, err
+	}
+
+	migrations, err := LoadMigrations()
+	if err != nil {
+		return nil, err
+	}
+	applied, err := s.appliedMigrations(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var ran []Migration
+	for _, m := range migrations {
+		if _, ok := applied[m.Version]; ok {
+			continue
+		}
+		if err := s.applyMigration(ctx, m); err != nil {
+			return ran, err
+		}
+		log.Printf("Migration: Applied %04d_%s", m.Version, m.Name)
+		ran = append(ran, m)
+	}
+	return ran, nil
+}
+
+// applyMigration runs a migration and records it in one transaction
+func (s *SQLiteDB) applyMigration(ctx context.Context, m Migration) error {
+	tx, err := s.DB.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+	defer tx.Rollback()
+
+	// Databases created before versioned migrations already hold most of the initial
+	// schema, with later columns added ad hoc. Add any columns missing from existing
+	// tables so the initial migration's CREATE ... IF NOT EXISTS statements can be adopted.
+	if m.Version == 1 {
+		if err := addLegacyColumns(ctx, tx); err != nil {
+			return fmt.Errorf("failed to upgrade legacy schema: %w", err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, m.SQL); err != nil {
+		return fmt.Errorf("migration %04d_%s failed: %w", m.Version, m.Name, err)
+	}
+	if _, err := tx.ExecContext(ctx,
+		"INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (?, ?, ?, ?)",
+		m.Version, m.Name, m.Checksum, time.Now().UTC().Format(time.RFC3339),
+	); err != nil {
+		return fmt.Errorf("failed to record migration %04d_%s: %w", m.Version, m.Name, err)
+	}
+
+	return tx.Commit()
+}
+
+func (s *SQLiteDB) ensureMigrationsTable(ctx context.Context) error {
+	_, err := s.DB.ExecContext(ctx, `
+		CREATE TABLE IF NOT EXISTS schema_migrations (
+			version INTEGER PRIMARY KEY,
+			name TEXT NOT NULL,
+			checksum TEXT NOT NULL,
+			applied_at TEXT NOT NULL
+		)
+	`)
+	if err != nil {
+		return fmt.Errorf("failed to create schema_migrations table: %w", err)
+	}
+	return nil
+}
+
+func (s *SQLiteDB) appliedMigrations(ctx context.Context) (map[int]appliedMigration, error) {
+	var exists int
+	if err := s.DB.GetContext(ctx, &exists, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'"); err != nil {
+		return nil, fmt.Errorf("failed to check schema_migrations table: %w", err)
+	}
+	applied := make(map[int]appliedMigration)
+	if exists == 0 {
+		return applied, nil
+	}
+
+	var rows []appliedMigration
+	if err := s.DB.SelectContext(ctx, &rows, "SELECT version, name, checksum, applied_at FROM schema_migrations"); err != nil {
+		return nil, fmt.Errorf("failed to read schema_migrations: %w", err)
+	}
+	for _, row := range rows {
+		applied[row.Version] = row
+	}
+	return applied, nil
+}
+
+// legacyColumns were added to existing tables by the ad-hoc migrations that ran on
+// every start before versioned migrations existed
+var legacyColumns = []struct{ table, column, definition string }{
+	{"app_settings", "reminder_rate_limit_per_hour", "INTEGER NOT NULL DEFAULT 1"},
+	{"chores", "manual_assignee_id", "TEXT REFERENCES users(id) ON DELETE SET NULL"},
+	{"approval_requests", "result", "TEXT"},
+	{"approval_requests", "executed_at", "TEXT"},
+	{"approval_requests", "execution_error", "TEXT"},
+	{"bills", "currency", "TEXT NOT NULL DEFAULT 'PLN'"},
+	{"bills", "original_amount", "TEXT"},
+	{"bills", "exchange_rate", "TEXT"},
+	{"payments", "currency", "TEXT NOT NULL DEFAULT 'PLN'"},
+	{"payments", "original_amount", "TEXT"},
+	{"payments", "exchange_rate", "TEXT"},
+	{"loans", "currency", "TEXT NOT NULL DEFAULT 'PLN'"},
+	{"loans", "original_amount", "TEXT"},
+	{"loans", "exchange_rate", "TEXT"},
+	{"supply_items", "last_restock_currency", "TEXT"},
+	{"supply_items", "last_restock_original_amount", "TEXT"},
+	{"supply_items", "last_restock_exchange_rate", "TEXT"},
+	{"app_settings", "base_currency", "TEXT NOT NULL DEFAULT 'PLN'"},
+}
+
+// addLegacyColumns adds legacyColumns missing from tables that already exist
+func addLegacyColumns(ctx context.Context, db *sqlx.Tx) error {
+	for _, c := range legacyColumns {
+		var tableCount, columnCount int
+		if err := db.GetContext(ctx, &tableCount, "SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?", c.table); err != nil {
+			return fmt.Errorf("failed to check %s table: %w", c.table, err)
+		}
+		if tableCount == 0 {
+			continue
+		}
+		if err := db.GetContext(ctx, &columnCount, "SELECT COUNT(*) FROM pragma_table_info(?) WHERE name = ?", c.table, c.column); err != nil {
+			return fmt.Errorf("failed to check %s column: %w", c.table, err)
+		}
+		if columnCount > 0 {
+			continue
+		}
+		if _, err := db.ExecContext(ctx, fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", c.table, c.column, c.definition)); err != nil {
+			return fmt.Errorf("failed to add %s column to %s: %w", c.column, c.table, err)
+		}
+		log.Printf("Migration: Added %s column to %s", c.column, c.table)
+	}
+	return nil
+}
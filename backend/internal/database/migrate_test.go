@@ -0,0 +1,61 @@
+package database
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLoadMigrations(t *testing.T) {
+	migrations, err := LoadMigrations()
+	require.NoError(t, err)
+	require.NotEmpty(t, migrations)
+
+	for i, m := range migrations {
+		assert.Equal(t, i+1, m.Version, "migration versions must be consecutive")
+		assert.Len(t, m.Checksum, 64)
+	}
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	db, err := OpenSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+
+	migrations, err := LoadMigrations()
+	require.NoError(t, err)
+
+	applied, err := db.Migrate(ctx)
+	require.NoError(t, err)
+	assert.Len(t, applied, len(migrations))
+
+	// A second run is a no-op
+	applied, err = db.Migrate(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, applied)
+
+	statuses, err := db.MigrationStatus(ctx)
+	require.NoError(t, err)
+	for _, status := range statuses {
+		assert.True(t, status.Applied)
+	}
+	require.NoError(t, db.VerifyMigrations(ctx))
+
+	// An edited migration fails verification and blocks further migrations
+	_, err = db.DB.ExecContext(ctx, "UPDATE schema_migrations SET checksum = 'edited' WHERE version = 1")
+	require.NoError(t, err)
+	assert.ErrorContains(t, db.VerifyMigrations(ctx), "modified")
+	_, err = db.Migrate(ctx)
+	assert.Error(t, err)
+
+	// So does a migration applied by a newer binary
+	_, err = db.DB.ExecContext(ctx, "UPDATE schema_migrations SET checksum = ? WHERE version = 1", migrations[0].Checksum)
+	require.NoError(t, err)
+	_, err = db.DB.ExecContext(ctx, "INSERT INTO schema_migrations (version, name, checksum, applied_at) VALUES (9999, 'future', 'x', '2026-01-01T00:00:00Z')")
+	require.NoError(t, err)
+	assert.ErrorContains(t, db.VerifyMigrations(ctx), "unknown")
+}
@@ -81,6 +81,9 @@ CREATE TABLE IF NOT EXISTS bills (
     period_end TEXT NOT NULL,
     payment_deadline TEXT,
     total_amount_pln TEXT NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'PLN',
+    original_amount TEXT,
+    exchange_rate TEXT,
     total_units TEXT,
     notes TEXT,
     status TEXT NOT NULL DEFAULT 'draft',
@@ -145,6 +148,9 @@ CREATE TABLE IF NOT EXISTS payments (
     bill_id TEXT NOT NULL REFERENCES bills(id) ON DELETE CASCADE,
     payer_user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
     amount_pln TEXT NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'PLN',
+    original_amount TEXT,
+    exchange_rate TEXT,
     paid_at TEXT NOT NULL DEFAULT (datetime('now')),
     method TEXT,
     reference TEXT
@@ -162,6 +168,9 @@ CREATE TABLE IF NOT EXISTS loans (
     lender_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
     borrower_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
     amount_pln TEXT NOT NULL,
+    currency TEXT NOT NULL DEFAULT 'PLN',
+    original_amount TEXT,
+    exchange_rate TEXT,
     note TEXT,
     due_date TEXT,
     status TEXT NOT NULL DEFAULT 'open',
@@ -278,6 +287,9 @@ CREATE TABLE IF NOT EXISTS supply_items (
     last_restocked_at TEXT,
     last_restocked_by_user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
     last_restock_amount_pln TEXT,
+    last_restock_currency TEXT,
+    last_restock_original_amount TEXT,
+    last_restock_exchange_rate TEXT,
     needs_refund INTEGER NOT NULL DEFAULT 0,
     notes TEXT
 );
@@ -346,6 +358,22 @@ CREATE TABLE IF NOT EXISTS password_reset_tokens (
 CREATE INDEX IF NOT EXISTS idx_reset_tokens_user ON password_reset_tokens(user_id);
 CREATE INDEX IF NOT EXISTS idx_reset_tokens_expires ON password_reset_tokens(expires_at);
 
+-- Stored responses of mutating requests sent with an Idempotency-Key header
+CREATE TABLE IF NOT EXISTS idempotency_keys (
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    idempotency_key TEXT NOT NULL,
+    route TEXT NOT NULL,
+    request_hash TEXT NOT NULL,
+    status_code INTEGER NOT NULL DEFAULT 0, -- 0 while the first request is in progress
+    response_body BLOB,
+    content_type TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    expires_at TEXT NOT NULL,
+    PRIMARY KEY (user_id, idempotency_key, route)
+);
+
+CREATE INDEX IF NOT EXISTS idx_idempotency_keys_expires ON idempotency_keys(expires_at);
+
 -- ============================================
 -- NOTIFICATIONS
 -- ============================================
@@ -444,12 +472,65 @@ CREATE TABLE IF NOT EXISTS approval_requests (
     reviewed_by TEXT REFERENCES users(id) ON DELETE SET NULL,
     reviewed_at TEXT,
     review_notes TEXT,
+    result TEXT,
+    executed_at TEXT,
+    execution_error TEXT,
     created_at TEXT NOT NULL DEFAULT (datetime('now'))
 );
 
 CREATE INDEX IF NOT EXISTS idx_approval_status ON approval_requests(status);
 CREATE INDEX IF NOT EXISTS idx_approval_user ON approval_requests(user_id);
 
+CREATE TABLE IF NOT EXISTS approval_policies (
+    id TEXT PRIMARY KEY,
+    action TEXT NOT NULL,
+    role TEXT,
+    min_amount_pln TEXT,
+    enabled INTEGER NOT NULL DEFAULT 1,
+    is_system INTEGER NOT NULL DEFAULT 0,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_approval_policies_action ON approval_policies(action);
+
+-- ============================================
+-- LEDGER (double-entry, append-only)
+-- ============================================
+
+CREATE TABLE IF NOT EXISTS ledger_entries (
+    id TEXT PRIMARY KEY,
+    transaction_id TEXT NOT NULL,
+    account_type TEXT NOT NULL CHECK (account_type IN ('user', 'group', 'household')),
+    account_id TEXT NOT NULL,
+    amount_pln TEXT NOT NULL,
+    source_type TEXT NOT NULL,
+    source_id TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    occurred_at TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_ledger_account ON ledger_entries(account_type, account_id, occurred_at);
+CREATE INDEX IF NOT EXISTS idx_ledger_source ON ledger_entries(source_type, source_id);
+CREATE INDEX IF NOT EXISTS idx_ledger_transaction ON ledger_entries(transaction_id);
+
+-- ============================================
+-- EXCHANGE RATES
+-- ============================================
+
+-- rate is the number of base currency units per one unit of currency on rate_date
+CREATE TABLE IF NOT EXISTS exchange_rates (
+    id TEXT PRIMARY KEY,
+    currency TEXT NOT NULL,
+    base_currency TEXT NOT NULL,
+    rate_date TEXT NOT NULL,
+    rate TEXT NOT NULL,
+    source TEXT NOT NULL DEFAULT 'manual',
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE(currency, base_currency, rate_date)
+);
+
 -- ============================================
 -- APP SETTINGS (singleton)
 -- ============================================
@@ -460,6 +541,7 @@ CREATE TABLE IF NOT EXISTS app_settings (
     default_language TEXT NOT NULL DEFAULT 'en',
     disable_auto_detect INTEGER NOT NULL DEFAULT 0,
     reminder_rate_limit_per_hour INTEGER NOT NULL DEFAULT 1,
+    base_currency TEXT NOT NULL DEFAULT 'PLN',
     updated_at TEXT NOT NULL DEFAULT (datetime('now'))
 );
 
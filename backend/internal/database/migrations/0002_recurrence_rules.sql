@@ -0,0 +1,4 @@
+-- RFC 5545 recurrence rules for chores and recurring bill templates.
+-- When set, the rule replaces the frequency for scheduling.
+ALTER TABLE chores ADD COLUMN recurrence_rule TEXT;
+ALTER TABLE recurring_bill_templates ADD COLUMN recurrence_rule TEXT;
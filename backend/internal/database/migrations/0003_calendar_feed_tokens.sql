@@ -0,0 +1,12 @@
+-- Private iCalendar feed tokens. Calendar clients cannot refresh JWTs, so each feed URL
+-- carries its own long-lived token; only its SHA-256 hash is stored.
+CREATE TABLE IF NOT EXISTS calendar_feed_tokens (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    token_hash TEXT NOT NULL UNIQUE,
+    name TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now')),
+    last_used_at TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_calendar_feed_tokens_user ON calendar_feed_tokens(user_id);
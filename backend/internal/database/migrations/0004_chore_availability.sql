@@ -0,0 +1,23 @@
+-- Absence periods: users get no chore assignments between start_date and end_date (inclusive)
+CREATE TABLE IF NOT EXISTS user_absences (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    start_date TEXT NOT NULL,
+    end_date TEXT NOT NULL,
+    reason TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_absences_user ON user_absences(user_id);
+CREATE INDEX IF NOT EXISTS idx_user_absences_dates ON user_absences(start_date, end_date);
+
+-- Per-chore assignment preferences: preferred users are picked before the others,
+-- excluded users are never picked automatically
+CREATE TABLE IF NOT EXISTS chore_preferences (
+    id TEXT PRIMARY KEY,
+    chore_id TEXT NOT NULL REFERENCES chores(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    preference TEXT NOT NULL CHECK (preference IN ('preferred', 'excluded')),
+    updated_at TEXT NOT NULL DEFAULT (datetime('now')),
+    UNIQUE (chore_id, user_id)
+);
@@ -0,0 +1,11 @@
+-- What happens to a chore's assignments after the escalation delay:
+-- 'keep' leaves them with the assignee, 'reassign' hands them to someone else
+ALTER TABLE chores ADD COLUMN overdue_policy TEXT NOT NULL DEFAULT 'keep';
+
+-- User who assigned the chore, notified when it becomes overdue. NULL for scheduled assignments.
+ALTER TABLE chore_assignments ADD COLUMN assigned_by_user_id TEXT REFERENCES users(id) ON DELETE SET NULL;
+
+-- Points for overdue assignments: 'none' keeps them, 'withhold' earns nothing, 'deduct' subtracts them.
+-- Escalation to the assigner or admins happens overdue_escalation_hours after the due date.
+ALTER TABLE chore_settings ADD COLUMN overdue_penalty TEXT NOT NULL DEFAULT 'none';
+ALTER TABLE chore_settings ADD COLUMN overdue_escalation_hours INTEGER NOT NULL DEFAULT 24;
@@ -0,0 +1,21 @@
+-- Completion verification: a chore can require a photo of the result and/or
+-- confirmation by another resident before an assignment is done
+ALTER TABLE chores ADD COLUMN require_photo INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE chores ADD COLUMN require_confirmation INTEGER NOT NULL DEFAULT 0;
+
+-- Submitted completions of chore assignments, with their photo and review.
+-- Photos are stored on disk, photo_path is relative to the photo directory.
+CREATE TABLE IF NOT EXISTS chore_completions (
+    id TEXT PRIMARY KEY,
+    assignment_id TEXT NOT NULL REFERENCES chore_assignments(id) ON DELETE CASCADE,
+    submitted_by_user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    photo_path TEXT,
+    photo_content_type TEXT,
+    status TEXT NOT NULL CHECK (status IN ('awaiting_confirmation', 'confirmed', 'disputed')),
+    reviewer_user_id TEXT REFERENCES users(id) ON DELETE SET NULL,
+    review_comment TEXT,
+    submitted_at TEXT NOT NULL DEFAULT (datetime('now')),
+    reviewed_at TEXT
+);
+
+CREATE INDEX IF NOT EXISTS idx_chore_completions_assignment ON chore_completions(assignment_id);
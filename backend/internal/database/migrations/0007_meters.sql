@@ -0,0 +1,36 @@
+-- Physical meters owned by a user or group. rollover_at is the modulus of a rolling
+-- counter: the value that shows as zero again (100000 for a five digit counter that
+-- displays at most 99999), NULL when it never rolls over.
+CREATE TABLE IF NOT EXISTS meters (
+    id TEXT PRIMARY KEY,
+    subject_type TEXT NOT NULL CHECK (subject_type IN ('user', 'group')),
+    subject_id TEXT NOT NULL,
+    utility_type TEXT NOT NULL,
+    serial_number TEXT,
+    unit TEXT NOT NULL,
+    rollover_at TEXT,
+    is_active INTEGER NOT NULL DEFAULT 1,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_meters_subject ON meters(subject_type, subject_id);
+
+-- Replacement of the counter behind a meter: the old counter's final value closes
+-- it and the new counter starts counting from its initial value
+CREATE TABLE IF NOT EXISTS meter_replacements (
+    id TEXT PRIMARY KEY,
+    meter_id TEXT NOT NULL REFERENCES meters(id) ON DELETE CASCADE,
+    replaced_at TEXT NOT NULL,
+    old_final_value TEXT NOT NULL,
+    new_initial_value TEXT NOT NULL,
+    old_serial_number TEXT,
+    new_serial_number TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_meter_replacements_meter ON meter_replacements(meter_id, replaced_at);
+
+-- Readings of a meter. Readings without one are compared per subject as before.
+ALTER TABLE consumptions ADD COLUMN meter_id TEXT REFERENCES meters(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_consumptions_meter ON consumptions(meter_id, recorded_at);
@@ -0,0 +1,4 @@
+-- Readings whose usage is far from the subject's history are flagged as suspicious
+-- and need confirmation before their bill can be posted or closed
+ALTER TABLE consumptions ADD COLUMN anomaly_status TEXT NOT NULL DEFAULT 'none' CHECK (anomaly_status IN ('none', 'suspicious', 'confirmed'));
+ALTER TABLE consumptions ADD COLUMN anomaly_reason TEXT;
@@ -0,0 +1,20 @@
+-- Tariffs price the bills of a utility by components instead of a single rate per unit.
+-- components is a JSON array of fixed amounts (split by weight) and variable rates
+-- (by units, optionally limited to a register and a usage tier).
+CREATE TABLE IF NOT EXISTS tariffs (
+    id TEXT PRIMARY KEY,
+    utility_type TEXT NOT NULL,
+    name TEXT NOT NULL,
+    valid_from TEXT NOT NULL,
+    valid_to TEXT,
+    components TEXT NOT NULL DEFAULT '[]',
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_tariffs_utility ON tariffs(utility_type, valid_from);
+
+-- Meters with several registers (day/night) list them as a JSON array, NULL for one register.
+-- Readings and replacements of such meters name the register they belong to.
+ALTER TABLE meters ADD COLUMN registers TEXT;
+ALTER TABLE meter_replacements ADD COLUMN register TEXT;
+ALTER TABLE consumptions ADD COLUMN register TEXT;
@@ -0,0 +1,13 @@
+-- Periods a user lived in the household, with the group they belonged to at the time.
+-- end_date is the last day of the stay (inclusive), NULL while the user still lives here.
+-- Bills are split by the days each user was present within the bill period.
+CREATE TABLE IF NOT EXISTS residencies (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    group_id TEXT REFERENCES groups(id) ON DELETE SET NULL,
+    start_date TEXT NOT NULL,
+    end_date TEXT,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_residencies_user ON residencies(user_id, start_date);
@@ -0,0 +1,25 @@
+-- Effective-dated changes of group weights and memberships. A change records the value
+-- before and after effective_from, so allocations of earlier bill periods keep using the
+-- configuration of the time. Without changes the current values apply to every period.
+CREATE TABLE IF NOT EXISTS group_weight_changes (
+    id TEXT PRIMARY KEY,
+    group_id TEXT NOT NULL REFERENCES groups(id) ON DELETE CASCADE,
+    old_weight REAL NOT NULL,
+    new_weight REAL NOT NULL,
+    effective_from TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_weight_changes_group ON group_weight_changes(group_id, effective_from);
+
+-- Group ids are kept when a group is deleted so the history stays readable
+CREATE TABLE IF NOT EXISTS group_membership_changes (
+    id TEXT PRIMARY KEY,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    old_group_id TEXT,
+    new_group_id TEXT,
+    effective_from TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_group_membership_changes_user ON group_membership_changes(user_id, effective_from);
@@ -0,0 +1,29 @@
+-- Transfers residents make to carry out an applied settlement plan, keyed by the
+-- fingerprint of the plan. Groups settle through one of their members.
+CREATE TABLE IF NOT EXISTS settlement_transfers (
+    id TEXT PRIMARY KEY,
+    fingerprint TEXT NOT NULL,
+    from_user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    to_user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount_pln TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_settlement_transfers_fingerprint ON settlement_transfers(fingerprint);
+
+-- Bill shares one resident fronted for another that a settlement cleared: positive for
+-- the resident whose share was covered, negative for the one who was paid back
+CREATE TABLE IF NOT EXISTS settlement_bill_shares (
+    id TEXT PRIMARY KEY,
+    fingerprint TEXT NOT NULL,
+    bill_id TEXT NOT NULL REFERENCES bills(id) ON DELETE CASCADE,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    amount_pln TEXT NOT NULL,
+    created_at TEXT NOT NULL DEFAULT (datetime('now'))
+);
+
+CREATE INDEX IF NOT EXISTS idx_settlement_bill_shares_bill ON settlement_bill_shares(bill_id);
+
+-- Loans a settlement cleared are paid off by loan payments that name it. The money
+-- moved through the settlement transfers, so only those are posted to the ledger.
+ALTER TABLE loan_payments ADD COLUMN settlement_fingerprint TEXT;
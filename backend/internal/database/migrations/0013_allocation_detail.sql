@@ -0,0 +1,3 @@
+-- Allocations frozen when a bill is posted keep the full breakdown of the time (presence,
+-- personal and shared amounts, units and tariff components) as JSON
+ALTER TABLE allocations ADD COLUMN detail TEXT;
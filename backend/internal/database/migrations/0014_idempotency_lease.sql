@@ -0,0 +1,3 @@
+-- Requests still in progress hold their idempotency key only until locked_until, so a key
+-- left behind by a crash can be used again long before the record expires
+ALTER TABLE idempotency_keys ADD COLUMN locked_until TEXT;
@@ -3,7 +3,6 @@ package database
 import (
 	"context"
 	"database/sql"
-	"embed"
 	"fmt"
 	"log"
 	"os"
@@ -14,16 +13,13 @@ import (
 	_ "github.com/mattn/go-sqlite3"
 )
 
-//go:embed schema.sql
-var schemaFS embed.FS
-
 // SQLiteDB wraps the SQLite database connection
 type SQLiteDB struct {
 	DB *sqlx.DB
 }
 
-// NewSQLiteDB creates a new SQLite database connection
-func NewSQLiteDB(dbPath string) (*SQLiteDB, error) {
+// OpenSQLiteDB opens a SQLite database connection without applying migrations
+func OpenSQLiteDB(dbPath string) (*SQLiteDB, error) {
 	// Ensure parent directory exists
 	dir := filepath.Dir(dbPath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
@@ -52,78 +48,23 @@ func NewSQLiteDB(dbPath string) (*SQLiteDB, error) {
 		return nil, fmt.Errorf("failed to ping SQLite database: %w", err)
 	}
 
-	sqlite := &SQLiteDB{DB: db}
-
-	// Initialize schema
-	if err := sqlite.initSchema(ctx); err != nil {
-		db.Close()
-		return nil, fmt.Errorf("failed to initialize schema: %w", err)
-	}
-
-	log.Println("SQLite database initialized successfully")
-	return sqlite, nil
+	return &SQLiteDB{DB: db}, nil
 }
 
-// initSchema creates all tables if they don't exist
-func (s *SQLiteDB) initSchema(ctx context.Context) error {
-	schemaSQL, err := schemaFS.ReadFile("schema.sql")
-	if err != nil {
-		return fmt.Errorf("failed to read schema.sql: %w", err)
-	}
-
-	_, err = s.DB.ExecContext(ctx, string(schemaSQL))
-	if err != nil {
-		return fmt.Errorf("failed to execute schema: %w", err)
-	}
-
-	// Run migrations for existing databases
-	if err := s.runMigrations(ctx); err != nil {
-		return fmt.Errorf("failed to run migrations: %w", err)
-	}
-
-	return nil
-}
-
-// runMigrations applies incremental migrations for existing databases
-func (s *SQLiteDB) runMigrations(ctx context.Context) error {
-	// Migration: Add reminder_rate_limit_per_hour column to app_settings if not exists
-	var count int
-	err := s.DB.GetContext(ctx, &count, `
-		SELECT COUNT(*) FROM pragma_table_info('app_settings')
-		WHERE name = 'reminder_rate_limit_per_hour'
-	`)
+// NewSQLiteDB opens the database and applies pending migrations
+func NewSQLiteDB(dbPath string) (*SQLiteDB, error) {
+	sqlite, err := OpenSQLiteDB(dbPath)
 	if err != nil {
-		return fmt.Errorf("failed to check app_settings column: %w", err)
-	}
-	if count == 0 {
-		_, err = s.DB.ExecContext(ctx, `
-			ALTER TABLE app_settings ADD COLUMN reminder_rate_limit_per_hour INTEGER NOT NULL DEFAULT 1
-		`)
-		if err != nil {
-			return fmt.Errorf("failed to add reminder_rate_limit_per_hour column: %w", err)
-		}
-		log.Println("Migration: Added reminder_rate_limit_per_hour column to app_settings")
+		return nil, err
 	}
 
-	// Migration: Add manual_assignee_id column to chores if not exists
-	err = s.DB.GetContext(ctx, &count, `
-		SELECT COUNT(*) FROM pragma_table_info('chores')
-		WHERE name = 'manual_assignee_id'
-	`)
-	if err != nil {
-		return fmt.Errorf("failed to check chores column: %w", err)
-	}
-	if count == 0 {
-		_, err = s.DB.ExecContext(ctx, `
-			ALTER TABLE chores ADD COLUMN manual_assignee_id TEXT REFERENCES users(id) ON DELETE SET NULL
-		`)
-		if err != nil {
-			return fmt.Errorf("failed to add manual_assignee_id column: %w", err)
-		}
-		log.Println("Migration: Added manual_assignee_id column to chores")
+	if _, err := sqlite.Migrate(context.Background()); err != nil {
+		sqlite.Close()
+		return nil, fmt.Errorf("failed to migrate database: %w", err)
 	}
 
-	return nil
+	log.Println("SQLite database initialized successfully")
+	return sqlite, nil
 }
 
 // Close closes the database connection
@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"errors"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/sainaif/holy-home/internal/middleware"
 	"github.com/sainaif/holy-home/internal/services"
+	"github.com/sainaif/holy-home/internal/utils"
 )
 
 type ApprovalHandler struct {
@@ -61,14 +64,20 @@ func (h *ApprovalHandler) ApproveRequest(c *fiber.Ctx) error {
 	}
 	c.BodyParser(&req)
 
-	err = h.approvalService.ApproveRequest(c.Context(), requestID, reviewerID, req.Notes)
+	request, err := h.approvalService.ApproveRequest(c.Context(), requestID, reviewerID, req.Notes)
+	if errors.Is(err, services.ErrApprovalExecutionFailed) {
+		return c.Status(fiber.StatusUnprocessableEntity).JSON(fiber.Map{
+			"error":   err.Error(),
+			"request": request,
+		})
+	}
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
-	return c.JSON(fiber.Map{"success": true})
+	return c.JSON(fiber.Map{"success": true, "request": request})
 }
 
 // RejectRequest rejects an approval request (ADMIN only)
@@ -101,3 +110,91 @@ func (h *ApprovalHandler) RejectRequest(c *fiber.Ctx) error {
 
 	return c.JSON(fiber.Map{"success": true})
 }
+
+// GetPolicies retrieves all approval policies
+func (h *ApprovalHandler) GetPolicies(c *fiber.Ctx) error {
+	policies, err := h.approvalService.ListPolicies(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve policies",
+		})
+	}
+	return c.JSON(policies)
+}
+
+// CreatePolicy creates a custom approval policy
+func (h *ApprovalHandler) CreatePolicy(c *fiber.Ctx) error {
+	var req struct {
+		Action       string       `json:"action"`
+		Role         *string      `json:"role"`
+		MinAmountPLN *utils.Money `json:"minAmountPLN"`
+		Enabled      *bool        `json:"enabled"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	enabled := true
+	if req.Enabled != nil {
+		enabled = *req.Enabled
+	}
+
+	policy, err := h.approvalService.CreatePolicy(c.Context(), req.Action, req.Role, req.MinAmountPLN, enabled)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(policy)
+}
+
+// UpdatePolicy updates an approval policy
+func (h *ApprovalHandler) UpdatePolicy(c *fiber.Ctx) error {
+	policyID := c.Params("id")
+	if policyID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid policy ID",
+		})
+	}
+
+	var req struct {
+		Role         *string      `json:"role"`
+		MinAmountPLN *utils.Money `json:"minAmountPLN"`
+		Enabled      bool         `json:"enabled"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	policy, err := h.approvalService.UpdatePolicy(c.Context(), policyID, req.Role, req.MinAmountPLN, req.Enabled)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(policy)
+}
+
+// DeletePolicy deletes a custom approval policy
+func (h *ApprovalHandler) DeletePolicy(c *fiber.Ctx) error {
+	policyID := c.Params("id")
+	if policyID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid policy ID",
+		})
+	}
+
+	if err := h.approvalService.DeletePolicy(c.Context(), policyID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{"success": true})
+}
@@ -1,22 +1,52 @@
 package handlers
 
 import (
+	"errors"
+	"strings"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/sainaif/holy-home/internal/services"
+	"github.com/sainaif/holy-home/internal/utils"
 )
 
 type BackupHandler struct {
-	backupService *services.BackupService
+	backupService        *services.BackupService
+	backupArchiveService *services.BackupArchiveService
 }
 
-func NewBackupHandler(backupService *services.BackupService) *BackupHandler {
+func NewBackupHandler(backupService *services.BackupService, backupArchiveService *services.BackupArchiveService) *BackupHandler {
 	return &BackupHandler{
-		backupService: backupService,
+		backupService:        backupService,
+		backupArchiveService: backupArchiveService,
 	}
 }
 
-// ExportBackup exports all data as JSON (ADMIN only)
+// BackupPassphraseHeader carries the passphrase for encrypted exports and imports.
+// A header keeps it out of URLs and access logs.
+const BackupPassphraseHeader = "X-Backup-Passphrase"
+
+// ExportBackup exports all data as JSON (ADMIN only).
+// With a passphrase header the export is encrypted.
 func (h *BackupHandler) ExportBackup(c *fiber.Ctx) error {
+	passphrase := c.Get(BackupPassphraseHeader)
+	if passphrase != "" {
+		data, err := h.backupService.ExportEncryptedJSON(c.Context(), passphrase)
+		if errors.Is(err, utils.ErrPassphraseTooShort) {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+
+		c.Set("Content-Type", "application/octet-stream")
+		c.Set("Content-Disposition", "attachment; filename=holy-home-backup.json.enc")
+		return c.Send(data)
+	}
+
 	jsonData, err := h.backupService.ExportJSON(c.Context())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
@@ -31,7 +61,9 @@ func (h *BackupHandler) ExportBackup(c *fiber.Ctx) error {
 	return c.Send(jsonData)
 }
 
-// ImportBackup imports all data from JSON (ADMIN only, DANGEROUS)
+// ImportBackup imports all data from JSON (ADMIN only, DANGEROUS).
+// The mode query parameter selects replace (default), merge or dry-run.
+// Encrypted backups need the passphrase header.
 func (h *BackupHandler) ImportBackup(c *fiber.Ctx) error {
 	// Read the uploaded JSON file
 	jsonData := c.Body()
@@ -42,8 +74,26 @@ func (h *BackupHandler) ImportBackup(c *fiber.Ctx) error {
 		})
 	}
 
+	passphrase := c.Get(BackupPassphraseHeader)
+	mode := services.ImportMode(c.Query("mode", string(services.ImportModeReplace)))
+	switch mode {
+	case services.ImportModeReplace, services.ImportModeMerge:
+	case services.ImportModeDryRun:
+		report, err := h.backupService.PreviewImport(c.Context(), jsonData, passphrase)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": err.Error(),
+			})
+		}
+		return c.JSON(report)
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid mode, expected replace, merge or dry-run",
+		})
+	}
+
 	// Import the backup
-	result, err := h.backupService.ImportJSON(c.Context(), jsonData)
+	result, err := h.backupService.ImportJSON(c.Context(), jsonData, mode, passphrase)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
@@ -52,6 +102,7 @@ func (h *BackupHandler) ImportBackup(c *fiber.Ctx) error {
 
 	response := fiber.Map{
 		"message": "Backup imported successfully",
+		"mode":    mode,
 	}
 
 	// Include password reset info if any users had their passwords reset
@@ -64,3 +115,62 @@ func (h *BackupHandler) ImportBackup(c *fiber.Ctx) error {
 
 	return c.JSON(response)
 }
+
+// ListArchives returns the stored backup archives, newest first
+func (h *BackupHandler) ListArchives(c *fiber.Ctx) error {
+	archives, err := h.backupArchiveService.ListArchives()
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(archives)
+}
+
+// CreateArchive writes a backup archive now instead of waiting for the schedule
+func (h *BackupHandler) CreateArchive(c *fiber.Ctx) error {
+	archive, err := h.backupArchiveService.CreateArchive(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(archive)
+}
+
+// DownloadArchive sends a stored backup archive
+func (h *BackupHandler) DownloadArchive(c *fiber.Ctx) error {
+	path, err := h.backupArchiveService.ArchivePath(c.Params("name"))
+	if err != nil {
+		return archiveError(c, err)
+	}
+
+	if strings.HasSuffix(path, ".enc") {
+		c.Set("Content-Type", "application/octet-stream")
+	} else {
+		c.Set("Content-Type", "application/gzip")
+	}
+	return c.Download(path, c.Params("name"))
+}
+
+// DeleteArchive removes a stored backup archive
+func (h *BackupHandler) DeleteArchive(c *fiber.Ctx) error {
+	if err := h.backupArchiveService.DeleteArchive(c.Params("name")); err != nil {
+		return archiveError(c, err)
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+func archiveError(c *fiber.Ctx, err error) error {
+	if errors.Is(err, services.ErrBackupArchiveNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		"error": err.Error(),
+	})
+}
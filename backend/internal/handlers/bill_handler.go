@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -167,6 +168,9 @@ func (h *BillHandler) PostBill(c *fiber.Ctx) error {
 		h.auditService.LogAction(c.Context(), userID, userEmail, userEmail, "post_bill", "bill", &billID,
 			map[string]interface{}{"bill_type": bill.Type, "status": "draft"},
 			c.IP(), c.Get("User-Agent"), "failure")
+		if errors.Is(err, services.ErrSuspiciousReadings) {
+			return h.suspiciousReadingsConflict(c, billID, err)
+		}
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -233,6 +237,9 @@ func (h *BillHandler) CloseBill(c *fiber.Ctx) error {
 		h.auditService.LogAction(c.Context(), userID, userEmail, userEmail, "close_bill", "bill", &billID,
 			map[string]interface{}{"bill_type": bill.Type, "status": "posted"},
 			c.IP(), c.Get("User-Agent"), "failure")
+		if errors.Is(err, services.ErrSuspiciousReadings) {
+			return h.suspiciousReadingsConflict(c, billID, err)
+		}
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
@@ -251,6 +258,33 @@ func (h *BillHandler) CloseBill(c *fiber.Ctx) error {
 	})
 }
 
+// GetSuspiciousReadings lists the readings of a bill that need confirmation before posting
+func (h *BillHandler) GetSuspiciousReadings(c *fiber.Ctx) error {
+	readings, err := h.billService.GetSuspiciousReadings(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(readings)
+}
+
+// suspiciousReadingsConflict responds with the readings blocking a bill status change
+func (h *BillHandler) suspiciousReadingsConflict(c *fiber.Ctx, billID string, err error) error {
+	readings, listErr := h.billService.GetSuspiciousReadings(c.Context(), billID)
+	if listErr != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": listErr.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+		"error":              err.Error(),
+		"suspiciousReadings": readings,
+	})
+}
+
 // ReopenBill reopens a bill to a previous status (ADMIN only)
 func (h *BillHandler) ReopenBill(c *fiber.Ctx) error {
 	billID := c.Params("id")
@@ -479,6 +513,43 @@ func (h *BillHandler) MarkConsumptionInvalid(c *fiber.Ctx) error {
 	return c.JSON(fiber.Map{"message": "Consumption marked as invalid"})
 }
 
+// ConfirmConsumption confirms a suspicious reading (user can confirm their own, admin any)
+func (h *BillHandler) ConfirmConsumption(c *fiber.Ctx) error {
+	consumptionID := c.Params("id")
+	if consumptionID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid consumption ID",
+		})
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	userEmail, err := middleware.GetUserEmail(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	role, _ := middleware.GetUserRole(c)
+	consumption, err := h.consumptionService.ConfirmConsumption(c.Context(), consumptionID, userID, role == "ADMIN")
+	if err != nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.auditService.LogAction(c.Context(), userID, userEmail, userEmail, "confirm_reading", "consumption", &consumptionID,
+		map[string]interface{}{"bill_id": consumption.BillID, "units": consumption.Units, "anomaly_reason": consumption.AnomalyReason},
+		c.IP(), c.Get("User-Agent"), "success")
+
+	return c.JSON(consumption)
+}
+
 // GetBillAllocation returns allocation breakdown for a bill
 func (h *BillHandler) GetBillAllocation(c *fiber.Ctx) error {
 	billID := c.Params("id")
@@ -0,0 +1,119 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sainaif/holy-home/internal/middleware"
+	"github.com/sainaif/holy-home/internal/services"
+)
+
+type CalendarHandler struct {
+	calendarService *services.CalendarService
+}
+
+func NewCalendarHandler(calendarService *services.CalendarService) *CalendarHandler {
+	return &CalendarHandler{
+		calendarService: calendarService,
+	}
+}
+
+// GetFeed serves the iCalendar feed for the token in the query string (public, token-authenticated)
+func (h *CalendarHandler) GetFeed(c *fiber.Ctx) error {
+	feed, err := h.calendarService.Feed(c.Context(), c.Query("token"), time.Now())
+	if errors.Is(err, services.ErrCalendarFeedNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": "Calendar feed not found",
+		})
+	}
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to generate calendar feed",
+		})
+	}
+
+	c.Set("Content-Type", "text/calendar; charset=utf-8")
+	c.Set("Content-Disposition", `inline; filename="holy-home.ics"`)
+	c.Set("Cache-Control", "private, no-cache")
+	return c.Send(feed)
+}
+
+// GetFeedTokens lists the current user's calendar feed tokens
+func (h *CalendarHandler) GetFeedTokens(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	tokens, err := h.calendarService.ListFeedTokens(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve calendar feeds",
+		})
+	}
+
+	return c.JSON(tokens)
+}
+
+// CreateFeedToken creates a calendar feed token. The feed URL is only returned here.
+func (h *CalendarHandler) CreateFeedToken(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req struct {
+		Name string `json:"name"`
+	}
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	feedToken, token, err := h.calendarService.CreateFeedToken(c.Context(), userID, req.Name)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"feed": feedToken,
+		"url":  h.calendarService.FeedURL(token),
+	})
+}
+
+// DeleteFeedToken revokes a calendar feed token
+func (h *CalendarHandler) DeleteFeedToken(c *fiber.Ctx) error {
+	tokenID := c.Params("id")
+	if tokenID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid calendar feed ID",
+		})
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	if err := h.calendarService.RevokeFeedToken(c.Context(), userID, tokenID); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Calendar feed revoked successfully",
+	})
+}
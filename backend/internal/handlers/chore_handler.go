@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"io"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
@@ -9,20 +10,16 @@ import (
 )
 
 type ChoreHandler struct {
-	choreService    *services.ChoreService
-	approvalService *services.ApprovalService
-	roleService     *services.RoleService
-	auditService    *services.AuditService
-	eventService    *services.EventService
+	choreService *services.ChoreService
+	auditService *services.AuditService
+	eventService *services.EventService
 }
 
-func NewChoreHandler(choreService *services.ChoreService, approvalService *services.ApprovalService, roleService *services.RoleService, auditService *services.AuditService, eventService *services.EventService) *ChoreHandler {
+func NewChoreHandler(choreService *services.ChoreService, auditService *services.AuditService, eventService *services.EventService) *ChoreHandler {
 	return &ChoreHandler{
-		choreService:    choreService,
-		approvalService: approvalService,
-		roleService:     roleService,
-		auditService:    auditService,
-		eventService:    eventService,
+		choreService: choreService,
+		auditService: auditService,
+		eventService: eventService,
 	}
 }
 
@@ -77,14 +74,53 @@ func (h *ChoreHandler) GetChoresWithAssignments(c *fiber.Ctx) error {
 	return c.JSON(chores)
 }
 
+// GetChoreSettings retrieves the household-wide chore settings
+func (h *ChoreHandler) GetChoreSettings(c *fiber.Ctx) error {
+	settings, err := h.choreService.GetChoreSettings(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(settings)
+}
+
+// UpdateChoreSettings changes how overdue assignments are handled (ADMIN only)
+func (h *ChoreHandler) UpdateChoreSettings(c *fiber.Ctx) error {
+	var req services.UpdateChoreSettingsRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	settings, err := h.choreService.UpdateChoreSettings(c.Context(), req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(settings)
+}
+
 // AssignChore assigns a chore to a user (ADMIN only)
 func (h *ChoreHandler) AssignChore(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
 	var req services.AssignChoreRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid request body",
 		})
 	}
+	req.AssignedByUserID = &userID
 
 	assignment, err := h.choreService.AssignChore(c.Context(), req)
 	if err != nil {
@@ -170,12 +206,20 @@ func (h *ChoreHandler) UpdateChoreAssignment(c *fiber.Ctx) error {
 		})
 	}
 
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
 	var req services.UpdateChoreAssignmentRequest
 	if err := c.BodyParser(&req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid request body",
 		})
 	}
+	req.UserID = userID
 
 	if err := h.choreService.UpdateChoreAssignment(c.Context(), assignmentID, req); err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -276,20 +320,40 @@ func (h *ChoreHandler) AutoAssignChore(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(assignment)
 }
 
-// GetUserLeaderboard retrieves user leaderboard based on points
-func (h *ChoreHandler) GetUserLeaderboard(c *fiber.Ctx) error {
-	leaderboard, err := h.choreService.GetUserLeaderboard(c.Context())
+// FairAssignChore assigns a chore to the user with the lowest difficulty load (ADMIN only)
+func (h *ChoreHandler) FairAssignChore(c *fiber.Ctx) error {
+	choreID := c.Params("id")
+	if choreID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid chore ID",
+		})
+	}
+
+	var req struct {
+		DueDate time.Time `json:"dueDate"`
+	}
+
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	assignment, explanation, err := h.choreService.FairAssignChore(c.Context(), choreID, req.DueDate)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": err.Error(),
 		})
 	}
 
-	return c.JSON(leaderboard)
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"assignment":  assignment,
+		"explanation": explanation,
+	})
 }
 
-// DeleteChore deletes a chore (requires approval for non-admins)
-func (h *ChoreHandler) DeleteChore(c *fiber.Ctx) error {
+// PreviewFairAssignment shows whom fair assignment would pick on the dueDate query parameter, and why
+func (h *ChoreHandler) PreviewFairAssignment(c *fiber.Ctx) error {
 	choreID := c.Params("id")
 	if choreID == "" {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
@@ -297,6 +361,50 @@ func (h *ChoreHandler) DeleteChore(c *fiber.Ctx) error {
 		})
 	}
 
+	dueDate := time.Now()
+	if d := c.Query("dueDate"); d != "" {
+		parsed, err := time.Parse(time.RFC3339, d)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid dueDate, expected RFC 3339",
+			})
+		}
+		dueDate = parsed
+	}
+
+	explanation, err := h.choreService.ExplainFairAssignment(c.Context(), choreID, dueDate)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(explanation)
+}
+
+// ExplainChoreAssignment explains why the assignee of a fair chore assignment was picked
+func (h *ChoreHandler) ExplainChoreAssignment(c *fiber.Ctx) error {
+	assignmentID := c.Params("id")
+	if assignmentID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid assignment ID",
+		})
+	}
+
+	explanation, err := h.choreService.ExplainChoreAssignment(c.Context(), assignmentID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(explanation)
+}
+
+// SubmitCompletion reports an assignment as done, with an optional photo in the "photo"
+// multipart field. Only the assignee or an admin can submit.
+func (h *ChoreHandler) SubmitCompletion(c *fiber.Ctx) error {
+	assignmentID := c.Params("id")
 	userID, err := middleware.GetUserID(c)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
@@ -304,65 +412,160 @@ func (h *ChoreHandler) DeleteChore(c *fiber.Ctx) error {
 		})
 	}
 
-	userRole, err := middleware.GetUserRole(c)
+	assignment, err := h.choreService.GetChoreAssignment(c.Context(), assignmentID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+	allowed, err := canManageAvailability(c, assignment.AssigneeUserID)
 	if err != nil {
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 			"error": "Unauthorized",
 		})
 	}
+	if !allowed {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "Only the assignee can complete this chore",
+		})
+	}
 
-	// Check if user has permission
-	hasPermission, err := h.roleService.HasPermission(c.Context(), userRole, "chores.delete")
+	var photo []byte
+	if header, err := c.FormFile("photo"); err == nil {
+		if header.Size > int64(h.choreService.CompletionPhotoMaxBytes()) {
+			return c.Status(fiber.StatusRequestEntityTooLarge).JSON(fiber.Map{
+				"error": "Photo is too large",
+			})
+		}
+		file, err := header.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Failed to read uploaded photo",
+			})
+		}
+		defer file.Close()
+		if photo, err = io.ReadAll(file); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Failed to read uploaded photo",
+			})
+		}
+	}
+
+	assignment, err = h.choreService.SubmitCompletion(c.Context(), assignmentID, userID, photo)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to check permissions",
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
 		})
 	}
 
-	if !hasPermission {
-		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
-			"error": "You don't have permission to delete chores",
+	return c.JSON(assignment)
+}
+
+// ConfirmCompletion confirms a completion waiting for another resident
+func (h *ChoreHandler) ConfirmCompletion(c *fiber.Ctx) error {
+	return h.reviewCompletion(c, true)
+}
+
+// DisputeCompletion disputes a completion waiting for another resident
+func (h *ChoreHandler) DisputeCompletion(c *fiber.Ctx) error {
+	return h.reviewCompletion(c, false)
+}
+
+func (h *ChoreHandler) reviewCompletion(c *fiber.Ctx, confirm bool) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
 		})
 	}
 
-	// For ADMIN, delete directly. For others, create approval request
-	if userRole == "ADMIN" {
-		if err := h.choreService.DeleteChore(c.Context(), choreID); err != nil {
+	var req services.ReviewCompletionRequest
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(&req); err != nil {
 			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-				"error": err.Error(),
+				"error": "Invalid request body",
 			})
 		}
+	}
+
+	review := h.choreService.DisputeCompletion
+	if confirm {
+		review = h.choreService.ConfirmCompletion
+	}
+	assignment, err := review(c.Context(), c.Params("id"), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
 
-		// Log action
-		h.auditService.LogAction(c.Context(), userID, "", "", "chore.delete", "chore", &choreID, nil, c.IP(), c.Get("User-Agent"), "success")
+	return c.JSON(assignment)
+}
 
-		return c.JSON(fiber.Map{"success": true})
+// GetCompletions retrieves the completions submitted for an assignment
+func (h *ChoreHandler) GetCompletions(c *fiber.Ctx) error {
+	completions, err := h.choreService.GetCompletions(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
 	}
 
-	// Create approval request for non-admin
-	_, err = h.approvalService.CreateApprovalRequest(
-		c.Context(),
-		userID,
-		"", // Will be filled from user object
-		"", // Will be filled from user object
-		"chore.delete",
-		"chore",
-		&choreID,
-		map[string]interface{}{
-			"choreId": choreID,
-		},
-	)
+	return c.JSON(completions)
+}
+
+// GetCompletionPhoto serves the photo of a completion
+func (h *ChoreHandler) GetCompletionPhoto(c *fiber.Ctx) error {
+	photo, contentType, err := h.choreService.GetCompletionPhoto(c.Context(), c.Params("id"), c.Params("completionId"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	c.Set(fiber.HeaderContentType, contentType)
+	c.Set(fiber.HeaderCacheControl, "private, max-age=86400")
+	return c.Send(photo)
+}
+
+// GetUserLeaderboard retrieves user leaderboard based on points
+func (h *ChoreHandler) GetUserLeaderboard(c *fiber.Ctx) error {
+	leaderboard, err := h.choreService.GetUserLeaderboard(c.Context())
 	if err != nil {
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"error": "Failed to create approval request",
+			"error": err.Error(),
 		})
 	}
 
-	return c.JSON(fiber.Map{
-		"success":          true,
-		"requiresApproval": true,
-		"message":          "Delete request submitted for admin approval",
-	})
+	return c.JSON(leaderboard)
+}
+
+// DeleteChore deletes a chore (non-reviewers go through approval, see the route's approval policy)
+func (h *ChoreHandler) DeleteChore(c *fiber.Ctx) error {
+	choreID := c.Params("id")
+	if choreID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid chore ID",
+		})
+	}
+
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	if err := h.choreService.DeleteChore(c.Context(), choreID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	// Log action
+	h.auditService.LogAction(c.Context(), userID, "", "", "chore.delete", "chore", &choreID, nil, c.IP(), c.Get("User-Agent"), "success")
+
+	return c.JSON(fiber.Map{"success": true})
 }
 
 // UpdateChore updates an existing chore
@@ -664,3 +867,211 @@ func (h *ChoreHandler) GetMySwapRequests(c *fiber.Ctx) error {
 
 	return c.JSON(requests)
 }
+
+// ============================================
+// AVAILABILITY HANDLERS (Absences and Chore Preferences)
+// ============================================
+
+// canManageAvailability reports whether the current user may change the availability of
+// userID: users manage their own, admins anyone's
+func canManageAvailability(c *fiber.Ctx, userID string) (bool, error) {
+	currentUserID, err := middleware.GetUserID(c)
+	if err != nil {
+		return false, err
+	}
+	currentUserRole, err := middleware.GetUserRole(c)
+	if err != nil {
+		return false, err
+	}
+	return currentUserID == userID || currentUserRole == "ADMIN", nil
+}
+
+// GetAbsences lists absences, optionally filtered by the userId query parameter
+func (h *ChoreHandler) GetAbsences(c *fiber.Ctx) error {
+	var userID *string
+	if u := c.Query("userId"); u != "" {
+		userID = &u
+	}
+
+	absences, err := h.choreService.GetAbsences(c.Context(), userID)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to retrieve absences",
+		})
+	}
+
+	return c.JSON(absences)
+}
+
+// CreateAbsence records an absence of the current user, or of any user for admins
+func (h *ChoreHandler) CreateAbsence(c *fiber.Ctx) error {
+	var req services.CreateAbsenceRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	if req.UserID == "" {
+		userID, err := middleware.GetUserID(c)
+		if err != nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+				"error": "Unauthorized",
+			})
+		}
+		req.UserID = userID
+	}
+
+	allowed, err := canManageAvailability(c, req.UserID)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	if !allowed {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only record your own absences",
+		})
+	}
+
+	absence, err := h.choreService.CreateAbsence(c.Context(), req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(absence)
+}
+
+// DeleteAbsence deletes an absence of the current user, or of any user for admins
+func (h *ChoreHandler) DeleteAbsence(c *fiber.Ctx) error {
+	absenceID := c.Params("id")
+	if absenceID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid absence ID",
+		})
+	}
+
+	absence, err := h.choreService.GetAbsence(c.Context(), absenceID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	allowed, err := canManageAvailability(c, absence.UserID)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	if !allowed {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only delete your own absences",
+		})
+	}
+
+	if err := h.choreService.DeleteAbsence(c.Context(), absenceID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Absence deleted successfully",
+	})
+}
+
+// GetChorePreferences lists the assignment preferences recorded for a chore
+func (h *ChoreHandler) GetChorePreferences(c *fiber.Ctx) error {
+	choreID := c.Params("id")
+	if choreID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid chore ID",
+		})
+	}
+
+	preferences, err := h.choreService.GetChorePreferences(c.Context(), choreID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(preferences)
+}
+
+// SetChorePreference marks a user as preferred for, or excluded from, a chore
+func (h *ChoreHandler) SetChorePreference(c *fiber.Ctx) error {
+	choreID := c.Params("id")
+	userID := c.Params("userId")
+	if choreID == "" || userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid chore or user ID",
+		})
+	}
+
+	var req struct {
+		Preference string `json:"preference"` // preferred, excluded
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	allowed, err := canManageAvailability(c, userID)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	if !allowed {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only change your own chore preferences",
+		})
+	}
+
+	preference, err := h.choreService.SetChorePreference(c.Context(), choreID, userID, req.Preference)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(preference)
+}
+
+// RemoveChorePreference removes a user's preference for a chore
+func (h *ChoreHandler) RemoveChorePreference(c *fiber.Ctx) error {
+	choreID := c.Params("id")
+	userID := c.Params("userId")
+	if choreID == "" || userID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid chore or user ID",
+		})
+	}
+
+	allowed, err := canManageAvailability(c, userID)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	if !allowed {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only change your own chore preferences",
+		})
+	}
+
+	if err := h.choreService.RemoveChorePreference(c.Context(), choreID, userID); err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Chore preference removed successfully",
+	})
+}
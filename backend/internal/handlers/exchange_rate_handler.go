@@ -0,0 +1,116 @@
+package handlers
+
+import (
+	"io"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sainaif/holy-home/internal/services"
+)
+
+type ExchangeRateHandler struct {
+	currencyService *services.CurrencyService
+}
+
+func NewExchangeRateHandler(currencyService *services.CurrencyService) *ExchangeRateHandler {
+	return &ExchangeRateHandler{currencyService: currencyService}
+}
+
+// GetRates returns the base currency and all stored exchange rates
+func (h *ExchangeRateHandler) GetRates(c *fiber.Ctx) error {
+	base, err := h.currencyService.BaseCurrency(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	rates, err := h.currencyService.ListRates(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"baseCurrency": base,
+		"rates":        rates,
+	})
+}
+
+// SetRate stores a manually entered exchange rate
+func (h *ExchangeRateHandler) SetRate(c *fiber.Ctx) error {
+	var req struct {
+		Currency string `json:"currency"`
+		Date     string `json:"date"` // YYYY-MM-DD
+		Rate     string `json:"rate"`
+	}
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	date, err := time.Parse("2006-01-02", req.Date)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid date, expected YYYY-MM-DD",
+		})
+	}
+
+	rate, err := h.currencyService.SetRate(c.Context(), req.Currency, date, req.Rate)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(rate)
+}
+
+// ImportRates imports exchange rates from a CSV upload (multipart "file" field or raw body)
+func (h *ExchangeRateHandler) ImportRates(c *fiber.Ctx) error {
+	data := c.Body()
+	if header, err := c.FormFile("file"); err == nil {
+		file, err := header.Open()
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Failed to read uploaded file",
+			})
+		}
+		defer file.Close()
+		if data, err = io.ReadAll(file); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Failed to read uploaded file",
+			})
+		}
+	}
+
+	if len(data) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Empty CSV file",
+		})
+	}
+
+	imported, err := h.currencyService.ImportRatesCSV(c.Context(), data)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"imported": imported,
+	})
+}
+
+// DeleteRate removes a stored exchange rate
+func (h *ExchangeRateHandler) DeleteRate(c *fiber.Ctx) error {
+	if err := h.currencyService.DeleteRate(c.Context(), c.Params("id")); err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
@@ -1,19 +1,22 @@
 package handlers
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/gofiber/fiber/v2"
 
+	"github.com/sainaif/holy-home/internal/middleware"
 	"github.com/sainaif/holy-home/internal/services"
 )
 
 type ExportHandler struct {
-	exportService *services.ExportService
+	exportService    *services.ExportService
+	statementService *services.StatementService
 }
 
-func NewExportHandler(exportService *services.ExportService) *ExportHandler {
-	return &ExportHandler{exportService: exportService}
+func NewExportHandler(exportService *services.ExportService, statementService *services.StatementService) *ExportHandler {
+	return &ExportHandler{exportService: exportService, statementService: statementService}
 }
 
 // ExportBills exports bills to CSV
@@ -122,3 +125,91 @@ func (h *ExportHandler) ExportConsumptions(c *fiber.Ctx) error {
 	c.Set("Content-Disposition", "attachment; filename=consumptions.csv")
 	return c.Send(csv)
 }
+
+// ExportStatement exports a user's account statement for a month (or from/to dates) as HTML or PDF
+func (h *ExportHandler) ExportStatement(c *fiber.Ctx) error {
+	currentUserID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	currentUserRole, err := middleware.GetUserRole(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	// Allow users to export their own statement or admins to export anyone's
+	userID := c.Query("user_id", currentUserID)
+	if userID != currentUserID && currentUserRole != "ADMIN" {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{
+			"error": "You can only export your own statement",
+		})
+	}
+
+	format := c.Query("format", "html")
+	if format != "html" && format != "pdf" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "format must be html or pdf",
+		})
+	}
+
+	// Default to the current month
+	now := time.Now()
+	from := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.UTC)
+	if m := c.Query("month"); m != "" {
+		parsed, err := time.Parse("2006-01", m)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid month, expected YYYY-MM",
+			})
+		}
+		from = parsed
+	}
+	to := from.AddDate(0, 1, 0).Add(-time.Second)
+
+	if f := c.Query("from"); f != "" {
+		parsed, err := time.Parse("2006-01-02", f)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid from date",
+			})
+		}
+		from = parsed
+	}
+	if t := c.Query("to"); t != "" {
+		parsed, err := time.Parse("2006-01-02", t)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid to date",
+			})
+		}
+		to = parsed.Add(24*time.Hour - time.Second)
+	}
+
+	statement, err := h.statementService.GetUserStatement(c.Context(), userID, from, to)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	filename := fmt.Sprintf("statement-%s-%s", from.Format("2006-01-02"), to.Format("2006-01-02"))
+	if format == "pdf" {
+		c.Set("Content-Type", "application/pdf")
+		c.Set("Content-Disposition", "attachment; filename="+filename+".pdf")
+		return c.Send(services.RenderStatementPDF(statement))
+	}
+
+	html, err := services.RenderStatementHTML(statement)
+	if err != nil {
+		return fiber.NewError(fiber.StatusInternalServerError, err.Error())
+	}
+
+	c.Set("Content-Type", "text/html; charset=utf-8")
+	c.Set("Content-Disposition", "inline; filename="+filename+".html")
+	return c.Send(html)
+}
@@ -135,6 +135,25 @@ func (h *GroupHandler) UpdateGroup(c *fiber.Ctx) error {
 	})
 }
 
+// GetWeightHistory lists the weight changes of a group
+func (h *GroupHandler) GetWeightHistory(c *fiber.Ctx) error {
+	groupID := c.Params("id")
+	if groupID == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid group ID",
+		})
+	}
+
+	changes, err := h.groupService.GetWeightHistory(c.Context(), groupID)
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(changes)
+}
+
 // DeleteGroup deletes a group (ADMIN only)
 func (h *GroupHandler) DeleteGroup(c *fiber.Ctx) error {
 	userID, err := middleware.GetUserID(c)
@@ -0,0 +1,102 @@
+package handlers
+
+import (
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sainaif/holy-home/internal/middleware"
+	"github.com/sainaif/holy-home/internal/services"
+)
+
+type LedgerHandler struct {
+	ledgerService *services.LedgerService
+}
+
+func NewLedgerHandler(ledgerService *services.LedgerService) *LedgerHandler {
+	return &LedgerHandler{ledgerService: ledgerService}
+}
+
+// GetBalances returns the balance of every ledger account
+func (h *LedgerHandler) GetBalances(c *fiber.Ctx) error {
+	balances, err := h.ledgerService.GetBalances(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(balances)
+}
+
+// GetStatement returns an account statement with running balances.
+// Defaults to the current user's account; filters: from, to (YYYY-MM-DD or RFC3339)
+// and source (comma-separated source types).
+func (h *LedgerHandler) GetStatement(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	account := services.LedgerAccount{
+		Type: c.Query("accountType", "user"),
+		ID:   c.Query("accountId"),
+	}
+	if account.ID == "" {
+		if account.Type != "user" {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "accountId is required",
+			})
+		}
+		account.ID = userID
+	}
+
+	var filter services.LedgerStatementFilter
+	if filter.From, err = parseStatementDate(c.Query("from"), false); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid from date",
+		})
+	}
+	if filter.To, err = parseStatementDate(c.Query("to"), true); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid to date",
+		})
+	}
+	if source := c.Query("source"); source != "" {
+		for _, sourceType := range strings.Split(source, ",") {
+			if sourceType = strings.TrimSpace(sourceType); sourceType != "" {
+				filter.SourceTypes = append(filter.SourceTypes, sourceType)
+			}
+		}
+	}
+
+	statement, err := h.ledgerService.GetStatement(c.Context(), account, filter)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(statement)
+}
+
+// parseStatementDate accepts a date or an RFC3339 timestamp; a bare date used as the
+// end of a range covers the whole day
+func parseStatementDate(value string, endOfDay bool) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	if parsed, err := time.Parse(time.RFC3339, value); err == nil {
+		return &parsed, nil
+	}
+	parsed, err := time.Parse("2006-01-02", value)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		parsed = parsed.Add(24*time.Hour - time.Second)
+	}
+	return &parsed, nil
+}
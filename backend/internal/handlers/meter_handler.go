@@ -0,0 +1,205 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sainaif/holy-home/internal/middleware"
+	"github.com/sainaif/holy-home/internal/services"
+)
+
+type MeterHandler struct {
+	meterService *services.MeterService
+	auditService *services.AuditService
+}
+
+func NewMeterHandler(meterService *services.MeterService, auditService *services.AuditService) *MeterHandler {
+	return &MeterHandler{
+		meterService: meterService,
+		auditService: auditService,
+	}
+}
+
+// CreateMeter registers a new meter
+func (h *MeterHandler) CreateMeter(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	userEmail, err := middleware.GetUserEmail(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req services.CreateMeterRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	meter, err := h.meterService.CreateMeter(c.Context(), req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.auditService.LogAction(c.Context(), userID, userEmail, userEmail, "create_meter", "meter", &meter.ID,
+		map[string]interface{}{"subject_type": meter.SubjectType, "subject_id": meter.SubjectID, "utility_type": meter.UtilityType},
+		c.IP(), c.Get("User-Agent"), "success")
+
+	return c.Status(fiber.StatusCreated).JSON(meter)
+}
+
+// GetMeters retrieves all meters
+func (h *MeterHandler) GetMeters(c *fiber.Ctx) error {
+	meters, err := h.meterService.GetMeters(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(meters)
+}
+
+// GetMeter retrieves a specific meter
+func (h *MeterHandler) GetMeter(c *fiber.Ctx) error {
+	meter, err := h.meterService.GetMeter(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(meter)
+}
+
+// UpdateMeter updates a meter
+func (h *MeterHandler) UpdateMeter(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	userEmail, err := middleware.GetUserEmail(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req services.UpdateMeterRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	meterID := c.Params("id")
+	meter, err := h.meterService.UpdateMeter(c.Context(), meterID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.auditService.LogAction(c.Context(), userID, userEmail, userEmail, "update_meter", "meter", &meterID,
+		map[string]interface{}{"utility_type": meter.UtilityType, "is_active": meter.IsActive},
+		c.IP(), c.Get("User-Agent"), "success")
+
+	return c.JSON(meter)
+}
+
+// DeleteMeter deletes a meter without readings
+func (h *MeterHandler) DeleteMeter(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	userEmail, err := middleware.GetUserEmail(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	meterID := c.Params("id")
+	if err := h.meterService.DeleteMeter(c.Context(), meterID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.auditService.LogAction(c.Context(), userID, userEmail, userEmail, "delete_meter", "meter", &meterID,
+		nil, c.IP(), c.Get("User-Agent"), "success")
+
+	return c.JSON(fiber.Map{"message": "Meter deleted successfully"})
+}
+
+// ReplaceMeter records the counter behind a meter being swapped
+func (h *MeterHandler) ReplaceMeter(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	userEmail, err := middleware.GetUserEmail(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req services.ReplaceMeterRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	meterID := c.Params("id")
+	replacement, err := h.meterService.ReplaceMeter(c.Context(), meterID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.auditService.LogAction(c.Context(), userID, userEmail, userEmail, "replace_meter", "meter", &meterID,
+		map[string]interface{}{"old_final_value": replacement.OldFinalValue, "new_initial_value": replacement.NewInitialValue},
+		c.IP(), c.Get("User-Agent"), "success")
+
+	return c.Status(fiber.StatusCreated).JSON(replacement)
+}
+
+// GetReplacements retrieves the replacements of a meter
+func (h *MeterHandler) GetReplacements(c *fiber.Ctx) error {
+	replacements, err := h.meterService.GetReplacements(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(replacements)
+}
+
+// GetReadings retrieves the readings of a meter
+func (h *MeterHandler) GetReadings(c *fiber.Ctx) error {
+	readings, err := h.meterService.GetReadings(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(readings)
+}
@@ -2,12 +2,12 @@ package handlers
 
 import (
 	"log"
-	"strconv"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sainaif/holy-home/internal/middleware"
 	"github.com/sainaif/holy-home/internal/models"
 	"github.com/sainaif/holy-home/internal/services"
+	"github.com/sainaif/holy-home/internal/utils"
 )
 
 type PaymentHandler struct {
@@ -23,9 +23,10 @@ func NewPaymentHandler(paymentService *services.PaymentService, auditService *se
 }
 
 type RecordPaymentRequest struct {
-	BillID string  `json:"billId"`
-	Amount string  `json:"amount"`
-	Method *string `json:"method,omitempty"`
+	BillID   string  `json:"billId"`
+	Amount   string  `json:"amount"`
+	Currency string  `json:"currency,omitempty"`
+	Method   *string `json:"method,omitempty"`
 }
 
 // RecordPayment records a payment made by the current user for a bill
@@ -58,7 +59,7 @@ func (h *PaymentHandler) RecordPayment(c *fiber.Ctx) error {
 	}
 
 	// Parse amount
-	amountFloat, err := strconv.ParseFloat(req.Amount, 64)
+	amount, err := utils.ParseMoney(req.Amount)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
 			"error": "Invalid amount format",
@@ -67,15 +68,16 @@ func (h *PaymentHandler) RecordPayment(c *fiber.Ctx) error {
 
 	// Record the payment
 	payment, err := h.paymentService.RecordPayment(c.Context(), services.RecordPaymentRequest{
-		BillID: req.BillID,
-		Amount: amountFloat,
-		Method: req.Method,
+		BillID:   req.BillID,
+		Amount:   amount,
+		Currency: req.Currency,
+		Method:   req.Method,
 	}, userID)
 
 	if err != nil {
 		log.Printf("Payment error for bill %s, user %s: %v", req.BillID, userID, err)
 		h.auditService.LogAction(c.Context(), userID, userEmail, userEmail, "record_payment", "payment", nil,
-			map[string]interface{}{"bill_id": req.BillID, "amount": amountFloat},
+			map[string]interface{}{"bill_id": req.BillID, "amount": amount},
 			c.IP(), c.Get("User-Agent"), "failure")
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
 			"error": err.Error(),
@@ -83,7 +85,7 @@ func (h *PaymentHandler) RecordPayment(c *fiber.Ctx) error {
 	}
 
 	h.auditService.LogAction(c.Context(), userID, userEmail, userEmail, "record_payment", "payment", &payment.ID,
-		map[string]interface{}{"bill_id": req.BillID, "amount": amountFloat},
+		map[string]interface{}{"bill_id": req.BillID, "amount": amount},
 		c.IP(), c.Get("User-Agent"), "success")
 
 	return c.Status(fiber.StatusCreated).JSON(payment)
@@ -11,13 +11,14 @@ import (
 )
 
 type RecurringBillTemplateRequest struct {
-	CustomType  string                           `json:"customType"`
-	Frequency   string                           `json:"frequency"`
-	Amount      string                           `json:"amount"` // Comes as string from JSON
-	DayOfMonth  int                              `json:"dayOfMonth"`
-	StartDate   time.Time                        `json:"startDate"` // Required
-	Allocations []models.RecurringBillAllocation `json:"allocations"`
-	Notes       *string                          `json:"notes,omitempty"`
+	CustomType     string                           `json:"customType"`
+	Frequency      string                           `json:"frequency"`
+	Amount         string                           `json:"amount"` // Comes as string from JSON
+	DayOfMonth     int                              `json:"dayOfMonth"`
+	StartDate      time.Time                        `json:"startDate"`                // Required
+	RecurrenceRule *string                          `json:"recurrenceRule,omitempty"` // RFC 5545 RRULE, replaces frequency and day of month
+	Allocations    []models.RecurringBillAllocation `json:"allocations"`
+	Notes          *string                          `json:"notes,omitempty"`
 }
 
 type RecurringBillHandler struct {
@@ -56,13 +57,14 @@ func (h *RecurringBillHandler) CreateRecurringBillTemplate(c *fiber.Ctx) error {
 
 	// Build template model - Amount is now a string
 	template := &models.RecurringBillTemplate{
-		CustomType:  req.CustomType,
-		Frequency:   req.Frequency,
-		Amount:      req.Amount,
-		DayOfMonth:  req.DayOfMonth,
-		StartDate:   req.StartDate,
-		Allocations: req.Allocations,
-		Notes:       req.Notes,
+		CustomType:     req.CustomType,
+		Frequency:      req.Frequency,
+		Amount:         req.Amount,
+		DayOfMonth:     req.DayOfMonth,
+		StartDate:      req.StartDate,
+		RecurrenceRule: req.RecurrenceRule,
+		Allocations:    req.Allocations,
+		Notes:          req.Notes,
 	}
 
 	if err := h.recurringBillService.CreateTemplate(c.Context(), template); err != nil {
@@ -0,0 +1,93 @@
+package handlers
+
+import (
+	"errors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sainaif/holy-home/internal/middleware"
+	"github.com/sainaif/holy-home/internal/services"
+)
+
+type SettlementHandler struct {
+	settlementService *services.SettlementService
+	eventService      *services.EventService
+	auditService      *services.AuditService
+}
+
+func NewSettlementHandler(settlementService *services.SettlementService, eventService *services.EventService, auditService *services.AuditService) *SettlementHandler {
+	return &SettlementHandler{
+		settlementService: settlementService,
+		eventService:      eventService,
+		auditService:      auditService,
+	}
+}
+
+// GetPlan returns the minimum set of transfers that settles the household
+func (h *SettlementHandler) GetPlan(c *fiber.Ctx) error {
+	plan, err := h.settlementService.GetPlan(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(plan)
+}
+
+// ApplyPlan records the transfers of a previously fetched settlement plan and closes the debts they clear
+func (h *SettlementHandler) ApplyPlan(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	userEmail, err := middleware.GetUserEmail(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req struct {
+		Fingerprint string `json:"fingerprint"`
+	}
+	if err := c.BodyParser(&req); err != nil || req.Fingerprint == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "fingerprint of the settlement plan is required",
+		})
+	}
+
+	result, err := h.settlementService.ApplyPlan(c.Context(), req.Fingerprint)
+	if err != nil {
+		h.auditService.LogAction(c.Context(), userID, userEmail, userEmail, "apply_settlement", "settlement", nil,
+			map[string]interface{}{"fingerprint": req.Fingerprint, "error": err.Error()},
+			c.IP(), c.Get("User-Agent"), "failure")
+
+		status := fiber.StatusInternalServerError
+		switch {
+		case errors.Is(err, services.ErrSettlementPlanStale):
+			status = fiber.StatusConflict
+		case errors.Is(err, services.ErrNothingToSettle):
+			status = fiber.StatusBadRequest
+		}
+		return c.Status(status).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.auditService.LogAction(c.Context(), userID, userEmail, userEmail, "apply_settlement", "settlement", &req.Fingerprint,
+		map[string]interface{}{
+			"transfers":   result.TransfersRecorded,
+			"loans":       result.LoansSettled,
+			"bill_shares": result.BillSharesSettled,
+		},
+		c.IP(), c.Get("User-Agent"), "success")
+
+	// Broadcast balance updated event
+	h.eventService.Broadcast(services.EventBalanceUpdated, map[string]interface{}{
+		"timestamp": result.Plan.GeneratedAt,
+	})
+
+	return c.JSON(result)
+}
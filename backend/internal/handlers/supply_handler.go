@@ -6,6 +6,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/sainaif/holy-home/internal/middleware"
 	"github.com/sainaif/holy-home/internal/services"
+	"github.com/sainaif/holy-home/internal/utils"
 )
 
 type SupplyHandler struct {
@@ -39,8 +40,8 @@ func (h *SupplyHandler) GetSettings(c *fiber.Ctx) error {
 // UpdateSettings updates supply settings (ADMIN only)
 func (h *SupplyHandler) UpdateSettings(c *fiber.Ctx) error {
 	var req struct {
-		WeeklyContributionPLN float64 `json:"weeklyContributionPLN"`
-		ContributionDay       string  `json:"contributionDay"`
+		WeeklyContributionPLN utils.Money `json:"weeklyContributionPLN"`
+		ContributionDay       string      `json:"contributionDay"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -63,8 +64,8 @@ func (h *SupplyHandler) UpdateSettings(c *fiber.Ctx) error {
 // AdjustBudget manually adjusts budget (ADMIN only)
 func (h *SupplyHandler) AdjustBudget(c *fiber.Ctx) error {
 	var req struct {
-		Adjustment float64 `json:"adjustment"`
-		Notes      string  `json:"notes"`
+		Adjustment utils.Money `json:"adjustment"`
+		Notes      string      `json:"notes"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -277,9 +278,10 @@ func (h *SupplyHandler) RestockItem(c *fiber.Ctx) error {
 	}
 
 	var req struct {
-		QuantityToAdd int      `json:"quantityToAdd"`
-		AmountPLN     *float64 `json:"amountPLN"`
-		NeedsRefund   bool     `json:"needsRefund"`
+		QuantityToAdd int          `json:"quantityToAdd"`
+		AmountPLN     *utils.Money `json:"amountPLN"`
+		Currency      string       `json:"currency"`
+		NeedsRefund   bool         `json:"needsRefund"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -288,7 +290,7 @@ func (h *SupplyHandler) RestockItem(c *fiber.Ctx) error {
 		})
 	}
 
-	if err := h.supplyService.RestockItem(c.Context(), itemID, userID, req.QuantityToAdd, req.AmountPLN, req.NeedsRefund); err != nil {
+	if err := h.supplyService.RestockItem(c.Context(), itemID, userID, req.QuantityToAdd, req.AmountPLN, req.Currency, req.NeedsRefund); err != nil {
 		h.auditService.LogAction(c.Context(), userID, userEmail, userEmail, "restock_supply_item", "supply", &itemID,
 			map[string]interface{}{"quantity": req.QuantityToAdd, "error": err.Error()},
 			c.IP(), c.Get("User-Agent"), "failure")
@@ -465,8 +467,8 @@ func (h *SupplyHandler) CreateContribution(c *fiber.Ctx) error {
 	}
 
 	var req struct {
-		AmountPLN float64 `json:"amountPLN"`
-		Notes     *string `json:"notes"`
+		AmountPLN utils.Money `json:"amountPLN"`
+		Notes     *string     `json:"notes"`
 	}
 
 	if err := c.BodyParser(&req); err != nil {
@@ -0,0 +1,144 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/sainaif/holy-home/internal/middleware"
+	"github.com/sainaif/holy-home/internal/services"
+)
+
+type TariffHandler struct {
+	tariffService *services.TariffService
+	auditService  *services.AuditService
+}
+
+func NewTariffHandler(tariffService *services.TariffService, auditService *services.AuditService) *TariffHandler {
+	return &TariffHandler{
+		tariffService: tariffService,
+		auditService:  auditService,
+	}
+}
+
+// CreateTariff defines a new tariff
+func (h *TariffHandler) CreateTariff(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	userEmail, err := middleware.GetUserEmail(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req services.CreateTariffRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	tariff, err := h.tariffService.CreateTariff(c.Context(), req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.auditService.LogAction(c.Context(), userID, userEmail, userEmail, "create_tariff", "tariff", &tariff.ID,
+		map[string]interface{}{"utility_type": tariff.UtilityType, "name": tariff.Name, "components": len(tariff.Components)},
+		c.IP(), c.Get("User-Agent"), "success")
+
+	return c.Status(fiber.StatusCreated).JSON(tariff)
+}
+
+// GetTariffs retrieves all tariffs
+func (h *TariffHandler) GetTariffs(c *fiber.Ctx) error {
+	tariffs, err := h.tariffService.GetTariffs(c.Context())
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(tariffs)
+}
+
+// GetTariff retrieves a specific tariff
+func (h *TariffHandler) GetTariff(c *fiber.Ctx) error {
+	tariff, err := h.tariffService.GetTariff(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(tariff)
+}
+
+// UpdateTariff updates a tariff
+func (h *TariffHandler) UpdateTariff(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	userEmail, err := middleware.GetUserEmail(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	var req services.UpdateTariffRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	tariffID := c.Params("id")
+	tariff, err := h.tariffService.UpdateTariff(c.Context(), tariffID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.auditService.LogAction(c.Context(), userID, userEmail, userEmail, "update_tariff", "tariff", &tariffID,
+		map[string]interface{}{"utility_type": tariff.UtilityType, "name": tariff.Name, "components": len(tariff.Components)},
+		c.IP(), c.Get("User-Agent"), "success")
+
+	return c.JSON(tariff)
+}
+
+// DeleteTariff deletes a tariff
+func (h *TariffHandler) DeleteTariff(c *fiber.Ctx) error {
+	userID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	userEmail, err := middleware.GetUserEmail(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+
+	tariffID := c.Params("id")
+	if err := h.tariffService.DeleteTariff(c.Context(), tariffID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.auditService.LogAction(c.Context(), userID, userEmail, userEmail, "delete_tariff", "tariff", &tariffID,
+		nil, c.IP(), c.Get("User-Agent"), "success")
+
+	return c.JSON(fiber.Map{"message": "Tariff deleted successfully"})
+}
@@ -4,6 +4,7 @@ import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/sainaif/holy-home/internal/config"
 	"github.com/sainaif/holy-home/internal/middleware"
+	"github.com/sainaif/holy-home/internal/models"
 	"github.com/sainaif/holy-home/internal/services"
 )
 
@@ -132,6 +133,12 @@ func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 	if req.Role != nil {
 		details["newRole"] = *req.Role
 	}
+	if req.GroupID != nil {
+		details["newGroupId"] = *req.GroupID
+		if req.GroupEffectiveFrom != nil {
+			details["groupEffectiveFrom"] = req.GroupEffectiveFrom.Format("2006-01-02")
+		}
+	}
 	h.auditService.LogAction(c.Context(), currentUserID, currentEmail, "", "user.update", "user", &userID, details, c.IP(), c.Get("User-Agent"), "success")
 
 	return c.JSON(fiber.Map{
@@ -342,3 +349,138 @@ func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 		"message": "User deleted successfully",
 	})
 }
+
+// GetResidencies retrieves the periods a user lived in the household
+func (h *UserHandler) GetResidencies(c *fiber.Ctx) error {
+	residencies, err := h.userService.GetResidencies(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(residencies)
+}
+
+// GetGroupHistory lists the group changes of a user
+func (h *UserHandler) GetGroupHistory(c *fiber.Ctx) error {
+	changes, err := h.userService.GetGroupHistory(c.Context(), c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	return c.JSON(changes)
+}
+
+// CreateResidency records a period a user lived in the household (ADMIN only)
+func (h *UserHandler) CreateResidency(c *fiber.Ctx) error {
+	currentUserID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	currentEmail, err := middleware.GetUserEmail(c)
+	if err != nil {
+		currentEmail = "unknown"
+	}
+
+	var req services.CreateResidencyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	userID := c.Params("id")
+	residency, err := h.userService.CreateResidency(c.Context(), userID, req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.auditService.LogAction(c.Context(), currentUserID, currentEmail, "", "user.residency.create", "user", &userID,
+		residencyAuditDetails(residency), c.IP(), c.Get("User-Agent"), "success")
+
+	return c.Status(fiber.StatusCreated).JSON(residency)
+}
+
+// UpdateResidency changes the dates or group of a user's residency (ADMIN only)
+func (h *UserHandler) UpdateResidency(c *fiber.Ctx) error {
+	currentUserID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	currentEmail, err := middleware.GetUserEmail(c)
+	if err != nil {
+		currentEmail = "unknown"
+	}
+
+	var req services.UpdateResidencyRequest
+	if err := c.BodyParser(&req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": "Invalid request body",
+		})
+	}
+
+	userID := c.Params("id")
+	residency, err := h.userService.UpdateResidency(c.Context(), userID, c.Params("residencyId"), req)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.auditService.LogAction(c.Context(), currentUserID, currentEmail, "", "user.residency.update", "user", &userID,
+		residencyAuditDetails(residency), c.IP(), c.Get("User-Agent"), "success")
+
+	return c.JSON(residency)
+}
+
+// DeleteResidency deletes a user's residency (ADMIN only)
+func (h *UserHandler) DeleteResidency(c *fiber.Ctx) error {
+	currentUserID, err := middleware.GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	currentEmail, err := middleware.GetUserEmail(c)
+	if err != nil {
+		currentEmail = "unknown"
+	}
+
+	userID := c.Params("id")
+	residencyID := c.Params("residencyId")
+	if err := h.userService.DeleteResidency(c.Context(), userID, residencyID); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+			"error": err.Error(),
+		})
+	}
+
+	h.auditService.LogAction(c.Context(), currentUserID, currentEmail, "", "user.residency.delete", "user", &userID,
+		map[string]interface{}{"residencyId": residencyID}, c.IP(), c.Get("User-Agent"), "success")
+
+	return c.JSON(fiber.Map{
+		"message": "Residency deleted successfully",
+	})
+}
+
+func residencyAuditDetails(residency *models.Residency) map[string]interface{} {
+	details := map[string]interface{}{
+		"residencyId": residency.ID,
+		"startDate":   residency.StartDate.Format("2006-01-02"),
+	}
+	if residency.EndDate != nil {
+		details["endDate"] = residency.EndDate.Format("2006-01-02")
+	}
+	if residency.GroupID != nil {
+		details["groupId"] = *residency.GroupID
+	}
+	return details
+}
@@ -1,12 +1,15 @@
 package middleware
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/sainaif/holy-home/internal/config"
+	"github.com/sainaif/holy-home/internal/models"
 	"github.com/sainaif/holy-home/internal/utils"
 )
 
@@ -22,22 +25,7 @@ const (
 // Supports both Authorization header and query param (for SSE)
 func AuthMiddleware(cfg *config.Config) fiber.Handler {
 	return func(c *fiber.Ctx) error {
-		var token string
-
-		// Try Authorization header first
-		authHeader := c.Get("Authorization")
-		if authHeader != "" {
-			parts := strings.Split(authHeader, " ")
-			if len(parts) == 2 && parts[0] == "Bearer" {
-				token = parts[1]
-			}
-		}
-
-		// Fall back to query param (for EventSource/SSE)
-		if token == "" {
-			token = c.Query("token")
-		}
-
+		token := bearerToken(c)
 		if token == "" {
 			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
 				"error": "Missing authorization token",
@@ -60,6 +48,19 @@ func AuthMiddleware(cfg *config.Config) fiber.Handler {
 	}
 }
 
+// bearerToken returns the access token from the Authorization header,
+// falling back to the token query param (for EventSource/SSE)
+func bearerToken(c *fiber.Ctx) string {
+	authHeader := c.Get("Authorization")
+	if authHeader != "" {
+		parts := strings.Split(authHeader, " ")
+		if len(parts) == 2 && parts[0] == "Bearer" {
+			return parts[1]
+		}
+	}
+	return c.Query("token")
+}
+
 // RequireRole creates a middleware that checks for specific roles
 func RequireRole(roles ...string) fiber.Handler {
 	return func(c *fiber.Ctx) error {
@@ -109,9 +110,44 @@ func GetUserEmail(c *fiber.Ctx) (string, error) {
 	return email, nil
 }
 
+// ApprovalGate decides whether a permitted call needs approval and records it as a pending request
+type ApprovalGate interface {
+	RequiresApproval(ctx context.Context, roleName, action string, amount *utils.Money) (bool, error)
+	CreateApprovalRequest(ctx context.Context, userID string, userEmail, userName string, action, resourceType string, resourceID *string, details map[string]interface{}) (*models.ApprovalRequest, error)
+}
+
+// ApprovalRoute describes the action a route performs, for matching approval policies
+type ApprovalRoute struct {
+	Action       string // e.g., "bill.delete"
+	ResourceType string // e.g., "bill"; the resource ID is taken from the :id route param
+	AmountField  string // optional JSON body field compared against policy thresholds
+}
+
+// PermissionOption configures RequirePermission
+type PermissionOption func(*permissionOptions)
+
+type permissionOptions struct {
+	approvalGate  ApprovalGate
+	approvalRoute ApprovalRoute
+}
+
+// WithApproval holds calls that an approval policy applies to. Instead of reaching the
+// handler, the full request is stored as a pending ApprovalRequest and 202 is returned.
+func WithApproval(gate ApprovalGate, route ApprovalRoute) PermissionOption {
+	return func(o *permissionOptions) {
+		o.approvalGate = gate
+		o.approvalRoute = route
+	}
+}
+
 // RequirePermission creates a middleware that checks for specific permissions
 // This requires the RoleService to check if the user's role has the permission
-func RequirePermission(permission string, roleServiceGetter func() interface{}) fiber.Handler {
+func RequirePermission(permission string, roleServiceGetter func() interface{}, opts ...PermissionOption) fiber.Handler {
+	var options permissionOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
 	return func(c *fiber.Ctx) error {
 		userRole, ok := c.Locals("userRole").(string)
 		if !ok {
@@ -161,6 +197,86 @@ func RequirePermission(permission string, roleServiceGetter func() interface{})
 			})
 		}
 
+		if options.approvalGate != nil {
+			return requestApproval(c, options.approvalGate, options.approvalRoute, userRole)
+		}
+
+		return c.Next()
+	}
+}
+
+// requestApproval passes the call on if no approval policy applies, otherwise it
+// records the method, path, params, query and body as a pending approval request
+func requestApproval(c *fiber.Ctx, gate ApprovalGate, route ApprovalRoute, userRole string) error {
+	var body interface{}
+	if len(c.Body()) > 0 {
+		decoder := json.NewDecoder(bytes.NewReader(c.Body()))
+		decoder.UseNumber()
+		if err := decoder.Decode(&body); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Invalid request body",
+			})
+		}
+	}
+
+	var amount *utils.Money
+	if fields, ok := body.(map[string]interface{}); ok && route.AmountField != "" {
+		if value, ok := fields[route.AmountField]; ok {
+			raw, _ := json.Marshal(value)
+			var parsed utils.Money
+			if err := parsed.UnmarshalJSON(raw); err != nil {
+				return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+					"error": fmt.Sprintf("Invalid %s", route.AmountField),
+				})
+			}
+			amount = &parsed
+		}
+	}
+
+	required, err := gate.RequiresApproval(c.Context(), userRole, route.Action, amount)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to check approval policy",
+		})
+	}
+	if !required {
 		return c.Next()
 	}
+
+	userID, err := GetUserID(c)
+	if err != nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{
+			"error": "Unauthorized",
+		})
+	}
+	userEmail, _ := GetUserEmail(c)
+
+	var resourceID *string
+	if id := c.Params("id"); id != "" {
+		resourceID = &id
+	}
+
+	details := map[string]interface{}{
+		"method": c.Method(),
+		"path":   c.Path(),
+		"params": c.AllParams(),
+		"query":  c.Queries(),
+	}
+	if body != nil {
+		details["body"] = body
+	}
+
+	request, err := gate.CreateApprovalRequest(c.Context(), userID, userEmail, "", route.Action, route.ResourceType, resourceID, details)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to create approval request",
+		})
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"success":          true,
+		"requiresApproval": true,
+		"approvalRequest":  request,
+		"message":          "Request submitted for admin approval",
+	})
 }
@@ -0,0 +1,148 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/sainaif/holy-home/internal/config"
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/utils"
+)
+
+const (
+	IdempotencyKeyHeader     = "Idempotency-Key"
+	IdempotentReplayedHeader = "Idempotent-Replayed"
+	maxIdempotencyKeyLength  = 255
+
+	// idempotencyLease is how long a request in progress holds its key. A key whose request
+	// crashed the process is free again once the lease runs out.
+	idempotencyLease = time.Minute
+)
+
+// IdempotencyStore persists responses to requests sent with an Idempotency-Key header
+type IdempotencyStore interface {
+	Reserve(ctx context.Context, record *models.IdempotencyRecord) (bool, error)
+	Get(ctx context.Context, userID, key, route string) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *models.IdempotencyRecord) error
+	Delete(ctx context.Context, userID, key, route string) error
+}
+
+// IdempotencyMiddleware makes mutating requests that carry an Idempotency-Key header safe
+// to retry. The first response for each (user, key, route) is stored for cfg.App.IdempotencyTTL
+// and replayed on retries; reusing a key with a different body returns 409, and so does a
+// retry while the first request holds its lease. Server errors, returned errors and panics
+// release the key, so a failed request can be retried with the same key.
+func IdempotencyMiddleware(cfg *config.Config, store IdempotencyStore) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		key := c.Get(IdempotencyKeyHeader)
+		if key == "" || !isMutatingMethod(c.Method()) {
+			return c.Next()
+		}
+		if len(key) > maxIdempotencyKeyLength {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
+				"error": "Idempotency-Key is too long",
+			})
+		}
+
+		// This runs before the per-route AuthMiddleware, so the token is checked here to scope
+		// keys per user. Requests without a valid token are left for the route to reject.
+		claims, err := utils.ValidateAccessToken(bearerToken(c), cfg.JWT.Secret)
+		if err != nil {
+			return c.Next()
+		}
+
+		hash := sha256.Sum256(c.Body())
+		now := time.Now().UTC()
+		lockedUntil := now.Add(idempotencyLease)
+		record := &models.IdempotencyRecord{
+			UserID:      claims.UserID,
+			Key:         key,
+			Route:       c.Method() + " " + c.Path(),
+			RequestHash: hex.EncodeToString(hash[:]),
+			CreatedAt:   now,
+			ExpiresAt:   now.Add(cfg.App.IdempotencyTTL),
+			LockedUntil: &lockedUntil,
+		}
+
+		reserved, err := store.Reserve(c.Context(), record)
+		if err != nil {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+				"error": "Failed to check idempotency key",
+			})
+		}
+		if !reserved {
+			return replayIdempotentResponse(c, store, record)
+		}
+
+		defer func() {
+			if r := recover(); r != nil {
+				releaseIdempotencyKey(c, store, record)
+				panic(r)
+			}
+		}()
+		if err := c.Next(); err != nil {
+			releaseIdempotencyKey(c, store, record)
+			return err
+		}
+
+		status := c.Response().StatusCode()
+		if status >= fiber.StatusInternalServerError {
+			releaseIdempotencyKey(c, store, record)
+			return nil
+		}
+
+		record.StatusCode = status
+		record.LockedUntil = nil
+		record.ResponseBody = append([]byte(nil), c.Response().Body()...)
+		record.ContentType = string(c.Response().Header.ContentType())
+		if err := store.Complete(c.Context(), record); err != nil {
+			log.Printf("Failed to store idempotent response for %s: %v", record.Route, err)
+		}
+		return nil
+	}
+}
+
+// replayIdempotentResponse answers a retry with the stored response of the first request
+func replayIdempotentResponse(c *fiber.Ctx, store IdempotencyStore, record *models.IdempotencyRecord) error {
+	existing, err := store.Get(c.Context(), record.UserID, record.Key, record.Route)
+	if err != nil {
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
+			"error": "Failed to check idempotency key",
+		})
+	}
+	if existing != nil && existing.RequestHash != record.RequestHash {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "Idempotency-Key was already used with a different request body",
+		})
+	}
+	if existing == nil || existing.StatusCode == 0 {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"error": "A request with this Idempotency-Key is still being processed",
+		})
+	}
+
+	c.Set(IdempotentReplayedHeader, "true")
+	if existing.ContentType != "" {
+		c.Set(fiber.HeaderContentType, existing.ContentType)
+	}
+	return c.Status(existing.StatusCode).Send(existing.ResponseBody)
+}
+
+// releaseIdempotencyKey forgets a reserved key so the request can be retried
+func releaseIdempotencyKey(c *fiber.Ctx, store IdempotencyStore, record *models.IdempotencyRecord) {
+	if err := store.Delete(c.Context(), record.UserID, record.Key, record.Route); err != nil {
+		log.Printf("Failed to release idempotency key for %s: %v", record.Route, err)
+	}
+}
+
+func isMutatingMethod(method string) bool {
+	switch method {
+	case fiber.MethodPost, fiber.MethodPut, fiber.MethodPatch, fiber.MethodDelete:
+		return true
+	}
+	return false
+}
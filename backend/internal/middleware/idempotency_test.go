@@ -0,0 +1,215 @@
+package middleware
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/sainaif/holy-home/internal/config"
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testSecret = "test-secret-with-at-least-32-characters"
+
+// memoryIdempotencyStore keeps records in a map keyed by user, key and route
+type memoryIdempotencyStore struct {
+	records map[string]models.IdempotencyRecord
+}
+
+func newMemoryIdempotencyStore() *memoryIdempotencyStore {
+	return &memoryIdempotencyStore{records: map[string]models.IdempotencyRecord{}}
+}
+
+func (m *memoryIdempotencyStore) id(userID, key, route string) string {
+	return userID + "|" + key + "|" + route
+}
+
+func (m *memoryIdempotencyStore) Reserve(ctx context.Context, record *models.IdempotencyRecord) (bool, error) {
+	id := m.id(record.UserID, record.Key, record.Route)
+	if existing, ok := m.records[id]; ok && existing.ExpiresAt.After(record.CreatedAt) {
+		if existing.StatusCode != 0 || (existing.LockedUntil != nil && existing.LockedUntil.After(record.CreatedAt)) {
+			return false, nil
+		}
+	}
+	m.records[id] = *record
+	return true, nil
+}
+
+func (m *memoryIdempotencyStore) Get(ctx context.Context, userID, key, route string) (*models.IdempotencyRecord, error) {
+	record, ok := m.records[m.id(userID, key, route)]
+	if !ok {
+		return nil, nil
+	}
+	return &record, nil
+}
+
+func (m *memoryIdempotencyStore) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	m.records[m.id(record.UserID, record.Key, record.Route)] = *record
+	return nil
+}
+
+func (m *memoryIdempotencyStore) Delete(ctx context.Context, userID, key, route string) error {
+	delete(m.records, m.id(userID, key, route))
+	return nil
+}
+
+func setupIdempotencyApp(store IdempotencyStore) (*fiber.App, *int) {
+	cfg := &config.Config{
+		App: config.AppConfig{IdempotencyTTL: time.Hour},
+		JWT: config.JWTConfig{Secret: testSecret},
+	}
+	calls := 0
+
+	app := fiber.New()
+	app.Use(recover.New())
+	app.Use(IdempotencyMiddleware(cfg, store))
+	app.Post("/payments", func(c *fiber.Ctx) error {
+		calls++
+		if strings.Contains(string(c.Body()), "fail") {
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "boom"})
+		}
+		if strings.Contains(string(c.Body()), "panic") {
+			panic("handler bug")
+		}
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"call": calls})
+	})
+	return app, &calls
+}
+
+func idempotentRequest(t *testing.T, app *fiber.App, userID, key, body string) (int, string, string) {
+	token, err := utils.GenerateAccessToken(userID, userID+"@example.com", "RESIDENT", testSecret, time.Minute)
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(fiber.MethodPost, "/payments", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+token)
+	if key != "" {
+		req.Header.Set(IdempotencyKeyHeader, key)
+	}
+
+	resp, err := app.Test(req)
+	require.NoError(t, err)
+	data, _ := io.ReadAll(resp.Body)
+	return resp.StatusCode, string(data), resp.Header.Get(IdempotentReplayedHeader)
+}
+
+func TestIdempotencyMiddleware_ReplaysResponse(t *testing.T) {
+	app, calls := setupIdempotencyApp(newMemoryIdempotencyStore())
+
+	status, body, replayed := idempotentRequest(t, app, "user-1", "key-1", `{"amount":"10"}`)
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.JSONEq(t, `{"call":1}`, body)
+	assert.Empty(t, replayed)
+
+	status, body, replayed = idempotentRequest(t, app, "user-1", "key-1", `{"amount":"10"}`)
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.JSONEq(t, `{"call":1}`, body)
+	assert.Equal(t, "true", replayed)
+	assert.Equal(t, 1, *calls)
+
+	// Keys are scoped per user
+	status, body, _ = idempotentRequest(t, app, "user-2", "key-1", `{"amount":"10"}`)
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.JSONEq(t, `{"call":2}`, body)
+
+	// Requests without a key are never deduplicated
+	idempotentRequest(t, app, "user-1", "", `{"amount":"10"}`)
+	idempotentRequest(t, app, "user-1", "", `{"amount":"10"}`)
+	assert.Equal(t, 4, *calls)
+}
+
+func TestIdempotencyMiddleware_DifferentBodyConflicts(t *testing.T) {
+	app, calls := setupIdempotencyApp(newMemoryIdempotencyStore())
+
+	idempotentRequest(t, app, "user-1", "key-1", `{"amount":"10"}`)
+	status, _, _ := idempotentRequest(t, app, "user-1", "key-1", `{"amount":"20"}`)
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.Equal(t, 1, *calls)
+}
+
+func TestIdempotencyMiddleware_InProgressConflicts(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	app, calls := setupIdempotencyApp(store)
+
+	// A retry arriving while the first request is still running
+	body := `{"amount":"10"}`
+	hash := sha256.Sum256([]byte(body))
+	lockedUntil := time.Now().Add(time.Minute)
+	store.records[store.id("user-1", "key-1", "POST /payments")] = models.IdempotencyRecord{
+		UserID:      "user-1",
+		Key:         "key-1",
+		Route:       "POST /payments",
+		RequestHash: hex.EncodeToString(hash[:]),
+		ExpiresAt:   time.Now().Add(time.Hour),
+		LockedUntil: &lockedUntil,
+	}
+
+	status, _, _ := idempotentRequest(t, app, "user-1", "key-1", body)
+	assert.Equal(t, fiber.StatusConflict, status)
+	assert.Equal(t, 0, *calls)
+}
+
+func TestIdempotencyMiddleware_ExpiredLeaseIsTakenOver(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	app, calls := setupIdempotencyApp(store)
+
+	// The first request crashed the process before it completed
+	body := `{"amount":"10"}`
+	hash := sha256.Sum256([]byte(body))
+	lockedUntil := time.Now().Add(-time.Second)
+	store.records[store.id("user-1", "key-1", "POST /payments")] = models.IdempotencyRecord{
+		UserID:      "user-1",
+		Key:         "key-1",
+		Route:       "POST /payments",
+		RequestHash: hex.EncodeToString(hash[:]),
+		ExpiresAt:   time.Now().Add(time.Hour),
+		LockedUntil: &lockedUntil,
+	}
+
+	status, _, _ := idempotentRequest(t, app, "user-1", "key-1", body)
+	assert.Equal(t, fiber.StatusCreated, status)
+	assert.Equal(t, 1, *calls)
+
+	// The stored response holds no lease and is replayed
+	record := store.records[store.id("user-1", "key-1", "POST /payments")]
+	assert.Nil(t, record.LockedUntil)
+	_, _, replayed := idempotentRequest(t, app, "user-1", "key-1", body)
+	assert.Equal(t, "true", replayed)
+	assert.Equal(t, 1, *calls)
+}
+
+func TestIdempotencyMiddleware_PanicReleasesKey(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	app, calls := setupIdempotencyApp(store)
+
+	status, _, _ := idempotentRequest(t, app, "user-1", "key-1", `{"panic":true}`)
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Empty(t, store.records)
+
+	status, _, _ = idempotentRequest(t, app, "user-1", "key-1", `{"panic":true}`)
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Equal(t, 2, *calls)
+}
+
+func TestIdempotencyMiddleware_ServerErrorIsNotStored(t *testing.T) {
+	store := newMemoryIdempotencyStore()
+	app, calls := setupIdempotencyApp(store)
+
+	status, _, _ := idempotentRequest(t, app, "user-1", "key-1", `{"fail":true}`)
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Empty(t, store.records)
+
+	status, _, _ = idempotentRequest(t, app, "user-1", "key-1", `{"fail":true}`)
+	assert.Equal(t, fiber.StatusInternalServerError, status)
+	assert.Equal(t, 2, *calls)
+}
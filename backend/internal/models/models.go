@@ -44,6 +44,38 @@ type Group struct {
 	CreatedAt time.Time `db:"created_at" json:"createdAt"`
 }
 
+// GroupWeightChange records a group's weight changing on a day. Allocations of days before
+// EffectiveFrom use OldWeight.
+type GroupWeightChange struct {
+	ID            string    `db:"id" json:"id"`
+	GroupID       string    `db:"group_id" json:"groupId"`
+	OldWeight     float64   `db:"old_weight" json:"oldWeight"`
+	NewWeight     float64   `db:"new_weight" json:"newWeight"`
+	EffectiveFrom time.Time `db:"effective_from" json:"effectiveFrom"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+}
+
+// GroupMembershipChange records a user moving between groups on a day, nil for no group.
+// Allocations of days before EffectiveFrom use OldGroupID.
+type GroupMembershipChange struct {
+	ID            string    `db:"id" json:"id"`
+	UserID        string    `db:"user_id" json:"userId"`
+	OldGroupID    *string   `db:"old_group_id" json:"oldGroupId,omitempty"`
+	NewGroupID    *string   `db:"new_group_id" json:"newGroupId,omitempty"`
+	EffectiveFrom time.Time `db:"effective_from" json:"effectiveFrom"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Residency is a period a user lived in the household as a member of a group, or on their own
+type Residency struct {
+	ID        string     `db:"id" json:"id"`
+	UserID    string     `db:"user_id" json:"userId"`
+	GroupID   *string    `db:"group_id" json:"groupId,omitempty"`
+	StartDate time.Time  `db:"start_date" json:"startDate"`
+	EndDate   *time.Time `db:"end_date" json:"endDate,omitempty"` // inclusive, nil while still living here
+	CreatedAt time.Time  `db:"created_at" json:"createdAt"`
+}
+
 // Bill represents a utility bill or shared expense
 type Bill struct {
 	ID                  string     `db:"id" json:"id"`
@@ -53,7 +85,10 @@ type Bill struct {
 	PeriodStart         time.Time  `db:"period_start" json:"periodStart"`
 	PeriodEnd           time.Time  `db:"period_end" json:"periodEnd"`
 	PaymentDeadline     *time.Time `db:"payment_deadline" json:"paymentDeadline,omitempty"` // optional deadline for payment
-	TotalAmountPLN      string     `db:"total_amount_pln" json:"totalAmountPLN"`            // Decimal as string
+	TotalAmountPLN      string     `db:"total_amount_pln" json:"totalAmountPLN"`            // Decimal as string, in the base currency
+	Currency            string     `db:"currency" json:"currency"`                          // currency the amount was entered in
+	OriginalAmount      *string    `db:"original_amount" json:"originalAmount,omitempty"`   // amount in Currency when it is not the base currency
+	ExchangeRate        *string    `db:"exchange_rate" json:"exchangeRate,omitempty"`       // base currency units per unit of Currency
 	TotalUnits          string     `db:"total_units" json:"totalUnits,omitempty"`           // Decimal as string
 	Notes               *string    `db:"notes" json:"notes,omitempty"`
 	Status              string     `db:"status" json:"status"` // draft, posted, closed
@@ -67,12 +102,13 @@ type Bill struct {
 // RecurringBillTemplate represents a template for auto-generating bills
 type RecurringBillTemplate struct {
 	ID              string                    `db:"id" json:"id"`
-	CustomType      string                    `db:"custom_type" json:"customType"`  // name of the bill (e.g., "Netflix", "Rent")
-	Frequency       string                    `db:"frequency" json:"frequency"`     // monthly, quarterly, yearly
-	Amount          string                    `db:"amount" json:"amount"`           // fixed amount per period (decimal as string)
-	DayOfMonth      int                       `db:"day_of_month" json:"dayOfMonth"` // 1-31, day when bill is due
-	StartDate       time.Time                 `db:"start_date" json:"startDate"`    // required start date for first bill
-	Allocations     []RecurringBillAllocation `db:"-" json:"allocations"`           // Loaded separately
+	CustomType      string                    `db:"custom_type" json:"customType"`                   // name of the bill (e.g., "Netflix", "Rent")
+	Frequency       string                    `db:"frequency" json:"frequency"`                      // monthly, quarterly, yearly
+	Amount          string                    `db:"amount" json:"amount"`                            // fixed amount per period (decimal as string)
+	DayOfMonth      int                       `db:"day_of_month" json:"dayOfMonth"`                  // 1-31, day when bill is due
+	StartDate       time.Time                 `db:"start_date" json:"startDate"`                     // required start date for first bill
+	RecurrenceRule  *string                   `db:"recurrence_rule" json:"recurrenceRule,omitempty"` // RFC 5545 RRULE, replaces frequency and day of month when set
+	Allocations     []RecurringBillAllocation `db:"-" json:"allocations"`                            // Loaded separately
 	Notes           *string                   `db:"notes" json:"notes,omitempty"`
 	IsActive        bool                      `db:"is_active" json:"isActive"`
 	CurrentBillID   *string                   `db:"current_bill_id" json:"currentBillId,omitempty"` // ID of the current active bill
@@ -97,37 +133,97 @@ type RecurringBillAllocation struct {
 
 // Consumption represents individual usage readings
 type Consumption struct {
-	ID          string    `db:"id" json:"id"`
-	BillID      string    `db:"bill_id" json:"billId"`
-	SubjectType string    `db:"subject_type" json:"subjectType"` // "user" or "group"
-	SubjectID   string    `db:"subject_id" json:"subjectId"`     // user ID or group ID
-	Units       string    `db:"units" json:"units"`              // Decimal as string
-	MeterValue  *string   `db:"meter_value" json:"meterValue,omitempty"`
-	RecordedAt  time.Time `db:"recorded_at" json:"recordedAt"`
-	Source      string    `db:"source" json:"source"` // user, admin
+	ID            string    `db:"id" json:"id"`
+	BillID        string    `db:"bill_id" json:"billId"`
+	SubjectType   string    `db:"subject_type" json:"subjectType"` // "user" or "group"
+	SubjectID     string    `db:"subject_id" json:"subjectId"`     // user ID or group ID
+	Units         string    `db:"units" json:"units"`              // Decimal as string
+	MeterValue    *string   `db:"meter_value" json:"meterValue,omitempty"`
+	MeterID       *string   `db:"meter_id" json:"meterId,omitempty"`  // meter the reading was taken from
+	Register      *string   `db:"register" json:"register,omitempty"` // register of a multi-register meter, e.g. day or night
+	RecordedAt    time.Time `db:"recorded_at" json:"recordedAt"`
+	Source        string    `db:"source" json:"source"`                // user, admin
+	AnomalyStatus string    `db:"anomaly_status" json:"anomalyStatus"` // none, suspicious, confirmed
+	AnomalyReason *string   `db:"anomaly_reason" json:"anomalyReason,omitempty"`
+}
+
+// Meter represents a physical meter owned by a user or group
+type Meter struct {
+	ID           string    `db:"id" json:"id"`
+	SubjectType  string    `db:"subject_type" json:"subjectType"` // "user" or "group"
+	SubjectID    string    `db:"subject_id" json:"subjectId"`     // user ID or group ID
+	UtilityType  string    `db:"utility_type" json:"utilityType"` // electricity, gas, water, heating, inne
+	SerialNumber *string   `db:"serial_number" json:"serialNumber,omitempty"`
+	Unit         string    `db:"unit" json:"unit"`                        // e.g. kWh, m3
+	RolloverAt   *string   `db:"rollover_at" json:"rolloverAt,omitempty"` // Decimal as string, modulus of the counter: 100000 for one showing at most 99999
+	Registers    []string  `db:"-" json:"registers,omitempty"`            // counters of a multi-register meter, e.g. day and night
+	IsActive     bool      `db:"is_active" json:"isActive"`
+	CreatedAt    time.Time `db:"created_at" json:"createdAt"`
+}
+
+// MeterReplacement records the counter behind a meter being swapped for a new one
+type MeterReplacement struct {
+	ID              string    `db:"id" json:"id"`
+	MeterID         string    `db:"meter_id" json:"meterId"`
+	ReplacedAt      time.Time `db:"replaced_at" json:"replacedAt"`
+	OldFinalValue   string    `db:"old_final_value" json:"oldFinalValue"`     // Decimal as string
+	NewInitialValue string    `db:"new_initial_value" json:"newInitialValue"` // Decimal as string
+	OldSerialNumber *string   `db:"old_serial_number" json:"oldSerialNumber,omitempty"`
+	NewSerialNumber *string   `db:"new_serial_number" json:"newSerialNumber,omitempty"`
+	Register        *string   `db:"register" json:"register,omitempty"` // register swapped on a multi-register meter
+	CreatedAt       time.Time `db:"created_at" json:"createdAt"`
+}
+
+// Tariff prices the bills of a utility by components instead of a single rate per unit
+type Tariff struct {
+	ID          string            `db:"id" json:"id"`
+	UtilityType string            `db:"utility_type" json:"utilityType"` // bill type, or the custom type of "inne" bills
+	Name        string            `db:"name" json:"name"`
+	ValidFrom   time.Time         `db:"valid_from" json:"validFrom"`
+	ValidTo     *time.Time        `db:"valid_to" json:"validTo,omitempty"`
+	Components  []TariffComponent `db:"-" json:"components"`
+	CreatedAt   time.Time         `db:"created_at" json:"createdAt"`
+}
+
+// TariffComponent is one charge of a tariff. Fixed components are an amount per bill split by
+// weight, variable components a rate per unit charged on each subject's usage.
+type TariffComponent struct {
+	Name     string  `json:"name"`
+	Kind     string  `json:"kind"`               // fixed, variable
+	Amount   *string `json:"amount,omitempty"`   // fixed: Decimal as string, amount per bill
+	Rate     *string `json:"rate,omitempty"`     // variable: Decimal as string, price per unit
+	Register *string `json:"register,omitempty"` // variable: only units of this register, all registers when empty
+	TierFrom *string `json:"tierFrom,omitempty"` // variable: units of a subject's usage before the rate applies
+	TierTo   *string `json:"tierTo,omitempty"`   // variable: units of a subject's usage after which the rate stops applying
 }
 
 // Payment represents a payment towards a bill
 type Payment struct {
-	ID          string    `db:"id" json:"id"`
-	BillID      string    `db:"bill_id" json:"billId"`
-	PayerUserID string    `db:"payer_user_id" json:"payerUserId"`
-	AmountPLN   string    `db:"amount_pln" json:"amountPLN"` // Decimal as string
-	PaidAt      time.Time `db:"paid_at" json:"paidAt"`
-	Method      *string   `db:"method" json:"method,omitempty"`
-	Reference   *string   `db:"reference" json:"reference,omitempty"`
+	ID             string    `db:"id" json:"id"`
+	BillID         string    `db:"bill_id" json:"billId"`
+	PayerUserID    string    `db:"payer_user_id" json:"payerUserId"`
+	AmountPLN      string    `db:"amount_pln" json:"amountPLN"` // Decimal as string, in the base currency
+	Currency       string    `db:"currency" json:"currency"`
+	OriginalAmount *string   `db:"original_amount" json:"originalAmount,omitempty"`
+	ExchangeRate   *string   `db:"exchange_rate" json:"exchangeRate,omitempty"`
+	PaidAt         time.Time `db:"paid_at" json:"paidAt"`
+	Method         *string   `db:"method" json:"method,omitempty"`
+	Reference      *string   `db:"reference" json:"reference,omitempty"`
 }
 
 // Loan represents money lent between users
 type Loan struct {
-	ID         string     `db:"id" json:"id"`
-	LenderID   string     `db:"lender_id" json:"lenderId"`
-	BorrowerID string     `db:"borrower_id" json:"borrowerId"`
-	AmountPLN  string     `db:"amount_pln" json:"amountPLN"` // Decimal as string
-	Note       *string    `db:"note" json:"note,omitempty"`
-	DueDate    *time.Time `db:"due_date" json:"dueDate,omitempty"`
-	Status     string     `db:"status" json:"status"` // open, partial, settled
-	CreatedAt  time.Time  `db:"created_at" json:"createdAt"`
+	ID             string     `db:"id" json:"id"`
+	LenderID       string     `db:"lender_id" json:"lenderId"`
+	BorrowerID     string     `db:"borrower_id" json:"borrowerId"`
+	AmountPLN      string     `db:"amount_pln" json:"amountPLN"` // Decimal as string, in the base currency
+	Currency       string     `db:"currency" json:"currency"`
+	OriginalAmount *string    `db:"original_amount" json:"originalAmount,omitempty"` // amount as entered, before conversion and offsetting
+	ExchangeRate   *string    `db:"exchange_rate" json:"exchangeRate,omitempty"`
+	Note           *string    `db:"note" json:"note,omitempty"`
+	DueDate        *time.Time `db:"due_date" json:"dueDate,omitempty"`
+	Status         string     `db:"status" json:"status"` // open, partial, settled
+	CreatedAt      time.Time  `db:"created_at" json:"createdAt"`
 }
 
 // LoanPayment represents a partial or full loan repayment
@@ -137,6 +233,8 @@ type LoanPayment struct {
 	AmountPLN string    `db:"amount_pln" json:"amountPLN"` // Decimal as string
 	PaidAt    time.Time `db:"paid_at" json:"paidAt"`
 	Note      *string   `db:"note" json:"note,omitempty"`
+	// Set when the loan was cleared by an applied settlement plan instead of a direct repayment
+	SettlementFingerprint *string `db:"settlement_fingerprint" json:"settlementFingerprint,omitempty"`
 }
 
 // Chore represents a household task
@@ -146,37 +244,77 @@ type Chore struct {
 	Description          *string   `db:"description" json:"description,omitempty"`
 	Frequency            string    `db:"frequency" json:"frequency"`                           // daily, weekly, monthly, custom, irregular
 	CustomInterval       *int      `db:"custom_interval" json:"customInterval,omitempty"`      // days for custom frequency
+	RecurrenceRule       *string   `db:"recurrence_rule" json:"recurrenceRule,omitempty"`      // RFC 5545 RRULE, replaces frequency when set
 	Difficulty           int       `db:"difficulty" json:"difficulty"`                         // 1-5 scale
 	Priority             int       `db:"priority" json:"priority"`                             // 1-5 scale
-	AssignmentMode       string    `db:"assignment_mode" json:"assignmentMode"`                // manual, round_robin, random
+	AssignmentMode       string    `db:"assignment_mode" json:"assignmentMode"`                // manual, round_robin, random, fair
 	ManualAssigneeID     *string   `db:"manual_assignee_id" json:"manualAssigneeId,omitempty"` // User ID for manual assignment mode
 	NotificationsEnabled bool      `db:"notifications_enabled" json:"notificationsEnabled"`
-	ReminderHours        *int      `db:"reminder_hours" json:"reminderHours,omitempty"` // hours before due
+	ReminderHours        *int      `db:"reminder_hours" json:"reminderHours,omitempty"`   // hours before due
+	OverduePolicy        string    `db:"overdue_policy" json:"overduePolicy"`             // keep, reassign
+	RequirePhoto         bool      `db:"require_photo" json:"requirePhoto"`               // completion needs a photo
+	RequireConfirmation  bool      `db:"require_confirmation" json:"requireConfirmation"` // completion needs another resident's confirmation
 	IsActive             bool      `db:"is_active" json:"isActive"`
 	CreatedAt            time.Time `db:"created_at" json:"createdAt"`
 }
 
 // ChoreAssignment represents a chore assigned to a user
 type ChoreAssignment struct {
-	ID             string     `db:"id" json:"id"`
-	ChoreID        string     `db:"chore_id" json:"choreId"`
-	AssigneeUserID string     `db:"assignee_user_id" json:"assigneeUserId"`
-	DueDate        time.Time  `db:"due_date" json:"dueDate"`
-	Status         string     `db:"status" json:"status"` // pending, in_progress, done, overdue
-	CompletedAt    *time.Time `db:"completed_at" json:"completedAt,omitempty"`
-	Points         int        `db:"points" json:"points"`       // points earned for completion
-	IsOnTime       bool       `db:"is_on_time" json:"isOnTime"` // completed before due date
+	ID               string     `db:"id" json:"id"`
+	ChoreID          string     `db:"chore_id" json:"choreId"`
+	AssigneeUserID   string     `db:"assignee_user_id" json:"assigneeUserId"`
+	DueDate          time.Time  `db:"due_date" json:"dueDate"`
+	Status           string     `db:"status" json:"status"` // pending, in_progress, awaiting_confirmation, done, overdue
+	CompletedAt      *time.Time `db:"completed_at" json:"completedAt,omitempty"`
+	Points           int        `db:"points" json:"points"`                                  // points earned for completion
+	IsOnTime         bool       `db:"is_on_time" json:"isOnTime"`                            // completed before due date
+	AssignedByUserID *string    `db:"assigned_by_user_id" json:"assignedByUserId,omitempty"` // nil for scheduled assignments
+}
+
+// ChoreCompletion is a submitted completion of a chore assignment, with its proof and review
+type ChoreCompletion struct {
+	ID                string     `db:"id" json:"id"`
+	AssignmentID      string     `db:"assignment_id" json:"assignmentId"`
+	SubmittedByUserID string     `db:"submitted_by_user_id" json:"submittedByUserId"`
+	PhotoPath         *string    `db:"photo_path" json:"photoPath,omitempty"` // relative to the photo directory
+	PhotoContentType  *string    `db:"photo_content_type" json:"photoContentType,omitempty"`
+	Status            string     `db:"status" json:"status"` // awaiting_confirmation, confirmed, disputed
+	ReviewerUserID    *string    `db:"reviewer_user_id" json:"reviewerUserId,omitempty"`
+	ReviewComment     *string    `db:"review_comment" json:"reviewComment,omitempty"`
+	SubmittedAt       time.Time  `db:"submitted_at" json:"submittedAt"`
+	ReviewedAt        *time.Time `db:"reviewed_at" json:"reviewedAt,omitempty"`
+}
+
+// UserAbsence is a period in which a user gets no chore assignments
+type UserAbsence struct {
+	ID        string    `db:"id" json:"id"`
+	UserID    string    `db:"user_id" json:"userId"`
+	StartDate time.Time `db:"start_date" json:"startDate"`
+	EndDate   time.Time `db:"end_date" json:"endDate"` // inclusive
+	Reason    *string   `db:"reason" json:"reason,omitempty"`
+	CreatedAt time.Time `db:"created_at" json:"createdAt"`
+}
+
+// ChorePreference records that a user prefers, or is excluded from, a chore
+type ChorePreference struct {
+	ID         string    `db:"id" json:"id"`
+	ChoreID    string    `db:"chore_id" json:"choreId"`
+	UserID     string    `db:"user_id" json:"userId"`
+	Preference string    `db:"preference" json:"preference"` // preferred, excluded
+	UpdatedAt  time.Time `db:"updated_at" json:"updatedAt"`
 }
 
 // ChoreSettings represents global chore system settings
 type ChoreSettings struct {
-	ID                    string    `db:"id" json:"id"`
-	DefaultAssignmentMode string    `db:"default_assignment_mode" json:"defaultAssignmentMode"` // round_robin, random, manual
-	GlobalNotifications   bool      `db:"global_notifications" json:"globalNotifications"`
-	DefaultReminderHours  int       `db:"default_reminder_hours" json:"defaultReminderHours"`
-	PointsEnabled         bool      `db:"points_enabled" json:"pointsEnabled"`
-	PointsMultiplier      float64   `db:"points_multiplier" json:"pointsMultiplier"` // base points = difficulty * multiplier
-	UpdatedAt             time.Time `db:"updated_at" json:"updatedAt"`
+	ID                     string    `db:"id" json:"id"`
+	DefaultAssignmentMode  string    `db:"default_assignment_mode" json:"defaultAssignmentMode"` // round_robin, random, manual
+	GlobalNotifications    bool      `db:"global_notifications" json:"globalNotifications"`
+	DefaultReminderHours   int       `db:"default_reminder_hours" json:"defaultReminderHours"`
+	PointsEnabled          bool      `db:"points_enabled" json:"pointsEnabled"`
+	PointsMultiplier       float64   `db:"points_multiplier" json:"pointsMultiplier"`              // base points = difficulty * multiplier
+	OverduePenalty         string    `db:"overdue_penalty" json:"overduePenalty"`                  // none, withhold, deduct
+	OverdueEscalationHours int       `db:"overdue_escalation_hours" json:"overdueEscalationHours"` // hours after the end of the due day
+	UpdatedAt              time.Time `db:"updated_at" json:"updatedAt"`
 }
 
 // ChoreSwapRequest represents a request to swap chore assignments between users
@@ -228,25 +366,40 @@ type AppSettings struct {
 	DefaultLanguage          string    `db:"default_language" json:"defaultLanguage"`                      // Default locale code (e.g., "en", "pl")
 	DisableAutoDetect        bool      `db:"disable_auto_detect" json:"disableAutoDetect"`                 // If true, always use default language
 	ReminderRateLimitPerHour int       `db:"reminder_rate_limit_per_hour" json:"reminderRateLimitPerHour"` // Max reminders per user per hour (0 = unlimited)
+	BaseCurrency             string    `db:"base_currency" json:"baseCurrency"`                            // Currency all balances are kept in (ISO 4217)
 	UpdatedAt                time.Time `db:"updated_at" json:"updatedAt"`
 }
 
+// ExchangeRate is the value of one unit of a currency in the base currency on a day
+type ExchangeRate struct {
+	ID           string    `db:"id" json:"id"`
+	Currency     string    `db:"currency" json:"currency"`
+	BaseCurrency string    `db:"base_currency" json:"baseCurrency"`
+	RateDate     time.Time `db:"rate_date" json:"rateDate"`
+	Rate         string    `db:"rate" json:"rate"`     // Decimal as string
+	Source       string    `db:"source" json:"source"` // manual, csv
+	CreatedAt    time.Time `db:"created_at" json:"createdAt"`
+}
+
 // SupplyItem represents a household supply with inventory tracking
 type SupplyItem struct {
-	ID                    string     `db:"id" json:"id"`
-	Name                  string     `db:"name" json:"name"`
-	Category              string     `db:"category" json:"category"`                // groceries, cleaning, toiletries, other
-	CurrentQuantity       int        `db:"current_quantity" json:"currentQuantity"` // How much is in stock now
-	MinQuantity           int        `db:"min_quantity" json:"minQuantity"`         // Threshold for low stock warning
-	Unit                  string     `db:"unit" json:"unit"`                        // pcs, kg, L, bottles, boxes, etc.
-	Priority              int        `db:"priority" json:"priority"`                // 1-5 (1=low, 5=urgent)
-	AddedByUserID         string     `db:"added_by_user_id" json:"addedByUserId"`
-	AddedAt               time.Time  `db:"added_at" json:"addedAt"`
-	LastRestockedAt       *time.Time `db:"last_restocked_at" json:"lastRestockedAt,omitempty"`
-	LastRestockedByUserID *string    `db:"last_restocked_by_user_id" json:"lastRestockedByUserId,omitempty"`
-	LastRestockAmountPLN  *string    `db:"last_restock_amount_pln" json:"lastRestockAmountPLN,omitempty"` // decimal as string
-	NeedsRefund           bool       `db:"needs_refund" json:"needsRefund"`                               // If last restock awaits reimbursement
-	Notes                 *string    `db:"notes" json:"notes,omitempty"`
+	ID                        string     `db:"id" json:"id"`
+	Name                      string     `db:"name" json:"name"`
+	Category                  string     `db:"category" json:"category"`                // groceries, cleaning, toiletries, other
+	CurrentQuantity           int        `db:"current_quantity" json:"currentQuantity"` // How much is in stock now
+	MinQuantity               int        `db:"min_quantity" json:"minQuantity"`         // Threshold for low stock warning
+	Unit                      string     `db:"unit" json:"unit"`                        // pcs, kg, L, bottles, boxes, etc.
+	Priority                  int        `db:"priority" json:"priority"`                // 1-5 (1=low, 5=urgent)
+	AddedByUserID             string     `db:"added_by_user_id" json:"addedByUserId"`
+	AddedAt                   time.Time  `db:"added_at" json:"addedAt"`
+	LastRestockedAt           *time.Time `db:"last_restocked_at" json:"lastRestockedAt,omitempty"`
+	LastRestockedByUserID     *string    `db:"last_restocked_by_user_id" json:"lastRestockedByUserId,omitempty"`
+	LastRestockAmountPLN      *string    `db:"last_restock_amount_pln" json:"lastRestockAmountPLN,omitempty"` // decimal as string, in the base currency
+	LastRestockCurrency       *string    `db:"last_restock_currency" json:"lastRestockCurrency,omitempty"`
+	LastRestockOriginalAmount *string    `db:"last_restock_original_amount" json:"lastRestockOriginalAmount,omitempty"`
+	LastRestockExchangeRate   *string    `db:"last_restock_exchange_rate" json:"lastRestockExchangeRate,omitempty"`
+	NeedsRefund               bool       `db:"needs_refund" json:"needsRefund"` // If last restock awaits reimbursement
+	Notes                     *string    `db:"notes" json:"notes,omitempty"`
 }
 
 // SupplyContribution represents a budget contribution
@@ -288,6 +441,16 @@ type Session struct {
 	ExpiresAt    time.Time `db:"expires_at" json:"expiresAt"`
 }
 
+// CalendarFeedToken grants read access to a user's iCalendar feed
+type CalendarFeedToken struct {
+	ID         string     `db:"id" json:"id"`
+	UserID     string     `db:"user_id" json:"userId"`
+	TokenHash  string     `db:"token_hash" json:"-"` // SHA-256 hash of the token
+	Name       string     `db:"name" json:"name"`    // User-friendly name (e.g., "Phone calendar")
+	CreatedAt  time.Time  `db:"created_at" json:"createdAt"`
+	LastUsedAt *time.Time `db:"last_used_at" json:"lastUsedAt,omitempty"`
+}
+
 // PasswordResetToken represents a password reset token for users
 type PasswordResetToken struct {
 	ID               string     `db:"id" json:"id"`
@@ -300,6 +463,20 @@ type PasswordResetToken struct {
 	CreatedByAdminID string     `db:"created_by_admin_id" json:"createdByAdminId"`
 }
 
+// IdempotencyRecord is the stored response to a mutating request sent with an Idempotency-Key header
+type IdempotencyRecord struct {
+	UserID       string     `db:"user_id" json:"userId"`
+	Key          string     `db:"idempotency_key" json:"key"`
+	Route        string     `db:"route" json:"route"`              // Method and path, e.g. "POST /api/payments"
+	RequestHash  string     `db:"request_hash" json:"requestHash"` // SHA-256 of the request body
+	StatusCode   int        `db:"status_code" json:"statusCode"`   // 0 while the first request is in progress
+	ResponseBody []byte     `db:"response_body" json:"-"`
+	ContentType  string     `db:"content_type" json:"contentType"`
+	CreatedAt    time.Time  `db:"created_at" json:"createdAt"`
+	ExpiresAt    time.Time  `db:"expires_at" json:"expiresAt"`
+	LockedUntil  *time.Time `db:"locked_until" json:"lockedUntil,omitempty"` // End of the in-progress lease, nil once the response is stored
+}
+
 // AuditLog represents a log entry for user/admin actions
 type AuditLog struct {
 	ID           string                 `db:"id" json:"id"`
@@ -348,11 +525,65 @@ type ApprovalRequest struct {
 	ResourceID   *string                `db:"resource_id" json:"resourceId,omitempty"`
 	Details      map[string]interface{} `db:"-" json:"details,omitempty"`
 	DetailsJSON  string                 `db:"details" json:"-"`     // JSON string for DB storage
-	Status       string                 `db:"status" json:"status"` // "pending", "approved", "rejected"
+	Status       string                 `db:"status" json:"status"` // "pending", "approved", "rejected", "failed"
 	ReviewedBy   *string                `db:"reviewed_by" json:"reviewedBy,omitempty"`
 	ReviewedAt   *time.Time             `db:"reviewed_at" json:"reviewedAt,omitempty"`
 	ReviewNotes  *string                `db:"review_notes" json:"reviewNotes,omitempty"`
-	CreatedAt    time.Time              `db:"created_at" json:"createdAt"`
+	// Outcome of executing the approved action
+	Result         map[string]interface{} `db:"-" json:"result,omitempty"`
+	ResultJSON     string                 `db:"result" json:"-"` // JSON string for DB storage
+	ExecutedAt     *time.Time             `db:"executed_at" json:"executedAt,omitempty"`
+	ExecutionError *string                `db:"execution_error" json:"executionError,omitempty"`
+	CreatedAt      time.Time              `db:"created_at" json:"createdAt"`
+}
+
+// ApprovalPolicy makes an action require approval before it takes effect.
+// Users whose role can review approvals are never held by a policy.
+type ApprovalPolicy struct {
+	ID           string    `db:"id" json:"id"`
+	Action       string    `db:"action" json:"action"`                         // e.g., "bill.delete"
+	Role         *string   `db:"role" json:"role,omitempty"`                   // nil applies to every role
+	MinAmountPLN *string   `db:"min_amount_pln" json:"minAmountPLN,omitempty"` // only larger amounts need approval
+	Enabled      bool      `db:"enabled" json:"enabled"`
+	IsSystem     bool      `db:"is_system" json:"isSystem"` // default policies can be disabled but not deleted
+	CreatedAt    time.Time `db:"created_at" json:"createdAt"`
+	UpdatedAt    time.Time `db:"updated_at" json:"updatedAt"`
+}
+
+// LedgerEntry is one side of a double-entry ledger transaction. The amounts of all
+// entries sharing a TransactionID sum to zero, so every account balance is the
+// sum of its entries.
+type LedgerEntry struct {
+	ID            string    `db:"id" json:"id"`
+	TransactionID string    `db:"transaction_id" json:"transactionId"`
+	AccountType   string    `db:"account_type" json:"accountType"` // user, group, household
+	AccountID     string    `db:"account_id" json:"accountId"`     // user or group ID, or household pot: bills, supplies
+	AmountPLN     string    `db:"amount_pln" json:"amountPLN"`     // positive: the account is owed more, negative: it owes more
+	SourceType    string    `db:"source_type" json:"sourceType"`   // bill, payment, loan, loan_payment, supply_contribution, supply_purchase, supply_refund, supply_adjustment, settlement_transfer
+	SourceID      string    `db:"source_id" json:"sourceId"`
+	Description   string    `db:"description" json:"description"`
+	OccurredAt    time.Time `db:"occurred_at" json:"occurredAt"`
+	CreatedAt     time.Time `db:"created_at" json:"createdAt"`
+}
+
+// SettlementTransfer is money one resident hands another to carry out an applied settlement plan
+type SettlementTransfer struct {
+	ID          string    `db:"id" json:"id"`
+	Fingerprint string    `db:"fingerprint" json:"fingerprint"`
+	FromUserID  string    `db:"from_user_id" json:"fromUserId"`
+	ToUserID    string    `db:"to_user_id" json:"toUserId"`
+	AmountPLN   string    `db:"amount_pln" json:"amountPLN"`
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
+}
+
+// SettlementBillShare is a fronted bill share an applied settlement plan cleared
+type SettlementBillShare struct {
+	ID          string    `db:"id" json:"id"`
+	Fingerprint string    `db:"fingerprint" json:"fingerprint"`
+	BillID      string    `db:"bill_id" json:"billId"`
+	UserID      string    `db:"user_id" json:"userId"`
+	AmountPLN   string    `db:"amount_pln" json:"amountPLN"` // positive: the user's share was covered, negative: the user was paid back
+	CreatedAt   time.Time `db:"created_at" json:"createdAt"`
 }
 
 // NotificationPreference represents a user's notification preferences
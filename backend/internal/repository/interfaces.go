@@ -51,6 +51,30 @@ type GroupRepository interface {
 	List(ctx context.Context) ([]models.Group, error)
 }
 
+// GroupWeightChangeRepository handles the weight history of groups
+type GroupWeightChangeRepository interface {
+	Create(ctx context.Context, change *models.GroupWeightChange) error
+	List(ctx context.Context) ([]models.GroupWeightChange, error)
+	ListByGroupID(ctx context.Context, groupID string) ([]models.GroupWeightChange, error)
+}
+
+// GroupMembershipChangeRepository handles the group membership history of users
+type GroupMembershipChangeRepository interface {
+	Create(ctx context.Context, change *models.GroupMembershipChange) error
+	List(ctx context.Context) ([]models.GroupMembershipChange, error)
+	ListByUserID(ctx context.Context, userID string) ([]models.GroupMembershipChange, error)
+}
+
+// ResidencyRepository handles the residency periods of users
+type ResidencyRepository interface {
+	Create(ctx context.Context, residency *models.Residency) error
+	GetByID(ctx context.Context, id string) (*models.Residency, error)
+	Update(ctx context.Context, residency *models.Residency) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]models.Residency, error)
+	ListByUserID(ctx context.Context, userID string) ([]models.Residency, error)
+}
+
 // BillRepository handles bill operations
 type BillRepository interface {
 	Create(ctx context.Context, bill *models.Bill) error
@@ -95,12 +119,40 @@ type ConsumptionRepository interface {
 	ListByBillID(ctx context.Context, billID string) ([]models.Consumption, error)
 	ListBySubject(ctx context.Context, subjectType, subjectID string) ([]models.Consumption, error)
 	ListFiltered(ctx context.Context, subjectType, subjectID *string, from, to *time.Time) ([]models.Consumption, error)
+	ListByMeterID(ctx context.Context, meterID string) ([]models.Consumption, error)
 	DeleteByBillID(ctx context.Context, billID string) error
 }
 
+// MeterRepository handles meter operations
+type MeterRepository interface {
+	Create(ctx context.Context, meter *models.Meter) error
+	GetByID(ctx context.Context, id string) (*models.Meter, error)
+	Update(ctx context.Context, meter *models.Meter) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]models.Meter, error)
+	ListBySubject(ctx context.Context, subjectType, subjectID string) ([]models.Meter, error)
+}
+
+// MeterReplacementRepository handles meter replacement operations
+type MeterReplacementRepository interface {
+	Create(ctx context.Context, replacement *models.MeterReplacement) error
+	List(ctx context.Context) ([]models.MeterReplacement, error)
+	ListByMeterID(ctx context.Context, meterID string) ([]models.MeterReplacement, error)
+}
+
+// TariffRepository handles tariff operations
+type TariffRepository interface {
+	Create(ctx context.Context, tariff *models.Tariff) error
+	GetByID(ctx context.Context, id string) (*models.Tariff, error)
+	Update(ctx context.Context, tariff *models.Tariff) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]models.Tariff, error)
+	ListByUtilityType(ctx context.Context, utilityType string) ([]models.Tariff, error)
+}
+
 // AllocationRepository handles bill allocation operations
 type AllocationRepository interface {
-	Create(ctx context.Context, billID, subjectType, subjectID, allocatedPLN string) error
+	Create(ctx context.Context, billID, subjectType, subjectID, allocatedPLN string, detail *string) error
 	GetByBillID(ctx context.Context, billID string) ([]Allocation, error)
 	DeleteByBillID(ctx context.Context, billID string) error
 	DeleteBySubjectID(ctx context.Context, subjectType, subjectID string) error
@@ -109,11 +161,12 @@ type AllocationRepository interface {
 
 // Allocation represents a calculated cost allocation (not in models, stored only)
 type Allocation struct {
-	ID           string `db:"id"`
-	BillID       string `db:"bill_id"`
-	SubjectType  string `db:"subject_type"`
-	SubjectID    string `db:"subject_id"`
-	AllocatedPLN string `db:"allocated_pln"`
+	ID           string  `db:"id"`
+	BillID       string  `db:"bill_id"`
+	SubjectType  string  `db:"subject_type"`
+	SubjectID    string  `db:"subject_id"`
+	AllocatedPLN string  `db:"allocated_pln"`
+	Detail       *string `db:"detail"` // JSON breakdown of the share, stored when the bill is posted
 }
 
 // PaymentRepository handles payment operations
@@ -195,6 +248,33 @@ type ChoreSwapRequestRepository interface {
 	ExpireOldRequests(ctx context.Context) error
 }
 
+// ChoreCompletionRepository handles submitted chore completions
+type ChoreCompletionRepository interface {
+	Create(ctx context.Context, completion *models.ChoreCompletion) error
+	Update(ctx context.Context, completion *models.ChoreCompletion) error
+	List(ctx context.Context) ([]models.ChoreCompletion, error)
+	ListByAssignmentID(ctx context.Context, assignmentID string) ([]models.ChoreCompletion, error)
+	GetLatestByAssignmentID(ctx context.Context, assignmentID string) (*models.ChoreCompletion, error)
+}
+
+// UserAbsenceRepository handles user absence operations
+type UserAbsenceRepository interface {
+	Create(ctx context.Context, absence *models.UserAbsence) error
+	GetByID(ctx context.Context, id string) (*models.UserAbsence, error)
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]models.UserAbsence, error)
+	ListByUserID(ctx context.Context, userID string) ([]models.UserAbsence, error)
+	ListOverlapping(ctx context.Context, from, to time.Time) ([]models.UserAbsence, error)
+}
+
+// ChorePreferenceRepository handles per-chore assignment preferences
+type ChorePreferenceRepository interface {
+	Set(ctx context.Context, preference *models.ChorePreference) error
+	Delete(ctx context.Context, choreID, userID string) error
+	List(ctx context.Context) ([]models.ChorePreference, error)
+	ListByChoreID(ctx context.Context, choreID string) ([]models.ChorePreference, error)
+}
+
 // SupplySettingsRepository handles supply settings (singleton)
 type SupplySettingsRepository interface {
 	Get(ctx context.Context) (*models.SupplySettings, error)
@@ -228,6 +308,7 @@ type SupplyItemHistoryRepository interface {
 	Create(ctx context.Context, history *models.SupplyItemHistory) error
 	ListBySupplyItemID(ctx context.Context, supplyItemID string) ([]models.SupplyItemHistory, error)
 	ListByUserID(ctx context.Context, userID string) ([]models.SupplyItemHistory, error)
+	List(ctx context.Context) ([]models.SupplyItemHistory, error)
 }
 
 // SessionRepository handles session operations
@@ -242,6 +323,16 @@ type SessionRepository interface {
 	ListByUserID(ctx context.Context, userID string) ([]models.Session, error)
 }
 
+// CalendarFeedTokenRepository handles calendar feed token operations
+type CalendarFeedTokenRepository interface {
+	Create(ctx context.Context, token *models.CalendarFeedToken) error
+	GetByID(ctx context.Context, id string) (*models.CalendarFeedToken, error)
+	GetByTokenHash(ctx context.Context, tokenHash string) (*models.CalendarFeedToken, error)
+	UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error
+	Delete(ctx context.Context, id string) error
+	ListByUserID(ctx context.Context, userID string) ([]models.CalendarFeedToken, error)
+}
+
 // PasswordResetTokenRepository handles password reset token operations
 type PasswordResetTokenRepository interface {
 	Create(ctx context.Context, token *models.PasswordResetToken) error
@@ -251,6 +342,18 @@ type PasswordResetTokenRepository interface {
 	DeleteExpired(ctx context.Context) error
 }
 
+// IdempotencyKeyRepository stores responses to requests sent with an Idempotency-Key header
+type IdempotencyKeyRepository interface {
+	// Reserve inserts an in-progress record, or replaces an expired one or one whose
+	// in-progress lease ran out. It returns false if a live record already exists for the
+	// same user, key and route.
+	Reserve(ctx context.Context, record *models.IdempotencyRecord) (bool, error)
+	Get(ctx context.Context, userID, key, route string) (*models.IdempotencyRecord, error)
+	Complete(ctx context.Context, record *models.IdempotencyRecord) error
+	Delete(ctx context.Context, userID, key, route string) error
+	DeleteExpired(ctx context.Context) error
+}
+
 // NotificationRepository handles notification operations
 type NotificationRepository interface {
 	Create(ctx context.Context, notification *models.Notification) error
@@ -268,6 +371,7 @@ type NotificationRepository interface {
 type NotificationPreferenceRepository interface {
 	GetByUserID(ctx context.Context, userID string) (*models.NotificationPreference, error)
 	Upsert(ctx context.Context, pref *models.NotificationPreference) error
+	List(ctx context.Context) ([]models.NotificationPreference, error)
 }
 
 // WebPushSubscriptionRepository handles web push subscription operations
@@ -276,6 +380,7 @@ type WebPushSubscriptionRepository interface {
 	GetByEndpoint(ctx context.Context, endpoint string) (*models.WebPushSubscription, error)
 	Delete(ctx context.Context, userID, endpoint string) error // SECURITY: userID required to prevent IDOR
 	ListByUserID(ctx context.Context, userID string) ([]models.WebPushSubscription, error)
+	List(ctx context.Context) ([]models.WebPushSubscription, error)
 }
 
 // PermissionRepository handles permission operations
@@ -303,6 +408,7 @@ type AuditLogRepository interface {
 	ListByUserID(ctx context.Context, userID string, limit int) ([]models.AuditLog, error)
 	ListByAction(ctx context.Context, action string, limit int) ([]models.AuditLog, error)
 	ListByResourceType(ctx context.Context, resourceType string, limit int) ([]models.AuditLog, error)
+	ListAll(ctx context.Context) ([]models.AuditLog, error)
 }
 
 // ApprovalRequestRepository handles approval request operations
@@ -315,6 +421,48 @@ type ApprovalRequestRepository interface {
 	ListByUserID(ctx context.Context, userID string) ([]models.ApprovalRequest, error)
 }
 
+// ApprovalPolicyRepository handles approval policies
+type ApprovalPolicyRepository interface {
+	Create(ctx context.Context, policy *models.ApprovalPolicy) error
+	GetByID(ctx context.Context, id string) (*models.ApprovalPolicy, error)
+	Update(ctx context.Context, policy *models.ApprovalPolicy) error
+	Delete(ctx context.Context, id string) error
+	List(ctx context.Context) ([]models.ApprovalPolicy, error)
+	ListByAction(ctx context.Context, action string) ([]models.ApprovalPolicy, error)
+}
+
+// LedgerEntryRepository handles the double-entry ledger. Entries are never updated or deleted.
+type LedgerEntryRepository interface {
+	// CreateBatch inserts the entries of one or more transactions in a single statement
+	CreateBatch(ctx context.Context, entries []models.LedgerEntry) error
+	List(ctx context.Context) ([]models.LedgerEntry, error)
+	ListByAccount(ctx context.Context, accountType, accountID string) ([]models.LedgerEntry, error)
+	ListBySource(ctx context.Context, sourceType, sourceID string) ([]models.LedgerEntry, error)
+	Count(ctx context.Context) (int, error)
+}
+
+// SettlementRepository handles the records of applied settlement plans
+type SettlementRepository interface {
+	CreateTransfer(ctx context.Context, transfer *models.SettlementTransfer) error
+	ListTransfers(ctx context.Context) ([]models.SettlementTransfer, error)
+	ListTransfersByFingerprint(ctx context.Context, fingerprint string) ([]models.SettlementTransfer, error)
+	CreateBillShare(ctx context.Context, share *models.SettlementBillShare) error
+	ListBillShares(ctx context.Context) ([]models.SettlementBillShare, error)
+	ListBillSharesByBillID(ctx context.Context, billID string) ([]models.SettlementBillShare, error)
+}
+
+// ExchangeRateRepository handles exchange rates to the base currency
+type ExchangeRateRepository interface {
+	// Upsert stores a rate, replacing any rate for the same currency pair and day
+	Upsert(ctx context.Context, rate *models.ExchangeRate) error
+	GetByID(ctx context.Context, id string) (*models.ExchangeRate, error)
+	// GetEffective returns the most recent rate on or before the given day
+	GetEffective(ctx context.Context, currency, baseCurrency string, date time.Time) (*models.ExchangeRate, error)
+	List(ctx context.Context, baseCurrency string) ([]models.ExchangeRate, error)
+	ListAll(ctx context.Context) ([]models.ExchangeRate, error)
+	Delete(ctx context.Context, id string) error
+}
+
 // AppSettingsRepository handles app settings (singleton)
 type AppSettingsRepository interface {
 	Get(ctx context.Context) (*models.AppSettings, error)
@@ -330,15 +478,27 @@ type SentReminderRepository interface {
 	List(ctx context.Context) ([]models.SentReminder, error)
 }
 
+// TxManager runs work atomically with repositories bound to a single database transaction
+type TxManager interface {
+	// WithTx commits if fn returns nil and rolls back otherwise
+	WithTx(ctx context.Context, fn func(ctx context.Context, repos *Repositories) error) error
+}
+
 // Repositories aggregates all repository interfaces
 type Repositories struct {
 	Users                    UserRepository
 	PasskeyCredentials       PasskeyCredentialRepository
 	Groups                   GroupRepository
+	GroupWeightChanges       GroupWeightChangeRepository
+	GroupMembershipChanges   GroupMembershipChangeRepository
+	Residencies              ResidencyRepository
 	Bills                    BillRepository
 	RecurringBillTemplates   RecurringBillTemplateRepository
 	RecurringBillAllocations RecurringBillAllocationRepository
 	Consumptions             ConsumptionRepository
+	Meters                   MeterRepository
+	MeterReplacements        MeterReplacementRepository
+	Tariffs                  TariffRepository
 	Allocations              AllocationRepository
 	Payments                 PaymentRepository
 	Loans                    LoanRepository
@@ -347,12 +507,17 @@ type Repositories struct {
 	ChoreAssignments         ChoreAssignmentRepository
 	ChoreSettings            ChoreSettingsRepository
 	ChoreSwapRequests        ChoreSwapRequestRepository
+	ChoreCompletions         ChoreCompletionRepository
+	UserAbsences             UserAbsenceRepository
+	ChorePreferences         ChorePreferenceRepository
 	SupplySettings           SupplySettingsRepository
 	SupplyItems              SupplyItemRepository
 	SupplyContributions      SupplyContributionRepository
 	SupplyItemHistory        SupplyItemHistoryRepository
 	Sessions                 SessionRepository
+	CalendarFeedTokens       CalendarFeedTokenRepository
 	PasswordResetTokens      PasswordResetTokenRepository
+	IdempotencyKeys          IdempotencyKeyRepository
 	Notifications            NotificationRepository
 	NotificationPreferences  NotificationPreferenceRepository
 	WebPushSubscriptions     WebPushSubscriptionRepository
@@ -360,6 +525,10 @@ type Repositories struct {
 	Roles                    RoleRepository
 	AuditLogs                AuditLogRepository
 	ApprovalRequests         ApprovalRequestRepository
+	ApprovalPolicies         ApprovalPolicyRepository
+	LedgerEntries            LedgerEntryRepository
+	Settlements              SettlementRepository
+	ExchangeRates            ExchangeRateRepository
 	AppSettings              AppSettingsRepository
 	SentReminders            SentReminderRepository
 }
@@ -4,35 +4,35 @@ import (
 	"context"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/repository"
 )
 
 // AllocationRow represents an allocation row in SQLite
 type AllocationRow struct {
-	ID           string `db:"id"`
-	BillID       string `db:"bill_id"`
-	SubjectType  string `db:"subject_type"`
-	SubjectID    string `db:"subject_id"`
-	AllocatedPLN string `db:"allocated_pln"`
+	ID           string  `db:"id"`
+	BillID       string  `db:"bill_id"`
+	SubjectType  string  `db:"subject_type"`
+	SubjectID    string  `db:"subject_id"`
+	AllocatedPLN string  `db:"allocated_pln"`
+	Detail       *string `db:"detail"`
 }
 
 // AllocationRepository implements repository.AllocationRepository for SQLite
 type AllocationRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewAllocationRepository creates a new SQLite allocation repository
-func NewAllocationRepository(db *sqlx.DB) *AllocationRepository {
+func NewAllocationRepository(db DBTX) *AllocationRepository {
 	return &AllocationRepository{db: db}
 }
 
 // Create creates a new allocation
-func (r *AllocationRepository) Create(ctx context.Context, billID, subjectType, subjectID, allocatedPLN string) error {
+func (r *AllocationRepository) Create(ctx context.Context, billID, subjectType, subjectID, allocatedPLN string, detail *string) error {
 	id := uuid.New().String()
 
-	query := `INSERT INTO allocations (id, bill_id, subject_type, subject_id, allocated_pln) VALUES (?, ?, ?, ?, ?)`
-	_, err := r.db.ExecContext(ctx, query, id, billID, subjectType, subjectID, allocatedPLN)
+	query := `INSERT INTO allocations (id, bill_id, subject_type, subject_id, allocated_pln, detail) VALUES (?, ?, ?, ?, ?, ?)`
+	_, err := r.db.ExecContext(ctx, query, id, billID, subjectType, subjectID, allocatedPLN, detail)
 	return err
 }
 
@@ -52,6 +52,7 @@ func (r *AllocationRepository) GetByBillID(ctx context.Context, billID string) (
 			SubjectType:  row.SubjectType,
 			SubjectID:    row.SubjectID,
 			AllocatedPLN: row.AllocatedPLN,
+			Detail:       row.Detail,
 		}
 	}
 	return allocations, nil
@@ -85,6 +86,7 @@ func (r *AllocationRepository) List(ctx context.Context) ([]repository.Allocatio
 			SubjectType:  row.SubjectType,
 			SubjectID:    row.SubjectID,
 			AllocatedPLN: row.AllocatedPLN,
+			Detail:       row.Detail,
 		}
 	}
 	return allocations, nil
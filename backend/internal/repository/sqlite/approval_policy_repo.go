@@ -0,0 +1,126 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sainaif/holy-home/internal/models"
+)
+
+// ApprovalPolicyRow represents an approval policy row in SQLite
+type ApprovalPolicyRow struct {
+	ID           string  `db:"id"`
+	Action       string  `db:"action"`
+	Role         *string `db:"role"`
+	MinAmountPLN *string `db:"min_amount_pln"`
+	Enabled      int     `db:"enabled"`
+	IsSystem     int     `db:"is_system"`
+	CreatedAt    string  `db:"created_at"`
+	UpdatedAt    string  `db:"updated_at"`
+}
+
+// ApprovalPolicyRepository implements repository.ApprovalPolicyRepository for SQLite
+type ApprovalPolicyRepository struct {
+	db DBTX
+}
+
+// NewApprovalPolicyRepository creates a new SQLite approval policy repository
+func NewApprovalPolicyRepository(db DBTX) *ApprovalPolicyRepository {
+	return &ApprovalPolicyRepository{db: db}
+}
+
+// Create creates a new approval policy
+func (r *ApprovalPolicyRepository) Create(ctx context.Context, policy *models.ApprovalPolicy) error {
+	if policy.ID == "" {
+		policy.ID = uuid.New().String()
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	query := `
+		INSERT INTO approval_policies (id, action, role, min_amount_pln, enabled, is_system, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		policy.ID,
+		policy.Action,
+		policy.Role,
+		policy.MinAmountPLN,
+		boolToInt(policy.Enabled),
+		boolToInt(policy.IsSystem),
+		now,
+		now,
+	)
+	return err
+}
+
+// GetByID retrieves an approval policy by ID
+func (r *ApprovalPolicyRepository) GetByID(ctx context.Context, id string) (*models.ApprovalPolicy, error) {
+	var row ApprovalPolicyRow
+	err := r.db.GetContext(ctx, &row, "SELECT * FROM approval_policies WHERE id = ?", id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rowToApprovalPolicy(&row), nil
+}
+
+// Update updates an existing approval policy
+func (r *ApprovalPolicyRepository) Update(ctx context.Context, policy *models.ApprovalPolicy) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	query := `UPDATE approval_policies SET role = ?, min_amount_pln = ?, enabled = ?, updated_at = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, policy.Role, policy.MinAmountPLN, boolToInt(policy.Enabled), now, policy.ID)
+	return err
+}
+
+// Delete deletes an approval policy
+func (r *ApprovalPolicyRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM approval_policies WHERE id = ?", id)
+	return err
+}
+
+// List returns all approval policies
+func (r *ApprovalPolicyRepository) List(ctx context.Context) ([]models.ApprovalPolicy, error) {
+	var rows []ApprovalPolicyRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM approval_policies ORDER BY action, created_at")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToApprovalPolicies(rows), nil
+}
+
+// ListByAction returns all approval policies for an action
+func (r *ApprovalPolicyRepository) ListByAction(ctx context.Context, action string) ([]models.ApprovalPolicy, error) {
+	var rows []ApprovalPolicyRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM approval_policies WHERE action = ? ORDER BY created_at", action)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToApprovalPolicies(rows), nil
+}
+
+func rowToApprovalPolicy(row *ApprovalPolicyRow) *models.ApprovalPolicy {
+	policy := &models.ApprovalPolicy{
+		ID:           row.ID,
+		Action:       row.Action,
+		Role:         row.Role,
+		MinAmountPLN: row.MinAmountPLN,
+		Enabled:      intToBool(row.Enabled),
+		IsSystem:     intToBool(row.IsSystem),
+	}
+	policy.CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
+	policy.UpdatedAt, _ = time.Parse(time.RFC3339, row.UpdatedAt)
+	return policy
+}
+
+func rowsToApprovalPolicies(rows []ApprovalPolicyRow) []models.ApprovalPolicy {
+	policies := make([]models.ApprovalPolicy, len(rows))
+	for i, row := range rows {
+		policies[i] = *rowToApprovalPolicy(&row)
+	}
+	return policies
+}
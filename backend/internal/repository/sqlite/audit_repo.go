@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
@@ -29,11 +28,11 @@ type AuditLogRow struct {
 
 // AuditLogRepository implements repository.AuditLogRepository for SQLite
 type AuditLogRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewAuditLogRepository creates a new SQLite audit log repository
-func NewAuditLogRepository(db *sqlx.DB) *AuditLogRepository {
+func NewAuditLogRepository(db DBTX) *AuditLogRepository {
 	return &AuditLogRepository{db: db}
 }
 
@@ -81,6 +80,16 @@ func (r *AuditLogRepository) List(ctx context.Context, limit, offset int) ([]mod
 	return rowsToAuditLogs(rows), nil
 }
 
+// ListAll returns every audit log, oldest first
+func (r *AuditLogRepository) ListAll(ctx context.Context) ([]models.AuditLog, error) {
+	var rows []AuditLogRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM audit_logs ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToAuditLogs(rows), nil
+}
+
 // ListByUserID returns audit logs by user
 func (r *AuditLogRepository) ListByUserID(ctx context.Context, userID string, limit int) ([]models.AuditLog, error) {
 	var rows []AuditLogRow
@@ -143,34 +152,41 @@ func rowsToAuditLogs(rows []AuditLogRow) []models.AuditLog {
 
 // ApprovalRequestRow represents an approval request row in SQLite
 type ApprovalRequestRow struct {
-	ID           string  `db:"id"`
-	UserID       string  `db:"user_id"`
-	UserEmail    string  `db:"user_email"`
-	UserName     string  `db:"user_name"`
-	Action       string  `db:"action"`
-	ResourceType string  `db:"resource_type"`
-	ResourceID   *string `db:"resource_id"`
-	Details      *string `db:"details"`
-	Status       string  `db:"status"`
-	ReviewedBy   *string `db:"reviewed_by"`
-	ReviewedAt   *string `db:"reviewed_at"`
-	ReviewNotes  *string `db:"review_notes"`
-	CreatedAt    string  `db:"created_at"`
+	ID             string  `db:"id"`
+	UserID         string  `db:"user_id"`
+	UserEmail      string  `db:"user_email"`
+	UserName       string  `db:"user_name"`
+	Action         string  `db:"action"`
+	ResourceType   string  `db:"resource_type"`
+	ResourceID     *string `db:"resource_id"`
+	Details        *string `db:"details"`
+	Status         string  `db:"status"`
+	ReviewedBy     *string `db:"reviewed_by"`
+	ReviewedAt     *string `db:"reviewed_at"`
+	ReviewNotes    *string `db:"review_notes"`
+	Result         *string `db:"result"`
+	ExecutedAt     *string `db:"executed_at"`
+	ExecutionError *string `db:"execution_error"`
+	CreatedAt      string  `db:"created_at"`
 }
 
 // ApprovalRequestRepository implements repository.ApprovalRequestRepository for SQLite
 type ApprovalRequestRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewApprovalRequestRepository creates a new SQLite approval request repository
-func NewApprovalRequestRepository(db *sqlx.DB) *ApprovalRequestRepository {
+func NewApprovalRequestRepository(db DBTX) *ApprovalRequestRepository {
 	return &ApprovalRequestRepository{db: db}
 }
 
 // Create creates a new approval request
 func (r *ApprovalRequestRepository) Create(ctx context.Context, request *models.ApprovalRequest) error {
-	id := uuid.New().String()
+	id := request.ID
+	if id == "" {
+		id = uuid.New().String()
+		request.ID = id
+	}
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	var details *string
@@ -221,8 +237,21 @@ func (r *ApprovalRequestRepository) Update(ctx context.Context, request *models.
 		reviewedAt = &ra
 	}
 
-	query := `UPDATE approval_requests SET status = ?, reviewed_by = ?, reviewed_at = ?, review_notes = ? WHERE id = ?`
-	_, err := r.db.ExecContext(ctx, query, request.Status, request.ReviewedBy, reviewedAt, request.ReviewNotes, request.ID)
+	var executedAt *string
+	if request.ExecutedAt != nil {
+		ea := request.ExecutedAt.UTC().Format(time.RFC3339)
+		executedAt = &ea
+	}
+
+	var result *string
+	if request.Result != nil {
+		resultJSON, _ := json.Marshal(request.Result)
+		rs := string(resultJSON)
+		result = &rs
+	}
+
+	query := `UPDATE approval_requests SET status = ?, reviewed_by = ?, reviewed_at = ?, review_notes = ?, result = ?, executed_at = ?, execution_error = ? WHERE id = ?`
+	_, err := r.db.ExecContext(ctx, query, request.Status, request.ReviewedBy, reviewedAt, request.ReviewNotes, result, executedAt, request.ExecutionError, request.ID)
 	return err
 }
 
@@ -258,16 +287,17 @@ func (r *ApprovalRequestRepository) ListByUserID(ctx context.Context, userID str
 
 func rowToApprovalRequest(row *ApprovalRequestRow) *models.ApprovalRequest {
 	request := &models.ApprovalRequest{
-		ID:           row.ID,
-		UserID:       row.UserID,
-		UserEmail:    row.UserEmail,
-		UserName:     row.UserName,
-		Action:       row.Action,
-		ResourceType: row.ResourceType,
-		ResourceID:   row.ResourceID,
-		Status:       row.Status,
-		ReviewedBy:   row.ReviewedBy,
-		ReviewNotes:  row.ReviewNotes,
+		ID:             row.ID,
+		UserID:         row.UserID,
+		UserEmail:      row.UserEmail,
+		UserName:       row.UserName,
+		Action:         row.Action,
+		ResourceType:   row.ResourceType,
+		ResourceID:     row.ResourceID,
+		Status:         row.Status,
+		ReviewedBy:     row.ReviewedBy,
+		ReviewNotes:    row.ReviewNotes,
+		ExecutionError: row.ExecutionError,
 	}
 	request.CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
 
@@ -278,6 +308,14 @@ func rowToApprovalRequest(row *ApprovalRequestRow) *models.ApprovalRequest {
 		t, _ := time.Parse(time.RFC3339, *row.ReviewedAt)
 		request.ReviewedAt = &t
 	}
+	if row.Result != nil {
+		request.ResultJSON = *row.Result
+		json.Unmarshal([]byte(*row.Result), &request.Result)
+	}
+	if row.ExecutedAt != nil {
+		t, _ := time.Parse(time.RFC3339, *row.ExecutedAt)
+		request.ExecutedAt = &t
+	}
 
 	return request
 }
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
@@ -24,11 +23,11 @@ type PasswordResetTokenRow struct {
 
 // PasswordResetTokenRepository implements repository.PasswordResetTokenRepository for SQLite
 type PasswordResetTokenRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewPasswordResetTokenRepository creates a new SQLite password reset token repository
-func NewPasswordResetTokenRepository(db *sqlx.DB) *PasswordResetTokenRepository {
+func NewPasswordResetTokenRepository(db DBTX) *PasswordResetTokenRepository {
 	return &PasswordResetTokenRepository{db: db}
 }
 
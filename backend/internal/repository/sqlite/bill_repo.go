@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
@@ -20,6 +19,9 @@ type BillRow struct {
 	PeriodEnd           string  `db:"period_end"`
 	PaymentDeadline     *string `db:"payment_deadline"`
 	TotalAmountPLN      string  `db:"total_amount_pln"`
+	Currency            string  `db:"currency"`
+	OriginalAmount      *string `db:"original_amount"`
+	ExchangeRate        *string `db:"exchange_rate"`
 	TotalUnits          *string `db:"total_units"`
 	Notes               *string `db:"notes"`
 	Status              string  `db:"status"`
@@ -32,11 +34,11 @@ type BillRow struct {
 
 // BillRepository implements repository.BillRepository for SQLite
 type BillRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewBillRepository creates a new SQLite bill repository
-func NewBillRepository(db *sqlx.DB) *BillRepository {
+func NewBillRepository(db DBTX) *BillRepository {
 	return &BillRepository{db: db}
 }
 
@@ -69,8 +71,9 @@ func (r *BillRepository) Create(ctx context.Context, bill *models.Bill) error {
 
 	query := `
 		INSERT INTO bills (id, type, custom_type, allocation_type, period_start, period_end, payment_deadline,
-			total_amount_pln, total_units, notes, status, reopened_at, reopen_reason, reopened_by, recurring_template_id, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			total_amount_pln, currency, original_amount, exchange_rate, total_units, notes, status, reopened_at,
+			reopen_reason, reopened_by, recurring_template_id, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ` + currencyOrBase + `, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -82,6 +85,9 @@ func (r *BillRepository) Create(ctx context.Context, bill *models.Bill) error {
 		bill.PeriodEnd.UTC().Format(time.RFC3339),
 		paymentDeadline,
 		bill.TotalAmountPLN,
+		bill.Currency,
+		bill.OriginalAmount,
+		bill.ExchangeRate,
 		totalUnits,
 		bill.Notes,
 		bill.Status,
@@ -129,7 +135,8 @@ func (r *BillRepository) Update(ctx context.Context, bill *models.Bill) error {
 	query := `
 		UPDATE bills SET
 			type = ?, custom_type = ?, allocation_type = ?, period_start = ?, period_end = ?, payment_deadline = ?,
-			total_amount_pln = ?, total_units = ?, notes = ?, status = ?, reopened_at = ?, reopen_reason = ?,
+			total_amount_pln = ?, currency = ` + currencyOrBase + `, original_amount = ?, exchange_rate = ?,
+			total_units = ?, notes = ?, status = ?, reopened_at = ?, reopen_reason = ?,
 			reopened_by = ?, recurring_template_id = ?
 		WHERE id = ?
 	`
@@ -142,6 +149,9 @@ func (r *BillRepository) Update(ctx context.Context, bill *models.Bill) error {
 		bill.PeriodEnd.UTC().Format(time.RFC3339),
 		paymentDeadline,
 		bill.TotalAmountPLN,
+		bill.Currency,
+		bill.OriginalAmount,
+		bill.ExchangeRate,
 		totalUnits,
 		bill.Notes,
 		bill.Status,
@@ -250,6 +260,9 @@ func rowToBill(row *BillRow) *models.Bill {
 		CustomType:          row.CustomType,
 		AllocationType:      row.AllocationType,
 		TotalAmountPLN:      row.TotalAmountPLN,
+		Currency:            row.Currency,
+		OriginalAmount:      row.OriginalAmount,
+		ExchangeRate:        row.ExchangeRate,
 		Notes:               row.Notes,
 		Status:              row.Status,
 		ReopenReason:        row.ReopenReason,
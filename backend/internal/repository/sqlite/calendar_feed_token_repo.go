@@ -0,0 +1,121 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sainaif/holy-home/internal/models"
+)
+
+// CalendarFeedTokenRow represents a calendar feed token row in SQLite
+type CalendarFeedTokenRow struct {
+	ID         string  `db:"id"`
+	UserID     string  `db:"user_id"`
+	TokenHash  string  `db:"token_hash"`
+	Name       string  `db:"name"`
+	CreatedAt  string  `db:"created_at"`
+	LastUsedAt *string `db:"last_used_at"`
+}
+
+// CalendarFeedTokenRepository implements repository.CalendarFeedTokenRepository for SQLite
+type CalendarFeedTokenRepository struct {
+	db DBTX
+}
+
+// NewCalendarFeedTokenRepository creates a new SQLite calendar feed token repository
+func NewCalendarFeedTokenRepository(db DBTX) *CalendarFeedTokenRepository {
+	return &CalendarFeedTokenRepository{db: db}
+}
+
+// Create creates a new calendar feed token
+func (r *CalendarFeedTokenRepository) Create(ctx context.Context, token *models.CalendarFeedToken) error {
+	if token.ID == "" {
+		token.ID = uuid.New().String()
+	}
+	if token.CreatedAt.IsZero() {
+		token.CreatedAt = time.Now()
+	}
+
+	query := `
+		INSERT INTO calendar_feed_tokens (id, user_id, token_hash, name, created_at)
+		VALUES (?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		token.ID,
+		token.UserID,
+		token.TokenHash,
+		token.Name,
+		token.CreatedAt.UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// GetByID retrieves a calendar feed token by ID
+func (r *CalendarFeedTokenRepository) GetByID(ctx context.Context, id string) (*models.CalendarFeedToken, error) {
+	var row CalendarFeedTokenRow
+	err := r.db.GetContext(ctx, &row, "SELECT * FROM calendar_feed_tokens WHERE id = ?", id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rowToCalendarFeedToken(&row), nil
+}
+
+// GetByTokenHash retrieves a calendar feed token by its hash
+func (r *CalendarFeedTokenRepository) GetByTokenHash(ctx context.Context, tokenHash string) (*models.CalendarFeedToken, error) {
+	var row CalendarFeedTokenRow
+	err := r.db.GetContext(ctx, &row, "SELECT * FROM calendar_feed_tokens WHERE token_hash = ?", tokenHash)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rowToCalendarFeedToken(&row), nil
+}
+
+// UpdateLastUsed records when the feed was last fetched with the token
+func (r *CalendarFeedTokenRepository) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, "UPDATE calendar_feed_tokens SET last_used_at = ? WHERE id = ?",
+		lastUsedAt.UTC().Format(time.RFC3339), id)
+	return err
+}
+
+// Delete deletes a calendar feed token
+func (r *CalendarFeedTokenRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM calendar_feed_tokens WHERE id = ?", id)
+	return err
+}
+
+// ListByUserID returns all calendar feed tokens of a user
+func (r *CalendarFeedTokenRepository) ListByUserID(ctx context.Context, userID string) ([]models.CalendarFeedToken, error) {
+	var rows []CalendarFeedTokenRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM calendar_feed_tokens WHERE user_id = ? ORDER BY created_at DESC", userID)
+	if err != nil {
+		return nil, err
+	}
+	tokens := make([]models.CalendarFeedToken, len(rows))
+	for i, row := range rows {
+		tokens[i] = *rowToCalendarFeedToken(&row)
+	}
+	return tokens, nil
+}
+
+func rowToCalendarFeedToken(row *CalendarFeedTokenRow) *models.CalendarFeedToken {
+	token := &models.CalendarFeedToken{
+		ID:        row.ID,
+		UserID:    row.UserID,
+		TokenHash: row.TokenHash,
+		Name:      row.Name,
+	}
+	token.CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
+	if row.LastUsedAt != nil {
+		t, _ := time.Parse(time.RFC3339, *row.LastUsedAt)
+		token.LastUsedAt = &t
+	}
+	return token
+}
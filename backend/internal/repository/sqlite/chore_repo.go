@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"time"
 
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
@@ -16,23 +15,27 @@ type ChoreRow struct {
 	Description          *string `db:"description"`
 	Frequency            string  `db:"frequency"`
 	CustomInterval       *int    `db:"custom_interval"`
+	RecurrenceRule       *string `db:"recurrence_rule"`
 	Difficulty           int     `db:"difficulty"`
 	Priority             int     `db:"priority"`
 	AssignmentMode       string  `db:"assignment_mode"`
 	ManualAssigneeID     *string `db:"manual_assignee_id"`
 	NotificationsEnabled int     `db:"notifications_enabled"`
 	ReminderHours        *int    `db:"reminder_hours"`
+	OverduePolicy        string  `db:"overdue_policy"`
+	RequirePhoto         int     `db:"require_photo"`
+	RequireConfirmation  int     `db:"require_confirmation"`
 	IsActive             int     `db:"is_active"`
 	CreatedAt            string  `db:"created_at"`
 }
 
 // ChoreRepository implements repository.ChoreRepository for SQLite
 type ChoreRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewChoreRepository creates a new SQLite chore repository
-func NewChoreRepository(db *sqlx.DB) *ChoreRepository {
+func NewChoreRepository(db DBTX) *ChoreRepository {
 	return &ChoreRepository{db: db}
 }
 
@@ -41,9 +44,10 @@ func (r *ChoreRepository) Create(ctx context.Context, chore *models.Chore) error
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	query := `
-		INSERT INTO chores (id, name, description, frequency, custom_interval, difficulty, priority,
-			assignment_mode, manual_assignee_id, notifications_enabled, reminder_hours, is_active, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO chores (id, name, description, frequency, custom_interval, recurrence_rule, difficulty, priority,
+			assignment_mode, manual_assignee_id, notifications_enabled, reminder_hours, overdue_policy,
+			require_photo, require_confirmation, is_active, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -52,12 +56,16 @@ func (r *ChoreRepository) Create(ctx context.Context, chore *models.Chore) error
 		chore.Description,
 		chore.Frequency,
 		chore.CustomInterval,
+		chore.RecurrenceRule,
 		chore.Difficulty,
 		chore.Priority,
 		chore.AssignmentMode,
 		chore.ManualAssigneeID,
 		boolToInt(chore.NotificationsEnabled),
 		chore.ReminderHours,
+		chore.OverduePolicy,
+		boolToInt(chore.RequirePhoto),
+		boolToInt(chore.RequireConfirmation),
 		boolToInt(chore.IsActive),
 		now,
 	)
@@ -81,8 +89,9 @@ func (r *ChoreRepository) GetByID(ctx context.Context, id string) (*models.Chore
 func (r *ChoreRepository) Update(ctx context.Context, chore *models.Chore) error {
 	query := `
 		UPDATE chores SET
-			name = ?, description = ?, frequency = ?, custom_interval = ?, difficulty = ?, priority = ?,
-			assignment_mode = ?, manual_assignee_id = ?, notifications_enabled = ?, reminder_hours = ?, is_active = ?
+			name = ?, description = ?, frequency = ?, custom_interval = ?, recurrence_rule = ?, difficulty = ?, priority = ?,
+			assignment_mode = ?, manual_assignee_id = ?, notifications_enabled = ?, reminder_hours = ?, overdue_policy = ?,
+			require_photo = ?, require_confirmation = ?, is_active = ?
 		WHERE id = ?
 	`
 
@@ -91,12 +100,16 @@ func (r *ChoreRepository) Update(ctx context.Context, chore *models.Chore) error
 		chore.Description,
 		chore.Frequency,
 		chore.CustomInterval,
+		chore.RecurrenceRule,
 		chore.Difficulty,
 		chore.Priority,
 		chore.AssignmentMode,
 		chore.ManualAssigneeID,
 		boolToInt(chore.NotificationsEnabled),
 		chore.ReminderHours,
+		chore.OverduePolicy,
+		boolToInt(chore.RequirePhoto),
+		boolToInt(chore.RequireConfirmation),
 		boolToInt(chore.IsActive),
 		chore.ID,
 	)
@@ -136,12 +149,16 @@ func rowToChore(row *ChoreRow) *models.Chore {
 		Description:          row.Description,
 		Frequency:            row.Frequency,
 		CustomInterval:       row.CustomInterval,
+		RecurrenceRule:       row.RecurrenceRule,
 		Difficulty:           row.Difficulty,
 		Priority:             row.Priority,
 		AssignmentMode:       row.AssignmentMode,
 		ManualAssigneeID:     row.ManualAssigneeID,
 		NotificationsEnabled: intToBool(row.NotificationsEnabled),
 		ReminderHours:        row.ReminderHours,
+		OverduePolicy:        row.OverduePolicy,
+		RequirePhoto:         intToBool(row.RequirePhoto),
+		RequireConfirmation:  intToBool(row.RequireConfirmation),
 		IsActive:             intToBool(row.IsActive),
 	}
 	chore.CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
@@ -158,23 +175,24 @@ func rowsToChores(rows []ChoreRow) []models.Chore {
 
 // ChoreAssignmentRow represents a chore assignment row in SQLite
 type ChoreAssignmentRow struct {
-	ID             string  `db:"id"`
-	ChoreID        string  `db:"chore_id"`
-	AssigneeUserID string  `db:"assignee_user_id"`
-	DueDate        string  `db:"due_date"`
-	Status         string  `db:"status"`
-	CompletedAt    *string `db:"completed_at"`
-	Points         int     `db:"points"`
-	IsOnTime       int     `db:"is_on_time"`
+	ID               string  `db:"id"`
+	ChoreID          string  `db:"chore_id"`
+	AssigneeUserID   string  `db:"assignee_user_id"`
+	DueDate          string  `db:"due_date"`
+	Status           string  `db:"status"`
+	CompletedAt      *string `db:"completed_at"`
+	Points           int     `db:"points"`
+	IsOnTime         int     `db:"is_on_time"`
+	AssignedByUserID *string `db:"assigned_by_user_id"`
 }
 
 // ChoreAssignmentRepository implements repository.ChoreAssignmentRepository for SQLite
 type ChoreAssignmentRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewChoreAssignmentRepository creates a new SQLite chore assignment repository
-func NewChoreAssignmentRepository(db *sqlx.DB) *ChoreAssignmentRepository {
+func NewChoreAssignmentRepository(db DBTX) *ChoreAssignmentRepository {
 	return &ChoreAssignmentRepository{db: db}
 }
 
@@ -187,8 +205,8 @@ func (r *ChoreAssignmentRepository) Create(ctx context.Context, assignment *mode
 	}
 
 	query := `
-		INSERT INTO chore_assignments (id, chore_id, assignee_user_id, due_date, status, completed_at, points, is_on_time)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO chore_assignments (id, chore_id, assignee_user_id, due_date, status, completed_at, points, is_on_time, assigned_by_user_id)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -200,6 +218,7 @@ func (r *ChoreAssignmentRepository) Create(ctx context.Context, assignment *mode
 		completedAt,
 		assignment.Points,
 		boolToInt(assignment.IsOnTime),
+		assignment.AssignedByUserID,
 	)
 	return err
 }
@@ -341,12 +360,13 @@ func (r *ChoreAssignmentRepository) ListFiltered(ctx context.Context, assigneeID
 
 func rowToChoreAssignment(row *ChoreAssignmentRow) *models.ChoreAssignment {
 	assignment := &models.ChoreAssignment{
-		ID:             row.ID,
-		ChoreID:        row.ChoreID,
-		AssigneeUserID: row.AssigneeUserID,
-		Status:         row.Status,
-		Points:         row.Points,
-		IsOnTime:       intToBool(row.IsOnTime),
+		ID:               row.ID,
+		ChoreID:          row.ChoreID,
+		AssigneeUserID:   row.AssigneeUserID,
+		Status:           row.Status,
+		Points:           row.Points,
+		IsOnTime:         intToBool(row.IsOnTime),
+		AssignedByUserID: row.AssignedByUserID,
 	}
 	assignment.DueDate, _ = time.Parse(time.RFC3339, row.DueDate)
 	if row.CompletedAt != nil {
@@ -366,22 +386,24 @@ func rowsToChoreAssignments(rows []ChoreAssignmentRow) []models.ChoreAssignment
 
 // ChoreSettingsRow represents chore settings row in SQLite
 type ChoreSettingsRow struct {
-	ID                    string  `db:"id"`
-	DefaultAssignmentMode string  `db:"default_assignment_mode"`
-	GlobalNotifications   int     `db:"global_notifications"`
-	DefaultReminderHours  int     `db:"default_reminder_hours"`
-	PointsEnabled         int     `db:"points_enabled"`
-	PointsMultiplier      float64 `db:"points_multiplier"`
-	UpdatedAt             string  `db:"updated_at"`
+	ID                     string  `db:"id"`
+	DefaultAssignmentMode  string  `db:"default_assignment_mode"`
+	GlobalNotifications    int     `db:"global_notifications"`
+	DefaultReminderHours   int     `db:"default_reminder_hours"`
+	PointsEnabled          int     `db:"points_enabled"`
+	PointsMultiplier       float64 `db:"points_multiplier"`
+	UpdatedAt              string  `db:"updated_at"`
+	OverduePenalty         string  `db:"overdue_penalty"`
+	OverdueEscalationHours int     `db:"overdue_escalation_hours"`
 }
 
 // ChoreSettingsRepository implements repository.ChoreSettingsRepository for SQLite
 type ChoreSettingsRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewChoreSettingsRepository creates a new SQLite chore settings repository
-func NewChoreSettingsRepository(db *sqlx.DB) *ChoreSettingsRepository {
+func NewChoreSettingsRepository(db DBTX) *ChoreSettingsRepository {
 	return &ChoreSettingsRepository{db: db}
 }
 
@@ -404,14 +426,16 @@ func (r *ChoreSettingsRepository) Upsert(ctx context.Context, settings *models.C
 
 	query := `
 		INSERT INTO chore_settings (id, default_assignment_mode, global_notifications, default_reminder_hours,
-			points_enabled, points_multiplier, updated_at)
-		VALUES ('singleton', ?, ?, ?, ?, ?, ?)
+			points_enabled, points_multiplier, overdue_penalty, overdue_escalation_hours, updated_at)
+		VALUES ('singleton', ?, ?, ?, ?, ?, ?, ?, ?)
 		ON CONFLICT(id) DO UPDATE SET
 			default_assignment_mode = excluded.default_assignment_mode,
 			global_notifications = excluded.global_notifications,
 			default_reminder_hours = excluded.default_reminder_hours,
 			points_enabled = excluded.points_enabled,
 			points_multiplier = excluded.points_multiplier,
+			overdue_penalty = excluded.overdue_penalty,
+			overdue_escalation_hours = excluded.overdue_escalation_hours,
 			updated_at = excluded.updated_at
 	`
 
@@ -421,6 +445,8 @@ func (r *ChoreSettingsRepository) Upsert(ctx context.Context, settings *models.C
 		settings.DefaultReminderHours,
 		boolToInt(settings.PointsEnabled),
 		settings.PointsMultiplier,
+		settings.OverduePenalty,
+		settings.OverdueEscalationHours,
 		now,
 	)
 	return err
@@ -428,12 +454,14 @@ func (r *ChoreSettingsRepository) Upsert(ctx context.Context, settings *models.C
 
 func rowToChoreSettings(row *ChoreSettingsRow) *models.ChoreSettings {
 	settings := &models.ChoreSettings{
-		ID:                    row.ID,
-		DefaultAssignmentMode: row.DefaultAssignmentMode,
-		GlobalNotifications:   intToBool(row.GlobalNotifications),
-		DefaultReminderHours:  row.DefaultReminderHours,
-		PointsEnabled:         intToBool(row.PointsEnabled),
-		PointsMultiplier:      row.PointsMultiplier,
+		ID:                     row.ID,
+		DefaultAssignmentMode:  row.DefaultAssignmentMode,
+		GlobalNotifications:    intToBool(row.GlobalNotifications),
+		DefaultReminderHours:   row.DefaultReminderHours,
+		PointsEnabled:          intToBool(row.PointsEnabled),
+		PointsMultiplier:       row.PointsMultiplier,
+		OverduePenalty:         row.OverduePenalty,
+		OverdueEscalationHours: row.OverdueEscalationHours,
 	}
 	settings.UpdatedAt, _ = time.Parse(time.RFC3339, row.UpdatedAt)
 	return settings
@@ -456,11 +484,11 @@ type ChoreSwapRequestRow struct {
 
 // ChoreSwapRequestRepository implements repository.ChoreSwapRequestRepository for SQLite
 type ChoreSwapRequestRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewChoreSwapRequestRepository creates a new SQLite chore swap request repository
-func NewChoreSwapRequestRepository(db *sqlx.DB) *ChoreSwapRequestRepository {
+func NewChoreSwapRequestRepository(db DBTX) *ChoreSwapRequestRepository {
 	return &ChoreSwapRequestRepository{db: db}
 }
 
@@ -624,3 +652,327 @@ func rowsToChoreSwapRequests(rows []ChoreSwapRequestRow) []models.ChoreSwapReque
 	}
 	return requests
 }
+
+// ChoreCompletionRow represents a chore completion row in SQLite
+type ChoreCompletionRow struct {
+	ID                string  `db:"id"`
+	AssignmentID      string  `db:"assignment_id"`
+	SubmittedByUserID string  `db:"submitted_by_user_id"`
+	PhotoPath         *string `db:"photo_path"`
+	PhotoContentType  *string `db:"photo_content_type"`
+	Status            string  `db:"status"`
+	ReviewerUserID    *string `db:"reviewer_user_id"`
+	ReviewComment     *string `db:"review_comment"`
+	SubmittedAt       string  `db:"submitted_at"`
+	ReviewedAt        *string `db:"reviewed_at"`
+}
+
+// ChoreCompletionRepository implements repository.ChoreCompletionRepository for SQLite
+type ChoreCompletionRepository struct {
+	db DBTX
+}
+
+// NewChoreCompletionRepository creates a new SQLite chore completion repository
+func NewChoreCompletionRepository(db DBTX) *ChoreCompletionRepository {
+	return &ChoreCompletionRepository{db: db}
+}
+
+// Create creates a new chore completion
+func (r *ChoreCompletionRepository) Create(ctx context.Context, completion *models.ChoreCompletion) error {
+	var reviewedAt *string
+	if completion.ReviewedAt != nil {
+		ra := completion.ReviewedAt.UTC().Format(time.RFC3339)
+		reviewedAt = &ra
+	}
+
+	query := `
+		INSERT INTO chore_completions (id, assignment_id, submitted_by_user_id, photo_path, photo_content_type,
+			status, reviewer_user_id, review_comment, submitted_at, reviewed_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		completion.ID,
+		completion.AssignmentID,
+		completion.SubmittedByUserID,
+		completion.PhotoPath,
+		completion.PhotoContentType,
+		completion.Status,
+		completion.ReviewerUserID,
+		completion.ReviewComment,
+		completion.SubmittedAt.UTC().Format(time.RFC3339),
+		reviewedAt,
+	)
+	return err
+}
+
+// Update records the review of a chore completion
+func (r *ChoreCompletionRepository) Update(ctx context.Context, completion *models.ChoreCompletion) error {
+	var reviewedAt *string
+	if completion.ReviewedAt != nil {
+		ra := completion.ReviewedAt.UTC().Format(time.RFC3339)
+		reviewedAt = &ra
+	}
+
+	query := `
+		UPDATE chore_completions SET
+			status = ?, reviewer_user_id = ?, review_comment = ?, reviewed_at = ?
+		WHERE id = ?
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		completion.Status,
+		completion.ReviewerUserID,
+		completion.ReviewComment,
+		reviewedAt,
+		completion.ID,
+	)
+	return err
+}
+
+// List returns all chore completions
+func (r *ChoreCompletionRepository) List(ctx context.Context) ([]models.ChoreCompletion, error) {
+	var rows []ChoreCompletionRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM chore_completions ORDER BY submitted_at")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToChoreCompletions(rows), nil
+}
+
+// ListByAssignmentID returns the completions submitted for an assignment, oldest first
+func (r *ChoreCompletionRepository) ListByAssignmentID(ctx context.Context, assignmentID string) ([]models.ChoreCompletion, error) {
+	var rows []ChoreCompletionRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM chore_completions WHERE assignment_id = ? ORDER BY submitted_at, rowid", assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToChoreCompletions(rows), nil
+}
+
+// GetLatestByAssignmentID returns the most recent completion submitted for an assignment
+func (r *ChoreCompletionRepository) GetLatestByAssignmentID(ctx context.Context, assignmentID string) (*models.ChoreCompletion, error) {
+	var row ChoreCompletionRow
+	err := r.db.GetContext(ctx, &row,
+		"SELECT * FROM chore_completions WHERE assignment_id = ? ORDER BY submitted_at DESC, rowid DESC LIMIT 1", assignmentID)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rowToChoreCompletion(&row), nil
+}
+
+func rowToChoreCompletion(row *ChoreCompletionRow) *models.ChoreCompletion {
+	completion := &models.ChoreCompletion{
+		ID:                row.ID,
+		AssignmentID:      row.AssignmentID,
+		SubmittedByUserID: row.SubmittedByUserID,
+		PhotoPath:         row.PhotoPath,
+		PhotoContentType:  row.PhotoContentType,
+		Status:            row.Status,
+		ReviewerUserID:    row.ReviewerUserID,
+		ReviewComment:     row.ReviewComment,
+	}
+	completion.SubmittedAt, _ = time.Parse(time.RFC3339, row.SubmittedAt)
+	if row.ReviewedAt != nil {
+		t, _ := time.Parse(time.RFC3339, *row.ReviewedAt)
+		completion.ReviewedAt = &t
+	}
+	return completion
+}
+
+func rowsToChoreCompletions(rows []ChoreCompletionRow) []models.ChoreCompletion {
+	completions := make([]models.ChoreCompletion, len(rows))
+	for i, row := range rows {
+		completions[i] = *rowToChoreCompletion(&row)
+	}
+	return completions
+}
+
+// UserAbsenceRow represents a user absence row in SQLite
+type UserAbsenceRow struct {
+	ID        string  `db:"id"`
+	UserID    string  `db:"user_id"`
+	StartDate string  `db:"start_date"`
+	EndDate   string  `db:"end_date"`
+	Reason    *string `db:"reason"`
+	CreatedAt string  `db:"created_at"`
+}
+
+// UserAbsenceRepository implements repository.UserAbsenceRepository for SQLite
+type UserAbsenceRepository struct {
+	db DBTX
+}
+
+// NewUserAbsenceRepository creates a new SQLite user absence repository
+func NewUserAbsenceRepository(db DBTX) *UserAbsenceRepository {
+	return &UserAbsenceRepository{db: db}
+}
+
+// Create creates a new user absence
+func (r *UserAbsenceRepository) Create(ctx context.Context, absence *models.UserAbsence) error {
+	query := `
+		INSERT INTO user_absences (id, user_id, start_date, end_date, reason, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		absence.ID,
+		absence.UserID,
+		absence.StartDate.UTC().Format(time.RFC3339),
+		absence.EndDate.UTC().Format(time.RFC3339),
+		absence.Reason,
+		absence.CreatedAt.UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// GetByID retrieves a user absence by ID
+func (r *UserAbsenceRepository) GetByID(ctx context.Context, id string) (*models.UserAbsence, error) {
+	var row UserAbsenceRow
+	err := r.db.GetContext(ctx, &row, "SELECT * FROM user_absences WHERE id = ?", id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rowToUserAbsence(&row), nil
+}
+
+// Delete deletes a user absence
+func (r *UserAbsenceRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM user_absences WHERE id = ?", id)
+	return err
+}
+
+// List returns all user absences
+func (r *UserAbsenceRepository) List(ctx context.Context) ([]models.UserAbsence, error) {
+	var rows []UserAbsenceRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM user_absences ORDER BY start_date")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToUserAbsences(rows), nil
+}
+
+// ListByUserID returns the absences of a user
+func (r *UserAbsenceRepository) ListByUserID(ctx context.Context, userID string) ([]models.UserAbsence, error) {
+	var rows []UserAbsenceRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM user_absences WHERE user_id = ? ORDER BY start_date", userID)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToUserAbsences(rows), nil
+}
+
+// ListOverlapping returns absences that include at least one day between from and to
+func (r *UserAbsenceRepository) ListOverlapping(ctx context.Context, from, to time.Time) ([]models.UserAbsence, error) {
+	var rows []UserAbsenceRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM user_absences WHERE start_date <= ? AND end_date >= ? ORDER BY start_date",
+		to.UTC().Format(time.RFC3339), from.UTC().Format(time.RFC3339))
+	if err != nil {
+		return nil, err
+	}
+	return rowsToUserAbsences(rows), nil
+}
+
+func rowToUserAbsence(row *UserAbsenceRow) *models.UserAbsence {
+	absence := &models.UserAbsence{
+		ID:     row.ID,
+		UserID: row.UserID,
+		Reason: row.Reason,
+	}
+	absence.StartDate, _ = time.Parse(time.RFC3339, row.StartDate)
+	absence.EndDate, _ = time.Parse(time.RFC3339, row.EndDate)
+	absence.CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
+	return absence
+}
+
+func rowsToUserAbsences(rows []UserAbsenceRow) []models.UserAbsence {
+	absences := make([]models.UserAbsence, len(rows))
+	for i, row := range rows {
+		absences[i] = *rowToUserAbsence(&row)
+	}
+	return absences
+}
+
+// ChorePreferenceRow represents a chore preference row in SQLite
+type ChorePreferenceRow struct {
+	ID         string `db:"id"`
+	ChoreID    string `db:"chore_id"`
+	UserID     string `db:"user_id"`
+	Preference string `db:"preference"`
+	UpdatedAt  string `db:"updated_at"`
+}
+
+// ChorePreferenceRepository implements repository.ChorePreferenceRepository for SQLite
+type ChorePreferenceRepository struct {
+	db DBTX
+}
+
+// NewChorePreferenceRepository creates a new SQLite chore preference repository
+func NewChorePreferenceRepository(db DBTX) *ChorePreferenceRepository {
+	return &ChorePreferenceRepository{db: db}
+}
+
+// Set creates or replaces the user's preference for a chore
+func (r *ChorePreferenceRepository) Set(ctx context.Context, preference *models.ChorePreference) error {
+	query := `
+		INSERT INTO chore_preferences (id, chore_id, user_id, preference, updated_at)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(chore_id, user_id) DO UPDATE SET
+			preference = excluded.preference,
+			updated_at = excluded.updated_at
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		preference.ID,
+		preference.ChoreID,
+		preference.UserID,
+		preference.Preference,
+		preference.UpdatedAt.UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// Delete removes the user's preference for a chore
+func (r *ChorePreferenceRepository) Delete(ctx context.Context, choreID, userID string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM chore_preferences WHERE chore_id = ? AND user_id = ?", choreID, userID)
+	return err
+}
+
+// List returns all chore preferences
+func (r *ChorePreferenceRepository) List(ctx context.Context) ([]models.ChorePreference, error) {
+	var rows []ChorePreferenceRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM chore_preferences")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToChorePreferences(rows), nil
+}
+
+// ListByChoreID returns the preferences recorded for a chore
+func (r *ChorePreferenceRepository) ListByChoreID(ctx context.Context, choreID string) ([]models.ChorePreference, error) {
+	var rows []ChorePreferenceRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM chore_preferences WHERE chore_id = ?", choreID)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToChorePreferences(rows), nil
+}
+
+func rowsToChorePreferences(rows []ChorePreferenceRow) []models.ChorePreference {
+	preferences := make([]models.ChorePreference, len(rows))
+	for i, row := range rows {
+		preferences[i] = models.ChorePreference{
+			ID:         row.ID,
+			ChoreID:    row.ChoreID,
+			UserID:     row.UserID,
+			Preference: row.Preference,
+		}
+		preferences[i].UpdatedAt, _ = time.Parse(time.RFC3339, row.UpdatedAt)
+	}
+	return preferences
+}
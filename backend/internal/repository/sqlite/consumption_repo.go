@@ -6,41 +6,54 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
 // ConsumptionRow represents a consumption row in SQLite
 type ConsumptionRow struct {
-	ID          string  `db:"id"`
-	BillID      string  `db:"bill_id"`
-	SubjectType string  `db:"subject_type"`
-	SubjectID   string  `db:"subject_id"`
-	Units       string  `db:"units"`
-	MeterValue  *string `db:"meter_value"`
-	RecordedAt  string  `db:"recorded_at"`
-	Source      string  `db:"source"`
+	ID            string  `db:"id"`
+	BillID        string  `db:"bill_id"`
+	SubjectType   string  `db:"subject_type"`
+	SubjectID     string  `db:"subject_id"`
+	Units         string  `db:"units"`
+	MeterValue    *string `db:"meter_value"`
+	RecordedAt    string  `db:"recorded_at"`
+	Source        string  `db:"source"`
+	MeterID       *string `db:"meter_id"`
+	AnomalyStatus string  `db:"anomaly_status"`
+	AnomalyReason *string `db:"anomaly_reason"`
+	Register      *string `db:"register"`
 }
 
 // ConsumptionRepository implements repository.ConsumptionRepository for SQLite
 type ConsumptionRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewConsumptionRepository creates a new SQLite consumption repository
-func NewConsumptionRepository(db *sqlx.DB) *ConsumptionRepository {
+func NewConsumptionRepository(db DBTX) *ConsumptionRepository {
 	return &ConsumptionRepository{db: db}
 }
 
 // Create creates a new consumption
 func (r *ConsumptionRepository) Create(ctx context.Context, consumption *models.Consumption) error {
-	id := uuid.New().String()
+	// Use the ID from consumption if set, otherwise generate a new one
+	id := consumption.ID
+	if id == "" {
+		id = uuid.New().String()
+		consumption.ID = id
+	}
 
 	query := `
-		INSERT INTO consumptions (id, bill_id, subject_type, subject_id, units, meter_value, recorded_at, source)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO consumptions (id, bill_id, subject_type, subject_id, units, meter_value, meter_id, recorded_at, source, anomaly_status, anomaly_reason, register)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
+	anomalyStatus := consumption.AnomalyStatus
+	if anomalyStatus == "" {
+		anomalyStatus = "none"
+	}
+
 	_, err := r.db.ExecContext(ctx, query,
 		id,
 		consumption.BillID,
@@ -48,8 +61,12 @@ func (r *ConsumptionRepository) Create(ctx context.Context, consumption *models.
 		consumption.SubjectID,
 		consumption.Units,
 		consumption.MeterValue,
+		consumption.MeterID,
 		consumption.RecordedAt.UTC().Format(time.RFC3339),
 		consumption.Source,
+		anomalyStatus,
+		consumption.AnomalyReason,
+		consumption.Register,
 	)
 	return err
 }
@@ -71,7 +88,8 @@ func (r *ConsumptionRepository) GetByID(ctx context.Context, id string) (*models
 func (r *ConsumptionRepository) Update(ctx context.Context, consumption *models.Consumption) error {
 	query := `
 		UPDATE consumptions SET
-			bill_id = ?, subject_type = ?, subject_id = ?, units = ?, meter_value = ?, recorded_at = ?, source = ?
+			bill_id = ?, subject_type = ?, subject_id = ?, units = ?, meter_value = ?, meter_id = ?, recorded_at = ?, source = ?,
+			anomaly_status = ?, anomaly_reason = ?, register = ?
 		WHERE id = ?
 	`
 
@@ -81,8 +99,12 @@ func (r *ConsumptionRepository) Update(ctx context.Context, consumption *models.
 		consumption.SubjectID,
 		consumption.Units,
 		consumption.MeterValue,
+		consumption.MeterID,
 		consumption.RecordedAt.UTC().Format(time.RFC3339),
 		consumption.Source,
+		consumption.AnomalyStatus,
+		consumption.AnomalyReason,
+		consumption.Register,
 		consumption.ID,
 	)
 	return err
@@ -158,6 +180,17 @@ func (r *ConsumptionRepository) ListFiltered(ctx context.Context, subjectType, s
 	return rowsToConsumptions(rows), nil
 }
 
+// ListByMeterID returns the readings of a meter
+func (r *ConsumptionRepository) ListByMeterID(ctx context.Context, meterID string) ([]models.Consumption, error) {
+	var rows []ConsumptionRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM consumptions WHERE meter_id = ? ORDER BY recorded_at DESC", meterID)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToConsumptions(rows), nil
+}
+
 // DeleteByBillID deletes all consumptions for a bill
 func (r *ConsumptionRepository) DeleteByBillID(ctx context.Context, billID string) error {
 	_, err := r.db.ExecContext(ctx, "DELETE FROM consumptions WHERE bill_id = ?", billID)
@@ -166,13 +199,17 @@ func (r *ConsumptionRepository) DeleteByBillID(ctx context.Context, billID strin
 
 func rowToConsumption(row *ConsumptionRow) *models.Consumption {
 	consumption := &models.Consumption{
-		ID:          row.ID,
-		BillID:      row.BillID,
-		SubjectType: row.SubjectType,
-		SubjectID:   row.SubjectID,
-		Units:       row.Units,
-		MeterValue:  row.MeterValue,
-		Source:      row.Source,
+		ID:            row.ID,
+		BillID:        row.BillID,
+		SubjectType:   row.SubjectType,
+		SubjectID:     row.SubjectID,
+		Units:         row.Units,
+		MeterValue:    row.MeterValue,
+		MeterID:       row.MeterID,
+		Source:        row.Source,
+		AnomalyStatus: row.AnomalyStatus,
+		AnomalyReason: row.AnomalyReason,
+		Register:      row.Register,
 	}
 
 	consumption.RecordedAt, _ = time.Parse(time.RFC3339, row.RecordedAt)
@@ -0,0 +1,151 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sainaif/holy-home/internal/models"
+)
+
+// ExchangeRateRow represents an exchange rate row in SQLite
+type ExchangeRateRow struct {
+	ID           string `db:"id"`
+	Currency     string `db:"currency"`
+	BaseCurrency string `db:"base_currency"`
+	RateDate     string `db:"rate_date"`
+	Rate         string `db:"rate"`
+	Source       string `db:"source"`
+	CreatedAt    string `db:"created_at"`
+}
+
+// ExchangeRateRepository implements repository.ExchangeRateRepository for SQLite
+type ExchangeRateRepository struct {
+	db DBTX
+}
+
+// NewExchangeRateRepository creates a new SQLite exchange rate repository
+func NewExchangeRateRepository(db DBTX) *ExchangeRateRepository {
+	return &ExchangeRateRepository{db: db}
+}
+
+// rateDay formats the day of t; rates are stored per calendar day
+func rateDay(t time.Time) string {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC).Format(time.RFC3339)
+}
+
+// Upsert stores a rate, replacing any rate for the same currency pair and day
+func (r *ExchangeRateRepository) Upsert(ctx context.Context, rate *models.ExchangeRate) error {
+	if rate.ID == "" {
+		rate.ID = uuid.New().String()
+	}
+	rate.CreatedAt = time.Now().UTC().Truncate(time.Second)
+	now := rate.CreatedAt.Format(time.RFC3339)
+
+	query := `
+		INSERT INTO exchange_rates (id, currency, base_currency, rate_date, rate, source, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(currency, base_currency, rate_date) DO UPDATE SET
+			rate = excluded.rate,
+			source = excluded.source,
+			created_at = excluded.created_at
+	`
+
+	_, err := r.db.ExecContext(ctx, query,
+		rate.ID,
+		rate.Currency,
+		rate.BaseCurrency,
+		rateDay(rate.RateDate),
+		rate.Rate,
+		rate.Source,
+		now,
+	)
+	if err != nil {
+		return err
+	}
+
+	// An existing row keeps its ID
+	return r.db.GetContext(ctx, &rate.ID,
+		"SELECT id FROM exchange_rates WHERE currency = ? AND base_currency = ? AND rate_date = ?",
+		rate.Currency, rate.BaseCurrency, rateDay(rate.RateDate))
+}
+
+// GetByID retrieves an exchange rate by ID
+func (r *ExchangeRateRepository) GetByID(ctx context.Context, id string) (*models.ExchangeRate, error) {
+	var row ExchangeRateRow
+	err := r.db.GetContext(ctx, &row, "SELECT * FROM exchange_rates WHERE id = ?", id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rowToExchangeRate(&row), nil
+}
+
+// GetEffective returns the most recent rate on or before the given day
+func (r *ExchangeRateRepository) GetEffective(ctx context.Context, currency, baseCurrency string, date time.Time) (*models.ExchangeRate, error) {
+	var row ExchangeRateRow
+	err := r.db.GetContext(ctx, &row, `
+		SELECT * FROM exchange_rates
+		WHERE currency = ? AND base_currency = ? AND rate_date <= ?
+		ORDER BY rate_date DESC LIMIT 1
+	`, currency, baseCurrency, rateDay(date))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rowToExchangeRate(&row), nil
+}
+
+// List returns all rates to a base currency, newest first
+func (r *ExchangeRateRepository) List(ctx context.Context, baseCurrency string) ([]models.ExchangeRate, error) {
+	var rows []ExchangeRateRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM exchange_rates WHERE base_currency = ? ORDER BY rate_date DESC, currency", baseCurrency)
+	if err != nil {
+		return nil, err
+	}
+	rates := make([]models.ExchangeRate, len(rows))
+	for i, row := range rows {
+		rates[i] = *rowToExchangeRate(&row)
+	}
+	return rates, nil
+}
+
+// ListAll returns the rates to every base currency
+func (r *ExchangeRateRepository) ListAll(ctx context.Context) ([]models.ExchangeRate, error) {
+	var rows []ExchangeRateRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM exchange_rates ORDER BY base_currency, rate_date DESC, currency")
+	if err != nil {
+		return nil, err
+	}
+	rates := make([]models.ExchangeRate, len(rows))
+	for i, row := range rows {
+		rates[i] = *rowToExchangeRate(&row)
+	}
+	return rates, nil
+}
+
+// Delete deletes an exchange rate
+func (r *ExchangeRateRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM exchange_rates WHERE id = ?", id)
+	return err
+}
+
+func rowToExchangeRate(row *ExchangeRateRow) *models.ExchangeRate {
+	rate := &models.ExchangeRate{
+		ID:           row.ID,
+		Currency:     row.Currency,
+		BaseCurrency: row.BaseCurrency,
+		Rate:         row.Rate,
+		Source:       row.Source,
+	}
+	rate.RateDate, _ = time.Parse(time.RFC3339, row.RateDate)
+	rate.CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
+	return rate
+}
@@ -0,0 +1,164 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/models"
+)
+
+// GroupWeightChangeRow represents a group weight change row in SQLite
+type GroupWeightChangeRow struct {
+	ID            string  `db:"id"`
+	GroupID       string  `db:"group_id"`
+	OldWeight     float64 `db:"old_weight"`
+	NewWeight     float64 `db:"new_weight"`
+	EffectiveFrom string  `db:"effective_from"`
+	CreatedAt     string  `db:"created_at"`
+}
+
+// GroupWeightChangeRepository implements repository.GroupWeightChangeRepository for SQLite
+type GroupWeightChangeRepository struct {
+	db DBTX
+}
+
+// NewGroupWeightChangeRepository creates a new SQLite group weight change repository
+func NewGroupWeightChangeRepository(db DBTX) *GroupWeightChangeRepository {
+	return &GroupWeightChangeRepository{db: db}
+}
+
+// Create creates a new group weight change
+func (r *GroupWeightChangeRepository) Create(ctx context.Context, change *models.GroupWeightChange) error {
+	query := `
+		INSERT INTO group_weight_changes (id, group_id, old_weight, new_weight, effective_from, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		change.ID,
+		change.GroupID,
+		change.OldWeight,
+		change.NewWeight,
+		change.EffectiveFrom.UTC().Format(time.RFC3339),
+		change.CreatedAt.UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// List returns all group weight changes, oldest first
+func (r *GroupWeightChangeRepository) List(ctx context.Context) ([]models.GroupWeightChange, error) {
+	var rows []GroupWeightChangeRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM group_weight_changes ORDER BY effective_from, rowid")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToGroupWeightChanges(rows), nil
+}
+
+// ListByGroupID returns the weight changes of a group, oldest first
+func (r *GroupWeightChangeRepository) ListByGroupID(ctx context.Context, groupID string) ([]models.GroupWeightChange, error) {
+	var rows []GroupWeightChangeRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM group_weight_changes WHERE group_id = ? ORDER BY effective_from, rowid", groupID)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToGroupWeightChanges(rows), nil
+}
+
+func rowToGroupWeightChange(row *GroupWeightChangeRow) *models.GroupWeightChange {
+	change := &models.GroupWeightChange{
+		ID:        row.ID,
+		GroupID:   row.GroupID,
+		OldWeight: row.OldWeight,
+		NewWeight: row.NewWeight,
+	}
+	change.EffectiveFrom, _ = time.Parse(time.RFC3339, row.EffectiveFrom)
+	change.CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
+	return change
+}
+
+func rowsToGroupWeightChanges(rows []GroupWeightChangeRow) []models.GroupWeightChange {
+	changes := make([]models.GroupWeightChange, len(rows))
+	for i, row := range rows {
+		changes[i] = *rowToGroupWeightChange(&row)
+	}
+	return changes
+}
+
+// GroupMembershipChangeRow represents a group membership change row in SQLite
+type GroupMembershipChangeRow struct {
+	ID            string  `db:"id"`
+	UserID        string  `db:"user_id"`
+	OldGroupID    *string `db:"old_group_id"`
+	NewGroupID    *string `db:"new_group_id"`
+	EffectiveFrom string  `db:"effective_from"`
+	CreatedAt     string  `db:"created_at"`
+}
+
+// GroupMembershipChangeRepository implements repository.GroupMembershipChangeRepository for SQLite
+type GroupMembershipChangeRepository struct {
+	db DBTX
+}
+
+// NewGroupMembershipChangeRepository creates a new SQLite group membership change repository
+func NewGroupMembershipChangeRepository(db DBTX) *GroupMembershipChangeRepository {
+	return &GroupMembershipChangeRepository{db: db}
+}
+
+// Create creates a new group membership change
+func (r *GroupMembershipChangeRepository) Create(ctx context.Context, change *models.GroupMembershipChange) error {
+	query := `
+		INSERT INTO group_membership_changes (id, user_id, old_group_id, new_group_id, effective_from, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		change.ID,
+		change.UserID,
+		change.OldGroupID,
+		change.NewGroupID,
+		change.EffectiveFrom.UTC().Format(time.RFC3339),
+		change.CreatedAt.UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// List returns all group membership changes, oldest first
+func (r *GroupMembershipChangeRepository) List(ctx context.Context) ([]models.GroupMembershipChange, error) {
+	var rows []GroupMembershipChangeRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM group_membership_changes ORDER BY effective_from, rowid")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToGroupMembershipChanges(rows), nil
+}
+
+// ListByUserID returns the group membership changes of a user, oldest first
+func (r *GroupMembershipChangeRepository) ListByUserID(ctx context.Context, userID string) ([]models.GroupMembershipChange, error) {
+	var rows []GroupMembershipChangeRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM group_membership_changes WHERE user_id = ? ORDER BY effective_from, rowid", userID)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToGroupMembershipChanges(rows), nil
+}
+
+func rowToGroupMembershipChange(row *GroupMembershipChangeRow) *models.GroupMembershipChange {
+	change := &models.GroupMembershipChange{
+		ID:         row.ID,
+		UserID:     row.UserID,
+		OldGroupID: row.OldGroupID,
+		NewGroupID: row.NewGroupID,
+	}
+	change.EffectiveFrom, _ = time.Parse(time.RFC3339, row.EffectiveFrom)
+	change.CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
+	return change
+}
+
+func rowsToGroupMembershipChanges(rows []GroupMembershipChangeRow) []models.GroupMembershipChange {
+	changes := make([]models.GroupMembershipChange, len(rows))
+	for i, row := range rows {
+		changes[i] = *rowToGroupMembershipChange(&row)
+	}
+	return changes
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
@@ -20,17 +19,22 @@ type GroupRow struct {
 
 // GroupRepository implements repository.GroupRepository for SQLite
 type GroupRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewGroupRepository creates a new SQLite group repository
-func NewGroupRepository(db *sqlx.DB) *GroupRepository {
+func NewGroupRepository(db DBTX) *GroupRepository {
 	return &GroupRepository{db: db}
 }
 
 // Create creates a new group
 func (r *GroupRepository) Create(ctx context.Context, group *models.Group) error {
-	id := uuid.New().String()
+	// Use the ID from group if set, otherwise generate a new one
+	id := group.ID
+	if id == "" {
+		id = uuid.New().String()
+		group.ID = id
+	}
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	query := `INSERT INTO groups (id, name, weight, created_at) VALUES (?, ?, ?, ?)`
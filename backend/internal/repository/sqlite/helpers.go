@@ -1,9 +1,12 @@
 package sqlite
 
 import (
+	"context"
+	"database/sql"
 	"encoding/hex"
 
 	"github.com/google/uuid"
+	"github.com/sainaif/holy-home/internal/utils"
 )
 
 // bytesToHex converts byte slice to hex string for storage
@@ -16,6 +19,14 @@ func hexToBytes(s string) ([]byte, error) {
 	return hex.DecodeString(s)
 }
 
+// DBTX is the subset of sqlx used by repositories. It is satisfied by both
+// *sqlx.DB and *sqlx.Tx, so repositories can be bound to a transaction.
+type DBTX interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	GetContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+	SelectContext(ctx context.Context, dest interface{}, query string, args ...interface{}) error
+}
+
 // generateID creates a new UUID-based ID
 func generateID() string {
 	return uuid.New().String()
@@ -52,3 +63,21 @@ func PtrString(s *string) string {
 	}
 	return *s
 }
+
+// currencyOrBase is an SQL expression for a currency placeholder that falls back to the
+// household base currency when the bound value is empty
+const currencyOrBase = "COALESCE(NULLIF(?, ''), (SELECT base_currency FROM app_settings WHERE id = 'singleton'), 'PLN')"
+
+// sumMoney adds up a column of decimal amounts exactly in Go instead of using SQLite's REAL arithmetic
+func sumMoney(ctx context.Context, db DBTX, query string, args ...interface{}) (string, error) {
+	var amounts []string
+	if err := db.SelectContext(ctx, &amounts, query, args...); err != nil {
+		return "0", err
+	}
+
+	var total utils.Money
+	for _, amount := range amounts {
+		total += utils.MoneyFromString(amount)
+	}
+	return total.String(), nil
+}
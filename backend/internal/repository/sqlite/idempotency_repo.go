@@ -0,0 +1,146 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/models"
+)
+
+// IdempotencyKeyRow represents an idempotency_keys row in SQLite
+type IdempotencyKeyRow struct {
+	UserID       string         `db:"user_id"`
+	Key          string         `db:"idempotency_key"`
+	Route        string         `db:"route"`
+	RequestHash  string         `db:"request_hash"`
+	StatusCode   int            `db:"status_code"`
+	ResponseBody []byte         `db:"response_body"`
+	ContentType  sql.NullString `db:"content_type"`
+	CreatedAt    string         `db:"created_at"`
+	ExpiresAt    string         `db:"expires_at"`
+	LockedUntil  sql.NullString `db:"locked_until"`
+}
+
+// IdempotencyKeyRepository implements repository.IdempotencyKeyRepository for SQLite
+type IdempotencyKeyRepository struct {
+	db DBTX
+}
+
+// NewIdempotencyKeyRepository creates a new SQLite idempotency key repository
+func NewIdempotencyKeyRepository(db DBTX) *IdempotencyKeyRepository {
+	return &IdempotencyKeyRepository{db: db}
+}
+
+// Reserve inserts an in-progress record unless a live one already exists for the user, key and route.
+// A record that was never completed is taken over once its lease runs out.
+func (r *IdempotencyKeyRepository) Reserve(ctx context.Context, record *models.IdempotencyRecord) (bool, error) {
+	var lockedUntil *string
+	if record.LockedUntil != nil {
+		lu := record.LockedUntil.UTC().Format(time.RFC3339)
+		lockedUntil = &lu
+	}
+
+	query := `
+		INSERT INTO idempotency_keys (user_id, idempotency_key, route, request_hash, status_code, created_at, expires_at, locked_until)
+		VALUES (?, ?, ?, ?, 0, ?, ?, ?)
+		ON CONFLICT(user_id, idempotency_key, route) DO UPDATE SET
+			request_hash = excluded.request_hash,
+			status_code = 0,
+			response_body = NULL,
+			content_type = NULL,
+			created_at = excluded.created_at,
+			expires_at = excluded.expires_at,
+			locked_until = excluded.locked_until
+		WHERE idempotency_keys.expires_at <= excluded.created_at
+			OR (idempotency_keys.status_code = 0
+				AND COALESCE(idempotency_keys.locked_until, idempotency_keys.created_at) <= excluded.created_at)
+	`
+	result, err := r.db.ExecContext(ctx, query,
+		record.UserID,
+		record.Key,
+		record.Route,
+		record.RequestHash,
+		record.CreatedAt.UTC().Format(time.RFC3339),
+		record.ExpiresAt.UTC().Format(time.RFC3339),
+		lockedUntil,
+	)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// Get retrieves the record for a user, key and route
+func (r *IdempotencyKeyRepository) Get(ctx context.Context, userID, key, route string) (*models.IdempotencyRecord, error) {
+	var row IdempotencyKeyRow
+	err := r.db.GetContext(ctx, &row,
+		"SELECT * FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ? AND route = ?",
+		userID, key, route)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rowToIdempotencyRecord(&row), nil
+}
+
+// Complete stores the response of a reserved record
+func (r *IdempotencyKeyRepository) Complete(ctx context.Context, record *models.IdempotencyRecord) error {
+	query := `
+		UPDATE idempotency_keys SET status_code = ?, response_body = ?, content_type = ?, locked_until = NULL
+		WHERE user_id = ? AND idempotency_key = ? AND route = ?
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		record.StatusCode,
+		record.ResponseBody,
+		StringPtr(record.ContentType),
+		record.UserID,
+		record.Key,
+		record.Route,
+	)
+	return err
+}
+
+// Delete removes a record so the key can be used again
+func (r *IdempotencyKeyRepository) Delete(ctx context.Context, userID, key, route string) error {
+	_, err := r.db.ExecContext(ctx,
+		"DELETE FROM idempotency_keys WHERE user_id = ? AND idempotency_key = ? AND route = ?",
+		userID, key, route)
+	return err
+}
+
+// DeleteExpired deletes all records past their replay window
+func (r *IdempotencyKeyRepository) DeleteExpired(ctx context.Context) error {
+	now := time.Now().UTC().Format(time.RFC3339)
+	_, err := r.db.ExecContext(ctx, "DELETE FROM idempotency_keys WHERE expires_at <= ?", now)
+	return err
+}
+
+func rowToIdempotencyRecord(row *IdempotencyKeyRow) *models.IdempotencyRecord {
+	createdAt, _ := time.Parse(time.RFC3339, row.CreatedAt)
+	expiresAt, _ := time.Parse(time.RFC3339, row.ExpiresAt)
+	var lockedUntil *time.Time
+	if row.LockedUntil.Valid {
+		t, _ := time.Parse(time.RFC3339, row.LockedUntil.String)
+		lockedUntil = &t
+	}
+
+	return &models.IdempotencyRecord{
+		UserID:       row.UserID,
+		Key:          row.Key,
+		Route:        row.Route,
+		RequestHash:  row.RequestHash,
+		StatusCode:   row.StatusCode,
+		ResponseBody: row.ResponseBody,
+		ContentType:  row.ContentType.String,
+		CreatedAt:    createdAt,
+		ExpiresAt:    expiresAt,
+		LockedUntil:  lockedUntil,
+	}
+}
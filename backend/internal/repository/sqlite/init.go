@@ -1,20 +1,25 @@
 package sqlite
 
 import (
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/repository"
 )
 
 // NewRepositories creates all SQLite repository implementations
-func NewRepositories(db *sqlx.DB) *repository.Repositories {
+func NewRepositories(db DBTX) *repository.Repositories {
 	return &repository.Repositories{
 		Users:                    NewUserRepository(db),
 		PasskeyCredentials:       NewPasskeyCredentialRepository(db),
 		Groups:                   NewGroupRepository(db),
+		GroupWeightChanges:       NewGroupWeightChangeRepository(db),
+		GroupMembershipChanges:   NewGroupMembershipChangeRepository(db),
+		Residencies:              NewResidencyRepository(db),
 		Bills:                    NewBillRepository(db),
 		RecurringBillTemplates:   NewRecurringBillTemplateRepository(db),
 		RecurringBillAllocations: NewRecurringBillAllocationRepository(db),
 		Consumptions:             NewConsumptionRepository(db),
+		Meters:                   NewMeterRepository(db),
+		MeterReplacements:        NewMeterReplacementRepository(db),
+		Tariffs:                  NewTariffRepository(db),
 		Allocations:              NewAllocationRepository(db),
 		Payments:                 NewPaymentRepository(db),
 		Loans:                    NewLoanRepository(db),
@@ -23,12 +28,17 @@ func NewRepositories(db *sqlx.DB) *repository.Repositories {
 		ChoreAssignments:         NewChoreAssignmentRepository(db),
 		ChoreSettings:            NewChoreSettingsRepository(db),
 		ChoreSwapRequests:        NewChoreSwapRequestRepository(db),
+		ChoreCompletions:         NewChoreCompletionRepository(db),
+		UserAbsences:             NewUserAbsenceRepository(db),
+		ChorePreferences:         NewChorePreferenceRepository(db),
 		SupplySettings:           NewSupplySettingsRepository(db),
 		SupplyItems:              NewSupplyItemRepository(db),
 		SupplyContributions:      NewSupplyContributionRepository(db),
 		SupplyItemHistory:        NewSupplyItemHistoryRepository(db),
 		Sessions:                 NewSessionRepository(db),
+		CalendarFeedTokens:       NewCalendarFeedTokenRepository(db),
 		PasswordResetTokens:      NewPasswordResetTokenRepository(db),
+		IdempotencyKeys:          NewIdempotencyKeyRepository(db),
 		Notifications:            NewNotificationRepository(db),
 		NotificationPreferences:  NewNotificationPreferenceRepository(db),
 		WebPushSubscriptions:     NewWebPushSubscriptionRepository(db),
@@ -36,6 +46,10 @@ func NewRepositories(db *sqlx.DB) *repository.Repositories {
 		Roles:                    NewRoleRepository(db),
 		AuditLogs:                NewAuditLogRepository(db),
 		ApprovalRequests:         NewApprovalRequestRepository(db),
+		ApprovalPolicies:         NewApprovalPolicyRepository(db),
+		LedgerEntries:            NewLedgerEntryRepository(db),
+		Settlements:              NewSettlementRepository(db),
+		ExchangeRates:            NewExchangeRateRepository(db),
 		AppSettings:              NewAppSettingsRepository(db),
 		SentReminders:            NewSentReminderRepository(db),
 	}
@@ -0,0 +1,135 @@
+package sqlite
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sainaif/holy-home/internal/models"
+)
+
+// LedgerEntryRow represents a ledger entry row in SQLite
+type LedgerEntryRow struct {
+	ID            string `db:"id"`
+	TransactionID string `db:"transaction_id"`
+	AccountType   string `db:"account_type"`
+	AccountID     string `db:"account_id"`
+	AmountPLN     string `db:"amount_pln"`
+	SourceType    string `db:"source_type"`
+	SourceID      string `db:"source_id"`
+	Description   string `db:"description"`
+	OccurredAt    string `db:"occurred_at"`
+	CreatedAt     string `db:"created_at"`
+}
+
+// LedgerEntryRepository implements repository.LedgerEntryRepository for SQLite
+type LedgerEntryRepository struct {
+	db DBTX
+}
+
+// NewLedgerEntryRepository creates a new SQLite ledger entry repository
+func NewLedgerEntryRepository(db DBTX) *LedgerEntryRepository {
+	return &LedgerEntryRepository{db: db}
+}
+
+// CreateBatch inserts entries in a single statement so a transaction is never half-written
+func (r *LedgerEntryRepository) CreateBatch(ctx context.Context, entries []models.LedgerEntry) error {
+	if len(entries) == 0 {
+		return nil
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+
+	placeholders := make([]string, len(entries))
+	args := make([]interface{}, 0, len(entries)*10)
+	for i := range entries {
+		entry := &entries[i]
+		if entry.ID == "" {
+			entry.ID = uuid.New().String()
+		}
+		placeholders[i] = "(?, ?, ?, ?, ?, ?, ?, ?, ?, ?)"
+		args = append(args,
+			entry.ID,
+			entry.TransactionID,
+			entry.AccountType,
+			entry.AccountID,
+			entry.AmountPLN,
+			entry.SourceType,
+			entry.SourceID,
+			entry.Description,
+			entry.OccurredAt.UTC().Format(time.RFC3339),
+			now,
+		)
+	}
+
+	query := `
+		INSERT INTO ledger_entries (id, transaction_id, account_type, account_id, amount_pln, source_type, source_id, description, occurred_at, created_at)
+		VALUES ` + strings.Join(placeholders, ", ")
+	_, err := r.db.ExecContext(ctx, query, args...)
+	return err
+}
+
+// List returns all ledger entries in posting order
+func (r *LedgerEntryRepository) List(ctx context.Context) ([]models.LedgerEntry, error) {
+	var rows []LedgerEntryRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM ledger_entries ORDER BY occurred_at, created_at, rowid")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToLedgerEntries(rows), nil
+}
+
+// ListByAccount returns all entries of an account in posting order
+func (r *LedgerEntryRepository) ListByAccount(ctx context.Context, accountType, accountID string) ([]models.LedgerEntry, error) {
+	var rows []LedgerEntryRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM ledger_entries WHERE account_type = ? AND account_id = ? ORDER BY occurred_at, created_at, rowid",
+		accountType, accountID)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToLedgerEntries(rows), nil
+}
+
+// ListBySource returns all entries posted for a source record
+func (r *LedgerEntryRepository) ListBySource(ctx context.Context, sourceType, sourceID string) ([]models.LedgerEntry, error) {
+	var rows []LedgerEntryRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM ledger_entries WHERE source_type = ? AND source_id = ? ORDER BY occurred_at, created_at, rowid",
+		sourceType, sourceID)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToLedgerEntries(rows), nil
+}
+
+// Count returns the number of ledger entries
+func (r *LedgerEntryRepository) Count(ctx context.Context) (int, error) {
+	var count int
+	err := r.db.GetContext(ctx, &count, "SELECT COUNT(*) FROM ledger_entries")
+	return count, err
+}
+
+func rowToLedgerEntry(row *LedgerEntryRow) *models.LedgerEntry {
+	entry := &models.LedgerEntry{
+		ID:            row.ID,
+		TransactionID: row.TransactionID,
+		AccountType:   row.AccountType,
+		AccountID:     row.AccountID,
+		AmountPLN:     row.AmountPLN,
+		SourceType:    row.SourceType,
+		SourceID:      row.SourceID,
+		Description:   row.Description,
+	}
+	entry.OccurredAt, _ = time.Parse(time.RFC3339, row.OccurredAt)
+	entry.CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
+	return entry
+}
+
+func rowsToLedgerEntries(rows []LedgerEntryRow) []models.LedgerEntry {
+	entries := make([]models.LedgerEntry, len(rows))
+	for i, row := range rows {
+		entries[i] = *rowToLedgerEntry(&row)
+	}
+	return entries
+}
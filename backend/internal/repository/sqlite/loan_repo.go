@@ -6,35 +6,39 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
 // LoanRow represents a loan row in SQLite
 type LoanRow struct {
-	ID         string  `db:"id"`
-	LenderID   string  `db:"lender_id"`
-	BorrowerID string  `db:"borrower_id"`
-	AmountPLN  string  `db:"amount_pln"`
-	Note       *string `db:"note"`
-	DueDate    *string `db:"due_date"`
-	Status     string  `db:"status"`
-	CreatedAt  string  `db:"created_at"`
+	ID             string  `db:"id"`
+	LenderID       string  `db:"lender_id"`
+	BorrowerID     string  `db:"borrower_id"`
+	AmountPLN      string  `db:"amount_pln"`
+	Currency       string  `db:"currency"`
+	OriginalAmount *string `db:"original_amount"`
+	ExchangeRate   *string `db:"exchange_rate"`
+	Note           *string `db:"note"`
+	DueDate        *string `db:"due_date"`
+	Status         string  `db:"status"`
+	CreatedAt      string  `db:"created_at"`
 }
 
 // LoanRepository implements repository.LoanRepository for SQLite
 type LoanRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewLoanRepository creates a new SQLite loan repository
-func NewLoanRepository(db *sqlx.DB) *LoanRepository {
+func NewLoanRepository(db DBTX) *LoanRepository {
 	return &LoanRepository{db: db}
 }
 
 // Create creates a new loan
 func (r *LoanRepository) Create(ctx context.Context, loan *models.Loan) error {
-	id := uuid.New().String()
+	if loan.ID == "" {
+		loan.ID = uuid.New().String()
+	}
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	var dueDate *string
@@ -44,15 +48,18 @@ func (r *LoanRepository) Create(ctx context.Context, loan *models.Loan) error {
 	}
 
 	query := `
-		INSERT INTO loans (id, lender_id, borrower_id, amount_pln, note, due_date, status, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO loans (id, lender_id, borrower_id, amount_pln, currency, original_amount, exchange_rate, note, due_date, status, created_at)
+		VALUES (?, ?, ?, ?, ` + currencyOrBase + `, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
-		id,
+		loan.ID,
 		loan.LenderID,
 		loan.BorrowerID,
 		loan.AmountPLN,
+		loan.Currency,
+		loan.OriginalAmount,
+		loan.ExchangeRate,
 		loan.Note,
 		dueDate,
 		loan.Status,
@@ -84,7 +91,8 @@ func (r *LoanRepository) Update(ctx context.Context, loan *models.Loan) error {
 
 	query := `
 		UPDATE loans SET
-			lender_id = ?, borrower_id = ?, amount_pln = ?, note = ?, due_date = ?, status = ?
+			lender_id = ?, borrower_id = ?, amount_pln = ?, currency = ` + currencyOrBase + `, original_amount = ?,
+			exchange_rate = ?, note = ?, due_date = ?, status = ?
 		WHERE id = ?
 	`
 
@@ -92,6 +100,9 @@ func (r *LoanRepository) Update(ctx context.Context, loan *models.Loan) error {
 		loan.LenderID,
 		loan.BorrowerID,
 		loan.AmountPLN,
+		loan.Currency,
+		loan.OriginalAmount,
+		loan.ExchangeRate,
 		loan.Note,
 		dueDate,
 		loan.Status,
@@ -164,12 +175,15 @@ func (r *LoanRepository) ListOpenBetweenUsers(ctx context.Context, lenderID, bor
 
 func rowToLoan(row *LoanRow) *models.Loan {
 	loan := &models.Loan{
-		ID:         row.ID,
-		LenderID:   row.LenderID,
-		BorrowerID: row.BorrowerID,
-		AmountPLN:  row.AmountPLN,
-		Note:       row.Note,
-		Status:     row.Status,
+		ID:             row.ID,
+		LenderID:       row.LenderID,
+		BorrowerID:     row.BorrowerID,
+		AmountPLN:      row.AmountPLN,
+		Currency:       row.Currency,
+		OriginalAmount: row.OriginalAmount,
+		ExchangeRate:   row.ExchangeRate,
+		Note:           row.Note,
+		Status:         row.Status,
 	}
 
 	loan.CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
@@ -192,34 +206,38 @@ func rowsToLoans(rows []LoanRow) []models.Loan {
 
 // LoanPaymentRow represents a loan payment row in SQLite
 type LoanPaymentRow struct {
-	ID        string  `db:"id"`
-	LoanID    string  `db:"loan_id"`
-	AmountPLN string  `db:"amount_pln"`
-	PaidAt    string  `db:"paid_at"`
-	Note      *string `db:"note"`
+	ID                    string  `db:"id"`
+	LoanID                string  `db:"loan_id"`
+	AmountPLN             string  `db:"amount_pln"`
+	PaidAt                string  `db:"paid_at"`
+	Note                  *string `db:"note"`
+	SettlementFingerprint *string `db:"settlement_fingerprint"`
 }
 
 // LoanPaymentRepository implements repository.LoanPaymentRepository for SQLite
 type LoanPaymentRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewLoanPaymentRepository creates a new SQLite loan payment repository
-func NewLoanPaymentRepository(db *sqlx.DB) *LoanPaymentRepository {
+func NewLoanPaymentRepository(db DBTX) *LoanPaymentRepository {
 	return &LoanPaymentRepository{db: db}
 }
 
 // Create creates a new loan payment
 func (r *LoanPaymentRepository) Create(ctx context.Context, payment *models.LoanPayment) error {
-	id := uuid.New().String()
+	if payment.ID == "" {
+		payment.ID = uuid.New().String()
+	}
 
-	query := `INSERT INTO loan_payments (id, loan_id, amount_pln, paid_at, note) VALUES (?, ?, ?, ?, ?)`
+	query := `INSERT INTO loan_payments (id, loan_id, amount_pln, paid_at, note, settlement_fingerprint) VALUES (?, ?, ?, ?, ?, ?)`
 	_, err := r.db.ExecContext(ctx, query,
-		id,
+		payment.ID,
 		payment.LoanID,
 		payment.AmountPLN,
 		payment.PaidAt.UTC().Format(time.RFC3339),
 		payment.Note,
+		payment.SettlementFingerprint,
 	)
 	return err
 }
@@ -265,23 +283,16 @@ func (r *LoanPaymentRepository) ListByLoanID(ctx context.Context, loanID string)
 
 // SumByLoanID returns the sum of payments for a loan
 func (r *LoanPaymentRepository) SumByLoanID(ctx context.Context, loanID string) (string, error) {
-	var sum sql.NullString
-	err := r.db.GetContext(ctx, &sum, "SELECT COALESCE(SUM(CAST(amount_pln AS REAL)), 0) FROM loan_payments WHERE loan_id = ?", loanID)
-	if err != nil {
-		return "0", err
-	}
-	if !sum.Valid || sum.String == "" {
-		return "0", nil
-	}
-	return sum.String, nil
+	return sumMoney(ctx, r.db, "SELECT amount_pln FROM loan_payments WHERE loan_id = ?", loanID)
 }
 
 func rowToLoanPayment(row *LoanPaymentRow) *models.LoanPayment {
 	payment := &models.LoanPayment{
-		ID:        row.ID,
-		LoanID:    row.LoanID,
-		AmountPLN: row.AmountPLN,
-		Note:      row.Note,
+		ID:                    row.ID,
+		LoanID:                row.LoanID,
+		AmountPLN:             row.AmountPLN,
+		Note:                  row.Note,
+		SettlementFingerprint: row.SettlementFingerprint,
 	}
 
 	payment.PaidAt, _ = time.Parse(time.RFC3339, row.PaidAt)
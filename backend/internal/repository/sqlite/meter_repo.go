@@ -0,0 +1,250 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/models"
+)
+
+// MeterRow represents a meter row in SQLite
+type MeterRow struct {
+	ID           string  `db:"id"`
+	SubjectType  string  `db:"subject_type"`
+	SubjectID    string  `db:"subject_id"`
+	UtilityType  string  `db:"utility_type"`
+	SerialNumber *string `db:"serial_number"`
+	Unit         string  `db:"unit"`
+	RolloverAt   *string `db:"rollover_at"`
+	IsActive     int     `db:"is_active"`
+	CreatedAt    string  `db:"created_at"`
+	Registers    *string `db:"registers"`
+}
+
+// MeterRepository implements repository.MeterRepository for SQLite
+type MeterRepository struct {
+	db DBTX
+}
+
+// NewMeterRepository creates a new SQLite meter repository
+func NewMeterRepository(db DBTX) *MeterRepository {
+	return &MeterRepository{db: db}
+}
+
+// Create creates a new meter
+func (r *MeterRepository) Create(ctx context.Context, meter *models.Meter) error {
+	isActive := 0
+	if meter.IsActive {
+		isActive = 1
+	}
+
+	query := `
+		INSERT INTO meters (id, subject_type, subject_id, utility_type, serial_number, unit, rollover_at, registers, is_active, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		meter.ID,
+		meter.SubjectType,
+		meter.SubjectID,
+		meter.UtilityType,
+		meter.SerialNumber,
+		meter.Unit,
+		meter.RolloverAt,
+		meterRegistersJSON(meter.Registers),
+		isActive,
+		meter.CreatedAt.UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// GetByID retrieves a meter by ID
+func (r *MeterRepository) GetByID(ctx context.Context, id string) (*models.Meter, error) {
+	var row MeterRow
+	err := r.db.GetContext(ctx, &row, "SELECT * FROM meters WHERE id = ?", id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rowToMeter(&row), nil
+}
+
+// Update updates an existing meter
+func (r *MeterRepository) Update(ctx context.Context, meter *models.Meter) error {
+	isActive := 0
+	if meter.IsActive {
+		isActive = 1
+	}
+
+	query := `
+		UPDATE meters SET
+			subject_type = ?, subject_id = ?, utility_type = ?, serial_number = ?, unit = ?, rollover_at = ?, registers = ?, is_active = ?
+		WHERE id = ?
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		meter.SubjectType,
+		meter.SubjectID,
+		meter.UtilityType,
+		meter.SerialNumber,
+		meter.Unit,
+		meter.RolloverAt,
+		meterRegistersJSON(meter.Registers),
+		isActive,
+		meter.ID,
+	)
+	return err
+}
+
+// Delete deletes a meter
+func (r *MeterRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM meters WHERE id = ?", id)
+	return err
+}
+
+// List returns all meters
+func (r *MeterRepository) List(ctx context.Context) ([]models.Meter, error) {
+	var rows []MeterRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM meters ORDER BY created_at, rowid")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToMeters(rows), nil
+}
+
+// ListBySubject returns the meters of a user or group
+func (r *MeterRepository) ListBySubject(ctx context.Context, subjectType, subjectID string) ([]models.Meter, error) {
+	var rows []MeterRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM meters WHERE subject_type = ? AND subject_id = ? ORDER BY created_at, rowid",
+		subjectType, subjectID)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToMeters(rows), nil
+}
+
+func rowToMeter(row *MeterRow) *models.Meter {
+	meter := &models.Meter{
+		ID:           row.ID,
+		SubjectType:  row.SubjectType,
+		SubjectID:    row.SubjectID,
+		UtilityType:  row.UtilityType,
+		SerialNumber: row.SerialNumber,
+		Unit:         row.Unit,
+		RolloverAt:   row.RolloverAt,
+		IsActive:     row.IsActive == 1,
+	}
+	meter.CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
+	if row.Registers != nil {
+		json.Unmarshal([]byte(*row.Registers), &meter.Registers)
+	}
+	return meter
+}
+
+// meterRegistersJSON encodes the registers of a meter, NULL for a single register meter
+func meterRegistersJSON(registers []string) *string {
+	if len(registers) == 0 {
+		return nil
+	}
+	registersJSON, _ := json.Marshal(registers)
+	value := string(registersJSON)
+	return &value
+}
+
+func rowsToMeters(rows []MeterRow) []models.Meter {
+	meters := make([]models.Meter, len(rows))
+	for i, row := range rows {
+		meters[i] = *rowToMeter(&row)
+	}
+	return meters
+}
+
+// MeterReplacementRow represents a meter replacement row in SQLite
+type MeterReplacementRow struct {
+	ID              string  `db:"id"`
+	MeterID         string  `db:"meter_id"`
+	ReplacedAt      string  `db:"replaced_at"`
+	OldFinalValue   string  `db:"old_final_value"`
+	NewInitialValue string  `db:"new_initial_value"`
+	OldSerialNumber *string `db:"old_serial_number"`
+	NewSerialNumber *string `db:"new_serial_number"`
+	CreatedAt       string  `db:"created_at"`
+	Register        *string `db:"register"`
+}
+
+// MeterReplacementRepository implements repository.MeterReplacementRepository for SQLite
+type MeterReplacementRepository struct {
+	db DBTX
+}
+
+// NewMeterReplacementRepository creates a new SQLite meter replacement repository
+func NewMeterReplacementRepository(db DBTX) *MeterReplacementRepository {
+	return &MeterReplacementRepository{db: db}
+}
+
+// Create creates a new meter replacement
+func (r *MeterReplacementRepository) Create(ctx context.Context, replacement *models.MeterReplacement) error {
+	query := `
+		INSERT INTO meter_replacements (id, meter_id, replaced_at, old_final_value, new_initial_value, old_serial_number, new_serial_number, register, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		replacement.ID,
+		replacement.MeterID,
+		replacement.ReplacedAt.UTC().Format(time.RFC3339),
+		replacement.OldFinalValue,
+		replacement.NewInitialValue,
+		replacement.OldSerialNumber,
+		replacement.NewSerialNumber,
+		replacement.Register,
+		replacement.CreatedAt.UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// List returns all meter replacements
+func (r *MeterReplacementRepository) List(ctx context.Context) ([]models.MeterReplacement, error) {
+	var rows []MeterReplacementRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM meter_replacements ORDER BY replaced_at, rowid")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToMeterReplacements(rows), nil
+}
+
+// ListByMeterID returns the replacements of a meter, oldest first
+func (r *MeterReplacementRepository) ListByMeterID(ctx context.Context, meterID string) ([]models.MeterReplacement, error) {
+	var rows []MeterReplacementRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM meter_replacements WHERE meter_id = ? ORDER BY replaced_at, rowid", meterID)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToMeterReplacements(rows), nil
+}
+
+func rowToMeterReplacement(row *MeterReplacementRow) *models.MeterReplacement {
+	replacement := &models.MeterReplacement{
+		ID:              row.ID,
+		MeterID:         row.MeterID,
+		OldFinalValue:   row.OldFinalValue,
+		NewInitialValue: row.NewInitialValue,
+		OldSerialNumber: row.OldSerialNumber,
+		NewSerialNumber: row.NewSerialNumber,
+		Register:        row.Register,
+	}
+	replacement.ReplacedAt, _ = time.Parse(time.RFC3339, row.ReplacedAt)
+	replacement.CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
+	return replacement
+}
+
+func rowsToMeterReplacements(rows []MeterReplacementRow) []models.MeterReplacement {
+	replacements := make([]models.MeterReplacement, len(rows))
+	for i, row := range rows {
+		replacements[i] = *rowToMeterReplacement(&row)
+	}
+	return replacements
+}
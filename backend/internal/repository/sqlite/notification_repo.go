@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
@@ -27,11 +26,11 @@ type NotificationRow struct {
 
 // NotificationRepository implements repository.NotificationRepository for SQLite
 type NotificationRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewNotificationRepository creates a new SQLite notification repository
-func NewNotificationRepository(db *sqlx.DB) *NotificationRepository {
+func NewNotificationRepository(db DBTX) *NotificationRepository {
 	return &NotificationRepository{db: db}
 }
 
@@ -195,11 +194,11 @@ type NotificationPreferenceRow struct {
 
 // NotificationPreferenceRepository implements repository.NotificationPreferenceRepository for SQLite
 type NotificationPreferenceRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewNotificationPreferenceRepository creates a new SQLite notification preference repository
-func NewNotificationPreferenceRepository(db *sqlx.DB) *NotificationPreferenceRepository {
+func NewNotificationPreferenceRepository(db DBTX) *NotificationPreferenceRepository {
 	return &NotificationPreferenceRepository{db: db}
 }
 
@@ -242,6 +241,21 @@ func (r *NotificationPreferenceRepository) Upsert(ctx context.Context, pref *mod
 	return err
 }
 
+// List returns the notification preferences of all users
+func (r *NotificationPreferenceRepository) List(ctx context.Context) ([]models.NotificationPreference, error) {
+	var rows []NotificationPreferenceRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM notification_preferences")
+	if err != nil {
+		return nil, err
+	}
+
+	prefs := make([]models.NotificationPreference, len(rows))
+	for i, row := range rows {
+		prefs[i] = *rowToNotificationPreference(&row)
+	}
+	return prefs, nil
+}
+
 func rowToNotificationPreference(row *NotificationPreferenceRow) *models.NotificationPreference {
 	pref := &models.NotificationPreference{
 		ID:          row.ID,
@@ -266,11 +280,11 @@ type WebPushSubscriptionRow struct {
 
 // WebPushSubscriptionRepository implements repository.WebPushSubscriptionRepository for SQLite
 type WebPushSubscriptionRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewWebPushSubscriptionRepository creates a new SQLite web push subscription repository
-func NewWebPushSubscriptionRepository(db *sqlx.DB) *WebPushSubscriptionRepository {
+func NewWebPushSubscriptionRepository(db DBTX) *WebPushSubscriptionRepository {
 	return &WebPushSubscriptionRepository{db: db}
 }
 
@@ -325,7 +339,20 @@ func (r *WebPushSubscriptionRepository) ListByUserID(ctx context.Context, userID
 	if err != nil {
 		return nil, err
 	}
+	return rowsToWebPushSubscriptions(rows), nil
+}
+
+// List returns all subscriptions
+func (r *WebPushSubscriptionRepository) List(ctx context.Context) ([]models.WebPushSubscription, error) {
+	var rows []WebPushSubscriptionRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM web_push_subscriptions")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToWebPushSubscriptions(rows), nil
+}
 
+func rowsToWebPushSubscriptions(rows []WebPushSubscriptionRow) []models.WebPushSubscription {
 	subs := make([]models.WebPushSubscription, len(rows))
 	for i, row := range rows {
 		subs[i] = models.WebPushSubscription{
@@ -337,5 +364,5 @@ func (r *WebPushSubscriptionRepository) ListByUserID(ctx context.Context, userID
 			Auth:           row.Auth,
 		}
 	}
-	return subs, nil
+	return subs
 }
@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"time"
 
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 	"github.com/sainaif/holy-home/internal/repository"
 )
@@ -27,11 +26,11 @@ type PasskeyCredentialRow struct {
 
 // PasskeyCredentialRepository implements repository.PasskeyCredentialRepository for SQLite
 type PasskeyCredentialRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewPasskeyCredentialRepository creates a new SQLite passkey credential repository
-func NewPasskeyCredentialRepository(db *sqlx.DB) *PasskeyCredentialRepository {
+func NewPasskeyCredentialRepository(db DBTX) *PasskeyCredentialRepository {
 	return &PasskeyCredentialRepository{db: db}
 }
 
@@ -6,45 +6,52 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
 // PaymentRow represents a payment row in SQLite
 type PaymentRow struct {
-	ID          string  `db:"id"`
-	BillID      string  `db:"bill_id"`
-	PayerUserID string  `db:"payer_user_id"`
-	AmountPLN   string  `db:"amount_pln"`
-	PaidAt      string  `db:"paid_at"`
-	Method      *string `db:"method"`
-	Reference   *string `db:"reference"`
+	ID             string  `db:"id"`
+	BillID         string  `db:"bill_id"`
+	PayerUserID    string  `db:"payer_user_id"`
+	AmountPLN      string  `db:"amount_pln"`
+	Currency       string  `db:"currency"`
+	OriginalAmount *string `db:"original_amount"`
+	ExchangeRate   *string `db:"exchange_rate"`
+	PaidAt         string  `db:"paid_at"`
+	Method         *string `db:"method"`
+	Reference      *string `db:"reference"`
 }
 
 // PaymentRepository implements repository.PaymentRepository for SQLite
 type PaymentRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewPaymentRepository creates a new SQLite payment repository
-func NewPaymentRepository(db *sqlx.DB) *PaymentRepository {
+func NewPaymentRepository(db DBTX) *PaymentRepository {
 	return &PaymentRepository{db: db}
 }
 
 // Create creates a new payment
 func (r *PaymentRepository) Create(ctx context.Context, payment *models.Payment) error {
-	id := uuid.New().String()
+	if payment.ID == "" {
+		payment.ID = uuid.New().String()
+	}
 
 	query := `
-		INSERT INTO payments (id, bill_id, payer_user_id, amount_pln, paid_at, method, reference)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
+		INSERT INTO payments (id, bill_id, payer_user_id, amount_pln, currency, original_amount, exchange_rate, paid_at, method, reference)
+		VALUES (?, ?, ?, ?, ` + currencyOrBase + `, ?, ?, ?, ?, ?)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
-		id,
+		payment.ID,
 		payment.BillID,
 		payment.PayerUserID,
 		payment.AmountPLN,
+		payment.Currency,
+		payment.OriginalAmount,
+		payment.ExchangeRate,
 		payment.PaidAt.UTC().Format(time.RFC3339),
 		payment.Method,
 		payment.Reference,
@@ -103,25 +110,20 @@ func (r *PaymentRepository) ListByPayerID(ctx context.Context, payerID string) (
 
 // SumByBillID returns the sum of payments for a bill
 func (r *PaymentRepository) SumByBillID(ctx context.Context, billID string) (string, error) {
-	var sum sql.NullString
-	err := r.db.GetContext(ctx, &sum, "SELECT COALESCE(SUM(CAST(amount_pln AS REAL)), 0) FROM payments WHERE bill_id = ?", billID)
-	if err != nil {
-		return "0", err
-	}
-	if !sum.Valid || sum.String == "" {
-		return "0", nil
-	}
-	return sum.String, nil
+	return sumMoney(ctx, r.db, "SELECT amount_pln FROM payments WHERE bill_id = ?", billID)
 }
 
 func rowToPayment(row *PaymentRow) *models.Payment {
 	payment := &models.Payment{
-		ID:          row.ID,
-		BillID:      row.BillID,
-		PayerUserID: row.PayerUserID,
-		AmountPLN:   row.AmountPLN,
-		Method:      row.Method,
-		Reference:   row.Reference,
+		ID:             row.ID,
+		BillID:         row.BillID,
+		PayerUserID:    row.PayerUserID,
+		AmountPLN:      row.AmountPLN,
+		Currency:       row.Currency,
+		OriginalAmount: row.OriginalAmount,
+		ExchangeRate:   row.ExchangeRate,
+		Method:         row.Method,
+		Reference:      row.Reference,
 	}
 
 	payment.PaidAt, _ = time.Parse(time.RFC3339, row.PaidAt)
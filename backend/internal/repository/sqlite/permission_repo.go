@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
@@ -21,11 +20,11 @@ type PermissionRow struct {
 
 // PermissionRepository implements repository.PermissionRepository for SQLite
 type PermissionRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewPermissionRepository creates a new SQLite permission repository
-func NewPermissionRepository(db *sqlx.DB) *PermissionRepository {
+func NewPermissionRepository(db DBTX) *PermissionRepository {
 	return &PermissionRepository{db: db}
 }
 
@@ -101,11 +100,11 @@ type RoleRow struct {
 
 // RoleRepository implements repository.RoleRepository for SQLite
 type RoleRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewRoleRepository creates a new SQLite role repository
-func NewRoleRepository(db *sqlx.DB) *RoleRepository {
+func NewRoleRepository(db DBTX) *RoleRepository {
 	return &RoleRepository{db: db}
 }
 
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
@@ -18,6 +17,7 @@ type RecurringBillTemplateRow struct {
 	Amount          string  `db:"amount"`
 	DayOfMonth      int     `db:"day_of_month"`
 	StartDate       string  `db:"start_date"`
+	RecurrenceRule  *string `db:"recurrence_rule"`
 	Notes           *string `db:"notes"`
 	IsActive        int     `db:"is_active"`
 	CurrentBillID   *string `db:"current_bill_id"`
@@ -29,11 +29,11 @@ type RecurringBillTemplateRow struct {
 
 // RecurringBillTemplateRepository implements repository.RecurringBillTemplateRepository for SQLite
 type RecurringBillTemplateRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewRecurringBillTemplateRepository creates a new SQLite recurring bill template repository
-func NewRecurringBillTemplateRepository(db *sqlx.DB) *RecurringBillTemplateRepository {
+func NewRecurringBillTemplateRepository(db DBTX) *RecurringBillTemplateRepository {
 	return &RecurringBillTemplateRepository{db: db}
 }
 
@@ -54,9 +54,9 @@ func (r *RecurringBillTemplateRepository) Create(ctx context.Context, template *
 	}
 
 	query := `
-		INSERT INTO recurring_bill_templates (id, custom_type, frequency, amount, day_of_month, start_date, notes,
+		INSERT INTO recurring_bill_templates (id, custom_type, frequency, amount, day_of_month, start_date, recurrence_rule, notes,
 			is_active, current_bill_id, next_due_date, last_generated_at, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
@@ -66,6 +66,7 @@ func (r *RecurringBillTemplateRepository) Create(ctx context.Context, template *
 		template.Amount,
 		template.DayOfMonth,
 		template.StartDate.UTC().Format(time.RFC3339),
+		template.RecurrenceRule,
 		template.Notes,
 		boolToInt(template.IsActive),
 		template.CurrentBillID,
@@ -102,7 +103,7 @@ func (r *RecurringBillTemplateRepository) Update(ctx context.Context, template *
 
 	query := `
 		UPDATE recurring_bill_templates SET
-			custom_type = ?, frequency = ?, amount = ?, day_of_month = ?, start_date = ?, notes = ?,
+			custom_type = ?, frequency = ?, amount = ?, day_of_month = ?, start_date = ?, recurrence_rule = ?, notes = ?,
 			is_active = ?, current_bill_id = ?, next_due_date = ?, last_generated_at = ?, updated_at = ?
 		WHERE id = ?
 	`
@@ -113,6 +114,7 @@ func (r *RecurringBillTemplateRepository) Update(ctx context.Context, template *
 		template.Amount,
 		template.DayOfMonth,
 		template.StartDate.UTC().Format(time.RFC3339),
+		template.RecurrenceRule,
 		template.Notes,
 		boolToInt(template.IsActive),
 		template.CurrentBillID,
@@ -164,14 +166,15 @@ func (r *RecurringBillTemplateRepository) ListDueBefore(ctx context.Context, dat
 
 func rowToRecurringBillTemplate(row *RecurringBillTemplateRow) *models.RecurringBillTemplate {
 	template := &models.RecurringBillTemplate{
-		ID:            row.ID,
-		CustomType:    row.CustomType,
-		Frequency:     row.Frequency,
-		Amount:        row.Amount,
-		DayOfMonth:    row.DayOfMonth,
-		Notes:         row.Notes,
-		IsActive:      intToBool(row.IsActive),
-		CurrentBillID: row.CurrentBillID,
+		ID:             row.ID,
+		CustomType:     row.CustomType,
+		Frequency:      row.Frequency,
+		Amount:         row.Amount,
+		DayOfMonth:     row.DayOfMonth,
+		RecurrenceRule: row.RecurrenceRule,
+		Notes:          row.Notes,
+		IsActive:       intToBool(row.IsActive),
+		CurrentBillID:  row.CurrentBillID,
 	}
 
 	template.StartDate, _ = time.Parse(time.RFC3339, row.StartDate)
@@ -210,11 +213,11 @@ type RecurringBillAllocationRow struct {
 
 // RecurringBillAllocationRepository implements repository.RecurringBillAllocationRepository for SQLite
 type RecurringBillAllocationRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewRecurringBillAllocationRepository creates a new SQLite recurring bill allocation repository
-func NewRecurringBillAllocationRepository(db *sqlx.DB) *RecurringBillAllocationRepository {
+func NewRecurringBillAllocationRepository(db DBTX) *RecurringBillAllocationRepository {
 	return &RecurringBillAllocationRepository{db: db}
 }
 
@@ -0,0 +1,139 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/models"
+)
+
+// ResidencyRow represents a residency row in SQLite
+type ResidencyRow struct {
+	ID        string  `db:"id"`
+	UserID    string  `db:"user_id"`
+	GroupID   *string `db:"group_id"`
+	StartDate string  `db:"start_date"`
+	EndDate   *string `db:"end_date"`
+	CreatedAt string  `db:"created_at"`
+}
+
+// ResidencyRepository implements repository.ResidencyRepository for SQLite
+type ResidencyRepository struct {
+	db DBTX
+}
+
+// NewResidencyRepository creates a new SQLite residency repository
+func NewResidencyRepository(db DBTX) *ResidencyRepository {
+	return &ResidencyRepository{db: db}
+}
+
+// Create creates a new residency
+func (r *ResidencyRepository) Create(ctx context.Context, residency *models.Residency) error {
+	var endDate *string
+	if residency.EndDate != nil {
+		ed := residency.EndDate.UTC().Format(time.RFC3339)
+		endDate = &ed
+	}
+
+	query := `
+		INSERT INTO residencies (id, user_id, group_id, start_date, end_date, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		residency.ID,
+		residency.UserID,
+		residency.GroupID,
+		residency.StartDate.UTC().Format(time.RFC3339),
+		endDate,
+		residency.CreatedAt.UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// GetByID retrieves a residency by ID
+func (r *ResidencyRepository) GetByID(ctx context.Context, id string) (*models.Residency, error) {
+	var row ResidencyRow
+	err := r.db.GetContext(ctx, &row, "SELECT * FROM residencies WHERE id = ?", id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rowToResidency(&row), nil
+}
+
+// Update updates an existing residency
+func (r *ResidencyRepository) Update(ctx context.Context, residency *models.Residency) error {
+	var endDate *string
+	if residency.EndDate != nil {
+		ed := residency.EndDate.UTC().Format(time.RFC3339)
+		endDate = &ed
+	}
+
+	query := `
+		UPDATE residencies SET
+			group_id = ?, start_date = ?, end_date = ?
+		WHERE id = ?
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		residency.GroupID,
+		residency.StartDate.UTC().Format(time.RFC3339),
+		endDate,
+		residency.ID,
+	)
+	return err
+}
+
+// Delete deletes a residency
+func (r *ResidencyRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM residencies WHERE id = ?", id)
+	return err
+}
+
+// List returns all residencies
+func (r *ResidencyRepository) List(ctx context.Context) ([]models.Residency, error) {
+	var rows []ResidencyRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM residencies ORDER BY start_date, rowid")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToResidencies(rows), nil
+}
+
+// ListByUserID returns the residencies of a user, oldest first
+func (r *ResidencyRepository) ListByUserID(ctx context.Context, userID string) ([]models.Residency, error) {
+	var rows []ResidencyRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM residencies WHERE user_id = ? ORDER BY start_date, rowid", userID)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToResidencies(rows), nil
+}
+
+func rowToResidency(row *ResidencyRow) *models.Residency {
+	residency := &models.Residency{
+		ID:      row.ID,
+		UserID:  row.UserID,
+		GroupID: row.GroupID,
+	}
+	residency.StartDate, _ = time.Parse(time.RFC3339, row.StartDate)
+	if row.EndDate != nil {
+		endDate, err := time.Parse(time.RFC3339, *row.EndDate)
+		if err == nil {
+			residency.EndDate = &endDate
+		}
+	}
+	residency.CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
+	return residency
+}
+
+func rowsToResidencies(rows []ResidencyRow) []models.Residency {
+	residencies := make([]models.Residency, len(rows))
+	for i, row := range rows {
+		residencies[i] = *rowToResidency(&row)
+	}
+	return residencies
+}
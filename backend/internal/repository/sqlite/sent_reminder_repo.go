@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
@@ -22,11 +21,11 @@ type SentReminderRow struct {
 
 // SentReminderRepository implements repository.SentReminderRepository for SQLite
 type SentReminderRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewSentReminderRepository creates a new SQLite sent reminder repository
-func NewSentReminderRepository(db *sqlx.DB) *SentReminderRepository {
+func NewSentReminderRepository(db DBTX) *SentReminderRepository {
 	return &SentReminderRepository{db: db}
 }
 
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
@@ -25,11 +24,11 @@ type SessionRow struct {
 
 // SessionRepository implements repository.SessionRepository for SQLite
 type SessionRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewSessionRepository creates a new SQLite session repository
-func NewSessionRepository(db *sqlx.DB) *SessionRepository {
+func NewSessionRepository(db DBTX) *SessionRepository {
 	return &SessionRepository{db: db}
 }
 
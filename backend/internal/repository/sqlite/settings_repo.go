@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"time"
 
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
@@ -16,16 +15,17 @@ type AppSettingsRow struct {
 	DefaultLanguage          string `db:"default_language"`
 	DisableAutoDetect        int    `db:"disable_auto_detect"`
 	ReminderRateLimitPerHour int    `db:"reminder_rate_limit_per_hour"`
+	BaseCurrency             string `db:"base_currency"`
 	UpdatedAt                string `db:"updated_at"`
 }
 
 // AppSettingsRepository implements repository.AppSettingsRepository for SQLite
 type AppSettingsRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewAppSettingsRepository creates a new SQLite app settings repository
-func NewAppSettingsRepository(db *sqlx.DB) *AppSettingsRepository {
+func NewAppSettingsRepository(db DBTX) *AppSettingsRepository {
 	return &AppSettingsRepository{db: db}
 }
 
@@ -47,13 +47,14 @@ func (r *AppSettingsRepository) Upsert(ctx context.Context, settings *models.App
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	query := `
-		INSERT INTO app_settings (id, app_name, default_language, disable_auto_detect, reminder_rate_limit_per_hour, updated_at)
-		VALUES ('singleton', ?, ?, ?, ?, ?)
+		INSERT INTO app_settings (id, app_name, default_language, disable_auto_detect, reminder_rate_limit_per_hour, base_currency, updated_at)
+		VALUES ('singleton', ?, ?, ?, ?, COALESCE(NULLIF(?, ''), 'PLN'), ?)
 		ON CONFLICT(id) DO UPDATE SET
 			app_name = excluded.app_name,
 			default_language = excluded.default_language,
 			disable_auto_detect = excluded.disable_auto_detect,
 			reminder_rate_limit_per_hour = excluded.reminder_rate_limit_per_hour,
+			base_currency = excluded.base_currency,
 			updated_at = excluded.updated_at
 	`
 
@@ -62,6 +63,7 @@ func (r *AppSettingsRepository) Upsert(ctx context.Context, settings *models.App
 		settings.DefaultLanguage,
 		boolToInt(settings.DisableAutoDetect),
 		settings.ReminderRateLimitPerHour,
+		settings.BaseCurrency,
 		now,
 	)
 	return err
@@ -74,6 +76,7 @@ func rowToAppSettings(row *AppSettingsRow) *models.AppSettings {
 		DefaultLanguage:          row.DefaultLanguage,
 		DisableAutoDetect:        intToBool(row.DisableAutoDetect),
 		ReminderRateLimitPerHour: row.ReminderRateLimitPerHour,
+		BaseCurrency:             row.BaseCurrency,
 	}
 	settings.UpdatedAt, _ = time.Parse(time.RFC3339, row.UpdatedAt)
 	return settings
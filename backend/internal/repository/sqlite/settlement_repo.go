@@ -0,0 +1,144 @@
+package sqlite
+
+import (
+	"context"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/models"
+)
+
+// SettlementTransferRow represents a settlement transfer row in SQLite
+type SettlementTransferRow struct {
+	ID          string `db:"id"`
+	Fingerprint string `db:"fingerprint"`
+	FromUserID  string `db:"from_user_id"`
+	ToUserID    string `db:"to_user_id"`
+	AmountPLN   string `db:"amount_pln"`
+	CreatedAt   string `db:"created_at"`
+}
+
+// SettlementBillShareRow represents a settlement bill share row in SQLite
+type SettlementBillShareRow struct {
+	ID          string `db:"id"`
+	Fingerprint string `db:"fingerprint"`
+	BillID      string `db:"bill_id"`
+	UserID      string `db:"user_id"`
+	AmountPLN   string `db:"amount_pln"`
+	CreatedAt   string `db:"created_at"`
+}
+
+// SettlementRepository implements repository.SettlementRepository for SQLite
+type SettlementRepository struct {
+	db DBTX
+}
+
+// NewSettlementRepository creates a new SQLite settlement repository
+func NewSettlementRepository(db DBTX) *SettlementRepository {
+	return &SettlementRepository{db: db}
+}
+
+// CreateTransfer records a transfer of an applied settlement plan
+func (r *SettlementRepository) CreateTransfer(ctx context.Context, transfer *models.SettlementTransfer) error {
+	query := `
+		INSERT INTO settlement_transfers (id, fingerprint, from_user_id, to_user_id, amount_pln, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		transfer.ID,
+		transfer.Fingerprint,
+		transfer.FromUserID,
+		transfer.ToUserID,
+		transfer.AmountPLN,
+		transfer.CreatedAt.UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// ListTransfers returns all settlement transfers, oldest first
+func (r *SettlementRepository) ListTransfers(ctx context.Context) ([]models.SettlementTransfer, error) {
+	var rows []SettlementTransferRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM settlement_transfers ORDER BY created_at, rowid")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToSettlementTransfers(rows), nil
+}
+
+// ListTransfersByFingerprint returns the transfers of one applied settlement plan
+func (r *SettlementRepository) ListTransfersByFingerprint(ctx context.Context, fingerprint string) ([]models.SettlementTransfer, error) {
+	var rows []SettlementTransferRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM settlement_transfers WHERE fingerprint = ? ORDER BY rowid", fingerprint)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToSettlementTransfers(rows), nil
+}
+
+// CreateBillShare records a bill share cleared by an applied settlement plan
+func (r *SettlementRepository) CreateBillShare(ctx context.Context, share *models.SettlementBillShare) error {
+	query := `
+		INSERT INTO settlement_bill_shares (id, fingerprint, bill_id, user_id, amount_pln, created_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		share.ID,
+		share.Fingerprint,
+		share.BillID,
+		share.UserID,
+		share.AmountPLN,
+		share.CreatedAt.UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// ListBillShares returns all bill shares cleared by settlements
+func (r *SettlementRepository) ListBillShares(ctx context.Context) ([]models.SettlementBillShare, error) {
+	var rows []SettlementBillShareRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM settlement_bill_shares ORDER BY created_at, rowid")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToSettlementBillShares(rows), nil
+}
+
+// ListBillSharesByBillID returns the shares of a bill cleared by settlements
+func (r *SettlementRepository) ListBillSharesByBillID(ctx context.Context, billID string) ([]models.SettlementBillShare, error) {
+	var rows []SettlementBillShareRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM settlement_bill_shares WHERE bill_id = ? ORDER BY rowid", billID)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToSettlementBillShares(rows), nil
+}
+
+func rowsToSettlementTransfers(rows []SettlementTransferRow) []models.SettlementTransfer {
+	transfers := make([]models.SettlementTransfer, len(rows))
+	for i, row := range rows {
+		transfers[i] = models.SettlementTransfer{
+			ID:          row.ID,
+			Fingerprint: row.Fingerprint,
+			FromUserID:  row.FromUserID,
+			ToUserID:    row.ToUserID,
+			AmountPLN:   row.AmountPLN,
+		}
+		transfers[i].CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
+	}
+	return transfers
+}
+
+func rowsToSettlementBillShares(rows []SettlementBillShareRow) []models.SettlementBillShare {
+	shares := make([]models.SettlementBillShare, len(rows))
+	for i, row := range rows {
+		shares[i] = models.SettlementBillShare{
+			ID:          row.ID,
+			Fingerprint: row.Fingerprint,
+			BillID:      row.BillID,
+			UserID:      row.UserID,
+			AmountPLN:   row.AmountPLN,
+		}
+		shares[i].CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
+	}
+	return shares
+}
@@ -6,7 +6,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
@@ -25,11 +24,11 @@ type SupplySettingsRow struct {
 
 // SupplySettingsRepository implements repository.SupplySettingsRepository for SQLite
 type SupplySettingsRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewSupplySettingsRepository creates a new SQLite supply settings repository
-func NewSupplySettingsRepository(db *sqlx.DB) *SupplySettingsRepository {
+func NewSupplySettingsRepository(db DBTX) *SupplySettingsRepository {
 	return &SupplySettingsRepository{db: db}
 }
 
@@ -94,29 +93,32 @@ func rowToSupplySettings(row *SupplySettingsRow) *models.SupplySettings {
 
 // SupplyItemRow represents a supply item row in SQLite
 type SupplyItemRow struct {
-	ID                    string  `db:"id"`
-	Name                  string  `db:"name"`
-	Category              string  `db:"category"`
-	CurrentQuantity       int     `db:"current_quantity"`
-	MinQuantity           int     `db:"min_quantity"`
-	Unit                  string  `db:"unit"`
-	Priority              int     `db:"priority"`
-	AddedByUserID         string  `db:"added_by_user_id"`
-	AddedAt               string  `db:"added_at"`
-	LastRestockedAt       *string `db:"last_restocked_at"`
-	LastRestockedByUserID *string `db:"last_restocked_by_user_id"`
-	LastRestockAmountPLN  *string `db:"last_restock_amount_pln"`
-	NeedsRefund           int     `db:"needs_refund"`
-	Notes                 *string `db:"notes"`
+	ID                        string  `db:"id"`
+	Name                      string  `db:"name"`
+	Category                  string  `db:"category"`
+	CurrentQuantity           int     `db:"current_quantity"`
+	MinQuantity               int     `db:"min_quantity"`
+	Unit                      string  `db:"unit"`
+	Priority                  int     `db:"priority"`
+	AddedByUserID             string  `db:"added_by_user_id"`
+	AddedAt                   string  `db:"added_at"`
+	LastRestockedAt           *string `db:"last_restocked_at"`
+	LastRestockedByUserID     *string `db:"last_restocked_by_user_id"`
+	LastRestockAmountPLN      *string `db:"last_restock_amount_pln"`
+	LastRestockCurrency       *string `db:"last_restock_currency"`
+	LastRestockOriginalAmount *string `db:"last_restock_original_amount"`
+	LastRestockExchangeRate   *string `db:"last_restock_exchange_rate"`
+	NeedsRefund               int     `db:"needs_refund"`
+	Notes                     *string `db:"notes"`
 }
 
 // SupplyItemRepository implements repository.SupplyItemRepository for SQLite
 type SupplyItemRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewSupplyItemRepository creates a new SQLite supply item repository
-func NewSupplyItemRepository(db *sqlx.DB) *SupplyItemRepository {
+func NewSupplyItemRepository(db DBTX) *SupplyItemRepository {
 	return &SupplyItemRepository{db: db}
 }
 
@@ -126,8 +128,9 @@ func (r *SupplyItemRepository) Create(ctx context.Context, item *models.SupplyIt
 
 	query := `
 		INSERT INTO supply_items (id, name, category, current_quantity, min_quantity, unit, priority,
-			added_by_user_id, added_at, last_restocked_at, last_restocked_by_user_id, last_restock_amount_pln, needs_refund, notes)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+			added_by_user_id, added_at, last_restocked_at, last_restocked_by_user_id, last_restock_amount_pln,
+			last_restock_currency, last_restock_original_amount, last_restock_exchange_rate, needs_refund, notes)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
 	`
 
 	var lastRestockedAt *string
@@ -149,6 +152,9 @@ func (r *SupplyItemRepository) Create(ctx context.Context, item *models.SupplyIt
 		lastRestockedAt,
 		item.LastRestockedByUserID,
 		item.LastRestockAmountPLN,
+		item.LastRestockCurrency,
+		item.LastRestockOriginalAmount,
+		item.LastRestockExchangeRate,
 		boolToInt(item.NeedsRefund),
 		item.Notes,
 	)
@@ -179,7 +185,8 @@ func (r *SupplyItemRepository) Update(ctx context.Context, item *models.SupplyIt
 	query := `
 		UPDATE supply_items SET
 			name = ?, category = ?, current_quantity = ?, min_quantity = ?, unit = ?, priority = ?,
-			last_restocked_at = ?, last_restocked_by_user_id = ?, last_restock_amount_pln = ?, needs_refund = ?, notes = ?
+			last_restocked_at = ?, last_restocked_by_user_id = ?, last_restock_amount_pln = ?, last_restock_currency = ?,
+			last_restock_original_amount = ?, last_restock_exchange_rate = ?, needs_refund = ?, notes = ?
 		WHERE id = ?
 	`
 
@@ -193,6 +200,9 @@ func (r *SupplyItemRepository) Update(ctx context.Context, item *models.SupplyIt
 		lastRestockedAt,
 		item.LastRestockedByUserID,
 		item.LastRestockAmountPLN,
+		item.LastRestockCurrency,
+		item.LastRestockOriginalAmount,
+		item.LastRestockExchangeRate,
 		boolToInt(item.NeedsRefund),
 		item.Notes,
 		item.ID,
@@ -238,18 +248,21 @@ func (r *SupplyItemRepository) ListLowStock(ctx context.Context) ([]models.Suppl
 
 func rowToSupplyItem(row *SupplyItemRow) *models.SupplyItem {
 	item := &models.SupplyItem{
-		ID:                    row.ID,
-		Name:                  row.Name,
-		Category:              row.Category,
-		CurrentQuantity:       row.CurrentQuantity,
-		MinQuantity:           row.MinQuantity,
-		Unit:                  row.Unit,
-		Priority:              row.Priority,
-		AddedByUserID:         row.AddedByUserID,
-		LastRestockedByUserID: row.LastRestockedByUserID,
-		LastRestockAmountPLN:  row.LastRestockAmountPLN,
-		NeedsRefund:           intToBool(row.NeedsRefund),
-		Notes:                 row.Notes,
+		ID:                        row.ID,
+		Name:                      row.Name,
+		Category:                  row.Category,
+		CurrentQuantity:           row.CurrentQuantity,
+		MinQuantity:               row.MinQuantity,
+		Unit:                      row.Unit,
+		Priority:                  row.Priority,
+		AddedByUserID:             row.AddedByUserID,
+		LastRestockedByUserID:     row.LastRestockedByUserID,
+		LastRestockAmountPLN:      row.LastRestockAmountPLN,
+		LastRestockCurrency:       row.LastRestockCurrency,
+		LastRestockOriginalAmount: row.LastRestockOriginalAmount,
+		LastRestockExchangeRate:   row.LastRestockExchangeRate,
+		NeedsRefund:               intToBool(row.NeedsRefund),
+		Notes:                     row.Notes,
 	}
 	item.AddedAt, _ = time.Parse(time.RFC3339, row.AddedAt)
 
@@ -283,17 +296,19 @@ type SupplyContributionRow struct {
 
 // SupplyContributionRepository implements repository.SupplyContributionRepository for SQLite
 type SupplyContributionRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewSupplyContributionRepository creates a new SQLite supply contribution repository
-func NewSupplyContributionRepository(db *sqlx.DB) *SupplyContributionRepository {
+func NewSupplyContributionRepository(db DBTX) *SupplyContributionRepository {
 	return &SupplyContributionRepository{db: db}
 }
 
 // Create creates a new supply contribution
 func (r *SupplyContributionRepository) Create(ctx context.Context, contribution *models.SupplyContribution) error {
-	id := uuid.New().String()
+	if contribution.ID == "" {
+		contribution.ID = uuid.New().String()
+	}
 	now := time.Now().UTC().Format(time.RFC3339)
 
 	query := `
@@ -302,7 +317,7 @@ func (r *SupplyContributionRepository) Create(ctx context.Context, contribution
 	`
 
 	_, err := r.db.ExecContext(ctx, query,
-		id,
+		contribution.ID,
 		contribution.UserID,
 		contribution.AmountPLN,
 		contribution.PeriodStart.UTC().Format(time.RFC3339),
@@ -367,15 +382,7 @@ func (r *SupplyContributionRepository) ListByPeriod(ctx context.Context, start,
 
 // SumByUserID returns total contributions by user
 func (r *SupplyContributionRepository) SumByUserID(ctx context.Context, userID string) (string, error) {
-	var sum sql.NullString
-	err := r.db.GetContext(ctx, &sum, "SELECT COALESCE(SUM(CAST(amount_pln AS REAL)), 0) FROM supply_contributions WHERE user_id = ?", userID)
-	if err != nil {
-		return "0", err
-	}
-	if !sum.Valid || sum.String == "" {
-		return "0", nil
-	}
-	return sum.String, nil
+	return sumMoney(ctx, r.db, "SELECT amount_pln FROM supply_contributions WHERE user_id = ?", userID)
 }
 
 func rowToSupplyContribution(row *SupplyContributionRow) *models.SupplyContribution {
@@ -416,11 +423,11 @@ type SupplyItemHistoryRow struct {
 
 // SupplyItemHistoryRepository implements repository.SupplyItemHistoryRepository for SQLite
 type SupplyItemHistoryRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewSupplyItemHistoryRepository creates a new SQLite supply item history repository
-func NewSupplyItemHistoryRepository(db *sqlx.DB) *SupplyItemHistoryRepository {
+func NewSupplyItemHistoryRepository(db DBTX) *SupplyItemHistoryRepository {
 	return &SupplyItemHistoryRepository{db: db}
 }
 
@@ -469,6 +476,16 @@ func (r *SupplyItemHistoryRepository) ListByUserID(ctx context.Context, userID s
 	return rowsToSupplyItemHistories(rows), nil
 }
 
+// List returns the history of all supply items
+func (r *SupplyItemHistoryRepository) List(ctx context.Context) ([]models.SupplyItemHistory, error) {
+	var rows []SupplyItemHistoryRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM supply_item_history ORDER BY created_at")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToSupplyItemHistories(rows), nil
+}
+
 func rowToSupplyItemHistory(row *SupplyItemHistoryRow) *models.SupplyItemHistory {
 	history := &models.SupplyItemHistory{
 		ID:            row.ID,
@@ -0,0 +1,154 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/models"
+)
+
+// TariffRow represents a tariff row in SQLite
+type TariffRow struct {
+	ID          string  `db:"id"`
+	UtilityType string  `db:"utility_type"`
+	Name        string  `db:"name"`
+	ValidFrom   string  `db:"valid_from"`
+	ValidTo     *string `db:"valid_to"`
+	Components  string  `db:"components"`
+	CreatedAt   string  `db:"created_at"`
+}
+
+// TariffRepository implements repository.TariffRepository for SQLite
+type TariffRepository struct {
+	db DBTX
+}
+
+// NewTariffRepository creates a new SQLite tariff repository
+func NewTariffRepository(db DBTX) *TariffRepository {
+	return &TariffRepository{db: db}
+}
+
+// Create creates a new tariff
+func (r *TariffRepository) Create(ctx context.Context, tariff *models.Tariff) error {
+	componentsJSON, _ := json.Marshal(tariffComponents(tariff.Components))
+
+	query := `
+		INSERT INTO tariffs (id, utility_type, name, valid_from, valid_to, components, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		tariff.ID,
+		tariff.UtilityType,
+		tariff.Name,
+		tariff.ValidFrom.UTC().Format(time.RFC3339),
+		formatTariffTime(tariff.ValidTo),
+		string(componentsJSON),
+		tariff.CreatedAt.UTC().Format(time.RFC3339),
+	)
+	return err
+}
+
+// GetByID retrieves a tariff by ID
+func (r *TariffRepository) GetByID(ctx context.Context, id string) (*models.Tariff, error) {
+	var row TariffRow
+	err := r.db.GetContext(ctx, &row, "SELECT * FROM tariffs WHERE id = ?", id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return rowToTariff(&row), nil
+}
+
+// Update updates an existing tariff
+func (r *TariffRepository) Update(ctx context.Context, tariff *models.Tariff) error {
+	componentsJSON, _ := json.Marshal(tariffComponents(tariff.Components))
+
+	query := `
+		UPDATE tariffs SET
+			utility_type = ?, name = ?, valid_from = ?, valid_to = ?, components = ?
+		WHERE id = ?
+	`
+	_, err := r.db.ExecContext(ctx, query,
+		tariff.UtilityType,
+		tariff.Name,
+		tariff.ValidFrom.UTC().Format(time.RFC3339),
+		formatTariffTime(tariff.ValidTo),
+		string(componentsJSON),
+		tariff.ID,
+	)
+	return err
+}
+
+// Delete deletes a tariff
+func (r *TariffRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, "DELETE FROM tariffs WHERE id = ?", id)
+	return err
+}
+
+// List returns all tariffs
+func (r *TariffRepository) List(ctx context.Context) ([]models.Tariff, error) {
+	var rows []TariffRow
+	err := r.db.SelectContext(ctx, &rows, "SELECT * FROM tariffs ORDER BY utility_type, valid_from, rowid")
+	if err != nil {
+		return nil, err
+	}
+	return rowsToTariffs(rows), nil
+}
+
+// ListByUtilityType returns the tariffs of a utility, oldest first
+func (r *TariffRepository) ListByUtilityType(ctx context.Context, utilityType string) ([]models.Tariff, error) {
+	var rows []TariffRow
+	err := r.db.SelectContext(ctx, &rows,
+		"SELECT * FROM tariffs WHERE utility_type = ? COLLATE NOCASE ORDER BY valid_from, rowid", utilityType)
+	if err != nil {
+		return nil, err
+	}
+	return rowsToTariffs(rows), nil
+}
+
+// tariffComponents keeps a tariff without components stored as an empty list
+func tariffComponents(components []models.TariffComponent) []models.TariffComponent {
+	if components == nil {
+		return []models.TariffComponent{}
+	}
+	return components
+}
+
+func formatTariffTime(t *time.Time) *string {
+	if t == nil {
+		return nil
+	}
+	value := t.UTC().Format(time.RFC3339)
+	return &value
+}
+
+func rowToTariff(row *TariffRow) *models.Tariff {
+	tariff := &models.Tariff{
+		ID:          row.ID,
+		UtilityType: row.UtilityType,
+		Name:        row.Name,
+	}
+	tariff.ValidFrom, _ = time.Parse(time.RFC3339, row.ValidFrom)
+	if row.ValidTo != nil {
+		validTo, err := time.Parse(time.RFC3339, *row.ValidTo)
+		if err == nil {
+			tariff.ValidTo = &validTo
+		}
+	}
+	tariff.CreatedAt, _ = time.Parse(time.RFC3339, row.CreatedAt)
+	json.Unmarshal([]byte(row.Components), &tariff.Components)
+	tariff.Components = tariffComponents(tariff.Components)
+	return tariff
+}
+
+func rowsToTariffs(rows []TariffRow) []models.Tariff {
+	tariffs := make([]models.Tariff, len(rows))
+	for i, row := range rows {
+		tariffs[i] = *rowToTariff(&row)
+	}
+	return tariffs
+}
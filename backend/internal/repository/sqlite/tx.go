@@ -0,0 +1,41 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/jmoiron/sqlx"
+	"github.com/sainaif/holy-home/internal/repository"
+)
+
+// TxManager implements repository.TxManager for SQLite
+type TxManager struct {
+	db *sqlx.DB
+}
+
+// NewTxManager creates a new SQLite transaction manager
+func NewTxManager(db *sqlx.DB) *TxManager {
+	return &TxManager{db: db}
+}
+
+// WithTx runs fn with repositories bound to a new transaction.
+// The connection pool holds a single connection, so fn must only use the
+// repositories it is given; using outer repositories would block.
+func (m *TxManager) WithTx(ctx context.Context, fn func(ctx context.Context, repos *repository.Repositories) error) error {
+	tx, err := m.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+
+	if err := fn(ctx, NewRepositories(tx)); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	return nil
+}
@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/google/uuid"
-	"github.com/jmoiron/sqlx"
 	"github.com/sainaif/holy-home/internal/models"
 )
 
@@ -28,11 +27,11 @@ type UserRow struct {
 
 // UserRepository implements repository.UserRepository for SQLite
 type UserRepository struct {
-	db *sqlx.DB
+	db DBTX
 }
 
 // NewUserRepository creates a new SQLite user repository
-func NewUserRepository(db *sqlx.DB) *UserRepository {
+func NewUserRepository(db DBTX) *UserRepository {
 	return &UserRepository{db: db}
 }
 
@@ -2,8 +2,11 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"time"
 
 	"github.com/sainaif/holy-home/internal/models"
 	"github.com/sainaif/holy-home/internal/repository"
@@ -11,234 +14,357 @@ import (
 )
 
 type AllocationService struct {
-	users        repository.UserRepository
-	groups       repository.GroupRepository
-	consumptions repository.ConsumptionRepository
-	allocations  repository.AllocationRepository
-	bills        repository.BillRepository
+	users              repository.UserRepository
+	groups             repository.GroupRepository
+	residencies        repository.ResidencyRepository
+	groupWeightChanges repository.GroupWeightChangeRepository
+	membershipChanges  repository.GroupMembershipChangeRepository
+	consumptions       repository.ConsumptionRepository
+	meters             repository.MeterRepository
+	meterReplacements  repository.MeterReplacementRepository
+	tariffs            repository.TariffRepository
+	allocations        repository.AllocationRepository
+	bills              repository.BillRepository
 }
 
 func NewAllocationService(
 	users repository.UserRepository,
 	groups repository.GroupRepository,
+	residencies repository.ResidencyRepository,
+	groupWeightChanges repository.GroupWeightChangeRepository,
+	membershipChanges repository.GroupMembershipChangeRepository,
 	consumptions repository.ConsumptionRepository,
+	meters repository.MeterRepository,
+	meterReplacements repository.MeterReplacementRepository,
+	tariffs repository.TariffRepository,
 	allocations repository.AllocationRepository,
 	bills repository.BillRepository,
 ) *AllocationService {
 	return &AllocationService{
-		users:        users,
-		groups:       groups,
-		consumptions: consumptions,
-		allocations:  allocations,
-		bills:        bills,
+		users:              users,
+		groups:             groups,
+		residencies:        residencies,
+		groupWeightChanges: groupWeightChanges,
+		membershipChanges:  membershipChanges,
+		consumptions:       consumptions,
+		meters:             meters,
+		meterReplacements:  meterReplacements,
+		tariffs:            tariffs,
+		allocations:        allocations,
+		bills:              bills,
 	}
 }
 
 // AllocationBreakdown represents cost breakdown per user/group
 type AllocationBreakdown struct {
-	SubjectID   string  `json:"subjectId"`
-	SubjectType string  `json:"subjectType"` // "user" or "group"
-	SubjectName string  `json:"subjectName"`
-	Weight      float64 `json:"weight"`
-	Amount      float64 `json:"amount"`
+	SubjectID   string      `json:"subjectId"`
+	SubjectType string      `json:"subjectType"` // "user" or "group"
+	SubjectName string      `json:"subjectName"`
+	Weight      float64     `json:"weight"`
+	Amount      utils.Money `json:"amount"`
+	// Share of the bill period the subject's members lived in the household, when not all of it
+	Presence *float64 `json:"presence,omitempty"`
 	// For metered allocation (electricity)
-	PersonalAmount *float64 `json:"personalAmount,omitempty"`
-	SharedAmount   *float64 `json:"sharedAmount,omitempty"`
-	Units          *float64 `json:"units,omitempty"`
+	PersonalAmount *utils.Money `json:"personalAmount,omitempty"`
+	SharedAmount   *utils.Money `json:"sharedAmount,omitempty"`
+	Units          *float64     `json:"units,omitempty"`
+	// For bills priced by a tariff
+	Components []AllocationComponent `json:"components,omitempty"`
 }
 
-// CalculateSimpleAllocation divides total cost by weights
-func (s *AllocationService) CalculateSimpleAllocation(ctx context.Context, billID string, totalAmount float64) ([]AllocationBreakdown, error) {
-	// Get all active users
-	users, err := s.users.ListActive(ctx)
+// AllocationComponent is a subject's share of one tariff component
+type AllocationComponent struct {
+	Name     string      `json:"name"`
+	Kind     string      `json:"kind"` // fixed, variable, remainder
+	Register *string     `json:"register,omitempty"`
+	Units    *float64    `json:"units,omitempty"` // variable: units charged at the component's rate
+	Amount   utils.Money `json:"amount"`
+}
+
+// allocationSubject is a user or group receiving a share of a bill
+type allocationSubject struct {
+	id          string
+	subjectType string
+	name        string
+	weight      float64 // summed weight of the subject's members, prorated by their days present
+	fullWeight  float64 // summed average weight of the subject's members over their days present
+	memberCount int
+}
+
+// allocationStay is the days of the bill period a user belonged to a subject
+type allocationStay struct {
+	user    models.User
+	groupID *string
+	from    time.Time // first day
+	to      time.Time // last day, inclusive
+}
+
+// collectSubjects groups the users living in the household during the bill period into
+// allocation subjects. Users with recorded residencies take part with the group of each
+// stay, their weight prorated by the days of the bill period they lived here. Users without
+// any take part while active, for the whole period, with the group they belonged to on
+// each day. Group weights are those in effect on each day, so changes of groups and
+// weights do not alter the split of earlier periods.
+// Users in a group are aggregated to the group, others are listed individually.
+// Subjects are ordered groups first, then individual users, each in listing order.
+func (s *AllocationService) collectSubjects(ctx context.Context, billID string) ([]*allocationSubject, error) {
+	bill, err := s.bills.GetByID(ctx, billID)
+	if err != nil || bill == nil {
+		return nil, errors.New("bill not found")
+	}
+	start, end := residencyDay(bill.PeriodStart), residencyDay(bill.PeriodEnd)
+	if end.Before(start) {
+		end = start
+	}
+
+	users, err := s.users.List(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get users: %w", err)
 	}
 
-	if len(users) == 0 {
-		return nil, errors.New("no active users found")
+	residencies, err := s.residencies.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get residencies: %w", err)
+	}
+	residenciesByUser := make(map[string][]models.Residency)
+	for _, r := range residencies {
+		residenciesByUser[r.UserID] = append(residenciesByUser[r.UserID], r)
+	}
+
+	membershipChanges, err := s.membershipChanges.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group history: %w", err)
+	}
+	membershipChangesByUser := make(map[string][]models.GroupMembershipChange)
+	for _, c := range membershipChanges {
+		membershipChangesByUser[c.UserID] = append(membershipChangesByUser[c.UserID], c)
+	}
+
+	var stays []allocationStay
+	for _, u := range users {
+		userResidencies, ok := residenciesByUser[u.ID]
+		if !ok {
+			if u.IsActive {
+				stays = append(stays, membershipStays(u, membershipChangesByUser[u.ID], start, end)...)
+			}
+			continue
+		}
+		for _, r := range userResidencies {
+			from, to := start, end
+			if stayStart := residencyDay(r.StartDate); stayStart.After(from) {
+				from = stayStart
+			}
+			if r.EndDate != nil {
+				if stayEnd := residencyDay(*r.EndDate); stayEnd.Before(to) {
+					to = stayEnd
+				}
+			}
+			if !to.Before(from) {
+				stays = append(stays, allocationStay{user: u, groupID: r.GroupID, from: from, to: to})
+			}
+		}
+	}
+
+	if len(stays) == 0 {
+		return nil, errors.New("no users lived in the household during the bill period")
 	}
 
-	// Get all groups
 	groups, err := s.groups.List(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get groups: %w", err)
 	}
 
-	// Build group weight map
-	groupWeights := make(map[string]float64)
+	groupsByID := make(map[string]models.Group, len(groups))
 	for _, g := range groups {
-		groupWeights[g.ID] = g.Weight
+		groupsByID[g.ID] = g
 	}
 
-	// Calculate weights for each user
-	userWeights := make(map[string]float64)
+	weightChanges, err := s.groupWeightChanges.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get weight history: %w", err)
+	}
+	weightChangesByGroup := make(map[string][]models.GroupWeightChange)
+	for _, c := range weightChanges {
+		weightChangesByGroup[c.GroupID] = append(weightChangesByGroup[c.GroupID], c)
+	}
+
+	periodDays := stayDays(start, end)
+	var groupSubjects, individualSubjects []*allocationSubject
+	subjectIndex := make(map[string]*allocationSubject)
+	counted := make(map[string]bool) // subject and user pairs already counted as members
 	totalWeight := 0.0
+	for _, stay := range stays {
+		subjectType, subjectID, name := "user", stay.user.ID, stay.user.Name
+		if stay.groupID != nil {
+			subjectType, subjectID, name = "group", *stay.groupID, groupsByID[*stay.groupID].Name
+		}
 
-	for _, u := range users {
-		weight := 1.0 // default weight
-		if u.GroupID != nil {
-			if gw, ok := groupWeights[*u.GroupID]; ok {
-				weight = gw
+		// Sum of the weight in effect on each day of the stay
+		weightDays := 0.0
+		for day := stay.from; !day.After(stay.to); day = day.AddDate(0, 0, 1) {
+			weight := 1.0 // default weight
+			if g, ok := groupsByID[subjectID]; ok && subjectType == "group" {
+				weight = groupWeightOn(g, weightChangesByGroup[g.ID], day)
+			}
+			weightDays += weight
+		}
+
+		key := subjectType + ":" + subjectID
+		subject, ok := subjectIndex[key]
+		if !ok {
+			subject = &allocationSubject{id: subjectID, subjectType: subjectType, name: name}
+			subjectIndex[key] = subject
+			if subjectType == "group" {
+				groupSubjects = append(groupSubjects, subject)
+			} else {
+				individualSubjects = append(individualSubjects, subject)
 			}
 		}
-		userWeights[u.ID] = weight
-		totalWeight += weight
+
+		// A user who left and came back within the period is still one member
+		subject.weight += weightDays / periodDays
+		totalWeight += weightDays / periodDays
+		if memberKey := key + ":" + stay.user.ID; !counted[memberKey] {
+			counted[memberKey] = true
+			subject.fullWeight += weightDays / stayDays(stay.from, stay.to)
+			subject.memberCount++
+		}
 	}
 
 	if totalWeight == 0 {
 		return nil, errors.New("total weight is zero")
 	}
 
-	// Group users by group or show individually
-	groupAllocations := make(map[string]struct {
-		groupID   string
-		groupName string
-		weight    float64
-		amount    float64
-	})
-	individualAllocations := []AllocationBreakdown{}
+	return append(groupSubjects, individualSubjects...), nil
+}
 
-	for _, u := range users {
-		weight := userWeights[u.ID]
-		amount := (weight / totalWeight) * totalAmount
-
-		if u.GroupID != nil {
-			// User is in a group - aggregate to group
-			if existing, ok := groupAllocations[*u.GroupID]; ok {
-				existing.amount += amount
-				groupAllocations[*u.GroupID] = existing
-			} else {
-				// Find group name
-				groupName := ""
-				for _, g := range groups {
-					if g.ID == *u.GroupID {
-						groupName = g.Name
-						break
-					}
-				}
-				groupAllocations[*u.GroupID] = struct {
-					groupID   string
-					groupName string
-					weight    float64
-					amount    float64
-				}{
-					groupID:   *u.GroupID,
-					groupName: groupName,
-					weight:    weight,
-					amount:    amount,
-				}
-			}
-		} else {
-			// User is not in a group - show individually with Name
-			individualAllocations = append(individualAllocations, AllocationBreakdown{
-				SubjectID:   u.ID,
-				SubjectType: "user",
-				SubjectName: u.Name,
-				Weight:      weight,
-				Amount:      utils.RoundToTwoDecimals(amount),
-			})
+// membershipStays splits the days from start to end at the user's group changes, which
+// are ordered oldest first
+func membershipStays(user models.User, changes []models.GroupMembershipChange, start, end time.Time) []allocationStay {
+	var stays []allocationStay
+	from := start
+	for _, change := range changes {
+		day := residencyDay(change.EffectiveFrom)
+		if !day.After(from) || day.After(end) {
+			continue
 		}
+		stays = append(stays, allocationStay{user: user, groupID: groupOn(user, changes, from), from: from, to: day.AddDate(0, 0, -1)})
+		from = day
 	}
+	return append(stays, allocationStay{user: user, groupID: groupOn(user, changes, from), from: from, to: end})
+}
 
-	// Build final breakdown
-	breakdown := make([]AllocationBreakdown, 0, len(groupAllocations)+len(individualAllocations))
-
-	// Add group allocations
-	for _, ga := range groupAllocations {
-		breakdown = append(breakdown, AllocationBreakdown{
-			SubjectID:   ga.groupID,
-			SubjectType: "group",
-			SubjectName: ga.groupName,
-			Weight:      ga.weight,
-			Amount:      utils.RoundToTwoDecimals(ga.amount),
-		})
+// groupOn returns the group a user belonged to on a day: the old group of the first change
+// after the day, or the current group
+func groupOn(user models.User, changes []models.GroupMembershipChange, day time.Time) *string {
+	for _, change := range changes {
+		if residencyDay(change.EffectiveFrom).After(day) {
+			return change.OldGroupID
+		}
 	}
+	return user.GroupID
+}
 
-	// Add individual allocations
-	breakdown = append(breakdown, individualAllocations...)
+// groupWeightOn returns the weight of a group on a day: the old weight of the first change
+// after the day, or the current weight
+func groupWeightOn(group models.Group, changes []models.GroupWeightChange, day time.Time) float64 {
+	for _, change := range changes {
+		if residencyDay(change.EffectiveFrom).After(day) {
+			return change.OldWeight
+		}
+	}
+	return group.Weight
+}
 
-	return breakdown, nil
+// stayDays counts the days from one day to another, both included
+func stayDays(from, to time.Time) float64 {
+	return math.Round(to.Sub(from).Hours()/24) + 1
 }
 
-// CalculateMeteredAllocation calculates based on meter readings + shared common area
-func (s *AllocationService) CalculateMeteredAllocation(ctx context.Context, billID string, totalAmount float64, totalUnits *float64) ([]AllocationBreakdown, error) {
-	if totalUnits == nil || *totalUnits == 0 {
-		return nil, errors.New("totalUnits is required for metered allocation")
+// displayWeight returns the per-member weight shown in the breakdown
+func (a *allocationSubject) displayWeight() float64 {
+	if a.memberCount == 0 {
+		return 0
 	}
+	return a.fullWeight / float64(a.memberCount)
+}
 
-	// Get all consumptions for this bill
-	consumptions, err := s.consumptions.ListByBillID(ctx, billID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get consumptions: %w", err)
+// presence returns the share of the bill period the subject's members lived in the
+// household, nil when all of them were present the whole period
+func (a *allocationSubject) presence() *float64 {
+	if a.fullWeight == 0 || a.weight >= a.fullWeight {
+		return nil
 	}
+	return floatPtr(utils.RoundToThreeDecimals(a.weight / a.fullWeight))
+}
 
-	// Get all active users
-	users, err := s.users.ListActive(ctx)
+// CalculateSimpleAllocation divides total cost by weights.
+// Shares are distributed with the largest remainder method so they sum exactly to totalAmount.
+func (s *AllocationService) CalculateSimpleAllocation(ctx context.Context, billID string, totalAmount utils.Money) ([]AllocationBreakdown, error) {
+	subjects, err := s.collectSubjects(ctx, billID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get users: %w", err)
+		return nil, err
 	}
 
-	if len(users) == 0 {
-		return nil, errors.New("no active users found")
+	weights := make([]float64, len(subjects))
+	for i, subject := range subjects {
+		weights[i] = subject.weight
 	}
+	shares := totalAmount.Allocate(weights)
 
-	// Get all groups
-	groups, err := s.groups.List(ctx)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get groups: %w", err)
+	breakdown := make([]AllocationBreakdown, 0, len(subjects))
+	for i, subject := range subjects {
+		breakdown = append(breakdown, AllocationBreakdown{
+			SubjectID:   subject.id,
+			SubjectType: subject.subjectType,
+			SubjectName: subject.name,
+			Weight:      subject.displayWeight(),
+			Presence:    subject.presence(),
+			Amount:      shares[i],
+		})
 	}
 
-	// Build group weight map
-	groupWeights := make(map[string]float64)
-	for _, g := range groups {
-		groupWeights[g.ID] = g.Weight
-	}
+	return breakdown, nil
+}
 
-	// Calculate user weights
-	userWeights := make(map[string]float64)
-	totalWeight := 0.0
+// CalculateMeteredAllocation calculates based on meter readings + shared common area.
+// The bill is split into a personal pool (proportional to metered units) and a shared
+// pool (proportional to weights); both pools are distributed exactly.
+func (s *AllocationService) CalculateMeteredAllocation(ctx context.Context, billID string, totalAmount utils.Money, totalUnits *float64) ([]AllocationBreakdown, error) {
+	if totalUnits == nil || *totalUnits == 0 {
+		return nil, errors.New("totalUnits is required for metered allocation")
+	}
 
-	for _, u := range users {
-		weight := 1.0 // default weight
-		if u.GroupID != nil {
-			if gw, ok := groupWeights[*u.GroupID]; ok {
-				weight = gw
-			}
-		}
-		userWeights[u.ID] = weight
-		totalWeight += weight
+	// Get all consumptions for this bill
+	consumptions, err := s.consumptions.ListByBillID(ctx, billID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consumptions: %w", err)
 	}
 
-	if totalWeight == 0 {
-		return nil, errors.New("total weight is zero")
+	subjects, err := s.collectSubjects(ctx, billID)
+	if err != nil {
+		return nil, err
 	}
 
-	// Calculate total consumed units from readings (aggregated by subject)
+	// Calculate consumed units from readings (aggregated by subject)
 	subjectUnits := make(map[string]float64)
-	totalConsumedUnits := 0.0
-
 	for _, c := range consumptions {
-		units := utils.DecimalStringToFloat(c.Units)
-
-		if units <= 0 && c.MeterValue != nil {
-			derivedUnits, derr := s.deriveUnitsFromMeter(ctx, c)
-			switch {
-			case derr == nil:
-				units = derivedUnits
-			case errors.Is(derr, ErrNoPreviousReading):
-				units = utils.DecimalStringToFloat(*c.MeterValue)
-			default:
-				return nil, derr
-			}
+		units, err := s.consumedUnits(ctx, c)
+		if err != nil {
+			return nil, err
 		}
-
-		if units <= 0 {
-			continue
-		}
-
 		subjectUnits[c.SubjectID] += units // Aggregate units per subject (group or user)
-		totalConsumedUnits += units
+	}
+
+	// Only readings of subjects taking part in the split count towards the personal pool,
+	// otherwise their share of the bill would not be allocated to anyone
+	unitWeights := make([]float64, len(subjects))
+	shareWeights := make([]float64, len(subjects))
+	totalConsumedUnits := 0.0
+	for i, subject := range subjects {
+		unitWeights[i] = subjectUnits[subject.id]
+		shareWeights[i] = subject.weight
+		totalConsumedUnits += unitWeights[i]
 	}
 
 	// Calculate personal and shared pools
@@ -246,114 +372,149 @@ func (s *AllocationService) CalculateMeteredAllocation(ctx context.Context, bill
 	if personalPoolRatio > 1.0 {
 		personalPoolRatio = 1.0 // cap at 100%
 	}
-	sharedPoolRatio := 1.0 - personalPoolRatio
 
-	personalPool := totalAmount * personalPoolRatio
-	sharedPool := totalAmount * sharedPoolRatio
+	personalPool := totalAmount.MulFloat(personalPoolRatio)
+	sharedPool := totalAmount - personalPool
 
-	// Calculate rate per unit for personal usage
-	ratePerUnit := 0.0
+	personalShares := make([]utils.Money, len(subjects))
 	if totalConsumedUnits > 0 {
-		ratePerUnit = personalPool / totalConsumedUnits
+		personalShares = personalPool.Allocate(unitWeights)
 	}
+	sharedShares := sharedPool.Allocate(shareWeights)
 
-	// Group users by group or show individually
-	groupAllocations := make(map[string]struct {
-		groupID        string
-		groupName      string
-		weight         float64
-		personalAmount float64
-		sharedAmount   float64
-		units          float64
-	})
-	individualAllocations := []AllocationBreakdown{}
+	breakdown := make([]AllocationBreakdown, 0, len(subjects))
+	for i, subject := range subjects {
+		personalAmount := personalShares[i]
+		sharedAmount := sharedShares[i]
+		breakdown = append(breakdown, AllocationBreakdown{
+			SubjectID:      subject.id,
+			SubjectType:    subject.subjectType,
+			SubjectName:    subject.name,
+			Weight:         subject.displayWeight(),
+			Presence:       subject.presence(),
+			Amount:         personalAmount + sharedAmount,
+			PersonalAmount: &personalAmount,
+			SharedAmount:   &sharedAmount,
+			Units:          floatPtr(utils.RoundToThreeDecimals(unitWeights[i])),
+		})
+	}
 
-	for _, u := range users {
-		weight := userWeights[u.ID]
-
-		// Determine subject ID for consumption lookup
-		var subjectID string
-		if u.GroupID != nil {
-			subjectID = *u.GroupID
-		} else {
-			subjectID = u.ID
+	return breakdown, nil
+}
+
+// CalculateTariffAllocation prices a bill by the components of its tariff. Fixed components are
+// split by weight and variable components charged on each subject's units of the matching
+// register and tier. Whatever the tariff does not explain of the bill total, such as usage of
+// common areas or taxes, is split by weight as a remainder so the shares sum to totalAmount.
+func (s *AllocationService) CalculateTariffAllocation(ctx context.Context, billID string, totalAmount utils.Money, tariff *models.Tariff) ([]AllocationBreakdown, error) {
+	consumptions, err := s.consumptions.ListByBillID(ctx, billID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get consumptions: %w", err)
+	}
+
+	subjects, err := s.collectSubjects(ctx, billID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Units per subject and register, readings without a register count under ""
+	registerUnits := make(map[string]map[string]float64)
+	for _, c := range consumptions {
+		units, err := s.consumedUnits(ctx, c)
+		if err != nil {
+			return nil, err
+		}
+		if registerUnits[c.SubjectID] == nil {
+			registerUnits[c.SubjectID] = make(map[string]float64)
 		}
+		register := ""
+		if c.Register != nil {
+			register = *c.Register
+		}
+		registerUnits[c.SubjectID][register] += units
+	}
 
-		// Personal amount based on consumption
-		units := subjectUnits[subjectID]
-		personalAmount := units * ratePerUnit
+	weights := make([]float64, len(subjects))
+	for i, subject := range subjects {
+		weights[i] = subject.weight
+	}
 
-		// Shared amount based on weight
-		sharedAmount := (weight / totalWeight) * sharedPool
+	breakdown := make([]AllocationBreakdown, len(subjects))
+	totalUnits := make([]float64, len(subjects))
+	personal := make([]utils.Money, len(subjects))
+	shared := make([]utils.Money, len(subjects))
+	charged := utils.Money(0)
+	for i, subject := range subjects {
+		for _, units := range registerUnits[subject.id] {
+			totalUnits[i] += units
+		}
+	}
 
-		if u.GroupID != nil {
-			// User is in a group - aggregate to group
-			if existing, ok := groupAllocations[*u.GroupID]; ok {
-				existing.sharedAmount += sharedAmount
-				// Personal amount is already aggregated at the group level from consumption
-				groupAllocations[*u.GroupID] = existing
-			} else {
-				// Find group name
-				groupName := ""
-				for _, g := range groups {
-					if g.ID == *u.GroupID {
-						groupName = g.Name
-						break
-					}
-				}
-				groupAllocations[*u.GroupID] = struct {
-					groupID        string
-					groupName      string
-					weight         float64
-					personalAmount float64
-					sharedAmount   float64
-					units          float64
-				}{
-					groupID:        *u.GroupID,
-					groupName:      groupName,
-					weight:         weight,
-					personalAmount: personalAmount, // From group's total consumption
-					sharedAmount:   sharedAmount,
-					units:          units,
+	for _, component := range tariff.Components {
+		switch component.Kind {
+		case "fixed":
+			var amount utils.Money
+			if component.Amount != nil {
+				amount = utils.MoneyFromString(*component.Amount)
+			}
+			shares := amount.Allocate(weights)
+			for i, share := range shares {
+				breakdown[i].Components = append(breakdown[i].Components, AllocationComponent{
+					Name:   component.Name,
+					Kind:   component.Kind,
+					Amount: share,
+				})
+				shared[i] += share
+				charged += share
+			}
+		case "variable":
+			rate := tariffValue(component.Rate, 0)
+			from := tariffValue(component.TierFrom, 0)
+			to := tariffValue(component.TierTo, math.Inf(1))
+			for i, subject := range subjects {
+				units := totalUnits[i]
+				if component.Register != nil {
+					units = registerUnits[subject.id][*component.Register]
 				}
+				tierUnits := math.Max(0, math.Min(units, to)-from)
+				amount := utils.MoneyFromFloat(tierUnits * rate)
+				breakdown[i].Components = append(breakdown[i].Components, AllocationComponent{
+					Name:     component.Name,
+					Kind:     component.Kind,
+					Register: component.Register,
+					Units:    floatPtr(utils.RoundToThreeDecimals(tierUnits)),
+					Amount:   amount,
+				})
+				personal[i] += amount
+				charged += amount
 			}
-		} else {
-			// User is not in a group - show individually
-			totalUserAmount := personalAmount + sharedAmount
-
-			individualAllocations = append(individualAllocations, AllocationBreakdown{
-				SubjectID:      u.ID,
-				SubjectType:    "user",
-				SubjectName:    u.Name,
-				Weight:         weight,
-				Amount:         utils.RoundToTwoDecimals(totalUserAmount),
-				PersonalAmount: floatPtr(utils.RoundToTwoDecimals(personalAmount)),
-				SharedAmount:   floatPtr(utils.RoundToTwoDecimals(sharedAmount)),
-				Units:          floatPtr(utils.RoundToThreeDecimals(units)),
-			})
 		}
 	}
 
-	// Build final breakdown
-	breakdown := make([]AllocationBreakdown, 0, len(groupAllocations)+len(individualAllocations))
-
-	// Add group allocations
-	for _, ga := range groupAllocations {
-		totalAmount := ga.personalAmount + ga.sharedAmount
-		breakdown = append(breakdown, AllocationBreakdown{
-			SubjectID:      ga.groupID,
-			SubjectType:    "group",
-			SubjectName:    ga.groupName,
-			Weight:         ga.weight,
-			Amount:         utils.RoundToTwoDecimals(totalAmount),
-			PersonalAmount: floatPtr(utils.RoundToTwoDecimals(ga.personalAmount)),
-			SharedAmount:   floatPtr(utils.RoundToTwoDecimals(ga.sharedAmount)),
-			Units:          floatPtr(utils.RoundToThreeDecimals(ga.units)),
-		})
+	if remainder := totalAmount - charged; remainder != 0 {
+		for i, share := range remainder.Allocate(weights) {
+			breakdown[i].Components = append(breakdown[i].Components, AllocationComponent{
+				Name:   "Remainder",
+				Kind:   "remainder",
+				Amount: share,
+			})
+			shared[i] += share
+		}
 	}
 
-	// Add individual allocations
-	breakdown = append(breakdown, individualAllocations...)
+	for i, subject := range subjects {
+		personalAmount := personal[i]
+		sharedAmount := shared[i]
+		breakdown[i].SubjectID = subject.id
+		breakdown[i].SubjectType = subject.subjectType
+		breakdown[i].SubjectName = subject.name
+		breakdown[i].Weight = subject.displayWeight()
+		breakdown[i].Presence = subject.presence()
+		breakdown[i].Amount = personalAmount + sharedAmount
+		breakdown[i].PersonalAmount = &personalAmount
+		breakdown[i].SharedAmount = &sharedAmount
+		breakdown[i].Units = floatPtr(utils.RoundToThreeDecimals(totalUnits[i]))
+	}
 
 	return breakdown, nil
 }
@@ -371,8 +532,6 @@ func (s *AllocationService) GetAllocationBreakdown(ctx context.Context, billID s
 		breakdown := make([]AllocationBreakdown, 0, len(storedAllocations))
 
 		for _, alloc := range storedAllocations {
-			amount := utils.DecimalStringToFloat(alloc.AllocatedPLN)
-
 			// Get subject name
 			var subjectName string
 			if alloc.SubjectType == "user" {
@@ -387,13 +546,20 @@ func (s *AllocationService) GetAllocationBreakdown(ctx context.Context, billID s
 				}
 			}
 
-			breakdown = append(breakdown, AllocationBreakdown{
-				SubjectID:   alloc.SubjectID,
-				SubjectType: alloc.SubjectType,
-				SubjectName: subjectName,
-				Weight:      1.0, // Not applicable for stored allocations
-				Amount:      utils.RoundToTwoDecimals(amount),
-			})
+			// Allocations frozen at posting keep their breakdown; older ones only have the amount
+			share := AllocationBreakdown{Weight: 1.0}
+			if alloc.Detail != nil {
+				if err := json.Unmarshal([]byte(*alloc.Detail), &share); err != nil {
+					return nil, fmt.Errorf("failed to decode allocation %s: %w", alloc.ID, err)
+				}
+			}
+			share.SubjectID = alloc.SubjectID
+			share.SubjectType = alloc.SubjectType
+			if subjectName != "" {
+				share.SubjectName = subjectName
+			}
+			share.Amount = utils.MoneyFromString(alloc.AllocatedPLN)
+			breakdown = append(breakdown, share)
 		}
 
 		return breakdown, nil
@@ -407,7 +573,16 @@ func (s *AllocationService) GetAllocationBreakdown(ctx context.Context, billID s
 	}
 
 	// Get total amount
-	totalAmount := utils.DecimalStringToFloat(bill.TotalAmountPLN)
+	totalAmount := utils.MoneyFromString(bill.TotalAmountPLN)
+
+	// Bills of a utility with a tariff are priced by its components
+	tariff, err := billTariff(ctx, s.tariffs, bill)
+	if err != nil {
+		return nil, err
+	}
+	if tariff != nil {
+		return s.CalculateTariffAllocation(ctx, billID, totalAmount, tariff)
+	}
 
 	// Determine allocation type
 	allocationType := "simple" // default
@@ -432,44 +607,33 @@ func floatPtr(f float64) *float64 {
 	return &f
 }
 
-// deriveUnitsFromMeter calculates usage delta when only meter readings were stored (legacy data)
-func (s *AllocationService) deriveUnitsFromMeter(ctx context.Context, consumption models.Consumption) (float64, error) {
-	if consumption.MeterValue == nil {
-		return 0, nil
-	}
-
-	currentValue := utils.DecimalStringToFloat(*consumption.MeterValue)
-
-	// Get all consumptions for this subject to find the most recent one before this reading
-	consumptions, err := s.consumptions.ListBySubject(ctx, consumption.SubjectType, consumption.SubjectID)
-	if err != nil {
-		return 0, fmt.Errorf("failed to fetch consumptions: %w", err)
-	}
-
-	var previous *models.Consumption
-	for i := range consumptions {
-		c := &consumptions[i]
-		if c.MeterValue == nil {
-			continue
+// consumedUnits returns the units a reading counts towards its subject's usage. Readings that
+// only stored a meter value derive their units from the previous reading.
+func (s *AllocationService) consumedUnits(ctx context.Context, c models.Consumption) (float64, error) {
+	units := utils.DecimalStringToFloat(c.Units)
+
+	if units <= 0 && c.MeterValue != nil {
+		derivedUnits, err := s.deriveUnitsFromMeter(ctx, c)
+		switch {
+		case err == nil:
+			units = derivedUnits
+		case errors.Is(err, ErrNoPreviousReading):
+			units = utils.DecimalStringToFloat(*c.MeterValue)
+		default:
+			return 0, err
 		}
-		if !c.RecordedAt.Before(consumption.RecordedAt) {
-			continue
-		}
-		if previous == nil || c.RecordedAt.After(previous.RecordedAt) {
-			previous = c
-		}
-	}
-
-	if previous == nil || previous.MeterValue == nil {
-		return 0, ErrNoPreviousReading
 	}
 
-	prevValue := utils.DecimalStringToFloat(*previous.MeterValue)
+	return math.Max(units, 0), nil
+}
 
-	units := currentValue - prevValue
-	if units < 0 {
-		return 0, errors.New("meter reading cannot be lower than previous reading")
+// deriveUnitsFromMeter calculates usage delta when only meter readings were stored (legacy data)
+func (s *AllocationService) deriveUnitsFromMeter(ctx context.Context, consumption models.Consumption) (float64, error) {
+	if consumption.MeterValue == nil {
+		return 0, nil
 	}
 
-	return units, nil
+	sources := meterSources{consumptions: s.consumptions, meters: s.meters, replacements: s.meterReplacements}
+	return sources.readingUnits(ctx, consumption.SubjectType, consumption.SubjectID, consumption.MeterID, consumption.Register,
+		utils.DecimalStringToFloat(*consumption.MeterValue), consumption.RecordedAt)
 }
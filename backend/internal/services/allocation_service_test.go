@@ -1,14 +1,121 @@
 package services
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/google/uuid"
 	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
 	"github.com/sainaif/holy-home/internal/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+type memoryResidencies struct {
+	repository.ResidencyRepository
+	residencies []models.Residency
+}
+
+func (m *memoryResidencies) Create(ctx context.Context, residency *models.Residency) error {
+	m.residencies = append(m.residencies, *residency)
+	return nil
+}
+
+func (m *memoryResidencies) List(ctx context.Context) ([]models.Residency, error) {
+	return m.residencies, nil
+}
+
+func (m *memoryResidencies) ListByUserID(ctx context.Context, userID string) ([]models.Residency, error) {
+	var residencies []models.Residency
+	for _, residency := range m.residencies {
+		if residency.UserID == userID {
+			residencies = append(residencies, residency)
+		}
+	}
+	return residencies, nil
+}
+
+func (m *memoryUsers) List(ctx context.Context) ([]models.User, error) {
+	return m.users, nil
+}
+
+func (m *memoryUsers) Update(ctx context.Context, user *models.User) error {
+	for i := range m.users {
+		if m.users[i].ID == user.ID {
+			m.users[i] = *user
+		}
+	}
+	return nil
+}
+
+func (m *memoryGroups) GetByID(ctx context.Context, id string) (*models.Group, error) {
+	for _, group := range m.groups {
+		if group.ID == id {
+			return &group, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryGroups) Update(ctx context.Context, group *models.Group) error {
+	for i := range m.groups {
+		if m.groups[i].ID == group.ID {
+			m.groups[i] = *group
+		}
+	}
+	return nil
+}
+
+type memoryGroupWeightChanges struct {
+	repository.GroupWeightChangeRepository
+	changes []models.GroupWeightChange
+}
+
+func (m *memoryGroupWeightChanges) Create(ctx context.Context, change *models.GroupWeightChange) error {
+	m.changes = append(m.changes, *change)
+	return nil
+}
+
+func (m *memoryGroupWeightChanges) List(ctx context.Context) ([]models.GroupWeightChange, error) {
+	return m.changes, nil
+}
+
+func (m *memoryGroupWeightChanges) ListByGroupID(ctx context.Context, groupID string) ([]models.GroupWeightChange, error) {
+	var changes []models.GroupWeightChange
+	for _, change := range m.changes {
+		if change.GroupID == groupID {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+type memoryMembershipChanges struct {
+	repository.GroupMembershipChangeRepository
+	changes []models.GroupMembershipChange
+}
+
+func (m *memoryMembershipChanges) Create(ctx context.Context, change *models.GroupMembershipChange) error {
+	m.changes = append(m.changes, *change)
+	return nil
+}
+
+func (m *memoryMembershipChanges) List(ctx context.Context) ([]models.GroupMembershipChange, error) {
+	return m.changes, nil
+}
+
+func (m *memoryMembershipChanges) ListByUserID(ctx context.Context, userID string) ([]models.GroupMembershipChange, error) {
+	var changes []models.GroupMembershipChange
+	for _, change := range m.changes {
+		if change.UserID == userID {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
 func TestAllocationBreakdown_RoundingPrecision(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -245,3 +352,102 @@ func TestGroupAggregationLogic(t *testing.T) {
 		assert.Equal(t, 3, individualCount, "Should have 3 individual users")
 	})
 }
+
+func TestProratedAllocation(t *testing.T) {
+	ctx := context.Background()
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+	date := func(month time.Month, d int) *time.Time { t := day(month, d); return &t }
+	coupleID := "couple"
+
+	users := &memoryUsers{users: []models.User{
+		{ID: "anna", Name: "Anna", IsActive: true},
+		{ID: "bartek", Name: "Bartek"},
+		{ID: "celina", Name: "Celina", GroupID: &coupleID, IsActive: true},
+		{ID: "dawid", Name: "Dawid"},
+		{ID: "ewa", Name: "Ewa", IsActive: true},
+	}}
+	groups := &memoryGroups{groups: []models.Group{{ID: coupleID, Name: "Couple", Weight: 1}}}
+	residencies := &memoryResidencies{}
+	bills := &memoryBills{bills: []models.Bill{{
+		ID: "b1", Type: "electricity", TotalAmountPLN: "300.00", PeriodStart: day(3, 1), PeriodEnd: day(3, 30),
+	}}}
+
+	userService := NewUserService(users, groups, residencies, &memoryMembershipChanges{}, nil, nil, nil)
+	_, err := userService.CreateResidency(ctx, "bartek", CreateResidencyRequest{StartDate: day(1, 1), EndDate: date(3, 10)})
+	require.NoError(t, err)
+	_, err = userService.CreateResidency(ctx, "bartek", CreateResidencyRequest{StartDate: day(3, 10)})
+	assert.Error(t, err, "stays of a user must not overlap")
+	_, err = userService.CreateResidency(ctx, "ewa", CreateResidencyRequest{StartDate: day(2, 1), EndDate: date(1, 31)})
+	assert.Error(t, err, "a stay must not end before it starts")
+	_, err = userService.CreateResidency(ctx, "ewa", CreateResidencyRequest{StartDate: day(1, 1), EndDate: date(2, 28)})
+	require.NoError(t, err)
+	residencies.residencies = append(residencies.residencies,
+		models.Residency{ID: "r-celina", UserID: "celina", GroupID: &coupleID, StartDate: day(3, 21)})
+
+	allocations := NewAllocationService(users, groups, residencies, &memoryGroupWeightChanges{}, &memoryMembershipChanges{}, nil, nil, nil, &memoryTariffs{}, &memoryAllocations{}, bills)
+	breakdown, err := allocations.GetAllocationBreakdown(ctx, "b1")
+	require.NoError(t, err)
+
+	// Anna without residencies pays for the whole period, Bartek for the 10 days until he
+	// moved out and Celina for the 10 days since she moved in. Dawid is inactive and Ewa
+	// moved out before the period.
+	require.Len(t, breakdown, 3)
+	assert.Equal(t, []string{"couple", "anna", "bartek"}, []string{breakdown[0].SubjectID, breakdown[1].SubjectID, breakdown[2].SubjectID})
+	assert.Equal(t, "60.00", breakdown[0].Amount.String())
+	assert.Equal(t, "180.00", breakdown[1].Amount.String())
+	assert.Equal(t, "60.00", breakdown[2].Amount.String())
+	assert.InDelta(t, 0.333, *breakdown[0].Presence, 0.001)
+	assert.Nil(t, breakdown[1].Presence)
+	assert.Equal(t, 1.0, breakdown[2].Weight)
+}
+
+func TestVersionedGroupAllocation(t *testing.T) {
+	ctx := context.Background()
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+	date := func(month time.Month, d int) *time.Time { t := day(month, d); return &t }
+	weight := func(w float64) *float64 { return &w }
+	coupleID := "couple"
+
+	users := &memoryUsers{users: []models.User{
+		{ID: "anna", Name: "Anna", IsActive: true},
+		{ID: "bartek", Name: "Bartek", IsActive: true},
+		{ID: "celina", Name: "Celina", GroupID: &coupleID, IsActive: true},
+	}}
+	groups := &memoryGroups{groups: []models.Group{{ID: coupleID, Name: "Couple", Weight: 1}}}
+	weightChanges := &memoryGroupWeightChanges{}
+	membershipChanges := &memoryMembershipChanges{}
+	bills := &memoryBills{bills: []models.Bill{
+		{ID: "jan", Type: "electricity", TotalAmountPLN: "450.00", PeriodStart: day(1, 1), PeriodEnd: day(1, 31)},
+		{ID: "mar", Type: "electricity", TotalAmountPLN: "450.00", PeriodStart: day(3, 1), PeriodEnd: day(3, 30)},
+	}}
+
+	groupService := NewGroupService(groups, weightChanges, users, nil)
+	require.NoError(t, groupService.UpdateGroup(ctx, coupleID, UpdateGroupRequest{Weight: weight(2), WeightEffectiveFrom: date(2, 1)}))
+	err := groupService.UpdateGroup(ctx, coupleID, UpdateGroupRequest{Weight: weight(3), WeightEffectiveFrom: date(1, 15)})
+	assert.Error(t, err, "changes must be added in order")
+
+	userService := NewUserService(users, groups, &memoryResidencies{}, membershipChanges, nil, nil, nil)
+	require.NoError(t, userService.UpdateUser(ctx, "bartek", UpdateUserRequest{GroupID: &coupleID, GroupEffectiveFrom: date(3, 16)}))
+	require.Len(t, membershipChanges.changes, 1)
+	assert.Nil(t, membershipChanges.changes[0].OldGroupID)
+
+	allocations := NewAllocationService(users, groups, &memoryResidencies{}, weightChanges, membershipChanges, nil, nil, nil, &memoryTariffs{}, &memoryAllocations{}, bills)
+
+	// In January the couple still had weight 1 and Bartek lived on his own
+	breakdown, err := allocations.GetAllocationBreakdown(ctx, "jan")
+	require.NoError(t, err)
+	require.Len(t, breakdown, 3)
+	assert.Equal(t, []string{"couple", "anna", "bartek"}, []string{breakdown[0].SubjectID, breakdown[1].SubjectID, breakdown[2].SubjectID})
+	for _, b := range breakdown {
+		assert.Equal(t, "150.00", b.Amount.String())
+	}
+
+	// In March the couple weighs 2 per member and Bartek joined it for the second half
+	breakdown, err = allocations.GetAllocationBreakdown(ctx, "mar")
+	require.NoError(t, err)
+	require.Len(t, breakdown, 3)
+	assert.Equal(t, "300.00", breakdown[0].Amount.String())
+	assert.Equal(t, "100.00", breakdown[1].Amount.String())
+	assert.Equal(t, "50.00", breakdown[2].Amount.String())
+	assert.InDelta(t, 0.5, *breakdown[2].Presence, 0.001)
+}
@@ -12,11 +12,13 @@ import (
 )
 
 type AppSettingsService struct {
-	appSettings repository.AppSettingsRepository
+	appSettings   repository.AppSettingsRepository
+	bills         repository.BillRepository
+	ledgerEntries repository.LedgerEntryRepository
 }
 
-func NewAppSettingsService(appSettings repository.AppSettingsRepository) *AppSettingsService {
-	return &AppSettingsService{appSettings: appSettings}
+func NewAppSettingsService(appSettings repository.AppSettingsRepository, bills repository.BillRepository, ledgerEntries repository.LedgerEntryRepository) *AppSettingsService {
+	return &AppSettingsService{appSettings: appSettings, bills: bills, ledgerEntries: ledgerEntries}
 }
 
 // GetSettings retrieves app settings (creates default if not exists)
@@ -34,6 +36,7 @@ func (s *AppSettingsService) GetSettings(ctx context.Context) (*models.AppSettin
 			DefaultLanguage:          "en",
 			DisableAutoDetect:        false,
 			ReminderRateLimitPerHour: 1,
+			BaseCurrency:             DefaultBaseCurrency,
 			UpdatedAt:                time.Now(),
 		}
 
@@ -66,6 +69,7 @@ type UpdateSettingsInput struct {
 	DefaultLanguage          *string `json:"defaultLanguage"`
 	DisableAutoDetect        *bool   `json:"disableAutoDetect"`
 	ReminderRateLimitPerHour *int    `json:"reminderRateLimitPerHour"`
+	BaseCurrency             *string `json:"baseCurrency"`
 }
 
 // UpdateSettings updates app settings (ADMIN only - enforced at handler)
@@ -101,6 +105,28 @@ func (s *AppSettingsService) UpdateSettings(ctx context.Context, input UpdateSet
 		settings.ReminderRateLimitPerHour = *input.ReminderRateLimitPerHour
 	}
 
+	if input.BaseCurrency != nil {
+		currency, err := NormalizeCurrency(*input.BaseCurrency)
+		if err != nil {
+			return err
+		}
+		if currency != settings.BaseCurrency {
+			// Stored amounts are in the old base currency, so it is fixed once money has been recorded
+			count, err := s.ledgerEntries.Count(ctx)
+			if err != nil {
+				return fmt.Errorf("database error: %w", err)
+			}
+			bills, err := s.bills.List(ctx)
+			if err != nil {
+				return fmt.Errorf("database error: %w", err)
+			}
+			if count > 0 || len(bills) > 0 {
+				return errors.New("base currency cannot be changed after financial records exist")
+			}
+			settings.BaseCurrency = currency
+		}
+	}
+
 	settings.UpdatedAt = time.Now()
 
 	if err := s.appSettings.Upsert(ctx, settings); err != nil {
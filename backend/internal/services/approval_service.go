@@ -4,19 +4,72 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"log"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/sainaif/holy-home/internal/models"
 	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/sainaif/holy-home/internal/utils"
 )
 
+// ErrApprovalExecutionFailed is returned when an approved action could not be carried out
+var ErrApprovalExecutionFailed = errors.New("approved action failed")
+
+var errApprovalAlreadyProcessed = errors.New("request not found or already processed")
+
+// ApprovalExecutor carries out the action stored in an approved request.
+// It runs inside a transaction and must only use the repositories it is given.
+// The returned map is stored as the request result.
+type ApprovalExecutor func(ctx context.Context, repos *repository.Repositories, request *models.ApprovalRequest) (map[string]interface{}, error)
+
+type approvalExecutorKey struct {
+	action       string
+	resourceType string
+}
+
 type ApprovalService struct {
-	approvalRequests repository.ApprovalRequestRepository
+	approvalRequests    repository.ApprovalRequestRepository
+	approvalPolicies    repository.ApprovalPolicyRepository
+	users               repository.UserRepository
+	roleService         *RoleService
+	notificationService *NotificationService
+	txManager           repository.TxManager
+	auditService        *AuditService
+	executors           map[approvalExecutorKey]ApprovalExecutor
+}
+
+func NewApprovalService(
+	approvalRequests repository.ApprovalRequestRepository,
+	approvalPolicies repository.ApprovalPolicyRepository,
+	users repository.UserRepository,
+	roleService *RoleService,
+	notificationService *NotificationService,
+	txManager repository.TxManager,
+	auditService *AuditService,
+) *ApprovalService {
+	return &ApprovalService{
+		approvalRequests:    approvalRequests,
+		approvalPolicies:    approvalPolicies,
+		users:               users,
+		roleService:         roleService,
+		notificationService: notificationService,
+		txManager:           txManager,
+		auditService:        auditService,
+		executors:           make(map[approvalExecutorKey]ApprovalExecutor),
+	}
 }
 
-func NewApprovalService(approvalRequests repository.ApprovalRequestRepository) *ApprovalService {
-	return &ApprovalService{approvalRequests: approvalRequests}
+// RegisterExecutor registers the executor run when a request for action on resourceType is approved
+func (s *ApprovalService) RegisterExecutor(action, resourceType string, executor ApprovalExecutor) {
+	s.executors[approvalExecutorKey{action: action, resourceType: resourceType}] = executor
+}
+
+// HasExecutor reports whether approving action on resourceType can be carried out automatically
+func (s *ApprovalService) HasExecutor(action, resourceType string) bool {
+	_, ok := s.executors[approvalExecutorKey{action: action, resourceType: resourceType}]
+	return ok
 }
 
 // CreateApprovalRequest creates a new approval request
@@ -28,6 +81,18 @@ func (s *ApprovalService) CreateApprovalRequest(
 	resourceID *string,
 	details map[string]interface{},
 ) (*models.ApprovalRequest, error) {
+	// Fill in requester info so reviewers can see who asked
+	if (userEmail == "" || userName == "") && s.users != nil {
+		if user, err := s.users.GetByID(ctx, userID); err == nil && user != nil {
+			if userEmail == "" {
+				userEmail = user.Email
+			}
+			if userName == "" {
+				userName = user.Name
+			}
+		}
+	}
+
 	// Convert details map to JSON string
 	var detailsJSON string
 	if details != nil {
@@ -43,6 +108,7 @@ func (s *ApprovalService) CreateApprovalRequest(
 		Action:       action,
 		ResourceType: resourceType,
 		ResourceID:   resourceID,
+		Details:      details,
 		DetailsJSON:  detailsJSON,
 		Status:       "pending",
 		CreatedAt:    time.Now(),
@@ -51,6 +117,9 @@ func (s *ApprovalService) CreateApprovalRequest(
 	if err := s.approvalRequests.Create(ctx, request); err != nil {
 		return nil, err
 	}
+
+	log.Printf("[APPROVAL] Request created: %s on %s by %s (ID=%s)", action, resourceType, userID, request.ID)
+
 	return request, nil
 }
 
@@ -64,35 +133,117 @@ func (s *ApprovalService) GetAllRequests(ctx context.Context) ([]models.Approval
 	return s.approvalRequests.List(ctx)
 }
 
-// ApproveRequest approves an approval request
-func (s *ApprovalService) ApproveRequest(ctx context.Context, requestID, reviewerID string, notes *string) error {
+// ApproveRequest approves a pending request and executes its stored action in a transaction.
+// If the action fails the transaction is rolled back, the request is marked "failed" with the
+// error recorded, and ErrApprovalExecutionFailed is returned along with the updated request.
+// A request whose action has no registered executor is resolved the same way.
+func (s *ApprovalService) ApproveRequest(ctx context.Context, requestID, reviewerID string, notes *string) (*models.ApprovalRequest, error) {
 	request, err := s.approvalRequests.GetByID(ctx, requestID)
-	if err != nil {
-		return errors.New("request not found")
+	if err != nil || request == nil {
+		return nil, errors.New("request not found")
 	}
 
 	if request.Status != "pending" {
-		return errors.New("request not found or already processed")
+		return nil, errApprovalAlreadyProcessed
+	}
+
+	executor, ok := s.executors[approvalExecutorKey{action: request.Action, resourceType: request.ResourceType}]
+	if !ok {
+		// Nothing can carry the action out (e.g. a custom policy or a request filed before
+		// its executor existed); resolve the request as failed instead of leaving it pending
+		executor = func(ctx context.Context, repos *repository.Repositories, request *models.ApprovalRequest) (map[string]interface{}, error) {
+			return nil, fmt.Errorf("no executor registered for action %q on %q; carry it out manually", request.Action, request.ResourceType)
+		}
 	}
 
 	now := time.Now()
-	request.Status = "approved"
 	request.ReviewedBy = &reviewerID
 	request.ReviewedAt = &now
 	request.ReviewNotes = notes
 
-	return s.approvalRequests.Update(ctx, request)
+	err = s.txManager.WithTx(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		// Re-check inside the transaction so a request is never executed twice
+		current, err := repos.ApprovalRequests.GetByID(ctx, request.ID)
+		if err != nil {
+			return err
+		}
+		if current == nil || current.Status != "pending" {
+			return errApprovalAlreadyProcessed
+		}
+
+		result, err := executor(ctx, repos, request)
+		if err != nil {
+			return err
+		}
+
+		executedAt := time.Now()
+		request.Status = "approved"
+		request.Result = result
+		request.ExecutedAt = &executedAt
+		request.ExecutionError = nil
+		return repos.ApprovalRequests.Update(ctx, request)
+	})
+	if errors.Is(err, errApprovalAlreadyProcessed) {
+		return nil, err
+	}
+	if err != nil {
+		failedAt := time.Now()
+		message := err.Error()
+		request.Status = "failed"
+		request.Result = nil
+		request.ExecutedAt = &failedAt
+		request.ExecutionError = &message
+		if updateErr := s.approvalRequests.Update(ctx, request); updateErr != nil {
+			log.Printf("[APPROVAL] Failed to record failure of request %s: %v", request.ID, updateErr)
+		}
+
+		log.Printf("[APPROVAL] Executing %s on %s failed (request %s): %v", request.Action, request.ResourceType, request.ID, err)
+		s.logExecution(ctx, request, reviewerID, map[string]interface{}{"error": message}, "failure")
+		s.notifyRequester(ctx, request)
+		return request, fmt.Errorf("%w: %v", ErrApprovalExecutionFailed, err)
+	}
+
+	log.Printf("[APPROVAL] Executed %s on %s (request %s, approved by %s)", request.Action, request.ResourceType, request.ID, reviewerID)
+	s.logExecution(ctx, request, reviewerID, request.Result, "success")
+	s.notifyRequester(ctx, request)
+	return request, nil
+}
+
+// logExecution writes the outcome of an approved action to the audit log under the reviewer's name
+func (s *ApprovalService) logExecution(ctx context.Context, request *models.ApprovalRequest, reviewerID string, details map[string]interface{}, status string) {
+	if s.auditService == nil {
+		return
+	}
+
+	var reviewerEmail, reviewerName string
+	if reviewer, err := s.users.GetByID(ctx, reviewerID); err == nil && reviewer != nil {
+		reviewerEmail = reviewer.Email
+		reviewerName = reviewer.Name
+	}
+
+	auditDetails := map[string]interface{}{
+		"approvalRequestId": request.ID,
+		"requestedBy":       request.UserID,
+	}
+	for k, v := range details {
+		auditDetails[k] = v
+	}
+
+	if err := s.auditService.LogAction(ctx, reviewerID, reviewerEmail, reviewerName, request.Action, request.ResourceType,
+		request.ResourceID, auditDetails, "", "", status); err != nil {
+		log.Printf("[APPROVAL] Failed to write audit log for request %s: %v", request.ID, err)
+	}
 }
 
 // RejectRequest rejects an approval request
 func (s *ApprovalService) RejectRequest(ctx context.Context, requestID, reviewerID string, notes *string) error {
 	request, err := s.approvalRequests.GetByID(ctx, requestID)
-	if err != nil {
+	if err != nil || request == nil {
 		return errors.New("request not found")
 	}
 
 	if request.Status != "pending" {
-		return errors.New("request not found or already processed")
+		return errApprovalAlreadyProcessed
 	}
 
 	now := time.Now()
@@ -101,14 +252,245 @@ func (s *ApprovalService) RejectRequest(ctx context.Context, requestID, reviewer
 	request.ReviewedAt = &now
 	request.ReviewNotes = notes
 
-	return s.approvalRequests.Update(ctx, request)
+	if err := s.approvalRequests.Update(ctx, request); err != nil {
+		return err
+	}
+
+	log.Printf("[APPROVAL] Rejected %s on %s (request %s, rejected by %s)", request.Action, request.ResourceType, request.ID, reviewerID)
+	s.notifyRequester(ctx, request)
+
+	return nil
+}
+
+// approvalActionLabels describes approvable actions in notifications
+var approvalActionLabels = map[string]string{
+	"chore.delete":         "usunięcie obowiązku",
+	"bill.delete":          "usunięcie rachunku",
+	"loan.delete":          "usunięcie pożyczki",
+	"supply.budget_adjust": "korektę budżetu zaopatrzenia",
+	"supply.refund":        "zwrot za zakupy",
+}
+
+// notifyRequester tells the user who filed a request how it was resolved
+func (s *ApprovalService) notifyRequester(ctx context.Context, request *models.ApprovalRequest) {
+	if s.notificationService == nil {
+		return
+	}
+
+	label, ok := approvalActionLabels[request.Action]
+	if !ok {
+		label = request.Action
+	}
+
+	var title, body string
+	switch request.Status {
+	case "approved":
+		title = "Wniosek zatwierdzony"
+		body = fmt.Sprintf("Twój wniosek o %s został zatwierdzony", label)
+	case "rejected":
+		title = "Wniosek odrzucony"
+		body = fmt.Sprintf("Twój wniosek o %s został odrzucony", label)
+	case "failed":
+		title = "Wniosek nie został wykonany"
+		body = fmt.Sprintf("Twój wniosek o %s został zatwierdzony, ale nie udało się go wykonać", label)
+	default:
+		return
+	}
+	if request.ReviewNotes != nil && *request.ReviewNotes != "" {
+		body = fmt.Sprintf("%s: %s", body, *request.ReviewNotes)
+	}
+
+	userID := request.UserID
+	now := time.Now()
+	if err := s.notificationService.CreateNotification(ctx, &models.Notification{
+		ID:           uuid.New().String(),
+		Channel:      "app",
+		TemplateID:   "approval",
+		ScheduledFor: now,
+		SentAt:       &now,
+		Status:       "sent",
+		UserID:       &userID,
+		Title:        title,
+		Body:         body,
+	}); err != nil {
+		log.Printf("[APPROVAL] Failed to notify user %s about request %s: %v", request.UserID, request.ID, err)
+	}
 }
 
 // GetRequest retrieves a specific approval request
 func (s *ApprovalService) GetRequest(ctx context.Context, requestID string) (*models.ApprovalRequest, error) {
 	request, err := s.approvalRequests.GetByID(ctx, requestID)
-	if err != nil {
+	if err != nil || request == nil {
 		return nil, errors.New("request not found")
 	}
 	return request, nil
 }
+
+// ========== Policies ==========
+
+// InitializeDefaultPolicies creates the default approval policies.
+// A default is only created when no policy exists for its action, so edited or
+// disabled defaults are preserved across restarts.
+func (s *ApprovalService) InitializeDefaultPolicies(ctx context.Context) error {
+	budgetThreshold := utils.MoneyFromMinor(10000).String() // 100 PLN
+
+	defaults := []models.ApprovalPolicy{
+		{Action: "chore.delete"},
+		{Action: "bill.delete"},
+		{Action: "loan.delete"},
+		{Action: "supply.budget_adjust", MinAmountPLN: &budgetThreshold},
+		{Action: "supply.refund"},
+	}
+
+	for _, policy := range defaults {
+		existing, err := s.approvalPolicies.ListByAction(ctx, policy.Action)
+		if err != nil {
+			return err
+		}
+		if len(existing) > 0 {
+			continue
+		}
+
+		policy.ID = uuid.New().String()
+		policy.Enabled = true
+		policy.IsSystem = true
+		if err := s.approvalPolicies.Create(ctx, &policy); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListPolicies retrieves all approval policies
+func (s *ApprovalService) ListPolicies(ctx context.Context) ([]models.ApprovalPolicy, error) {
+	return s.approvalPolicies.List(ctx)
+}
+
+// CreatePolicy creates a custom approval policy
+func (s *ApprovalService) CreatePolicy(ctx context.Context, action string, role *string, minAmount *utils.Money, enabled bool) (*models.ApprovalPolicy, error) {
+	if action == "" {
+		return nil, errors.New("action is required")
+	}
+
+	policy := &models.ApprovalPolicy{
+		ID:      uuid.New().String(),
+		Action:  action,
+		Role:    normalizePolicyRole(role),
+		Enabled: enabled,
+	}
+	if err := setPolicyMinAmount(policy, minAmount); err != nil {
+		return nil, err
+	}
+
+	if err := s.approvalPolicies.Create(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// UpdatePolicy changes who a policy applies to, its threshold and whether it is enabled
+func (s *ApprovalService) UpdatePolicy(ctx context.Context, policyID string, role *string, minAmount *utils.Money, enabled bool) (*models.ApprovalPolicy, error) {
+	policy, err := s.approvalPolicies.GetByID(ctx, policyID)
+	if err != nil || policy == nil {
+		return nil, errors.New("policy not found")
+	}
+
+	policy.Role = normalizePolicyRole(role)
+	policy.Enabled = enabled
+	policy.MinAmountPLN = nil
+	if err := setPolicyMinAmount(policy, minAmount); err != nil {
+		return nil, err
+	}
+	policy.UpdatedAt = time.Now()
+
+	if err := s.approvalPolicies.Update(ctx, policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// DeletePolicy deletes a custom approval policy; default policies can only be disabled
+func (s *ApprovalService) DeletePolicy(ctx context.Context, policyID string) error {
+	policy, err := s.approvalPolicies.GetByID(ctx, policyID)
+	if err != nil || policy == nil {
+		return errors.New("policy not found")
+	}
+
+	if policy.IsSystem {
+		return errors.New("cannot delete a default policy, disable it instead")
+	}
+
+	return s.approvalPolicies.Delete(ctx, policyID)
+}
+
+// RequiresApproval reports whether a user with roleName must get approval for action.
+// Roles that can review approvals are never held. amount is compared against policy
+// thresholds; a policy with a threshold does not apply when no amount is known.
+func (s *ApprovalService) RequiresApproval(ctx context.Context, roleName, action string, amount *utils.Money) (bool, error) {
+	if s.roleService != nil {
+		isReviewer, err := s.roleService.HasPermission(ctx, roleName, "approvals.review")
+		if err != nil {
+			return false, err
+		}
+		if isReviewer {
+			return false, nil
+		}
+	}
+
+	policies, err := s.approvalPolicies.ListByAction(ctx, action)
+	if err != nil {
+		return false, err
+	}
+
+	for _, policy := range policies {
+		if !policy.Enabled {
+			continue
+		}
+		if policy.Role != nil && *policy.Role != roleName {
+			continue
+		}
+		if policy.MinAmountPLN != nil {
+			if amount == nil || amount.Abs() <= utils.MoneyFromString(*policy.MinAmountPLN) {
+				continue
+			}
+		}
+		return true, nil
+	}
+	return false, nil
+}
+
+// DecodeApprovalBody decodes the request body captured with an approval request into dest
+func DecodeApprovalBody(request *models.ApprovalRequest, dest interface{}) error {
+	body, ok := request.Details["body"]
+	if !ok || body == nil {
+		return errors.New("approval request has no request body")
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("invalid request body: %w", err)
+	}
+	return nil
+}
+
+func normalizePolicyRole(role *string) *string {
+	if role == nil || *role == "" {
+		return nil
+	}
+	return role
+}
+
+func setPolicyMinAmount(policy *models.ApprovalPolicy, minAmount *utils.Money) error {
+	if minAmount == nil {
+		return nil
+	}
+	if *minAmount < 0 {
+		return errors.New("minimum amount cannot be negative")
+	}
+	amount := minAmount.String()
+	policy.MinAmountPLN = &amount
+	return nil
+}
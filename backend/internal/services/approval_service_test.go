@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/sainaif/holy-home/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryApprovalRequests is an in-memory approval request repository.
+// Updates made inside a fake transaction are discarded when it rolls back.
+type memoryApprovalRequests struct {
+	repository.ApprovalRequestRepository
+	requests map[string]models.ApprovalRequest
+}
+
+func (m *memoryApprovalRequests) GetByID(ctx context.Context, id string) (*models.ApprovalRequest, error) {
+	request, ok := m.requests[id]
+	if !ok {
+		return nil, nil
+	}
+	return &request, nil
+}
+
+func (m *memoryApprovalRequests) Update(ctx context.Context, request *models.ApprovalRequest) error {
+	m.requests[request.ID] = *request
+	return nil
+}
+
+type memoryTxManager struct {
+	approvalRequests *memoryApprovalRequests
+}
+
+func (m *memoryTxManager) WithTx(ctx context.Context, fn func(ctx context.Context, repos *repository.Repositories) error) error {
+	snapshot := make(map[string]models.ApprovalRequest, len(m.approvalRequests.requests))
+	for id, request := range m.approvalRequests.requests {
+		snapshot[id] = request
+	}
+	if err := fn(ctx, &repository.Repositories{ApprovalRequests: m.approvalRequests}); err != nil {
+		m.approvalRequests.requests = snapshot
+		return err
+	}
+	return nil
+}
+
+func newTestApprovalService(requests ...models.ApprovalRequest) (*ApprovalService, *memoryApprovalRequests) {
+	repo := &memoryApprovalRequests{requests: make(map[string]models.ApprovalRequest)}
+	for _, request := range requests {
+		repo.requests[request.ID] = request
+	}
+	return NewApprovalService(repo, &memoryApprovalPolicies{}, nil, nil, nil, &memoryTxManager{approvalRequests: repo}, nil), repo
+}
+
+type memoryApprovalPolicies struct {
+	repository.ApprovalPolicyRepository
+	policies []models.ApprovalPolicy
+}
+
+func (m *memoryApprovalPolicies) ListByAction(ctx context.Context, action string) ([]models.ApprovalPolicy, error) {
+	var policies []models.ApprovalPolicy
+	for _, policy := range m.policies {
+		if policy.Action == action {
+			policies = append(policies, policy)
+		}
+	}
+	return policies, nil
+}
+
+func TestApproveRequest_ExecutesRegisteredAction(t *testing.T) {
+	choreID := "chore-1"
+	service, repo := newTestApprovalService(models.ApprovalRequest{
+		ID: "req-1", Action: "chore.delete", ResourceType: "chore", ResourceID: &choreID, Status: "pending",
+	})
+
+	executed := 0
+	service.RegisterExecutor("chore.delete", "chore", func(ctx context.Context, repos *repository.Repositories, request *models.ApprovalRequest) (map[string]interface{}, error) {
+		executed++
+		return map[string]interface{}{"choreId": *request.ResourceID}, nil
+	})
+
+	request, err := service.ApproveRequest(context.Background(), "req-1", "admin-1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, 1, executed)
+	assert.Equal(t, "approved", request.Status)
+	assert.Equal(t, "chore-1", request.Result["choreId"])
+	assert.NotNil(t, request.ExecutedAt)
+	assert.Equal(t, "approved", repo.requests["req-1"].Status)
+
+	// A processed request is never executed again
+	_, err = service.ApproveRequest(context.Background(), "req-1", "admin-1", nil)
+	assert.Error(t, err)
+	assert.Equal(t, 1, executed)
+}
+
+func TestApproveRequest_FailedExecutionMarksRequestFailed(t *testing.T) {
+	service, repo := newTestApprovalService(models.ApprovalRequest{
+		ID: "req-1", Action: "chore.delete", ResourceType: "chore", Status: "pending",
+	})
+	service.RegisterExecutor("chore.delete", "chore", func(ctx context.Context, repos *repository.Repositories, request *models.ApprovalRequest) (map[string]interface{}, error) {
+		return nil, errors.New("chore not found")
+	})
+
+	request, err := service.ApproveRequest(context.Background(), "req-1", "admin-1", nil)
+	assert.ErrorIs(t, err, ErrApprovalExecutionFailed)
+	require.NotNil(t, request)
+	assert.Equal(t, "failed", request.Status)
+	require.NotNil(t, request.ExecutionError)
+	assert.Equal(t, "chore not found", *request.ExecutionError)
+	assert.Equal(t, "failed", repo.requests["req-1"].Status)
+}
+
+func TestApproveRequest_UnknownActionMarksRequestFailed(t *testing.T) {
+	service, repo := newTestApprovalService(models.ApprovalRequest{
+		ID: "req-1", Action: "bill.delete", ResourceType: "bill", Status: "pending",
+	})
+
+	request, err := service.ApproveRequest(context.Background(), "req-1", "admin-1", nil)
+	assert.ErrorIs(t, err, ErrApprovalExecutionFailed)
+	require.NotNil(t, request)
+	require.NotNil(t, request.ExecutionError)
+	assert.Contains(t, *request.ExecutionError, "no executor registered")
+	assert.Equal(t, "failed", repo.requests["req-1"].Status)
+	assert.Equal(t, "admin-1", *repo.requests["req-1"].ReviewedBy)
+}
+
+func TestRequiresApproval(t *testing.T) {
+	threshold := "100.00"
+	resident := "MIESZKANIEC"
+	service := &ApprovalService{approvalPolicies: &memoryApprovalPolicies{policies: []models.ApprovalPolicy{
+		{Action: "bill.delete", Enabled: true},
+		{Action: "loan.delete", Enabled: false},
+		{Action: "chore.delete", Role: &resident, Enabled: true},
+		{Action: "supply.budget_adjust", MinAmountPLN: &threshold, Enabled: true},
+	}}}
+
+	amount := func(s string) *utils.Money {
+		m := utils.MoneyFromString(s)
+		return &m
+	}
+
+	tests := []struct {
+		name   string
+		role   string
+		action string
+		amount *utils.Money
+		want   bool
+	}{
+		{"Policy for every role", "MIESZKANIEC", "bill.delete", nil, true},
+		{"Disabled policy", "MIESZKANIEC", "loan.delete", nil, false},
+		{"No policy", "MIESZKANIEC", "supply.refund", nil, false},
+		{"Role specific policy matches", "MIESZKANIEC", "chore.delete", nil, true},
+		{"Role specific policy other role", "GOSC", "chore.delete", nil, false},
+		{"Amount over threshold", "MIESZKANIEC", "supply.budget_adjust", amount("100.01"), true},
+		{"Negative amount over threshold", "MIESZKANIEC", "supply.budget_adjust", amount("-250"), true},
+		{"Amount at threshold", "MIESZKANIEC", "supply.budget_adjust", amount("100.00"), false},
+		{"Threshold without amount", "MIESZKANIEC", "supply.budget_adjust", nil, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.RequiresApproval(context.Background(), tt.role, tt.action, tt.amount)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestDecodeApprovalBody(t *testing.T) {
+	request := &models.ApprovalRequest{Details: map[string]interface{}{
+		"body": map[string]interface{}{"adjustment": 150.5, "notes": "Nowy odkurzacz"},
+	}}
+
+	var body struct {
+		Adjustment utils.Money `json:"adjustment"`
+		Notes      string      `json:"notes"`
+	}
+	require.NoError(t, DecodeApprovalBody(request, &body))
+	assert.Equal(t, utils.MoneyFromMinor(15050), body.Adjustment)
+	assert.Equal(t, "Nowy odkurzacz", body.Notes)
+
+	assert.Error(t, DecodeApprovalBody(&models.ApprovalRequest{}, &body))
+}
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/config"
+	"github.com/sainaif/holy-home/internal/utils"
+)
+
+const backupArchiveTimeFormat = "20060102T150405Z"
+
+// Archives written with a passphrase end in .enc
+var backupArchiveNamePattern = regexp.MustCompile(`^holy-home-backup-(\d{8}T\d{6}Z)\.json\.gz(\.enc)?$`)
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// ErrBackupArchiveNotFound is returned for archive names that do not exist or are not valid archive names
+var ErrBackupArchiveNotFound = errors.New("backup archive not found")
+
+// BackupArchive is a compressed backup stored in the backup directory
+type BackupArchive struct {
+	Name      string    `json:"name"`
+	Size      int64     `json:"size"`
+	Encrypted bool      `json:"encrypted"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// BackupRetention is how many daily, weekly and monthly archives to keep.
+// The newest archive of each of the most recent N days, ISO weeks and months is kept.
+type BackupRetention struct {
+	Daily   int
+	Weekly  int
+	Monthly int
+}
+
+// BackupArchiveService writes scheduled backups to disk and manages the stored archives
+type BackupArchiveService struct {
+	backupService *BackupService
+	dir           string
+	interval      time.Duration
+	retention     BackupRetention
+	passphrase    string
+}
+
+func NewBackupArchiveService(backupService *BackupService, cfg *config.Config) *BackupArchiveService {
+	return &BackupArchiveService{
+		backupService: backupService,
+		dir:           cfg.Backup.Dir,
+		interval:      cfg.Backup.Interval,
+		retention: BackupRetention{
+			Daily:   cfg.Backup.KeepDaily,
+			Weekly:  cfg.Backup.KeepWeekly,
+			Monthly: cfg.Backup.KeepMonthly,
+		},
+		passphrase: cfg.Backup.Passphrase,
+	}
+}
+
+// RunScheduled creates a backup if the newest archive is older than the backup interval
+func (s *BackupArchiveService) RunScheduled(ctx context.Context) error {
+	if s.interval <= 0 {
+		return nil
+	}
+
+	archives, err := s.ListArchives()
+	if err != nil {
+		return err
+	}
+	if len(archives) > 0 && time.Since(archives[0].CreatedAt) < s.interval {
+		return nil
+	}
+
+	archive, err := s.CreateArchive(ctx)
+	if err != nil {
+		return err
+	}
+	log.Printf("Scheduled backup written to %s (%d bytes)", archive.Name, archive.Size)
+	return nil
+}
+
+// CreateArchive exports all data into a new verified archive and applies the retention policy
+func (s *BackupArchiveService) CreateArchive(ctx context.Context) (*BackupArchive, error) {
+	data, err := s.backupService.ExportJSON(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := s.writeArchive(data, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.ApplyRetention(); err != nil {
+		log.Printf("Failed to apply backup retention: %v", err)
+	}
+	return archive, nil
+}
+
+// writeArchive compresses data into a timestamped archive, encrypting it when a passphrase
+// is configured. The archive is written to a temporary file and only renamed into place
+// after it parses back into BackupData.
+func (s *BackupArchiveService) writeArchive(data []byte, now time.Time) (*BackupArchive, error) {
+	if err := os.MkdirAll(s.dir, 0700); err != nil {
+		return nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+
+	var compressed bytes.Buffer
+	gz := gzip.NewWriter(&compressed)
+	if _, err := gz.Write(data); err != nil {
+		return nil, fmt.Errorf("failed to compress backup: %w", err)
+	}
+	if err := gz.Close(); err != nil {
+		return nil, fmt.Errorf("failed to compress backup: %w", err)
+	}
+
+	contents := compressed.Bytes()
+	name := "holy-home-backup-" + now.UTC().Format(backupArchiveTimeFormat) + ".json.gz"
+	if s.passphrase != "" {
+		encrypted, err := utils.EncryptWithPassphrase(contents, s.passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encrypt backup: %w", err)
+		}
+		contents = encrypted
+		name += ".enc"
+	}
+
+	tmp, err := os.CreateTemp(s.dir, ".backup-*.tmp")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create backup file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(contents); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, fmt.Errorf("failed to write backup: %w", err)
+	}
+
+	if err := verifyBackupArchive(tmp.Name(), s.passphrase); err != nil {
+		return nil, fmt.Errorf("backup verification failed: %w", err)
+	}
+
+	if err := os.Rename(tmp.Name(), filepath.Join(s.dir, name)); err != nil {
+		return nil, fmt.Errorf("failed to store backup: %w", err)
+	}
+
+	return s.archive(name)
+}
+
+// verifyBackupArchive checks that an archive decrypts, decompresses and parses into BackupData
+func verifyBackupArchive(path, passphrase string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if passphrase != "" {
+		if data, err = utils.DecryptWithPassphrase(data, passphrase); err != nil {
+			return err
+		}
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer gz.Close()
+
+	var backup BackupData
+	if err := json.NewDecoder(gz).Decode(&backup); err != nil {
+		return fmt.Errorf("invalid backup data: %w", err)
+	}
+	if backup.Version == "" {
+		return errors.New("backup has no version")
+	}
+	return nil
+}
+
+// ListArchives returns the stored archives, newest first
+func (s *BackupArchiveService) ListArchives() ([]BackupArchive, error) {
+	entries, err := os.ReadDir(s.dir)
+	if os.IsNotExist(err) {
+		return []BackupArchive{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %w", err)
+	}
+
+	archives := []BackupArchive{}
+	for _, entry := range entries {
+		if entry.IsDir() || !backupArchiveNamePattern.MatchString(entry.Name()) {
+			continue
+		}
+		archive, err := s.archive(entry.Name())
+		if err != nil {
+			return nil, err
+		}
+		archives = append(archives, *archive)
+	}
+
+	sort.Slice(archives, func(i, j int) bool { return archives[i].CreatedAt.After(archives[j].CreatedAt) })
+	return archives, nil
+}
+
+// ArchivePath returns the path of a stored archive
+func (s *BackupArchiveService) ArchivePath(name string) (string, error) {
+	archive, err := s.archive(name)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(s.dir, archive.Name), nil
+}
+
+// DeleteArchive removes a stored archive
+func (s *BackupArchiveService) DeleteArchive(name string) error {
+	path, err := s.ArchivePath(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to delete backup: %w", err)
+	}
+	return nil
+}
+
+// ApplyRetention deletes archives not kept by the retention policy and returns their names.
+// Nothing is deleted if every retention count is zero.
+func (s *BackupArchiveService) ApplyRetention() ([]string, error) {
+	if s.retention == (BackupRetention{}) {
+		return nil, nil
+	}
+
+	archives, err := s.ListArchives()
+	if err != nil {
+		return nil, err
+	}
+
+	keep := retainedBackups(archives, s.retention)
+	var deleted []string
+	for _, archive := range archives {
+		if keep[archive.Name] {
+			continue
+		}
+		if err := s.DeleteArchive(archive.Name); err != nil {
+			return deleted, err
+		}
+		deleted = append(deleted, archive.Name)
+	}
+	return deleted, nil
+}
+
+// retainedBackups returns the names of archives (sorted newest first) kept by the policy
+func retainedBackups(archives []BackupArchive, retention BackupRetention) map[string]bool {
+	keep := make(map[string]bool)
+	policies := []struct {
+		count  int
+		period func(time.Time) string
+	}{
+		{retention.Daily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{retention.Weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{retention.Monthly, func(t time.Time) string { return t.Format("2006-01") }},
+	}
+
+	for _, policy := range policies {
+		seen := make(map[string]bool)
+		for _, archive := range archives {
+			if len(seen) >= policy.count {
+				break
+			}
+			period := policy.period(archive.CreatedAt.UTC())
+			if seen[period] {
+				continue
+			}
+			seen[period] = true
+			keep[archive.Name] = true
+		}
+	}
+	return keep
+}
+
+// archive describes a stored archive, rejecting names that are not backup archives
+func (s *BackupArchiveService) archive(name string) (*BackupArchive, error) {
+	match := backupArchiveNamePattern.FindStringSubmatch(name)
+	if match == nil {
+		return nil, ErrBackupArchiveNotFound
+	}
+	createdAt, err := time.Parse(backupArchiveTimeFormat, match[1])
+	if err != nil {
+		return nil, ErrBackupArchiveNotFound
+	}
+
+	info, err := os.Stat(filepath.Join(s.dir, name))
+	if os.IsNotExist(err) {
+		return nil, ErrBackupArchiveNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup: %w", err)
+	}
+
+	return &BackupArchive{Name: name, Size: info.Size(), Encrypted: match[2] != "", CreatedAt: createdAt}, nil
+}
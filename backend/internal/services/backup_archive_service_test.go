@@ -0,0 +1,92 @@
+package services
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWriteBackupArchive(t *testing.T) {
+	s := &BackupArchiveService{dir: t.TempDir()}
+	now := time.Date(2026, 3, 14, 2, 0, 0, 0, time.UTC)
+
+	archive, err := s.writeArchive([]byte(`{"version":"1.0","users":[]}`), now)
+	require.NoError(t, err)
+	assert.Equal(t, "holy-home-backup-20260314T020000Z.json.gz", archive.Name)
+	assert.True(t, archive.CreatedAt.Equal(now))
+
+	archives, err := s.ListArchives()
+	require.NoError(t, err)
+	require.Len(t, archives, 1)
+	assert.Equal(t, archive.Name, archives[0].Name)
+
+	// Data that does not parse as a backup is never stored
+	_, err = s.writeArchive([]byte(`{"users":`), now.Add(time.Hour))
+	assert.ErrorContains(t, err, "verification failed")
+	entries, _ := os.ReadDir(s.dir)
+	assert.Len(t, entries, 1)
+
+	// Only archive names are accepted, so paths outside the directory cannot be reached
+	_, err = s.ArchivePath("../" + filepath.Base(s.dir) + "/" + archive.Name)
+	assert.ErrorIs(t, err, ErrBackupArchiveNotFound)
+
+	require.NoError(t, s.DeleteArchive(archive.Name))
+	assert.ErrorIs(t, s.DeleteArchive(archive.Name), ErrBackupArchiveNotFound)
+}
+
+func TestWriteEncryptedBackupArchive(t *testing.T) {
+	s := &BackupArchiveService{dir: t.TempDir(), passphrase: "correct horse battery"}
+	now := time.Date(2026, 3, 14, 2, 0, 0, 0, time.UTC)
+
+	archive, err := s.writeArchive([]byte(`{"version":"2.0","users":[{"id":"u1","passwordHash":"secret-hash"}]}`), now)
+	require.NoError(t, err)
+	assert.Equal(t, "holy-home-backup-20260314T020000Z.json.gz.enc", archive.Name)
+	assert.True(t, archive.Encrypted)
+
+	path, err := s.ArchivePath(archive.Name)
+	require.NoError(t, err)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.True(t, utils.IsPassphraseEncrypted(data))
+	assert.NotContains(t, string(data), "secret-hash")
+
+	// The stored archive imports as downloaded, given its passphrase
+	_, err = parseBackup(data, "")
+	assert.ErrorIs(t, err, ErrBackupPassphraseRequired)
+	backup, err := parseBackup(data, "correct horse battery")
+	require.NoError(t, err)
+	assert.Equal(t, "2.0", backup.Version)
+	require.Len(t, backup.Users, 1)
+	assert.Equal(t, "u1", backup.Users[0].ID)
+}
+
+func TestRetainedBackups(t *testing.T) {
+	// One archive every 12 hours for 70 days, newest first
+	var archives []BackupArchive
+	newest := time.Date(2026, 3, 31, 12, 0, 0, 0, time.UTC) // a Tuesday
+	for i := 0; i < 140; i++ {
+		createdAt := newest.Add(-time.Duration(i) * 12 * time.Hour)
+		archives = append(archives, BackupArchive{Name: createdAt.Format(time.RFC3339), CreatedAt: createdAt})
+	}
+
+	keep := retainedBackups(archives, BackupRetention{Daily: 3, Weekly: 2, Monthly: 3})
+
+	var kept []string
+	for _, archive := range archives {
+		if keep[archive.Name] {
+			kept = append(kept, archive.Name)
+		}
+	}
+	assert.Equal(t, []string{
+		"2026-03-31T12:00:00Z", // newest of today, this week and this month
+		"2026-03-30T12:00:00Z", // newest of yesterday
+		"2026-03-29T12:00:00Z", // newest of the day before, and of last week
+		"2026-02-28T12:00:00Z", // newest of February
+		"2026-01-31T12:00:00Z", // newest of January
+	}, kept)
+}
@@ -1,9 +1,13 @@
 package services
 
 import (
+	"bytes"
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"io"
 	"strings"
 	"time"
 
@@ -17,8 +21,14 @@ type BackupService struct {
 	db                       *sqlx.DB
 	users                    repository.UserRepository
 	groups                   repository.GroupRepository
+	residencies              repository.ResidencyRepository
+	groupWeightChanges       repository.GroupWeightChangeRepository
+	membershipChanges        repository.GroupMembershipChangeRepository
 	bills                    repository.BillRepository
 	consumptions             repository.ConsumptionRepository
+	meters                   repository.MeterRepository
+	meterReplacements        repository.MeterReplacementRepository
+	tariffs                  repository.TariffRepository
 	allocations              repository.AllocationRepository
 	payments                 repository.PaymentRepository
 	loans                    repository.LoanRepository
@@ -33,14 +43,38 @@ type BackupService struct {
 	recurringBillTemplates   repository.RecurringBillTemplateRepository
 	recurringBillAllocations repository.RecurringBillAllocationRepository
 	passkeyCredentials       repository.PasskeyCredentialRepository
+	roles                    repository.RoleRepository
+	permissions              repository.PermissionRepository
+	auditLogs                repository.AuditLogRepository
+	approvalRequests         repository.ApprovalRequestRepository
+	approvalPolicies         repository.ApprovalPolicyRepository
+	choreSwapRequests        repository.ChoreSwapRequestRepository
+	userAbsences             repository.UserAbsenceRepository
+	chorePreferences         repository.ChorePreferenceRepository
+	choreCompletions         repository.ChoreCompletionRepository
+	appSettings              repository.AppSettingsRepository
+	supplyItemHistory        repository.SupplyItemHistoryRepository
+	notificationPreferences  repository.NotificationPreferenceRepository
+	webPushSubscriptions     repository.WebPushSubscriptionRepository
+	sentReminders            repository.SentReminderRepository
+	exchangeRates            repository.ExchangeRateRepository
+	ledgerEntries            repository.LedgerEntryRepository
+	settlements              repository.SettlementRepository
+	ledgerService            *LedgerService
 }
 
 func NewBackupService(
 	db *sqlx.DB,
 	users repository.UserRepository,
 	groups repository.GroupRepository,
+	residencies repository.ResidencyRepository,
+	groupWeightChanges repository.GroupWeightChangeRepository,
+	membershipChanges repository.GroupMembershipChangeRepository,
 	bills repository.BillRepository,
 	consumptions repository.ConsumptionRepository,
+	meters repository.MeterRepository,
+	meterReplacements repository.MeterReplacementRepository,
+	tariffs repository.TariffRepository,
 	allocations repository.AllocationRepository,
 	payments repository.PaymentRepository,
 	loans repository.LoanRepository,
@@ -55,13 +89,37 @@ func NewBackupService(
 	recurringBillTemplates repository.RecurringBillTemplateRepository,
 	recurringBillAllocations repository.RecurringBillAllocationRepository,
 	passkeyCredentials repository.PasskeyCredentialRepository,
+	roles repository.RoleRepository,
+	permissions repository.PermissionRepository,
+	auditLogs repository.AuditLogRepository,
+	approvalRequests repository.ApprovalRequestRepository,
+	approvalPolicies repository.ApprovalPolicyRepository,
+	choreSwapRequests repository.ChoreSwapRequestRepository,
+	userAbsences repository.UserAbsenceRepository,
+	chorePreferences repository.ChorePreferenceRepository,
+	choreCompletions repository.ChoreCompletionRepository,
+	appSettings repository.AppSettingsRepository,
+	supplyItemHistory repository.SupplyItemHistoryRepository,
+	notificationPreferences repository.NotificationPreferenceRepository,
+	webPushSubscriptions repository.WebPushSubscriptionRepository,
+	sentReminders repository.SentReminderRepository,
+	exchangeRates repository.ExchangeRateRepository,
+	ledgerEntries repository.LedgerEntryRepository,
+	settlements repository.SettlementRepository,
+	ledgerService *LedgerService,
 ) *BackupService {
 	return &BackupService{
 		db:                       db,
 		users:                    users,
 		groups:                   groups,
+		residencies:              residencies,
+		groupWeightChanges:       groupWeightChanges,
+		membershipChanges:        membershipChanges,
 		bills:                    bills,
 		consumptions:             consumptions,
+		meters:                   meters,
+		meterReplacements:        meterReplacements,
+		tariffs:                  tariffs,
 		allocations:              allocations,
 		payments:                 payments,
 		loans:                    loans,
@@ -76,9 +134,35 @@ func NewBackupService(
 		recurringBillTemplates:   recurringBillTemplates,
 		recurringBillAllocations: recurringBillAllocations,
 		passkeyCredentials:       passkeyCredentials,
+		roles:                    roles,
+		permissions:              permissions,
+		auditLogs:                auditLogs,
+		approvalRequests:         approvalRequests,
+		approvalPolicies:         approvalPolicies,
+		choreSwapRequests:        choreSwapRequests,
+		userAbsences:             userAbsences,
+		chorePreferences:         chorePreferences,
+		choreCompletions:         choreCompletions,
+		appSettings:              appSettings,
+		supplyItemHistory:        supplyItemHistory,
+		notificationPreferences:  notificationPreferences,
+		webPushSubscriptions:     webPushSubscriptions,
+		sentReminders:            sentReminders,
+		exchangeRates:            exchangeRates,
+		ledgerEntries:            ledgerEntries,
+		settlements:              settlements,
+		ledgerService:            ledgerService,
 	}
 }
 
+// BackupVersion is the format written by ExportAll. Version 2 covers every table except
+// sessions, calendar feed tokens, password reset tokens and idempotency keys; version 1
+// files are upgraded on import.
+const (
+	BackupVersion   = "2.0"
+	backupVersionV1 = "1.0"
+)
+
 // BackupUser is a User struct with all fields exported for backup purposes
 // (models.User has json:"-" on PasswordHash and TOTPSecret)
 type BackupUser struct {
@@ -117,6 +201,35 @@ type ImportResult struct {
 	DefaultPassword         string   `json:"defaultPassword"`         // The default password assigned (only if there are users with reset passwords)
 }
 
+// ErrBackupPassphraseRequired is returned when importing an encrypted backup without a passphrase
+var ErrBackupPassphraseRequired = errors.New("backup is encrypted, a passphrase is required")
+
+// ImportMode selects how a backup is applied
+type ImportMode string
+
+const (
+	ImportModeReplace ImportMode = "replace" // clear every table, then load the backup
+	ImportModeMerge   ImportMode = "merge"   // upsert backup rows by ID and keep all other data
+	ImportModeDryRun  ImportMode = "dry-run" // report what an import would change without writing
+)
+
+// ImportReport previews an import
+type ImportReport struct {
+	Version                 string              `json:"version"`
+	Tables                  []ImportTableReport `json:"tables"`
+	UsersWithResetPasswords []string            `json:"usersWithResetPasswords"` // Users without a password hash, who would get the default password
+	MergeConflicts          []string            `json:"mergeConflicts"`          // Reasons the backup cannot be imported in merge mode
+}
+
+// ImportTableReport compares the rows of one table in a backup with the database
+type ImportTableReport struct {
+	Table          string   `json:"table"`
+	BackupRows     int      `json:"backupRows"`
+	ExistingRows   int      `json:"existingRows"`
+	NewRows        int      `json:"newRows"`        // Backup rows whose ID is not in the database
+	ConflictingIDs []string `json:"conflictingIds"` // IDs in both, overwritten by the backup in either mode
+}
+
 // BackupData represents a complete system backup
 type BackupData struct {
 	Version                  string                           `json:"version"`
@@ -124,8 +237,14 @@ type BackupData struct {
 	Users                    []BackupUser                     `json:"users"`
 	PasskeyCredentials       []BackupPasskeyCredential        `json:"passkeyCredentials"`
 	Groups                   []models.Group                   `json:"groups"`
+	Residencies              []models.Residency               `json:"residencies"`
+	GroupWeightChanges       []models.GroupWeightChange       `json:"groupWeightChanges"`
+	GroupMembershipChanges   []models.GroupMembershipChange   `json:"groupMembershipChanges"`
 	Bills                    []models.Bill                    `json:"bills"`
 	Consumptions             []models.Consumption             `json:"consumptions"`
+	Meters                   []models.Meter                   `json:"meters"`
+	MeterReplacements        []models.MeterReplacement        `json:"meterReplacements"`
+	Tariffs                  []models.Tariff                  `json:"tariffs"`
 	Allocations              []repository.Allocation          `json:"allocations"`
 	Payments                 []models.Payment                 `json:"payments"`
 	Loans                    []models.Loan                    `json:"loans"`
@@ -139,12 +258,32 @@ type BackupData struct {
 	SupplyContributions      []models.SupplyContribution      `json:"supplyContributions"`
 	RecurringBillTemplates   []models.RecurringBillTemplate   `json:"recurringBillTemplates"`
 	RecurringBillAllocations []models.RecurringBillAllocation `json:"recurringBillAllocations"`
+
+	// Added in version 2
+	Roles                   []models.Role                   `json:"roles"`
+	Permissions             []models.Permission             `json:"permissions"`
+	AuditLogs               []models.AuditLog               `json:"auditLogs"`
+	ApprovalRequests        []models.ApprovalRequest        `json:"approvalRequests"`
+	ApprovalPolicies        []models.ApprovalPolicy         `json:"approvalPolicies"`
+	ChoreSwapRequests       []models.ChoreSwapRequest       `json:"choreSwapRequests"`
+	UserAbsences            []models.UserAbsence            `json:"userAbsences"`
+	ChorePreferences        []models.ChorePreference        `json:"chorePreferences"`
+	ChoreCompletions        []models.ChoreCompletion        `json:"choreCompletions"`
+	AppSettings             *models.AppSettings             `json:"appSettings,omitempty"`
+	SupplyItemHistory       []models.SupplyItemHistory      `json:"supplyItemHistory"`
+	NotificationPreferences []models.NotificationPreference `json:"notificationPreferences"`
+	WebPushSubscriptions    []models.WebPushSubscription    `json:"webPushSubscriptions"`
+	SentReminders           []models.SentReminder           `json:"sentReminders"`
+	ExchangeRates           []models.ExchangeRate           `json:"exchangeRates"`
+	LedgerEntries           []models.LedgerEntry            `json:"ledgerEntries"`
+	SettlementTransfers     []models.SettlementTransfer     `json:"settlementTransfers"`
+	SettlementBillShares    []models.SettlementBillShare    `json:"settlementBillShares"`
 }
 
 // ExportAll exports all data from all collections
 func (s *BackupService) ExportAll(ctx context.Context) (*BackupData, error) {
 	backup := &BackupData{
-		Version:    "1.0",
+		Version:    BackupVersion,
 		ExportedAt: time.Now(),
 	}
 
@@ -199,6 +338,26 @@ func (s *BackupService) ExportAll(ctx context.Context) (*BackupData, error) {
 	}
 	backup.Groups = groups
 
+	// Export residencies
+	residencies, err := s.residencies.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch residencies: %w", err)
+	}
+	backup.Residencies = residencies
+
+	// Export group history
+	groupWeightChanges, err := s.groupWeightChanges.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group weight changes: %w", err)
+	}
+	backup.GroupWeightChanges = groupWeightChanges
+
+	membershipChanges, err := s.membershipChanges.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group membership changes: %w", err)
+	}
+	backup.GroupMembershipChanges = membershipChanges
+
 	// Export bills
 	bills, err := s.bills.List(ctx)
 	if err != nil {
@@ -213,6 +372,27 @@ func (s *BackupService) ExportAll(ctx context.Context) (*BackupData, error) {
 	}
 	backup.Consumptions = consumptions
 
+	// Export meters
+	meters, err := s.meters.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch meters: %w", err)
+	}
+	backup.Meters = meters
+
+	// Export meter replacements
+	meterReplacements, err := s.meterReplacements.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch meter replacements: %w", err)
+	}
+	backup.MeterReplacements = meterReplacements
+
+	// Export tariffs
+	tariffs, err := s.tariffs.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch tariffs: %w", err)
+	}
+	backup.Tariffs = tariffs
+
 	// Export payments
 	payments, err := s.payments.List(ctx)
 	if err != nil {
@@ -302,9 +482,183 @@ func (s *BackupService) ExportAll(ctx context.Context) (*BackupData, error) {
 	}
 	backup.RecurringBillAllocations = recurringBillAllocations
 
+	// Export roles
+	roles, err := s.roles.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch roles: %w", err)
+	}
+	backup.Roles = roles
+
+	// Export permissions
+	permissions, err := s.permissions.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch permissions: %w", err)
+	}
+	backup.Permissions = permissions
+
+	// Export audit logs
+	auditLogs, err := s.auditLogs.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch audit logs: %w", err)
+	}
+	backup.AuditLogs = auditLogs
+
+	// Export approval requests
+	approvalRequests, err := s.approvalRequests.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch approval requests: %w", err)
+	}
+	backup.ApprovalRequests = approvalRequests
+
+	// Export approval policies
+	approvalPolicies, err := s.approvalPolicies.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch approval policies: %w", err)
+	}
+	backup.ApprovalPolicies = approvalPolicies
+
+	// Export chore swap requests
+	choreSwapRequests, err := s.choreSwapRequests.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chore swap requests: %w", err)
+	}
+	backup.ChoreSwapRequests = choreSwapRequests
+
+	// Export user absences
+	userAbsences, err := s.userAbsences.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch user absences: %w", err)
+	}
+	backup.UserAbsences = userAbsences
+
+	// Export chore preferences
+	chorePreferences, err := s.chorePreferences.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chore preferences: %w", err)
+	}
+	backup.ChorePreferences = chorePreferences
+
+	// Export chore completions (photos stay on disk)
+	choreCompletions, err := s.choreCompletions.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch chore completions: %w", err)
+	}
+	backup.ChoreCompletions = choreCompletions
+
+	// Export app settings (singleton)
+	appSettings, err := s.appSettings.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch app settings: %w", err)
+	}
+	backup.AppSettings = appSettings
+
+	// Export supply item history
+	supplyItemHistory, err := s.supplyItemHistory.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch supply item history: %w", err)
+	}
+	backup.SupplyItemHistory = supplyItemHistory
+
+	// Export notification preferences
+	notificationPreferences, err := s.notificationPreferences.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch notification preferences: %w", err)
+	}
+	backup.NotificationPreferences = notificationPreferences
+
+	// Export web push subscriptions
+	webPushSubscriptions, err := s.webPushSubscriptions.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch web push subscriptions: %w", err)
+	}
+	backup.WebPushSubscriptions = webPushSubscriptions
+
+	// Export sent reminders
+	sentReminders, err := s.sentReminders.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch sent reminders: %w", err)
+	}
+	backup.SentReminders = sentReminders
+
+	// Export exchange rates
+	exchangeRates, err := s.exchangeRates.ListAll(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch exchange rates: %w", err)
+	}
+	backup.ExchangeRates = exchangeRates
+
+	// Export ledger entries
+	ledgerEntries, err := s.ledgerEntries.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch ledger entries: %w", err)
+	}
+	backup.LedgerEntries = ledgerEntries
+
+	// Export applied settlements
+	settlementTransfers, err := s.settlements.ListTransfers(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch settlement transfers: %w", err)
+	}
+	backup.SettlementTransfers = settlementTransfers
+
+	settlementBillShares, err := s.settlements.ListBillShares(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch settlement bill shares: %w", err)
+	}
+	backup.SettlementBillShares = settlementBillShares
+
 	return backup, nil
 }
 
+// backupCurrencyExpr is the currency of an imported amount; backups without one use the base currency
+const backupCurrencyExpr = `COALESCE(NULLIF(?, ''), (SELECT base_currency FROM app_settings WHERE id = 'singleton'), 'PLN')`
+
+// nullableJSON marshals a JSON column that is stored as NULL when empty
+func nullableJSON(v map[string]interface{}) (*string, error) {
+	if v == nil {
+		return nil, nil
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	s := string(data)
+	return &s, nil
+}
+
+// upgradeBackupV1 converts a version 1 backup to the current format. Version 1 did not
+// contain roles, permissions, the audit trail, approvals, swap requests, absences, chore
+// preferences, chore completions, meters, tariffs, residencies, group history, app settings, sent reminders or exchange rates, and
+// importing it left those tables untouched, so their current rows are carried over. The ledger is rebuilt after the import.
+func (s *BackupService) upgradeBackupV1(ctx context.Context, backup *BackupData) error {
+	current, err := s.ExportAll(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to read current data: %w", err)
+	}
+
+	backup.Roles = current.Roles
+	backup.Permissions = current.Permissions
+	backup.AuditLogs = current.AuditLogs
+	backup.ApprovalRequests = current.ApprovalRequests
+	backup.ApprovalPolicies = current.ApprovalPolicies
+	backup.ChoreSwapRequests = current.ChoreSwapRequests
+	backup.UserAbsences = current.UserAbsences
+	backup.ChorePreferences = current.ChorePreferences
+	backup.ChoreCompletions = current.ChoreCompletions
+	backup.Meters = current.Meters
+	backup.MeterReplacements = current.MeterReplacements
+	backup.Tariffs = current.Tariffs
+	backup.Residencies = current.Residencies
+	backup.GroupWeightChanges = current.GroupWeightChanges
+	backup.GroupMembershipChanges = current.GroupMembershipChanges
+	backup.AppSettings = current.AppSettings
+	backup.SentReminders = current.SentReminders
+	backup.ExchangeRates = current.ExchangeRates
+	backup.LedgerEntries = nil
+	backup.Version = BackupVersion
+	return nil
+}
+
 // ExportJSON exports backup data as JSON string
 func (s *BackupService) ExportJSON(ctx context.Context) ([]byte, error) {
 	backup, err := s.ExportAll(ctx)
@@ -320,13 +674,46 @@ func (s *BackupService) ExportJSON(ctx context.Context) ([]byte, error) {
 	return jsonData, nil
 }
 
-// ImportJSON imports backup data from JSON string
-// WARNING: This is a destructive operation that replaces all existing data
+// ExportEncryptedJSON exports all data as JSON encrypted with a passphrase
+func (s *BackupService) ExportEncryptedJSON(ctx context.Context, passphrase string) ([]byte, error) {
+	jsonData, err := s.ExportJSON(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return utils.EncryptWithPassphrase(jsonData, passphrase)
+}
+
+// ImportJSON imports backup data from JSON string in one transaction.
+// Replace mode clears every table first and upgrades version 1 backups; it destroys all existing data.
+// Merge mode keeps existing data, replaces rows that share an ID with a backup row, keeps the
+// current settings singletons and approval policies, and refuses backups that conflict with this instance.
+// Dry runs are served by PreviewImport and rejected here, so ImportJSON always writes.
 // Returns ImportResult with information about users who got default passwords
-func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*ImportResult, error) {
-	var backup BackupData
-	if err := json.Unmarshal(jsonData, &backup); err != nil {
-		return nil, fmt.Errorf("failed to unmarshal JSON backup: %w", err)
+func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte, mode ImportMode, passphrase string) (*ImportResult, error) {
+	backup, err := parseBackup(jsonData, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	switch mode {
+	case ImportModeReplace:
+		if backup.Version == backupVersionV1 {
+			if err := s.upgradeBackupV1(ctx, backup); err != nil {
+				return nil, fmt.Errorf("failed to upgrade version 1 backup: %w", err)
+			}
+		}
+	case ImportModeMerge:
+		conflicts, err := s.mergeConflicts(ctx, backup)
+		if err != nil {
+			return nil, err
+		}
+		if len(conflicts) > 0 {
+			return nil, fmt.Errorf("backup cannot be merged: %s", strings.Join(conflicts, "; "))
+		}
+		// Approval policies configure the instance, like the settings singletons
+		backup.ApprovalPolicies = nil
+	default:
+		return nil, fmt.Errorf("unsupported import mode %q", mode)
 	}
 
 	result := &ImportResult{
@@ -354,11 +741,29 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 		s.db.ExecContext(ctx, "PRAGMA foreign_keys = ON")
 	}()
 
-	// Delete existing data in reverse dependency order
+	w := &backupWriter{tx: tx, merge: mode == ImportModeMerge}
+
+	// Delete existing data in reverse dependency order; merging keeps it
 	tablesToClear := []string{
+		"ledger_entries",
+		"settlement_bill_shares",
+		"settlement_transfers",
+		"sent_reminders",
+		"chore_swap_requests",
+		"chore_completions",
+		"chore_preferences",
+		"user_absences",
+		"residencies",
+		"group_membership_changes",
+		"group_weight_changes",
+		"approval_requests",
+		"audit_logs",
 		"loan_payments",
 		"payments",
 		"consumptions",
+		"meter_replacements",
+		"meters",
+		"tariffs",
 		"allocations",
 		"chore_assignments",
 		"supply_contributions",
@@ -375,21 +780,95 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 		"chore_settings",
 		"supply_settings",
 		"sessions",
+		"calendar_feed_tokens",
 		"password_reset_tokens",
 		"passkey_credentials",
 		"users",
 		"groups",
+		"roles",
+		"permissions",
+		"approval_policies",
+		"exchange_rates",
+		"app_settings",
 	}
 
+	if w.merge {
+		tablesToClear = nil
+	}
 	for _, table := range tablesToClear {
 		if _, err := tx.ExecContext(ctx, fmt.Sprintf("DELETE FROM %s", table)); err != nil {
 			return nil, fmt.Errorf("failed to clear table %s: %w", table, err)
 		}
 	}
 
+	// Import app settings first, the base currency is the default for imported amounts
+	if backup.AppSettings != nil {
+		as := backup.AppSettings
+		disableAutoDetect := 0
+		if as.DisableAutoDetect {
+			disableAutoDetect = 1
+		}
+
+		err := w.insertSetting(ctx,
+			`INSERT INTO app_settings (id, app_name, default_language, disable_auto_detect, reminder_rate_limit_per_hour, base_currency, updated_at)
+			VALUES ('singleton', ?, ?, ?, ?, COALESCE(NULLIF(?, ''), 'PLN'), ?)`,
+			as.AppName, as.DefaultLanguage, disableAutoDetect, as.ReminderRateLimitPerHour,
+			as.BaseCurrency, as.UpdatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import app settings: %w", err)
+		}
+	}
+
+	// Import permissions
+	for _, p := range backup.Permissions {
+		err := w.insert(ctx,
+			`INSERT INTO permissions (id, name, description, category) VALUES (?, ?, ?, ?)`,
+			p.ID, p.Name, p.Description, p.Category)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import permission %s: %w", p.Name, err)
+		}
+	}
+
+	// Import roles
+	for _, role := range backup.Roles {
+		permissionsJSON, err := json.Marshal(role.Permissions)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal permissions of role %s: %w", role.Name, err)
+		}
+		if role.Permissions == nil {
+			permissionsJSON = []byte("[]")
+		}
+		isSystem := 0
+		if role.IsSystem {
+			isSystem = 1
+		}
+
+		err = w.insert(ctx,
+			`INSERT INTO roles (id, name, display_name, is_system, permissions, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			role.ID, role.Name, role.DisplayName, isSystem, string(permissionsJSON),
+			role.CreatedAt.UTC().Format(time.RFC3339), role.UpdatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import role %s: %w", role.Name, err)
+		}
+	}
+
+	// Import exchange rates
+	for _, rate := range backup.ExchangeRates {
+		rateDate := time.Date(rate.RateDate.Year(), rate.RateDate.Month(), rate.RateDate.Day(), 0, 0, 0, 0, time.UTC)
+		err := w.insert(ctx,
+			`INSERT INTO exchange_rates (id, currency, base_currency, rate_date, rate, source, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			rate.ID, rate.Currency, rate.BaseCurrency, rateDate.Format(time.RFC3339), rate.Rate, rate.Source,
+			rate.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import exchange rate %s: %w", rate.ID, err)
+		}
+	}
+
 	// Import groups
 	for _, group := range backup.Groups {
-		_, err := tx.ExecContext(ctx,
+		err := w.insert(ctx,
 			`INSERT INTO groups (id, name, weight, created_at) VALUES (?, ?, ?, ?)`,
 			group.ID, group.Name, group.Weight, group.CreatedAt.UTC().Format(time.RFC3339))
 		if err != nil {
@@ -432,7 +911,7 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 			result.UsersWithResetPasswords = append(result.UsersWithResetPasswords, user.Email)
 		}
 
-		_, err := tx.ExecContext(ctx,
+		err := w.insert(ctx,
 			`INSERT INTO users (id, email, username, name, password_hash, role, group_id, is_active, must_change_password, totp_secret, created_at)
 			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			user.ID, user.Email, username, user.Name, passwordHash, user.Role, groupID, isActive, mustChange, totpSecret, user.CreatedAt.UTC().Format(time.RFC3339))
@@ -457,7 +936,7 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 			backupState = 1
 		}
 
-		_, err := tx.ExecContext(ctx,
+		err := w.insert(ctx,
 			`INSERT INTO passkey_credentials (id, user_id, public_key, attestation_type, aaguid, sign_count, name, backup_eligible, backup_state, created_at, last_used_at)
 			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			fmt.Sprintf("%x", pc.ID), pc.UserID, pc.PublicKey, pc.AttestationType, pc.AAGUID,
@@ -468,6 +947,47 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 		}
 	}
 
+	// Import residencies
+	for _, residency := range backup.Residencies {
+		var endDate *string
+		if residency.EndDate != nil {
+			ed := residency.EndDate.UTC().Format(time.RFC3339)
+			endDate = &ed
+		}
+
+		err := w.insert(ctx,
+			`INSERT INTO residencies (id, user_id, group_id, start_date, end_date, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			residency.ID, residency.UserID, residency.GroupID, residency.StartDate.UTC().Format(time.RFC3339), endDate,
+			residency.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import residency %s: %w", residency.ID, err)
+		}
+	}
+
+	// Import group history
+	for _, change := range backup.GroupWeightChanges {
+		err := w.insert(ctx,
+			`INSERT INTO group_weight_changes (id, group_id, old_weight, new_weight, effective_from, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			change.ID, change.GroupID, change.OldWeight, change.NewWeight,
+			change.EffectiveFrom.UTC().Format(time.RFC3339), change.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import group weight change %s: %w", change.ID, err)
+		}
+	}
+
+	for _, change := range backup.GroupMembershipChanges {
+		err := w.insert(ctx,
+			`INSERT INTO group_membership_changes (id, user_id, old_group_id, new_group_id, effective_from, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			change.ID, change.UserID, change.OldGroupID, change.NewGroupID,
+			change.EffectiveFrom.UTC().Format(time.RFC3339), change.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import group membership change %s: %w", change.ID, err)
+		}
+	}
+
 	// Import bills
 	for _, bill := range backup.Bills {
 		var paymentDeadline, reopenedAt, totalUnits *string
@@ -483,13 +1003,13 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 			totalUnits = &bill.TotalUnits
 		}
 
-		_, err := tx.ExecContext(ctx,
+		err := w.insert(ctx,
 			`INSERT INTO bills (id, type, custom_type, allocation_type, period_start, period_end, payment_deadline,
-				total_amount_pln, total_units, notes, status, reopened_at, reopen_reason, reopened_by, recurring_template_id, created_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+				total_amount_pln, currency, original_amount, exchange_rate, total_units, notes, status, reopened_at, reopen_reason, reopened_by, recurring_template_id, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, `+backupCurrencyExpr+`, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			bill.ID, bill.Type, bill.CustomType, bill.AllocationType,
 			bill.PeriodStart.UTC().Format(time.RFC3339), bill.PeriodEnd.UTC().Format(time.RFC3339),
-			paymentDeadline, bill.TotalAmountPLN, totalUnits, bill.Notes, bill.Status,
+			paymentDeadline, bill.TotalAmountPLN, bill.Currency, bill.OriginalAmount, bill.ExchangeRate, totalUnits, bill.Notes, bill.Status,
 			reopenedAt, bill.ReopenReason, bill.ReopenedBy, bill.RecurringTemplateID,
 			bill.CreatedAt.UTC().Format(time.RFC3339))
 		if err != nil {
@@ -497,13 +1017,84 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 		}
 	}
 
+	// Import meters
+	for _, meter := range backup.Meters {
+		isActive := 0
+		if meter.IsActive {
+			isActive = 1
+		}
+
+		var registers *string
+		if len(meter.Registers) > 0 {
+			registersJSON, err := json.Marshal(meter.Registers)
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal registers of meter %s: %w", meter.ID, err)
+			}
+			value := string(registersJSON)
+			registers = &value
+		}
+
+		err := w.insert(ctx,
+			`INSERT INTO meters (id, subject_type, subject_id, utility_type, serial_number, unit, rollover_at, registers, is_active, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			meter.ID, meter.SubjectType, meter.SubjectID, meter.UtilityType, meter.SerialNumber,
+			meter.Unit, meter.RolloverAt, registers, isActive, meter.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import meter %s: %w", meter.ID, err)
+		}
+	}
+
+	// Import meter replacements
+	for _, r := range backup.MeterReplacements {
+		err := w.insert(ctx,
+			`INSERT INTO meter_replacements (id, meter_id, replaced_at, old_final_value, new_initial_value, old_serial_number, new_serial_number, register, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			r.ID, r.MeterID, r.ReplacedAt.UTC().Format(time.RFC3339), r.OldFinalValue, r.NewInitialValue,
+			r.OldSerialNumber, r.NewSerialNumber, r.Register, r.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import meter replacement %s: %w", r.ID, err)
+		}
+	}
+
+	// Import tariffs
+	for _, tariff := range backup.Tariffs {
+		componentsJSON, err := json.Marshal(tariff.Components)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal components of tariff %s: %w", tariff.ID, err)
+		}
+		if tariff.Components == nil {
+			componentsJSON = []byte("[]")
+		}
+
+		var validTo *string
+		if tariff.ValidTo != nil {
+			value := tariff.ValidTo.UTC().Format(time.RFC3339)
+			validTo = &value
+		}
+
+		err = w.insert(ctx,
+			`INSERT INTO tariffs (id, utility_type, name, valid_from, valid_to, components, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			tariff.ID, tariff.UtilityType, tariff.Name, tariff.ValidFrom.UTC().Format(time.RFC3339), validTo,
+			string(componentsJSON), tariff.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import tariff %s: %w", tariff.ID, err)
+		}
+	}
+
 	// Import consumptions
 	for _, consumption := range backup.Consumptions {
-		_, err := tx.ExecContext(ctx,
-			`INSERT INTO consumptions (id, bill_id, subject_type, subject_id, units, meter_value, recorded_at, source)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		anomalyStatus := consumption.AnomalyStatus
+		if anomalyStatus == "" {
+			anomalyStatus = "none"
+		}
+
+		err := w.insert(ctx,
+			`INSERT INTO consumptions (id, bill_id, subject_type, subject_id, units, meter_value, meter_id, register, recorded_at, source, anomaly_status, anomaly_reason)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			consumption.ID, consumption.BillID, consumption.SubjectType, consumption.SubjectID,
-			consumption.Units, consumption.MeterValue, consumption.RecordedAt.UTC().Format(time.RFC3339), consumption.Source)
+			consumption.Units, consumption.MeterValue, consumption.MeterID, consumption.Register, consumption.RecordedAt.UTC().Format(time.RFC3339), consumption.Source,
+			anomalyStatus, consumption.AnomalyReason)
 		if err != nil {
 			return nil, fmt.Errorf("failed to import consumption %s: %w", consumption.ID, err)
 		}
@@ -511,10 +1102,11 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 
 	// Import payments
 	for _, payment := range backup.Payments {
-		_, err := tx.ExecContext(ctx,
-			`INSERT INTO payments (id, bill_id, payer_user_id, amount_pln, paid_at, method, reference)
-			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		err := w.insert(ctx,
+			`INSERT INTO payments (id, bill_id, payer_user_id, amount_pln, currency, original_amount, exchange_rate, paid_at, method, reference)
+			VALUES (?, ?, ?, ?, `+backupCurrencyExpr+`, ?, ?, ?, ?, ?)`,
 			payment.ID, payment.BillID, payment.PayerUserID, payment.AmountPLN,
+			payment.Currency, payment.OriginalAmount, payment.ExchangeRate,
 			payment.PaidAt.UTC().Format(time.RFC3339), payment.Method, payment.Reference)
 		if err != nil {
 			return nil, fmt.Errorf("failed to import payment %s: %w", payment.ID, err)
@@ -529,10 +1121,11 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 			dueDate = &dd
 		}
 
-		_, err := tx.ExecContext(ctx,
-			`INSERT INTO loans (id, lender_id, borrower_id, amount_pln, note, due_date, status, created_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
-			loan.ID, loan.LenderID, loan.BorrowerID, loan.AmountPLN, loan.Note, dueDate, loan.Status,
+		err := w.insert(ctx,
+			`INSERT INTO loans (id, lender_id, borrower_id, amount_pln, currency, original_amount, exchange_rate, note, due_date, status, created_at)
+			VALUES (?, ?, ?, ?, `+backupCurrencyExpr+`, ?, ?, ?, ?, ?, ?)`,
+			loan.ID, loan.LenderID, loan.BorrowerID, loan.AmountPLN,
+			loan.Currency, loan.OriginalAmount, loan.ExchangeRate, loan.Note, dueDate, loan.Status,
 			loan.CreatedAt.UTC().Format(time.RFC3339))
 		if err != nil {
 			return nil, fmt.Errorf("failed to import loan %s: %w", loan.ID, err)
@@ -541,15 +1134,37 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 
 	// Import loan payments
 	for _, lp := range backup.LoanPayments {
-		_, err := tx.ExecContext(ctx,
-			`INSERT INTO loan_payments (id, loan_id, amount_pln, paid_at, note)
-			VALUES (?, ?, ?, ?, ?)`,
-			lp.ID, lp.LoanID, lp.AmountPLN, lp.PaidAt.UTC().Format(time.RFC3339), lp.Note)
+		err := w.insert(ctx,
+			`INSERT INTO loan_payments (id, loan_id, amount_pln, paid_at, note, settlement_fingerprint)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			lp.ID, lp.LoanID, lp.AmountPLN, lp.PaidAt.UTC().Format(time.RFC3339), lp.Note, lp.SettlementFingerprint)
 		if err != nil {
 			return nil, fmt.Errorf("failed to import loan payment %s: %w", lp.ID, err)
 		}
 	}
 
+	// Import settlement transfers
+	for _, st := range backup.SettlementTransfers {
+		err := w.insert(ctx,
+			`INSERT INTO settlement_transfers (id, fingerprint, from_user_id, to_user_id, amount_pln, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			st.ID, st.Fingerprint, st.FromUserID, st.ToUserID, st.AmountPLN, st.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import settlement transfer %s: %w", st.ID, err)
+		}
+	}
+
+	// Import settlement bill shares
+	for _, sb := range backup.SettlementBillShares {
+		err := w.insert(ctx,
+			`INSERT INTO settlement_bill_shares (id, fingerprint, bill_id, user_id, amount_pln, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			sb.ID, sb.Fingerprint, sb.BillID, sb.UserID, sb.AmountPLN, sb.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import settlement bill share %s: %w", sb.ID, err)
+		}
+	}
+
 	// Import chores
 	for _, chore := range backup.Chores {
 		isActive := 0
@@ -561,12 +1176,25 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 			notificationsEnabled = 1
 		}
 
-		_, err := tx.ExecContext(ctx,
-			`INSERT INTO chores (id, name, description, frequency, custom_interval, difficulty, priority, assignment_mode, manual_assignee_id, notifications_enabled, reminder_hours, is_active, created_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
-			chore.ID, chore.Name, chore.Description, chore.Frequency, chore.CustomInterval,
+		overduePolicy := chore.OverduePolicy
+		if overduePolicy == "" {
+			overduePolicy = "keep"
+		}
+		requirePhoto := 0
+		if chore.RequirePhoto {
+			requirePhoto = 1
+		}
+		requireConfirmation := 0
+		if chore.RequireConfirmation {
+			requireConfirmation = 1
+		}
+
+		err := w.insert(ctx,
+			`INSERT INTO chores (id, name, description, frequency, custom_interval, recurrence_rule, difficulty, priority, assignment_mode, manual_assignee_id, notifications_enabled, reminder_hours, overdue_policy, require_photo, require_confirmation, is_active, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			chore.ID, chore.Name, chore.Description, chore.Frequency, chore.CustomInterval, chore.RecurrenceRule,
 			chore.Difficulty, chore.Priority, chore.AssignmentMode, chore.ManualAssigneeID, notificationsEnabled,
-			chore.ReminderHours, isActive, chore.CreatedAt.UTC().Format(time.RFC3339))
+			chore.ReminderHours, overduePolicy, requirePhoto, requireConfirmation, isActive, chore.CreatedAt.UTC().Format(time.RFC3339))
 		if err != nil {
 			return nil, fmt.Errorf("failed to import chore %s: %w", chore.ID, err)
 		}
@@ -584,11 +1212,11 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 			isOnTime = 1
 		}
 
-		_, err := tx.ExecContext(ctx,
-			`INSERT INTO chore_assignments (id, chore_id, assignee_user_id, due_date, status, completed_at, points, is_on_time)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		err := w.insert(ctx,
+			`INSERT INTO chore_assignments (id, chore_id, assignee_user_id, due_date, status, completed_at, points, is_on_time, assigned_by_user_id)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			ca.ID, ca.ChoreID, ca.AssigneeUserID, ca.DueDate.UTC().Format(time.RFC3339),
-			ca.Status, completedAt, ca.Points, isOnTime)
+			ca.Status, completedAt, ca.Points, isOnTime, ca.AssignedByUserID)
 		if err != nil {
 			return nil, fmt.Errorf("failed to import chore assignment %s: %w", ca.ID, err)
 		}
@@ -606,11 +1234,17 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 			pointsEnabled = 1
 		}
 
-		_, err := tx.ExecContext(ctx,
-			`INSERT INTO chore_settings (id, default_assignment_mode, global_notifications, default_reminder_hours, points_enabled, points_multiplier, updated_at)
-			VALUES ('singleton', ?, ?, ?, ?, ?, ?)`,
+		overduePenalty, escalationHours := cs.OverduePenalty, cs.OverdueEscalationHours
+		if overduePenalty == "" {
+			// Backup taken before overdue handling existed
+			overduePenalty, escalationHours = "none", defaultOverdueEscalationHours
+		}
+
+		err := w.insertSetting(ctx,
+			`INSERT INTO chore_settings (id, default_assignment_mode, global_notifications, default_reminder_hours, points_enabled, points_multiplier, overdue_penalty, overdue_escalation_hours, updated_at)
+			VALUES ('singleton', ?, ?, ?, ?, ?, ?, ?, ?)`,
 			cs.DefaultAssignmentMode, globalNotifications, cs.DefaultReminderHours,
-			pointsEnabled, cs.PointsMultiplier, cs.UpdatedAt.UTC().Format(time.RFC3339))
+			pointsEnabled, cs.PointsMultiplier, overduePenalty, escalationHours, cs.UpdatedAt.UTC().Format(time.RFC3339))
 		if err != nil {
 			return nil, fmt.Errorf("failed to import chore settings: %w", err)
 		}
@@ -628,7 +1262,7 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 			read = 1
 		}
 
-		_, err := tx.ExecContext(ctx,
+		err := w.insert(ctx,
 			`INSERT INTO notifications (id, channel, template_id, scheduled_for, sent_at, status, read, user_id, title, body)
 			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			n.ID, n.Channel, n.TemplateID, n.ScheduledFor.UTC().Format(time.RFC3339),
@@ -646,7 +1280,7 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 			isActive = 1
 		}
 
-		_, err := tx.ExecContext(ctx,
+		err := w.insertSetting(ctx,
 			`INSERT INTO supply_settings (id, weekly_contribution_pln, contribution_day, current_budget_pln, last_contribution_at, is_active, budget_holder_user_id, created_at, updated_at)
 			VALUES ('singleton', ?, ?, ?, ?, ?, ?, ?, ?)`,
 			ss.WeeklyContributionPLN, ss.ContributionDay, ss.CurrentBudgetPLN,
@@ -675,12 +1309,14 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 			needsRefund = 1
 		}
 
-		_, err := tx.ExecContext(ctx,
-			`INSERT INTO supply_items (id, name, category, current_quantity, min_quantity, unit, priority, added_by_user_id, added_at, last_restocked_at, last_restocked_by_user_id, last_restock_amount_pln, needs_refund, notes)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		err := w.insert(ctx,
+			`INSERT INTO supply_items (id, name, category, current_quantity, min_quantity, unit, priority, added_by_user_id, added_at, last_restocked_at, last_restocked_by_user_id, last_restock_amount_pln,
+				last_restock_currency, last_restock_original_amount, last_restock_exchange_rate, needs_refund, notes)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			item.ID, item.Name, item.Category, item.CurrentQuantity, item.MinQuantity,
 			item.Unit, item.Priority, item.AddedByUserID, item.AddedAt.UTC().Format(time.RFC3339),
-			lastRestockedAt, lastRestockedByUserID, lastRestockAmountPLN, needsRefund, item.Notes)
+			lastRestockedAt, lastRestockedByUserID, lastRestockAmountPLN,
+			item.LastRestockCurrency, item.LastRestockOriginalAmount, item.LastRestockExchangeRate, needsRefund, item.Notes)
 		if err != nil {
 			return nil, fmt.Errorf("failed to import supply item %s: %w", item.ID, err)
 		}
@@ -688,7 +1324,7 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 
 	// Import supply contributions
 	for _, sc := range backup.SupplyContributions {
-		_, err := tx.ExecContext(ctx,
+		err := w.insert(ctx,
 			`INSERT INTO supply_contributions (id, user_id, amount_pln, period_start, period_end, type, notes, created_at)
 			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
 			sc.ID, sc.UserID, sc.AmountPLN, sc.PeriodStart.UTC().Format(time.RFC3339),
@@ -701,10 +1337,10 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 
 	// Import allocations (bill cost splits)
 	for _, alloc := range backup.Allocations {
-		_, err := tx.ExecContext(ctx,
-			`INSERT INTO allocations (id, bill_id, subject_type, subject_id, allocated_pln)
-			VALUES (?, ?, ?, ?, ?)`,
-			alloc.ID, alloc.BillID, alloc.SubjectType, alloc.SubjectID, alloc.AllocatedPLN)
+		err := w.insert(ctx,
+			`INSERT INTO allocations (id, bill_id, subject_type, subject_id, allocated_pln, detail)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			alloc.ID, alloc.BillID, alloc.SubjectType, alloc.SubjectID, alloc.AllocatedPLN, alloc.Detail)
 		if err != nil {
 			return nil, fmt.Errorf("failed to import allocation %s: %w", alloc.ID, err)
 		}
@@ -722,11 +1358,11 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 			lastGeneratedAt = &lga
 		}
 
-		_, err := tx.ExecContext(ctx,
-			`INSERT INTO recurring_bill_templates (id, custom_type, frequency, amount, day_of_month, start_date, notes, is_active, current_bill_id, next_due_date, last_generated_at, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		err := w.insert(ctx,
+			`INSERT INTO recurring_bill_templates (id, custom_type, frequency, amount, day_of_month, start_date, recurrence_rule, notes, is_active, current_bill_id, next_due_date, last_generated_at, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			template.ID, template.CustomType, template.Frequency, template.Amount, template.DayOfMonth,
-			template.StartDate.UTC().Format(time.RFC3339), template.Notes, isActive, template.CurrentBillID,
+			template.StartDate.UTC().Format(time.RFC3339), template.RecurrenceRule, template.Notes, isActive, template.CurrentBillID,
 			template.NextDueDate.UTC().Format(time.RFC3339), lastGeneratedAt,
 			template.CreatedAt.UTC().Format(time.RFC3339), template.UpdatedAt.UTC().Format(time.RFC3339))
 		if err != nil {
@@ -736,7 +1372,7 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 
 	// Import recurring bill allocations
 	for _, alloc := range backup.RecurringBillAllocations {
-		_, err := tx.ExecContext(ctx,
+		err := w.insert(ctx,
 			`INSERT INTO recurring_bill_allocations (id, template_id, subject_type, subject_id, allocation_type, percentage, fraction_numerator, fraction_denominator, fixed_amount)
 			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 			alloc.ID, alloc.TemplateID, alloc.SubjectType, alloc.SubjectID, alloc.AllocationType,
@@ -746,6 +1382,206 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 		}
 	}
 
+	// Import supply item history
+	for _, h := range backup.SupplyItemHistory {
+		err := w.insert(ctx,
+			`INSERT INTO supply_item_history (id, supply_item_id, user_id, action, quantity_delta, old_quantity, new_quantity, cost_pln, notes, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			h.ID, h.SupplyItemID, h.UserID, h.Action, h.QuantityDelta, h.OldQuantity, h.NewQuantity,
+			h.CostPLN, h.Notes, h.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import supply item history %s: %w", h.ID, err)
+		}
+	}
+
+	// Import notification preferences
+	for _, np := range backup.NotificationPreferences {
+		preferences := np.Preferences
+		if preferences == nil {
+			preferences = map[string]bool{}
+		}
+		preferencesJSON, err := json.Marshal(preferences)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal notification preferences %s: %w", np.ID, err)
+		}
+		allEnabled := 0
+		if np.AllEnabled {
+			allEnabled = 1
+		}
+
+		err = w.insert(ctx,
+			`INSERT INTO notification_preferences (id, user_id, preferences, all_enabled, updated_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			np.ID, np.UserID, string(preferencesJSON), allEnabled, np.UpdatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import notification preferences %s: %w", np.ID, err)
+		}
+	}
+
+	// Import web push subscriptions
+	for _, sub := range backup.WebPushSubscriptions {
+		err := w.insert(ctx,
+			`INSERT INTO web_push_subscriptions (id, user_id, endpoint, expiration_time, p256dh, auth)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			sub.ID, sub.UserID, sub.Endpoint, sub.ExpirationTime, sub.P256dh, sub.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import web push subscription %s: %w", sub.ID, err)
+		}
+	}
+
+	// Import chore swap requests
+	for _, sr := range backup.ChoreSwapRequests {
+		var respondedAt *string
+		if sr.RespondedAt != nil {
+			ra := sr.RespondedAt.UTC().Format(time.RFC3339)
+			respondedAt = &ra
+		}
+
+		err := w.insert(ctx,
+			`INSERT INTO chore_swap_requests (id, requester_user_id, requester_assignment_id, target_user_id, target_assignment_id, status, message, response_message, expires_at, responded_at, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			sr.ID, sr.RequesterUserID, sr.RequesterAssignmentID, sr.TargetUserID, sr.TargetAssignmentID,
+			sr.Status, sr.Message, sr.ResponseMessage, sr.ExpiresAt.UTC().Format(time.RFC3339),
+			respondedAt, sr.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import chore swap request %s: %w", sr.ID, err)
+		}
+	}
+
+	// Import user absences
+	for _, a := range backup.UserAbsences {
+		err := w.insert(ctx,
+			`INSERT INTO user_absences (id, user_id, start_date, end_date, reason, created_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			a.ID, a.UserID, a.StartDate.UTC().Format(time.RFC3339), a.EndDate.UTC().Format(time.RFC3339),
+			a.Reason, a.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import user absence %s: %w", a.ID, err)
+		}
+	}
+
+	// Import chore preferences
+	for _, p := range backup.ChorePreferences {
+		err := w.insert(ctx,
+			`INSERT INTO chore_preferences (id, chore_id, user_id, preference, updated_at)
+			VALUES (?, ?, ?, ?, ?)`,
+			p.ID, p.ChoreID, p.UserID, p.Preference, p.UpdatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import chore preference %s: %w", p.ID, err)
+		}
+	}
+
+	// Import chore completions
+	for _, cc := range backup.ChoreCompletions {
+		var reviewedAt *string
+		if cc.ReviewedAt != nil {
+			rt := cc.ReviewedAt.UTC().Format(time.RFC3339)
+			reviewedAt = &rt
+		}
+
+		err := w.insert(ctx,
+			`INSERT INTO chore_completions (id, assignment_id, submitted_by_user_id, photo_path, photo_content_type, status, reviewer_user_id, review_comment, submitted_at, reviewed_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			cc.ID, cc.AssignmentID, cc.SubmittedByUserID, cc.PhotoPath, cc.PhotoContentType, cc.Status,
+			cc.ReviewerUserID, cc.ReviewComment, cc.SubmittedAt.UTC().Format(time.RFC3339), reviewedAt)
+		if err != nil {
+			return nil, fmt.Errorf("failed to import chore completion %s: %w", cc.ID, err)
+		}
+	}
+
+	// Import sent reminders
+	for _, r := range backup.SentReminders {
+		err := w.insert(ctx,
+			`INSERT INTO sent_reminders (id, user_id, resource_type, resource_id, reminder_type, sent_at)
+			VALUES (?, ?, ?, ?, ?, ?)`,
+			r.ID, r.UserID, r.ResourceType, r.ResourceID, r.ReminderType, r.SentAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import sent reminder %s: %w", r.ID, err)
+		}
+	}
+
+	// Import approval policies
+	for _, p := range backup.ApprovalPolicies {
+		enabled := 0
+		if p.Enabled {
+			enabled = 1
+		}
+		isSystem := 0
+		if p.IsSystem {
+			isSystem = 1
+		}
+
+		err := w.insert(ctx,
+			`INSERT INTO approval_policies (id, action, role, min_amount_pln, enabled, is_system, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			p.ID, p.Action, p.Role, p.MinAmountPLN, enabled, isSystem,
+			p.CreatedAt.UTC().Format(time.RFC3339), p.UpdatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import approval policy %s: %w", p.ID, err)
+		}
+	}
+
+	// Import approval requests
+	for _, ar := range backup.ApprovalRequests {
+		details, err := nullableJSON(ar.Details)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal details of approval request %s: %w", ar.ID, err)
+		}
+		result, err := nullableJSON(ar.Result)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal result of approval request %s: %w", ar.ID, err)
+		}
+		var reviewedAt, executedAt *string
+		if ar.ReviewedAt != nil {
+			ra := ar.ReviewedAt.UTC().Format(time.RFC3339)
+			reviewedAt = &ra
+		}
+		if ar.ExecutedAt != nil {
+			ea := ar.ExecutedAt.UTC().Format(time.RFC3339)
+			executedAt = &ea
+		}
+
+		err = w.insert(ctx,
+			`INSERT INTO approval_requests (id, user_id, user_email, user_name, action, resource_type, resource_id, details, status,
+				reviewed_by, reviewed_at, review_notes, result, executed_at, execution_error, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			ar.ID, ar.UserID, ar.UserEmail, ar.UserName, ar.Action, ar.ResourceType, ar.ResourceID, details, ar.Status,
+			ar.ReviewedBy, reviewedAt, ar.ReviewNotes, result, executedAt, ar.ExecutionError,
+			ar.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import approval request %s: %w", ar.ID, err)
+		}
+	}
+
+	// Import audit logs
+	for _, al := range backup.AuditLogs {
+		details, err := nullableJSON(al.Details)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal details of audit log %s: %w", al.ID, err)
+		}
+
+		err = w.insert(ctx,
+			`INSERT INTO audit_logs (id, user_id, user_email, user_name, action, resource_type, resource_id, details, ip_address, user_agent, status, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			al.ID, al.UserID, al.UserEmail, al.UserName, al.Action, al.ResourceType, al.ResourceID, details,
+			al.IPAddress, al.UserAgent, al.Status, al.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import audit log %s: %w", al.ID, err)
+		}
+	}
+
+	// Import ledger entries
+	for _, le := range backup.LedgerEntries {
+		err := w.insert(ctx,
+			`INSERT INTO ledger_entries (id, transaction_id, account_type, account_id, amount_pln, source_type, source_id, description, occurred_at, created_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			le.ID, le.TransactionID, le.AccountType, le.AccountID, le.AmountPLN, le.SourceType, le.SourceID,
+			le.Description, le.OccurredAt.UTC().Format(time.RFC3339), le.CreatedAt.UTC().Format(time.RFC3339))
+		if err != nil {
+			return nil, fmt.Errorf("failed to import ledger entry %s: %w", le.ID, err)
+		}
+	}
+
 	// Commit the transaction
 	if err := tx.Commit(); err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
@@ -754,6 +1590,11 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 	// Re-enable foreign keys after successful commit (defer will also call this, but that's fine)
 	s.db.ExecContext(ctx, "PRAGMA foreign_keys = ON")
 
+	// Backups without ledger entries (version 1) get the ledger rebuilt from the imported records
+	if err := s.ledgerService.Backfill(ctx); err != nil {
+		return nil, fmt.Errorf("failed to rebuild ledger: %w", err)
+	}
+
 	// Set default password in result only if there are users with reset passwords
 	if len(result.UsersWithResetPasswords) > 0 {
 		result.DefaultPassword = defaultPassword
@@ -761,3 +1602,248 @@ func (s *BackupService) ImportJSON(ctx context.Context, jsonData []byte) (*Impor
 
 	return result, nil
 }
+
+// parseBackup decrypts, decompresses and decodes a backup file. Stored archives are gzip
+// compressed before encryption, so they can be imported as downloaded. Files without a
+// version are treated as version 1.
+func parseBackup(jsonData []byte, passphrase string) (*BackupData, error) {
+	if utils.IsPassphraseEncrypted(jsonData) {
+		if passphrase == "" {
+			return nil, ErrBackupPassphraseRequired
+		}
+		decrypted, err := utils.DecryptWithPassphrase(jsonData, passphrase)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decrypt backup: %w", err)
+		}
+		jsonData = decrypted
+	}
+	if bytes.HasPrefix(jsonData, gzipMagic) {
+		gz, err := gzip.NewReader(bytes.NewReader(jsonData))
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress backup: %w", err)
+		}
+		decompressed, err := io.ReadAll(gz)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress backup: %w", err)
+		}
+		jsonData = decompressed
+	}
+
+	var backup BackupData
+	if err := json.Unmarshal(jsonData, &backup); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal JSON backup: %w", err)
+	}
+
+	switch backup.Version {
+	case BackupVersion, backupVersionV1:
+	case "":
+		backup.Version = backupVersionV1
+	default:
+		return nil, fmt.Errorf("unsupported backup version %q", backup.Version)
+	}
+	return &backup, nil
+}
+
+// mergeConflicts returns the reasons a backup cannot be merged into the current data
+func (s *BackupService) mergeConflicts(ctx context.Context, backup *BackupData) ([]string, error) {
+	conflicts := []string{}
+
+	// Version 1 has no ledger, settings or roles to merge
+	if backup.Version != BackupVersion {
+		conflicts = append(conflicts, fmt.Sprintf("version %s backups can only be imported in replace mode", backup.Version))
+	}
+
+	// Amounts are stored in the base currency, so both sides must use the same one
+	current, err := s.appSettings.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch app settings: %w", err)
+	}
+	currentBase, backupBase := DefaultBaseCurrency, DefaultBaseCurrency
+	if current != nil && current.BaseCurrency != "" {
+		currentBase = current.BaseCurrency
+	}
+	if backup.AppSettings != nil && backup.AppSettings.BaseCurrency != "" {
+		backupBase = backup.AppSettings.BaseCurrency
+	}
+	if currentBase != backupBase {
+		conflicts = append(conflicts, fmt.Sprintf("backup amounts are in %s but this instance uses %s", backupBase, currentBase))
+	}
+
+	// Merging would replace an existing user that shares an email or username, orphaning their records
+	for _, user := range backup.Users {
+		existing, err := s.users.GetByEmail(ctx, user.Email)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch user: %w", err)
+		}
+		if existing != nil && existing.ID != user.ID {
+			conflicts = append(conflicts, fmt.Sprintf("user %s already exists with a different ID", user.Email))
+			continue
+		}
+		if user.Username == "" {
+			continue
+		}
+		existing, err = s.users.GetByUsername(ctx, user.Username)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch user: %w", err)
+		}
+		if existing != nil && existing.ID != user.ID {
+			conflicts = append(conflicts, fmt.Sprintf("username %s is already taken by another user", user.Username))
+		}
+	}
+
+	return conflicts, nil
+}
+
+// PreviewImport reports what importing a backup would change without writing anything.
+// Tables are compared as a replace import would load them.
+func (s *BackupService) PreviewImport(ctx context.Context, jsonData []byte, passphrase string) (*ImportReport, error) {
+	backup, err := parseBackup(jsonData, passphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &ImportReport{
+		Version:                 backup.Version,
+		UsersWithResetPasswords: []string{},
+	}
+
+	report.MergeConflicts, err = s.mergeConflicts(ctx, backup)
+	if err != nil {
+		return nil, err
+	}
+
+	if backup.Version == backupVersionV1 {
+		if err := s.upgradeBackupV1(ctx, backup); err != nil {
+			return nil, fmt.Errorf("failed to upgrade version 1 backup: %w", err)
+		}
+	}
+
+	for _, user := range backup.Users {
+		if strings.TrimSpace(user.PasswordHash) == "" {
+			report.UsersWithResetPasswords = append(report.UsersWithResetPasswords, user.Email)
+		}
+	}
+
+	for _, table := range backupTableIDs(backup) {
+		var existing []string
+		if err := s.db.SelectContext(ctx, &existing, fmt.Sprintf("SELECT id FROM %s", table.name)); err != nil {
+			return nil, fmt.Errorf("failed to read table %s: %w", table.name, err)
+		}
+		report.Tables = append(report.Tables, diffImportTable(table.name, table.ids, existing))
+	}
+
+	return report, nil
+}
+
+type backupTable struct {
+	name string
+	ids  []string
+}
+
+// backupTableIDs lists the row IDs of every table in a backup
+func backupTableIDs(backup *BackupData) []backupTable {
+	singleton := func(present bool) []string {
+		if present {
+			return []string{"singleton"}
+		}
+		return nil
+	}
+	ids := func(n int, id func(i int) string) []string {
+		out := make([]string, n)
+		for i := range out {
+			out[i] = id(i)
+		}
+		return out
+	}
+
+	return []backupTable{
+		{"app_settings", singleton(backup.AppSettings != nil)},
+		{"permissions", ids(len(backup.Permissions), func(i int) string { return backup.Permissions[i].ID })},
+		{"roles", ids(len(backup.Roles), func(i int) string { return backup.Roles[i].ID })},
+		{"exchange_rates", ids(len(backup.ExchangeRates), func(i int) string { return backup.ExchangeRates[i].ID })},
+		{"groups", ids(len(backup.Groups), func(i int) string { return backup.Groups[i].ID })},
+		{"users", ids(len(backup.Users), func(i int) string { return backup.Users[i].ID })},
+		{"passkey_credentials", ids(len(backup.PasskeyCredentials), func(i int) string { return fmt.Sprintf("%x", backup.PasskeyCredentials[i].ID) })},
+		{"residencies", ids(len(backup.Residencies), func(i int) string { return backup.Residencies[i].ID })},
+		{"group_weight_changes", ids(len(backup.GroupWeightChanges), func(i int) string { return backup.GroupWeightChanges[i].ID })},
+		{"group_membership_changes", ids(len(backup.GroupMembershipChanges), func(i int) string { return backup.GroupMembershipChanges[i].ID })},
+		{"bills", ids(len(backup.Bills), func(i int) string { return backup.Bills[i].ID })},
+		{"consumptions", ids(len(backup.Consumptions), func(i int) string { return backup.Consumptions[i].ID })},
+		{"meters", ids(len(backup.Meters), func(i int) string { return backup.Meters[i].ID })},
+		{"meter_replacements", ids(len(backup.MeterReplacements), func(i int) string { return backup.MeterReplacements[i].ID })},
+		{"tariffs", ids(len(backup.Tariffs), func(i int) string { return backup.Tariffs[i].ID })},
+		{"payments", ids(len(backup.Payments), func(i int) string { return backup.Payments[i].ID })},
+		{"loans", ids(len(backup.Loans), func(i int) string { return backup.Loans[i].ID })},
+		{"loan_payments", ids(len(backup.LoanPayments), func(i int) string { return backup.LoanPayments[i].ID })},
+		{"settlement_transfers", ids(len(backup.SettlementTransfers), func(i int) string { return backup.SettlementTransfers[i].ID })},
+		{"settlement_bill_shares", ids(len(backup.SettlementBillShares), func(i int) string { return backup.SettlementBillShares[i].ID })},
+		{"chores", ids(len(backup.Chores), func(i int) string { return backup.Chores[i].ID })},
+		{"chore_assignments", ids(len(backup.ChoreAssignments), func(i int) string { return backup.ChoreAssignments[i].ID })},
+		{"chore_settings", singleton(backup.ChoreSettings != nil)},
+		{"notifications", ids(len(backup.Notifications), func(i int) string { return backup.Notifications[i].ID })},
+		{"supply_settings", singleton(backup.SupplySettings != nil)},
+		{"supply_items", ids(len(backup.SupplyItems), func(i int) string { return backup.SupplyItems[i].ID })},
+		{"supply_contributions", ids(len(backup.SupplyContributions), func(i int) string { return backup.SupplyContributions[i].ID })},
+		{"allocations", ids(len(backup.Allocations), func(i int) string { return backup.Allocations[i].ID })},
+		{"recurring_bill_templates", ids(len(backup.RecurringBillTemplates), func(i int) string { return backup.RecurringBillTemplates[i].ID })},
+		{"recurring_bill_allocations", ids(len(backup.RecurringBillAllocations), func(i int) string { return backup.RecurringBillAllocations[i].ID })},
+		{"supply_item_history", ids(len(backup.SupplyItemHistory), func(i int) string { return backup.SupplyItemHistory[i].ID })},
+		{"notification_preferences", ids(len(backup.NotificationPreferences), func(i int) string { return backup.NotificationPreferences[i].ID })},
+		{"web_push_subscriptions", ids(len(backup.WebPushSubscriptions), func(i int) string { return backup.WebPushSubscriptions[i].ID })},
+		{"chore_swap_requests", ids(len(backup.ChoreSwapRequests), func(i int) string { return backup.ChoreSwapRequests[i].ID })},
+		{"user_absences", ids(len(backup.UserAbsences), func(i int) string { return backup.UserAbsences[i].ID })},
+		{"chore_preferences", ids(len(backup.ChorePreferences), func(i int) string { return backup.ChorePreferences[i].ID })},
+		{"chore_completions", ids(len(backup.ChoreCompletions), func(i int) string { return backup.ChoreCompletions[i].ID })},
+		{"sent_reminders", ids(len(backup.SentReminders), func(i int) string { return backup.SentReminders[i].ID })},
+		{"approval_policies", ids(len(backup.ApprovalPolicies), func(i int) string { return backup.ApprovalPolicies[i].ID })},
+		{"approval_requests", ids(len(backup.ApprovalRequests), func(i int) string { return backup.ApprovalRequests[i].ID })},
+		{"audit_logs", ids(len(backup.AuditLogs), func(i int) string { return backup.AuditLogs[i].ID })},
+		{"ledger_entries", ids(len(backup.LedgerEntries), func(i int) string { return backup.LedgerEntries[i].ID })},
+	}
+}
+
+// diffImportTable compares the IDs of a table in a backup with the IDs in the database
+func diffImportTable(table string, backupIDs, existingIDs []string) ImportTableReport {
+	existing := make(map[string]bool, len(existingIDs))
+	for _, id := range existingIDs {
+		existing[id] = true
+	}
+
+	report := ImportTableReport{
+		Table:          table,
+		BackupRows:     len(backupIDs),
+		ExistingRows:   len(existingIDs),
+		ConflictingIDs: []string{},
+	}
+	for _, id := range backupIDs {
+		if existing[id] {
+			report.ConflictingIDs = append(report.ConflictingIDs, id)
+		} else {
+			report.NewRows++
+		}
+	}
+	return report
+}
+
+// backupWriter inserts backup rows. Merge mode replaces rows that share an ID or unique key
+// with a backup row, and keeps the existing settings singletons.
+type backupWriter struct {
+	tx    *sqlx.Tx
+	merge bool
+}
+
+func (w *backupWriter) insert(ctx context.Context, query string, args ...interface{}) error {
+	if w.merge {
+		query = strings.Replace(query, "INSERT INTO", "INSERT OR REPLACE INTO", 1)
+	}
+	_, err := w.tx.ExecContext(ctx, query, args...)
+	return err
+}
+
+func (w *backupWriter) insertSetting(ctx context.Context, query string, args ...interface{}) error {
+	if w.merge {
+		query = strings.Replace(query, "INSERT INTO", "INSERT OR IGNORE INTO", 1)
+	}
+	_, err := w.tx.ExecContext(ctx, query, args...)
+	return err
+}
@@ -0,0 +1,50 @@
+package services
+
+import (
+	"testing"
+
+	"github.com/sainaif/holy-home/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseBackup(t *testing.T) {
+	backup, err := parseBackup([]byte(`{"version":"2.0","users":[]}`), "")
+	require.NoError(t, err)
+	assert.Equal(t, BackupVersion, backup.Version)
+
+	// Files from before versioning are version 1
+	backup, err = parseBackup([]byte(`{"users":[]}`), "")
+	require.NoError(t, err)
+	assert.Equal(t, "1.0", backup.Version)
+
+	_, err = parseBackup([]byte(`{"version":"3.0"}`), "")
+	assert.ErrorContains(t, err, "unsupported backup version")
+
+	_, err = parseBackup([]byte(`{"version":`), "")
+	assert.Error(t, err)
+
+	// Encrypted files need the passphrase
+	encrypted, err := utils.EncryptWithPassphrase([]byte(`{"version":"2.0"}`), "a long backup passphrase")
+	require.NoError(t, err)
+	_, err = parseBackup(encrypted, "")
+	assert.ErrorIs(t, err, ErrBackupPassphraseRequired)
+	_, err = parseBackup(encrypted, "not the passphrase")
+	assert.ErrorIs(t, err, utils.ErrWrongPassphrase)
+	backup, err = parseBackup(encrypted, "a long backup passphrase")
+	require.NoError(t, err)
+	assert.Equal(t, BackupVersion, backup.Version)
+}
+
+func TestDiffImportTable(t *testing.T) {
+	report := diffImportTable("bills", []string{"a", "b", "c"}, []string{"b", "c", "d", "e"})
+
+	assert.Equal(t, "bills", report.Table)
+	assert.Equal(t, 3, report.BackupRows)
+	assert.Equal(t, 4, report.ExistingRows)
+	assert.Equal(t, 1, report.NewRows)
+	assert.Equal(t, []string{"b", "c"}, report.ConflictingIDs)
+
+	empty := diffImportTable("loans", nil, nil)
+	assert.NotNil(t, empty.ConflictingIDs)
+}
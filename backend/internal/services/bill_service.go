@@ -2,6 +2,7 @@ package services
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"log"
@@ -13,46 +14,77 @@ import (
 	"github.com/sainaif/holy-home/internal/utils"
 )
 
+// ErrSuspiciousReadings is returned when a bill still has suspicious readings awaiting confirmation
+var ErrSuspiciousReadings = errors.New("bill has suspicious readings that need confirmation")
+
 type BillService struct {
 	bills               repository.BillRepository
 	consumptions        repository.ConsumptionRepository
+	meters              repository.MeterRepository
+	meterReplacements   repository.MeterReplacementRepository
+	tariffs             repository.TariffRepository
 	allocations         repository.AllocationRepository
 	payments            repository.PaymentRepository
+	settlements         repository.SettlementRepository
 	users               repository.UserRepository
 	groups              repository.GroupRepository
+	residencies         repository.ResidencyRepository
+	groupWeightChanges  repository.GroupWeightChangeRepository
+	membershipChanges   repository.GroupMembershipChangeRepository
+	currencyService     *CurrencyService
 	notificationService *NotificationService
+	txManager           repository.TxManager
 }
 
 func NewBillService(
 	bills repository.BillRepository,
 	consumptions repository.ConsumptionRepository,
+	meters repository.MeterRepository,
+	meterReplacements repository.MeterReplacementRepository,
+	tariffs repository.TariffRepository,
 	allocations repository.AllocationRepository,
 	payments repository.PaymentRepository,
+	settlements repository.SettlementRepository,
 	users repository.UserRepository,
 	groups repository.GroupRepository,
+	residencies repository.ResidencyRepository,
+	groupWeightChanges repository.GroupWeightChangeRepository,
+	membershipChanges repository.GroupMembershipChangeRepository,
+	currencyService *CurrencyService,
 	notificationService *NotificationService,
+	txManager repository.TxManager,
 ) *BillService {
 	return &BillService{
 		bills:               bills,
 		consumptions:        consumptions,
+		meters:              meters,
+		meterReplacements:   meterReplacements,
+		tariffs:             tariffs,
 		allocations:         allocations,
 		payments:            payments,
+		settlements:         settlements,
 		users:               users,
 		groups:              groups,
+		residencies:         residencies,
+		groupWeightChanges:  groupWeightChanges,
+		membershipChanges:   membershipChanges,
+		currencyService:     currencyService,
 		notificationService: notificationService,
+		txManager:           txManager,
 	}
 }
 
 type CreateBillRequest struct {
-	Type            string     `json:"type"`                     // electricity, gas, internet, inne
-	CustomType      *string    `json:"customType,omitempty"`     // required when type is "inne"
-	AllocationType  *string    `json:"allocationType,omitempty"` // "simple" or "metered", required when type is "inne"
-	PeriodStart     time.Time  `json:"periodStart"`
-	PeriodEnd       time.Time  `json:"periodEnd"`
-	PaymentDeadline *time.Time `json:"paymentDeadline,omitempty"` // optional payment deadline
-	TotalAmountPLN  float64    `json:"totalAmountPLN"`
-	TotalUnits      *float64   `json:"totalUnits,omitempty"`
-	Notes           *string    `json:"notes,omitempty"`
+	Type            string      `json:"type"`                     // electricity, gas, internet, inne
+	CustomType      *string     `json:"customType,omitempty"`     // required when type is "inne"
+	AllocationType  *string     `json:"allocationType,omitempty"` // "simple" or "metered", required when type is "inne"
+	PeriodStart     time.Time   `json:"periodStart"`
+	PeriodEnd       time.Time   `json:"periodEnd"`
+	PaymentDeadline *time.Time  `json:"paymentDeadline,omitempty"` // optional payment deadline
+	TotalAmountPLN  utils.Money `json:"totalAmountPLN"`            // in Currency
+	Currency        string      `json:"currency,omitempty"`        // defaults to the base currency
+	TotalUnits      *float64    `json:"totalUnits,omitempty"`
+	Notes           *string     `json:"notes,omitempty"`
 }
 
 // CreateBill creates a new bill in the database
@@ -92,7 +124,12 @@ func (s *BillService) CreateBill(ctx context.Context, req CreateBillRequest, cre
 		return nil, errors.New("period end must be after period start")
 	}
 
-	amountStr := utils.FloatToDecimalString(req.TotalAmountPLN)
+	// Foreign currency bills are converted at the rate of the end of the billing period
+	converted, err := s.currencyService.ToBase(ctx, req.TotalAmountPLN, req.Currency, req.PeriodEnd)
+	if err != nil {
+		return nil, err
+	}
+	amountStr := converted.Amount.String()
 
 	bill := models.Bill{
 		ID:              uuid.New().String(),
@@ -103,6 +140,9 @@ func (s *BillService) CreateBill(ctx context.Context, req CreateBillRequest, cre
 		PeriodEnd:       req.PeriodEnd,
 		PaymentDeadline: req.PaymentDeadline,
 		TotalAmountPLN:  amountStr,
+		Currency:        converted.Currency,
+		OriginalAmount:  converted.OriginalAmount,
+		ExchangeRate:    converted.ExchangeRate,
 		Notes:           req.Notes,
 		Status:          "draft",
 		CreatedAt:       time.Now(),
@@ -188,24 +228,116 @@ func (s *BillService) GetBill(ctx context.Context, billID string) (*models.Bill,
 	return bill, nil
 }
 
-// PostBill marks bill as posted (freezes allocations)
+// PostBill marks bill as posted, freezes its allocations and charges them in the ledger
 func (s *BillService) PostBill(ctx context.Context, billID string) error {
-	err := s.updateBillStatus(ctx, billID, "draft", "posted")
-	if err == nil {
-		log.Printf("[BILL] Posted: ID=%s (status changed from draft to posted)", billID)
+	if err := s.requireConfirmedReadings(ctx, billID); err != nil {
+		return err
 	}
-	return err
+	err := s.txManager.WithTx(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		if err := updateBillStatus(ctx, repos.Bills, billID, "draft", "posted"); err != nil {
+			return err
+		}
+		return syncBillLedger(ctx, repos, billID)
+	})
+	if err != nil {
+		return err
+	}
+	log.Printf("[BILL] Posted: ID=%s (status changed from draft to posted)", billID)
+
+	return nil
+}
+
+// syncBillLedger posts the bill's frozen allocations, or reverses them for a draft
+func syncBillLedger(ctx context.Context, repos *repository.Repositories, billID string) error {
+	bill, err := repos.Bills.GetByID(ctx, billID)
+	if err != nil || bill == nil {
+		return errors.New("bill not found")
+	}
+
+	var breakdown []AllocationBreakdown
+	if bill.Status != "draft" {
+		breakdown, err = freezeBillAllocations(ctx, repos, billID)
+		if err != nil {
+			return err
+		}
+	}
+
+	if err := syncLedger(ctx, repos.LedgerEntries, billLedgerRecord(bill, breakdown)); err != nil {
+		return fmt.Errorf("failed to post bill to ledger: %w", err)
+	}
+	return nil
+}
+
+// freezeBillAllocations stores the bill's current allocations with their full breakdown unless
+// it already has stored ones, so later changes to readings, tariffs or groups no longer move a
+// posted charge
+func freezeBillAllocations(ctx context.Context, repos *repository.Repositories, billID string) ([]AllocationBreakdown, error) {
+	stored, err := repos.Allocations.GetByBillID(ctx, billID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query allocations: %w", err)
+	}
+
+	breakdown, err := NewAllocationService(repos.Users, repos.Groups, repos.Residencies, repos.GroupWeightChanges, repos.GroupMembershipChanges, repos.Consumptions, repos.Meters, repos.MeterReplacements, repos.Tariffs, repos.Allocations, repos.Bills).GetAllocationBreakdown(ctx, billID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to allocate bill: %w", err)
+	}
+	if len(stored) > 0 {
+		return breakdown, nil
+	}
+
+	for _, allocation := range breakdown {
+		data, err := json.Marshal(allocation)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode allocation: %w", err)
+		}
+		detail := string(data)
+		if err := repos.Allocations.Create(ctx, billID, allocation.SubjectType, allocation.SubjectID, allocation.Amount.String(), &detail); err != nil {
+			return nil, fmt.Errorf("failed to store allocations: %w", err)
+		}
+	}
+	return breakdown, nil
 }
 
 // CloseBill marks bill as closed (no more changes)
 func (s *BillService) CloseBill(ctx context.Context, billID string) error {
-	err := s.updateBillStatus(ctx, billID, "posted", "closed")
+	if err := s.requireConfirmedReadings(ctx, billID); err != nil {
+		return err
+	}
+	err := updateBillStatus(ctx, s.bills, billID, "posted", "closed")
 	if err == nil {
 		log.Printf("[BILL] Closed: ID=%s (status changed from posted to closed)", billID)
 	}
 	return err
 }
 
+// GetSuspiciousReadings returns the valid readings of a bill that still await confirmation
+func (s *BillService) GetSuspiciousReadings(ctx context.Context, billID string) ([]models.Consumption, error) {
+	readings, err := s.consumptions.ListByBillID(ctx, billID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+
+	suspicious := make([]models.Consumption, 0)
+	for _, reading := range readings {
+		if reading.AnomalyStatus == "suspicious" && reading.Source != "invalid" {
+			suspicious = append(suspicious, reading)
+		}
+	}
+	return suspicious, nil
+}
+
+// requireConfirmedReadings refuses a status change while the bill has suspicious readings
+func (s *BillService) requireConfirmedReadings(ctx context.Context, billID string) error {
+	suspicious, err := s.GetSuspiciousReadings(ctx, billID)
+	if err != nil {
+		return err
+	}
+	if len(suspicious) > 0 {
+		return fmt.Errorf("%w (%d)", ErrSuspiciousReadings, len(suspicious))
+	}
+	return nil
+}
+
 // ReopenBill reverts a bill back to draft or posted status
 func (s *BillService) ReopenBill(ctx context.Context, billID string, userID string, targetStatus, reason string) error {
 	// Validate target status
@@ -233,6 +365,7 @@ func (s *BillService) ReopenBill(ctx context.Context, billID string, userID stri
 	}
 
 	now := time.Now()
+	fromStatus := bill.Status
 
 	// Update bill status and reopen metadata
 	bill.Status = targetStatus
@@ -240,17 +373,30 @@ func (s *BillService) ReopenBill(ctx context.Context, billID string, userID stri
 	bill.ReopenReason = &reason
 	bill.ReopenedBy = &userID
 
-	if err := s.bills.Update(ctx, bill); err != nil {
-		return fmt.Errorf("failed to reopen bill: %w", err)
+	err = s.txManager.WithTx(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		if err := repos.Bills.Update(ctx, bill); err != nil {
+			return fmt.Errorf("failed to reopen bill: %w", err)
+		}
+		// A draft is allocated anew when it is posted again; allocations of a recurring
+		// bill come from its template and are kept
+		if targetStatus == "draft" && bill.RecurringTemplateID == nil {
+			if err := repos.Allocations.DeleteByBillID(ctx, billID); err != nil {
+				return fmt.Errorf("failed to unfreeze allocations: %w", err)
+			}
+		}
+		return syncBillLedger(ctx, repos, billID)
+	})
+	if err != nil {
+		return err
 	}
 
-	log.Printf("[BILL] Reopened: ID=%s (from %s to %s, by user %s, reason: %q)", billID, bill.Status, targetStatus, userID, reason)
+	log.Printf("[BILL] Reopened: ID=%s (from %s to %s, by user %s, reason: %q)", billID, fromStatus, targetStatus, userID, reason)
 
 	return nil
 }
 
-func (s *BillService) updateBillStatus(ctx context.Context, billID string, fromStatus, toStatus string) error {
-	bill, err := s.bills.GetByID(ctx, billID)
+func updateBillStatus(ctx context.Context, bills repository.BillRepository, billID string, fromStatus, toStatus string) error {
+	bill, err := bills.GetByID(ctx, billID)
 	if err != nil {
 		return fmt.Errorf("bill not found or not in %s status", fromStatus)
 	}
@@ -260,7 +406,7 @@ func (s *BillService) updateBillStatus(ctx context.Context, billID string, fromS
 	}
 
 	bill.Status = toStatus
-	if err := s.bills.Update(ctx, bill); err != nil {
+	if err := bills.Update(ctx, bill); err != nil {
 		return fmt.Errorf("failed to update bill status: %w", err)
 	}
 
@@ -270,18 +416,73 @@ func (s *BillService) updateBillStatus(ctx context.Context, billID string, fromS
 // DeleteBill deletes a bill and all associated data
 func (s *BillService) DeleteBill(ctx context.Context, billID string) error {
 	// Check bill exists
-	_, err := s.bills.GetByID(ctx, billID)
-	if err != nil {
+	bill, err := s.bills.GetByID(ctx, billID)
+	if err != nil || bill == nil {
 		return errors.New("bill not found")
 	}
 
+	err = s.txManager.WithTx(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		return deleteBillWithReadings(ctx, repos.Bills, repos.Consumptions, repos.Allocations, repos.Payments, repos.LedgerEntries, bill)
+	})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[BILL] Deleted: ID=%s", billID)
+
+	return nil
+}
+
+// ExecuteApprovedDelete is the approval executor for "bill.delete" requests
+func (s *BillService) ExecuteApprovedDelete(ctx context.Context, repos *repository.Repositories, request *models.ApprovalRequest) (map[string]interface{}, error) {
+	if request.ResourceID == nil {
+		return nil, errors.New("approval request has no bill ID")
+	}
+
+	bill, err := repos.Bills.GetByID(ctx, *request.ResourceID)
+	if err != nil || bill == nil {
+		return nil, errors.New("bill not found")
+	}
+
+	if err := deleteBillWithReadings(ctx, repos.Bills, repos.Consumptions, repos.Allocations, repos.Payments, repos.LedgerEntries, bill); err != nil {
+		return nil, err
+	}
+
+	log.Printf("[BILL] Deleted after approval: ID=%s (request %s)", bill.ID, request.ID)
+
+	return map[string]interface{}{
+		"billId":         bill.ID,
+		"type":           bill.Type,
+		"totalAmountPLN": bill.TotalAmountPLN,
+	}, nil
+}
+
+// deleteBillWithReadings deletes a bill together with its consumptions and allocations
+// and reverses its charges and payments in the ledger
+func deleteBillWithReadings(ctx context.Context, bills repository.BillRepository, consumptions repository.ConsumptionRepository, allocations repository.AllocationRepository, payments repository.PaymentRepository, ledgerEntries repository.LedgerEntryRepository, bill *models.Bill) error {
+	billID := bill.ID
+
+	// Payments are removed together with the bill, so take them off the ledger as well
+	billPayments, err := payments.ListByBillID(ctx, billID)
+	if err != nil {
+		return fmt.Errorf("failed to list payments: %w", err)
+	}
+	for i := range billPayments {
+		if err := syncLedger(ctx, ledgerEntries, paymentLedgerRecord(&billPayments[i]).reversal()); err != nil {
+			return fmt.Errorf("failed to reverse payment in ledger: %w", err)
+		}
+	}
+	if err := syncLedger(ctx, ledgerEntries, billLedgerRecord(bill, nil).reversal()); err != nil {
+		return fmt.Errorf("failed to reverse bill in ledger: %w", err)
+	}
+
 	// Delete all consumptions
-	if err := s.consumptions.DeleteByBillID(ctx, billID); err != nil {
+	if err := consumptions.DeleteByBillID(ctx, billID); err != nil {
 		return fmt.Errorf("failed to delete consumptions: %w", err)
 	}
 
 	// Delete all allocations
-	if err := s.allocations.DeleteByBillID(ctx, billID); err != nil {
+	if err := allocations.DeleteByBillID(ctx, billID); err != nil {
 		return fmt.Errorf("failed to delete allocations: %w", err)
 	}
 
@@ -289,12 +490,10 @@ func (s *BillService) DeleteBill(ctx context.Context, billID string) error {
 	// They could be kept for audit purposes or handled separately
 
 	// Delete the bill
-	if err := s.bills.Delete(ctx, billID); err != nil {
+	if err := bills.Delete(ctx, billID); err != nil {
 		return fmt.Errorf("failed to delete bill: %w", err)
 	}
 
-	log.Printf("[BILL] Deleted: ID=%s", billID)
-
 	return nil
 }
 
@@ -317,23 +516,17 @@ func (s *BillService) GetBillPaymentStatus(ctx context.Context, billID string) (
 		return nil, err
 	}
 
-	// Get all payments for this bill
-	payments, err := s.payments.ListByBillID(ctx, billID)
+	// Build payment map by payer
+	paymentMap, err := billPaidByUser(ctx, s.payments, s.settlements, billID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build payment map by payer
-	paymentMap := make(map[string]float64)
-	for _, payment := range payments {
-		paidFloat := utils.DecimalStringToFloat(payment.AmountPLN)
-		paymentMap[payment.PayerUserID] += paidFloat
-	}
-
 	// Build status entries
 	var statusEntries []PaymentStatusEntry
 	for _, alloc := range allocations {
 		var subjectName string
+		allocated := utils.MoneyFromString(alloc.AllocatedPLN)
 
 		// Get subject name
 		if alloc.SubjectType == "user" {
@@ -345,18 +538,16 @@ func (s *BillService) GetBillPaymentStatus(ctx context.Context, billID string) (
 			}
 
 			// Get paid amount for this user
-			paidFloat := paymentMap[alloc.SubjectID]
-			allocFloat := utils.DecimalStringToFloat(alloc.AllocatedPLN)
-			remainingFloat := allocFloat - paidFloat
+			paid := paymentMap[alloc.SubjectID]
 
 			statusEntries = append(statusEntries, PaymentStatusEntry{
 				SubjectID:    alloc.SubjectID,
 				SubjectType:  alloc.SubjectType,
 				SubjectName:  subjectName,
 				AllocatedPLN: alloc.AllocatedPLN,
-				PaidPLN:      utils.FloatToDecimalString(paidFloat),
-				RemainingPLN: utils.FloatToDecimalString(remainingFloat),
-				IsPaid:       paidFloat >= allocFloat,
+				PaidPLN:      paid.String(),
+				RemainingPLN: (allocated - paid).String(),
+				IsPaid:       paid >= allocated,
 			})
 		} else if alloc.SubjectType == "group" {
 			group, err := s.groups.GetByID(ctx, alloc.SubjectID)
@@ -373,22 +564,19 @@ func (s *BillService) GetBillPaymentStatus(ctx context.Context, billID string) (
 			}
 
 			// Calculate total paid by all group members
-			totalPaidFloat := 0.0
+			var totalPaid utils.Money
 			for _, user := range groupUsers {
-				totalPaidFloat += paymentMap[user.ID]
+				totalPaid += paymentMap[user.ID]
 			}
 
-			allocFloat := utils.DecimalStringToFloat(alloc.AllocatedPLN)
-			remainingFloat := allocFloat - totalPaidFloat
-
 			statusEntries = append(statusEntries, PaymentStatusEntry{
 				SubjectID:    alloc.SubjectID,
 				SubjectType:  alloc.SubjectType,
 				SubjectName:  subjectName,
 				AllocatedPLN: alloc.AllocatedPLN,
-				PaidPLN:      utils.FloatToDecimalString(totalPaidFloat),
-				RemainingPLN: utils.FloatToDecimalString(remainingFloat),
-				IsPaid:       totalPaidFloat >= allocFloat,
+				PaidPLN:      totalPaid.String(),
+				RemainingPLN: (allocated - totalPaid).String(),
+				IsPaid:       totalPaid >= allocated,
 			})
 		}
 	}
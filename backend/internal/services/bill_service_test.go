@@ -1,9 +1,15 @@
 package services
 
 import (
+	"context"
 	"testing"
+	"time"
 
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/sainaif/holy-home/internal/utils"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestBillTypeValidation tests bill type validation logic
@@ -204,3 +210,135 @@ func TestBillStatusValidation(t *testing.T) {
 func stringPtr(s string) *string {
 	return &s
 }
+
+func (m *memoryBills) Update(ctx context.Context, bill *models.Bill) error {
+	for i := range m.bills {
+		if m.bills[i].ID == bill.ID {
+			m.bills[i] = *bill
+		}
+	}
+	return nil
+}
+
+func (m *memoryAllocations) Create(ctx context.Context, billID, subjectType, subjectID, allocatedPLN string, detail *string) error {
+	m.allocations = append(m.allocations, repository.Allocation{BillID: billID, SubjectType: subjectType, SubjectID: subjectID, AllocatedPLN: allocatedPLN, Detail: detail})
+	return nil
+}
+
+func (m *memoryAllocations) DeleteByBillID(ctx context.Context, billID string) error {
+	var kept []repository.Allocation
+	for _, alloc := range m.allocations {
+		if alloc.BillID != billID {
+			kept = append(kept, alloc)
+		}
+	}
+	m.allocations = kept
+	return nil
+}
+
+func TestPostBill_FreezesAllocations(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	metered := "metered"
+
+	users := &memoryUsers{users: []models.User{
+		{ID: "anna", Name: "Anna", IsActive: true},
+		{ID: "bartek", Name: "Bartek", IsActive: true},
+	}}
+	bills := &memoryBills{bills: []models.Bill{
+		{ID: "gas", Type: "gas", Status: "draft", TotalAmountPLN: "120.00", PeriodStart: day(1), PeriodEnd: day(30)},
+		{ID: "power", Type: "electricity", AllocationType: &metered, Status: "draft", TotalAmountPLN: "200.00", PeriodStart: day(1), PeriodEnd: day(30)},
+	}}
+	allocations := &memoryAllocations{}
+	ledger := &memoryLedgerEntries{}
+	repos := &repository.Repositories{
+		Users:                  users,
+		Groups:                 &memoryGroups{},
+		Residencies:            &memoryResidencies{},
+		GroupWeightChanges:     &memoryGroupWeightChanges{},
+		GroupMembershipChanges: &memoryMembershipChanges{},
+		Consumptions:           &memoryConsumptions{},
+		Tariffs:                &memoryTariffs{},
+		Allocations:            allocations,
+		Bills:                  bills,
+		LedgerEntries:          ledger,
+	}
+	service := NewBillService(bills, repos.Consumptions, nil, nil, repos.Tariffs, allocations, nil, nil, users, repos.Groups, repos.Residencies, repos.GroupWeightChanges, repos.GroupMembershipChanges, nil, nil, &memoryLedgerTxManager{repos: repos})
+	bill := func(id string) *models.Bill { b, _ := bills.GetByID(ctx, id); return b }
+
+	require.NoError(t, service.PostBill(ctx, "gas"))
+	assert.Equal(t, "posted", bill("gas").Status)
+	assert.Len(t, allocations.allocations, 2)
+	assert.Equal(t, utils.MoneyFromString("-60"), ledger.balance("user", "anna"))
+
+	// A new housemate doesn't move the charge that was already posted
+	users.users = append(users.users, models.User{ID: "celina", Name: "Celina", IsActive: true})
+	require.NoError(t, service.CloseBill(ctx, "gas"))
+	require.NoError(t, service.ReopenBill(ctx, "gas", "admin", "posted", "typo"))
+	assert.Len(t, allocations.allocations, 2)
+	assert.Equal(t, utils.MoneyFromString("-60"), ledger.balance("user", "anna"))
+
+	// Back in draft the bill is reversed and allocated anew when posted again
+	require.NoError(t, service.ReopenBill(ctx, "gas", "admin", "draft", "wrong split"))
+	assert.Empty(t, allocations.allocations)
+	assert.Zero(t, ledger.balance("user", "anna").Minor())
+	require.NoError(t, service.PostBill(ctx, "gas"))
+	assert.Len(t, allocations.allocations, 3)
+	assert.Equal(t, utils.MoneyFromString("-40"), ledger.balance("user", "anna"))
+
+	// A bill that cannot be allocated stays a draft with nothing posted
+	entries := len(ledger.entries)
+	assert.Error(t, service.PostBill(ctx, "power"))
+	assert.Equal(t, "draft", bill("power").Status)
+	assert.Len(t, ledger.entries, entries)
+}
+
+func TestPostBill_KeepsAllocationDetail(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	metered := "metered"
+
+	users := &memoryUsers{users: []models.User{
+		{ID: "anna", Name: "Anna", IsActive: true},
+		{ID: "bartek", Name: "Bartek", IsActive: true},
+	}}
+	bills := &memoryBills{bills: []models.Bill{
+		{ID: "power", Type: "electricity", AllocationType: &metered, Status: "draft", TotalAmountPLN: "200.00", TotalUnits: "100", PeriodStart: day(1), PeriodEnd: day(30)},
+	}}
+	allocations := &memoryAllocations{}
+	repos := &repository.Repositories{
+		Users:                  users,
+		Groups:                 &memoryGroups{},
+		Residencies:            &memoryResidencies{},
+		GroupWeightChanges:     &memoryGroupWeightChanges{},
+		GroupMembershipChanges: &memoryMembershipChanges{},
+		Consumptions: &memoryConsumptions{consumptions: []models.Consumption{
+			{ID: "r1", BillID: "power", SubjectType: "user", SubjectID: "anna", Units: "60", RecordedAt: day(30), AnomalyStatus: "none"},
+			{ID: "r2", BillID: "power", SubjectType: "user", SubjectID: "bartek", Units: "20", RecordedAt: day(30), AnomalyStatus: "none"},
+		}},
+		Tariffs:       &memoryTariffs{},
+		Allocations:   allocations,
+		Bills:         bills,
+		LedgerEntries: &memoryLedgerEntries{},
+	}
+	service := NewBillService(bills, repos.Consumptions, nil, nil, repos.Tariffs, allocations, nil, nil, users, repos.Groups, repos.Residencies, repos.GroupWeightChanges, repos.GroupMembershipChanges, nil, nil, &memoryLedgerTxManager{repos: repos})
+	allocationService := NewAllocationService(users, repos.Groups, repos.Residencies, repos.GroupWeightChanges, repos.GroupMembershipChanges, repos.Consumptions, nil, nil, repos.Tariffs, allocations, bills)
+
+	before, err := allocationService.GetAllocationBreakdown(ctx, "power")
+	require.NoError(t, err)
+	require.NoError(t, service.PostBill(ctx, "power"))
+	require.Len(t, allocations.allocations, 2)
+
+	// The posted breakdown is read back from the stored rows with all of its detail
+	after, err := allocationService.GetAllocationBreakdown(ctx, "power")
+	require.NoError(t, err)
+	assert.Equal(t, before, after)
+	for _, share := range after {
+		require.NotNil(t, share.PersonalAmount)
+		require.NotNil(t, share.SharedAmount)
+		require.NotNil(t, share.Units)
+	}
+	assert.Equal(t, 60.0, *after[0].Units)
+	assert.Equal(t, utils.MoneyFromString("120"), *after[0].PersonalAmount)
+	assert.Equal(t, utils.MoneyFromString("20"), *after[0].SharedAmount)
+}
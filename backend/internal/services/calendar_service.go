@@ -0,0 +1,337 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sainaif/holy-home/internal/config"
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/sainaif/holy-home/internal/utils"
+)
+
+// ErrCalendarFeedNotFound is returned for unknown or revoked feed tokens and inactive users
+var ErrCalendarFeedNotFound = errors.New("calendar feed not found")
+
+// CalendarService serves each user's private iCalendar feed of chore due dates, bill
+// payment deadlines and loan due dates. Calendar clients cannot refresh JWTs, so the feed
+// is authenticated with its own long-lived token that the user can revoke at any time.
+type CalendarService struct {
+	feedTokens       repository.CalendarFeedTokenRepository
+	users            repository.UserRepository
+	chores           repository.ChoreRepository
+	choreAssignments repository.ChoreAssignmentRepository
+	bills            repository.BillRepository
+	allocations      repository.AllocationRepository
+	payments         repository.PaymentRepository
+	settlements      repository.SettlementRepository
+	loans            repository.LoanRepository
+	baseURL          string
+}
+
+func NewCalendarService(
+	feedTokens repository.CalendarFeedTokenRepository,
+	users repository.UserRepository,
+	chores repository.ChoreRepository,
+	choreAssignments repository.ChoreAssignmentRepository,
+	bills repository.BillRepository,
+	allocations repository.AllocationRepository,
+	payments repository.PaymentRepository,
+	settlements repository.SettlementRepository,
+	loans repository.LoanRepository,
+	cfg *config.Config,
+) *CalendarService {
+	return &CalendarService{
+		feedTokens:       feedTokens,
+		users:            users,
+		chores:           chores,
+		choreAssignments: choreAssignments,
+		bills:            bills,
+		allocations:      allocations,
+		payments:         payments,
+		settlements:      settlements,
+		loans:            loans,
+		baseURL:          strings.TrimRight(cfg.App.BaseURL, "/"),
+	}
+}
+
+// CreateFeedToken creates a feed token for the user. The plain token is returned only
+// here; just its hash is stored.
+func (s *CalendarService) CreateFeedToken(ctx context.Context, userID, name string) (*models.CalendarFeedToken, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		name = "Kalendarz"
+	}
+	if len(name) > 100 {
+		return nil, "", errors.New("name must be at most 100 characters")
+	}
+
+	token, err := utils.GenerateSecureToken()
+	if err != nil {
+		return nil, "", err
+	}
+
+	feedToken := &models.CalendarFeedToken{
+		ID:        uuid.New().String(),
+		UserID:    userID,
+		TokenHash: utils.HashToken(token),
+		Name:      name,
+		CreatedAt: time.Now(),
+	}
+	if err := s.feedTokens.Create(ctx, feedToken); err != nil {
+		return nil, "", fmt.Errorf("failed to create calendar feed token: %w", err)
+	}
+
+	log.Printf("[CALENDAR] Feed token created: user ID %s (token ID: %s, name: %q)", userID, feedToken.ID, name)
+	return feedToken, token, nil
+}
+
+// ListFeedTokens returns the user's feed tokens
+func (s *CalendarService) ListFeedTokens(ctx context.Context, userID string) ([]models.CalendarFeedToken, error) {
+	return s.feedTokens.ListByUserID(ctx, userID)
+}
+
+// RevokeFeedToken deletes one of the user's feed tokens
+func (s *CalendarService) RevokeFeedToken(ctx context.Context, userID, tokenID string) error {
+	feedToken, err := s.feedTokens.GetByID(ctx, tokenID)
+	if err != nil || feedToken == nil || feedToken.UserID != userID {
+		return errors.New("calendar feed token not found")
+	}
+
+	if err := s.feedTokens.Delete(ctx, tokenID); err != nil {
+		return err
+	}
+
+	log.Printf("[CALENDAR] Feed token revoked: user ID %s (token ID: %s)", userID, tokenID)
+	return nil
+}
+
+// FeedURL returns the address calendar clients subscribe to. The token is a query
+// parameter so it stays out of access logs, which record only the path.
+func (s *CalendarService) FeedURL(token string) string {
+	return s.baseURL + "/api/calendar.ics?token=" + token
+}
+
+// Feed renders the iCalendar feed for a feed token
+func (s *CalendarService) Feed(ctx context.Context, token string, now time.Time) ([]byte, error) {
+	if utils.ValidateTokenFormat(token) != nil {
+		return nil, ErrCalendarFeedNotFound
+	}
+	feedToken, err := s.feedTokens.GetByTokenHash(ctx, utils.HashToken(token))
+	if err != nil {
+		return nil, err
+	}
+	if feedToken == nil {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	user, err := s.users.GetByID(ctx, feedToken.UserID)
+	if err != nil || user == nil || !user.IsActive {
+		return nil, ErrCalendarFeedNotFound
+	}
+
+	events, err := s.userEvents(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+
+	_ = s.feedTokens.UpdateLastUsed(ctx, feedToken.ID, now)
+
+	name := "Holy Home"
+	if user.Name != "" {
+		name += " – " + user.Name
+	}
+	return utils.WriteCalendar(name, events, now), nil
+}
+
+// userEvents collects the user's open chore assignments, deadlines of posted bills they
+// still owe on and due dates of open loans they lent or borrowed, ordered by date
+func (s *CalendarService) userEvents(ctx context.Context, user *models.User) ([]utils.CalendarEvent, error) {
+	var events []utils.CalendarEvent
+
+	choreEvents, err := s.choreEvents(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, choreEvents...)
+
+	billEvents, err := s.billEvents(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, billEvents...)
+
+	loanEvents, err := s.loanEvents(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	events = append(events, loanEvents...)
+
+	sort.SliceStable(events, func(i, j int) bool { return events[i].Date.Before(events[j].Date) })
+	return events, nil
+}
+
+func (s *CalendarService) choreEvents(ctx context.Context, user *models.User) ([]utils.CalendarEvent, error) {
+	assignments, err := s.choreAssignments.ListByAssigneeID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list chore assignments: %w", err)
+	}
+
+	chores := make(map[string]*models.Chore)
+	var events []utils.CalendarEvent
+	for _, assignment := range assignments {
+		if assignment.Status == "done" {
+			continue
+		}
+		chore, ok := chores[assignment.ChoreID]
+		if !ok {
+			chore, err = s.chores.GetByID(ctx, assignment.ChoreID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get chore: %w", err)
+			}
+			chores[assignment.ChoreID] = chore
+		}
+		if chore == nil {
+			continue
+		}
+
+		event := utils.CalendarEvent{
+			UID:     "chore-assignment-" + assignment.ID + "@holy-home",
+			Date:    assignment.DueDate,
+			Summary: "Zadanie: " + chore.Name,
+		}
+		if chore.Description != nil {
+			event.Description = *chore.Description
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func (s *CalendarService) billEvents(ctx context.Context, user *models.User) ([]utils.CalendarEvent, error) {
+	bills, err := s.bills.ListByStatus(ctx, "posted")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list bills: %w", err)
+	}
+
+	var events []utils.CalendarEvent
+	for _, bill := range bills {
+		if bill.PaymentDeadline == nil {
+			continue
+		}
+		owes, err := s.owesOnBill(ctx, user, bill.ID)
+		if err != nil {
+			return nil, err
+		}
+		if !owes {
+			continue
+		}
+
+		events = append(events, utils.CalendarEvent{
+			UID:     "bill-" + bill.ID + "@holy-home",
+			Date:    *bill.PaymentDeadline,
+			Summary: "Termin płatności: " + getBillTypeName(bill.Type, bill.CustomType),
+			Description: fmt.Sprintf("Okres rozliczeniowy: %s – %s",
+				bill.PeriodStart.Format("2006-01-02"), bill.PeriodEnd.Format("2006-01-02")),
+		})
+	}
+	return events, nil
+}
+
+// owesOnBill reports whether the bill allocates a share to the user, or to the user's
+// group, that has not been paid in full yet
+func (s *CalendarService) owesOnBill(ctx context.Context, user *models.User, billID string) (bool, error) {
+	allocations, err := s.allocations.GetByBillID(ctx, billID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get allocations: %w", err)
+	}
+
+	var paidByUser map[string]utils.Money
+	for _, alloc := range allocations {
+		isUser := alloc.SubjectType == "user" && alloc.SubjectID == user.ID
+		isGroup := alloc.SubjectType == "group" && user.GroupID != nil && alloc.SubjectID == *user.GroupID
+		if !isUser && !isGroup {
+			continue
+		}
+
+		if paidByUser == nil {
+			paidByUser, err = billPaidByUser(ctx, s.payments, s.settlements, billID)
+			if err != nil {
+				return false, err
+			}
+		}
+
+		payers := map[string]bool{user.ID: true}
+		if isGroup {
+			members, err := s.users.ListByGroupID(ctx, alloc.SubjectID)
+			if err != nil {
+				return false, fmt.Errorf("failed to list group members: %w", err)
+			}
+			for _, member := range members {
+				payers[member.ID] = true
+			}
+		}
+
+		var paid utils.Money
+		for payer := range payers {
+			paid += paidByUser[payer]
+		}
+		if paid < utils.MoneyFromString(alloc.AllocatedPLN) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (s *CalendarService) loanEvents(ctx context.Context, user *models.User) ([]utils.CalendarEvent, error) {
+	borrowed, err := s.loans.ListByBorrowerID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list loans: %w", err)
+	}
+	lent, err := s.loans.ListByLenderID(ctx, user.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list loans: %w", err)
+	}
+
+	names := make(map[string]string)
+	userName := func(id string) string {
+		if name, ok := names[id]; ok {
+			return name
+		}
+		name := "?"
+		if u, err := s.users.GetByID(ctx, id); err == nil && u != nil {
+			name = u.Name
+		}
+		names[id] = name
+		return name
+	}
+
+	var events []utils.CalendarEvent
+	addLoan := func(loan models.Loan, summary string) {
+		if loan.DueDate == nil || loan.Status == "settled" {
+			return
+		}
+		event := utils.CalendarEvent{
+			UID:     "loan-" + loan.ID + "@holy-home",
+			Date:    *loan.DueDate,
+			Summary: summary,
+		}
+		if loan.Note != nil {
+			event.Description = *loan.Note
+		}
+		events = append(events, event)
+	}
+	for _, loan := range borrowed {
+		addLoan(loan, "Spłata pożyczki dla: "+userName(loan.LenderID))
+	}
+	for _, loan := range lent {
+		addLoan(loan, "Zwrot pożyczki od: "+userName(loan.BorrowerID))
+	}
+	return events, nil
+}
@@ -0,0 +1,241 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/config"
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryCalendarFeedTokens struct {
+	repository.CalendarFeedTokenRepository
+	tokens []models.CalendarFeedToken
+}
+
+func (m *memoryCalendarFeedTokens) Create(ctx context.Context, token *models.CalendarFeedToken) error {
+	m.tokens = append(m.tokens, *token)
+	return nil
+}
+
+func (m *memoryCalendarFeedTokens) GetByID(ctx context.Context, id string) (*models.CalendarFeedToken, error) {
+	for _, token := range m.tokens {
+		if token.ID == id {
+			return &token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryCalendarFeedTokens) GetByTokenHash(ctx context.Context, tokenHash string) (*models.CalendarFeedToken, error) {
+	for _, token := range m.tokens {
+		if token.TokenHash == tokenHash {
+			return &token, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryCalendarFeedTokens) UpdateLastUsed(ctx context.Context, id string, lastUsedAt time.Time) error {
+	for i := range m.tokens {
+		if m.tokens[i].ID == id {
+			m.tokens[i].LastUsedAt = &lastUsedAt
+		}
+	}
+	return nil
+}
+
+func (m *memoryCalendarFeedTokens) Delete(ctx context.Context, id string) error {
+	for i := range m.tokens {
+		if m.tokens[i].ID == id {
+			m.tokens = append(m.tokens[:i], m.tokens[i+1:]...)
+			return nil
+		}
+	}
+	return nil
+}
+
+func (m *memoryChoreAssignments) ListByAssigneeID(ctx context.Context, assigneeID string) ([]models.ChoreAssignment, error) {
+	var assignments []models.ChoreAssignment
+	for _, assignment := range m.assignments {
+		if assignment.AssigneeUserID == assigneeID {
+			assignments = append(assignments, assignment)
+		}
+	}
+	return assignments, nil
+}
+
+func (m *memoryUsers) ListByGroupID(ctx context.Context, groupID string) ([]models.User, error) {
+	var users []models.User
+	for _, user := range m.users {
+		if user.GroupID != nil && *user.GroupID == groupID {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+type memoryBills struct {
+	repository.BillRepository
+	bills []models.Bill
+}
+
+func (m *memoryBills) ListByStatus(ctx context.Context, status string) ([]models.Bill, error) {
+	var bills []models.Bill
+	for _, bill := range m.bills {
+		if bill.Status == status {
+			bills = append(bills, bill)
+		}
+	}
+	return bills, nil
+}
+
+type memoryAllocations struct {
+	repository.AllocationRepository
+	allocations []repository.Allocation
+}
+
+func (m *memoryAllocations) GetByBillID(ctx context.Context, billID string) ([]repository.Allocation, error) {
+	var allocations []repository.Allocation
+	for _, alloc := range m.allocations {
+		if alloc.BillID == billID {
+			allocations = append(allocations, alloc)
+		}
+	}
+	return allocations, nil
+}
+
+type memoryPayments struct {
+	repository.PaymentRepository
+	payments []models.Payment
+}
+
+func (m *memoryPayments) ListByBillID(ctx context.Context, billID string) ([]models.Payment, error) {
+	var payments []models.Payment
+	for _, payment := range m.payments {
+		if payment.BillID == billID {
+			payments = append(payments, payment)
+		}
+	}
+	return payments, nil
+}
+
+type memoryLoans struct {
+	repository.LoanRepository
+	loans []models.Loan
+}
+
+func (m *memoryLoans) ListByBorrowerID(ctx context.Context, borrowerID string) ([]models.Loan, error) {
+	var loans []models.Loan
+	for _, loan := range m.loans {
+		if loan.BorrowerID == borrowerID {
+			loans = append(loans, loan)
+		}
+	}
+	return loans, nil
+}
+
+func (m *memoryLoans) ListByLenderID(ctx context.Context, lenderID string) ([]models.Loan, error) {
+	var loans []models.Loan
+	for _, loan := range m.loans {
+		if loan.LenderID == lenderID {
+			loans = append(loans, loan)
+		}
+	}
+	return loans, nil
+}
+
+func TestCalendarFeed(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	deadline := func(d int) *time.Time { t := day(d); return &t }
+	flat := "flat"
+	internet := "Światłowód"
+
+	users := &memoryUsers{users: []models.User{
+		{ID: "anna", Name: "Anna", GroupID: &flat, IsActive: true},
+		{ID: "bartek", Name: "Bartek", GroupID: &flat, IsActive: true},
+		{ID: "celina", Name: "Celina", IsActive: true},
+	}}
+	chores := &memoryChores{chores: []models.Chore{{ID: "dishes", Name: "Zmywanie"}}}
+	assignments := &memoryChoreAssignments{assignments: []models.ChoreAssignment{
+		{ID: "a1", ChoreID: "dishes", AssigneeUserID: "anna", DueDate: day(12), Status: "pending"},
+		{ID: "a2", ChoreID: "dishes", AssigneeUserID: "anna", DueDate: day(5), Status: "done"},
+		{ID: "a3", ChoreID: "dishes", AssigneeUserID: "celina", DueDate: day(13), Status: "pending"},
+	}}
+	bills := &memoryBills{bills: []models.Bill{
+		{ID: "gas", Type: "gas", Status: "posted", PaymentDeadline: deadline(20)},
+		{ID: "internet", Type: "inne", CustomType: &internet, Status: "posted", PaymentDeadline: deadline(25)},
+		{ID: "paid", Type: "electricity", Status: "posted", PaymentDeadline: deadline(15)},
+		{ID: "draft", Type: "gas", Status: "draft", PaymentDeadline: deadline(28)},
+		{ID: "celina-only", Type: "gas", Status: "posted", PaymentDeadline: deadline(18)},
+	}}
+	allocations := &memoryAllocations{allocations: []repository.Allocation{
+		{BillID: "gas", SubjectType: "user", SubjectID: "anna", AllocatedPLN: "50.00"},
+		{BillID: "internet", SubjectType: "group", SubjectID: "flat", AllocatedPLN: "80.00"},
+		{BillID: "paid", SubjectType: "user", SubjectID: "anna", AllocatedPLN: "30.00"},
+		{BillID: "draft", SubjectType: "user", SubjectID: "anna", AllocatedPLN: "10.00"},
+		{BillID: "celina-only", SubjectType: "user", SubjectID: "celina", AllocatedPLN: "10.00"},
+	}}
+	payments := &memoryPayments{payments: []models.Payment{
+		{BillID: "gas", PayerUserID: "anna", AmountPLN: "20.00"},
+		{BillID: "internet", PayerUserID: "bartek", AmountPLN: "40.00"},
+		{BillID: "paid", PayerUserID: "anna", AmountPLN: "30.00"},
+	}}
+	loans := &memoryLoans{loans: []models.Loan{
+		{ID: "l1", LenderID: "celina", BorrowerID: "anna", DueDate: deadline(30), Status: "open"},
+		{ID: "l2", LenderID: "anna", BorrowerID: "bartek", DueDate: deadline(22), Status: "partial"},
+		{ID: "l3", LenderID: "anna", BorrowerID: "celina", DueDate: deadline(11), Status: "settled"},
+		{ID: "l4", LenderID: "anna", BorrowerID: "celina", Status: "open"},
+	}}
+	feedTokens := &memoryCalendarFeedTokens{}
+
+	cfg := &config.Config{App: config.AppConfig{BaseURL: "https://home.example/"}}
+	s := NewCalendarService(feedTokens, users, chores, assignments, bills, allocations, payments, &memorySettlements{}, loans, cfg)
+
+	feedToken, token, err := s.CreateFeedToken(ctx, "anna", "  ")
+	require.NoError(t, err)
+	assert.Equal(t, "Kalendarz", feedToken.Name)
+	assert.NotContains(t, feedToken.TokenHash, token, "only the hash is stored")
+	assert.Equal(t, "https://home.example/api/calendar.ics?token="+token, s.FeedURL(token))
+
+	feed, err := s.Feed(ctx, token, now)
+	require.NoError(t, err)
+	ics := string(feed)
+
+	var summaries []string
+	for _, line := range strings.Split(ics, "\r\n") {
+		if strings.HasPrefix(line, "SUMMARY:") {
+			summaries = append(summaries, strings.TrimPrefix(line, "SUMMARY:"))
+		}
+	}
+	assert.Equal(t, []string{
+		"Zadanie: Zmywanie",            // 12th; done and other users' assignments are left out
+		"Termin płatności: Gaz",        // 20th; partly paid
+		"Zwrot pożyczki od: Bartek",    // 22nd; lent by Anna
+		"Termin płatności: Światłowód", // 25th; the group's share is not paid in full
+		"Spłata pożyczki dla: Celina",  // 30th; borrowed by Anna
+	}, summaries)
+	assert.Contains(t, ics, "UID:chore-assignment-a1@holy-home\r\n")
+	assert.NotNil(t, feedTokens.tokens[0].LastUsedAt)
+
+	// Unknown tokens, revoked tokens and inactive users have no feed
+	_, err = s.Feed(ctx, "not-a-token", now)
+	assert.ErrorIs(t, err, ErrCalendarFeedNotFound)
+
+	users.users[0].IsActive = false
+	_, err = s.Feed(ctx, token, now)
+	assert.ErrorIs(t, err, ErrCalendarFeedNotFound)
+	users.users[0].IsActive = true
+
+	assert.Error(t, s.RevokeFeedToken(ctx, "bartek", feedToken.ID), "tokens of other users cannot be revoked")
+	require.NoError(t, s.RevokeFeedToken(ctx, "anna", feedToken.ID))
+	_, err = s.Feed(ctx, token, now)
+	assert.ErrorIs(t, err, ErrCalendarFeedNotFound)
+}
@@ -6,35 +6,68 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"math"
 	"math/big"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/sainaif/holy-home/internal/config"
 	"github.com/sainaif/holy-home/internal/models"
 	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/sainaif/holy-home/internal/utils"
 )
 
 type ChoreService struct {
 	chores              repository.ChoreRepository
 	choreAssignments    repository.ChoreAssignmentRepository
 	choreSwapRequests   repository.ChoreSwapRequestRepository
+	userAbsences        repository.UserAbsenceRepository
+	chorePreferences    repository.ChorePreferenceRepository
+	choreCompletions    repository.ChoreCompletionRepository
+	choreSettings       repository.ChoreSettingsRepository
+	sentReminders       repository.SentReminderRepository
 	users               repository.UserRepository
 	notificationService *NotificationService
+	scheduleDays        int
+	fairWindowDays      int
+	fairHalfLifeDays    int
+	photoDir            string
+	photoMaxBytes       int
 }
 
 func NewChoreService(
 	chores repository.ChoreRepository,
 	choreAssignments repository.ChoreAssignmentRepository,
 	choreSwapRequests repository.ChoreSwapRequestRepository,
+	userAbsences repository.UserAbsenceRepository,
+	chorePreferences repository.ChorePreferenceRepository,
+	choreCompletions repository.ChoreCompletionRepository,
+	choreSettings repository.ChoreSettingsRepository,
+	sentReminders repository.SentReminderRepository,
 	users repository.UserRepository,
 	notificationService *NotificationService,
+	cfg *config.Config,
 ) *ChoreService {
 	return &ChoreService{
 		chores:              chores,
 		choreAssignments:    choreAssignments,
 		choreSwapRequests:   choreSwapRequests,
+		userAbsences:        userAbsences,
+		chorePreferences:    chorePreferences,
+		choreCompletions:    choreCompletions,
+		choreSettings:       choreSettings,
+		sentReminders:       sentReminders,
 		users:               users,
 		notificationService: notificationService,
+		scheduleDays:        cfg.Chores.ScheduleDays,
+		fairWindowDays:      cfg.Chores.FairWindowDays,
+		fairHalfLifeDays:    cfg.Chores.FairHalfLifeDays,
+		photoDir:            cfg.Chores.PhotoDir,
+		photoMaxBytes:       cfg.Chores.PhotoMaxBytes,
 	}
 }
 
@@ -43,22 +76,28 @@ type CreateChoreRequest struct {
 	Description          *string `json:"description,omitempty"`
 	Frequency            string  `json:"frequency"` // daily, weekly, monthly, custom, irregular
 	CustomInterval       *int    `json:"customInterval,omitempty"`
+	RecurrenceRule       *string `json:"recurrenceRule,omitempty"`   // RFC 5545 RRULE, replaces frequency when set
 	Difficulty           int     `json:"difficulty"`                 // 1-5
 	Priority             int     `json:"priority"`                   // 1-5
-	AssignmentMode       string  `json:"assignmentMode"`             // manual, round_robin, random
+	AssignmentMode       string  `json:"assignmentMode"`             // manual, round_robin, random, fair
 	ManualAssigneeID     *string `json:"manualAssigneeId,omitempty"` // User ID for manual assignment mode
 	NotificationsEnabled bool    `json:"notificationsEnabled"`
 	ReminderHours        *int    `json:"reminderHours,omitempty"`
+	OverduePolicy        string  `json:"overduePolicy"` // keep, reassign
+	RequirePhoto         bool    `json:"requirePhoto"`
+	RequireConfirmation  bool    `json:"requireConfirmation"`
 }
 
 type AssignChoreRequest struct {
-	ChoreID        string    `json:"choreId"`
-	AssigneeUserID string    `json:"assigneeUserId"`
-	DueDate        time.Time `json:"dueDate"`
+	ChoreID          string    `json:"choreId"`
+	AssigneeUserID   string    `json:"assigneeUserId"`
+	DueDate          time.Time `json:"dueDate"`
+	AssignedByUserID *string   `json:"-"` // Set from the session, nil for scheduled assignments
 }
 
 type UpdateChoreAssignmentRequest struct {
 	Status string `json:"status"` // pending, in_progress, done, overdue
+	UserID string `json:"-"`      // Set from the session, submits the completion of chores that need verification
 }
 
 type UpdateChoreRequest struct {
@@ -66,12 +105,16 @@ type UpdateChoreRequest struct {
 	Description          *string `json:"description,omitempty"`
 	Frequency            *string `json:"frequency,omitempty"`
 	CustomInterval       *int    `json:"customInterval,omitempty"`
+	RecurrenceRule       *string `json:"recurrenceRule,omitempty"` // Empty string removes the rule
 	Difficulty           *int    `json:"difficulty,omitempty"`
 	Priority             *int    `json:"priority,omitempty"`
 	AssignmentMode       *string `json:"assignmentMode,omitempty"`
 	ManualAssigneeID     *string `json:"manualAssigneeId,omitempty"` // User ID for manual assignment mode
 	NotificationsEnabled *bool   `json:"notificationsEnabled,omitempty"`
 	ReminderHours        *int    `json:"reminderHours,omitempty"`
+	OverduePolicy        *string `json:"overduePolicy,omitempty"`
+	RequirePhoto         *bool   `json:"requirePhoto,omitempty"`
+	RequireConfirmation  *bool   `json:"requireConfirmation,omitempty"`
 	IsActive             *bool   `json:"isActive,omitempty"`
 }
 
@@ -118,6 +161,12 @@ func (s *ChoreService) CreateChore(ctx context.Context, req CreateChoreRequest)
 	if req.AssignmentMode == "" {
 		req.AssignmentMode = "round_robin"
 	}
+	if req.OverduePolicy == "" {
+		req.OverduePolicy = "keep"
+	}
+	if !validOverduePolicies[req.OverduePolicy] {
+		return nil, errors.New("invalid overdue policy")
+	}
 
 	chore := models.Chore{
 		ID:                   uuid.New().String(),
@@ -131,9 +180,18 @@ func (s *ChoreService) CreateChore(ctx context.Context, req CreateChoreRequest)
 		ManualAssigneeID:     req.ManualAssigneeID,
 		NotificationsEnabled: req.NotificationsEnabled,
 		ReminderHours:        req.ReminderHours,
+		OverduePolicy:        req.OverduePolicy,
+		RequirePhoto:         req.RequirePhoto,
+		RequireConfirmation:  req.RequireConfirmation,
 		IsActive:             true,
 		CreatedAt:            time.Now(),
 	}
+	if req.RecurrenceRule != nil && *req.RecurrenceRule != "" {
+		chore.RecurrenceRule = req.RecurrenceRule
+		if _, err := choreRecurrence(&chore); err != nil {
+			return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+		}
+	}
 
 	if err := s.chores.Create(ctx, &chore); err != nil {
 		return nil, fmt.Errorf("failed to create chore: %w", err)
@@ -214,13 +272,14 @@ func (s *ChoreService) AssignChore(ctx context.Context, req AssignChoreRequest)
 	points := chore.Difficulty * 10
 
 	assignment := models.ChoreAssignment{
-		ID:             uuid.New().String(),
-		ChoreID:        req.ChoreID,
-		AssigneeUserID: req.AssigneeUserID,
-		DueDate:        req.DueDate,
-		Status:         "pending",
-		Points:         points,
-		IsOnTime:       false,
+		ID:               uuid.New().String(),
+		ChoreID:          req.ChoreID,
+		AssigneeUserID:   req.AssigneeUserID,
+		DueDate:          req.DueDate,
+		Status:           "pending",
+		Points:           points,
+		IsOnTime:         false,
+		AssignedByUserID: req.AssignedByUserID,
 	}
 
 	if err := s.choreAssignments.Create(ctx, &assignment); err != nil {
@@ -322,21 +381,18 @@ func (s *ChoreService) UpdateChoreAssignment(ctx context.Context, assignmentID s
 		return err
 	}
 
-	assignment.Status = req.Status
-
 	if req.Status == "done" {
-		now := time.Now()
-		assignment.CompletedAt = &now
-
-		// Check if completed on time and award bonus points
-		isOnTime := now.Before(assignment.DueDate) || now.Equal(assignment.DueDate)
-		assignment.IsOnTime = isOnTime
-
-		if isOnTime {
-			// 50% bonus for on-time completion
-			assignment.Points = int(float64(assignment.Points) * 1.5)
+		chore, err := s.GetChore(ctx, assignment.ChoreID)
+		if err != nil {
+			return err
 		}
+		if chore.RequirePhoto || chore.RequireConfirmation {
+			_, err := s.submitCompletion(ctx, chore, assignment, req.UserID, nil)
+			return err
+		}
+		completeAssignment(assignment, chore, time.Now())
 	} else {
+		assignment.Status = req.Status
 		assignment.CompletedAt = nil
 	}
 
@@ -378,42 +434,14 @@ func (s *ChoreService) SwapChoreAssignment(ctx context.Context, assignment1ID, a
 
 // RotateChore creates a new assignment based on a rotating schedule (ADMIN only)
 func (s *ChoreService) RotateChore(ctx context.Context, choreID string, dueDate time.Time) (*models.ChoreAssignment, error) {
-	// Get all active users
-	users, err := s.users.ListActive(ctx)
+	chore, err := s.GetChore(ctx, choreID)
 	if err != nil {
-		return nil, fmt.Errorf("database error: %w", err)
-	}
-
-	if len(users) == 0 {
-		return nil, errors.New("no active users to assign chore to")
+		return nil, err
 	}
 
-	// Get the last assignment for this chore
-	lastAssignment, err := s.choreAssignments.GetLatestByChoreID(ctx, choreID)
-
-	var nextUserID string
-
-	if err != nil || lastAssignment == nil {
-		// No previous assignment, assign to first user
-		nextUserID = users[0].ID
-	} else {
-		// Find next user in rotation
-		lastUserIndex := -1
-		for i, u := range users {
-			if u.ID == lastAssignment.AssigneeUserID {
-				lastUserIndex = i
-				break
-			}
-		}
-
-		if lastUserIndex == -1 {
-			// Last user no longer exists, start from beginning
-			nextUserID = users[0].ID
-		} else {
-			// Move to next user (circular)
-			nextUserIndex := (lastUserIndex + 1) % len(users)
-			nextUserID = users[nextUserIndex].ID
-		}
+	nextUserID, err := s.rotationAssignee(ctx, chore, dueDate, "")
+	if err != nil {
+		return nil, err
 	}
 
 	// Create new assignment
@@ -479,14 +507,28 @@ type UserStats struct {
 
 // AutoAssignChore automatically assigns a chore to the user with least workload
 func (s *ChoreService) AutoAssignChore(ctx context.Context, choreID string, dueDate time.Time) (*models.ChoreAssignment, error) {
-	// Get all active users
+	chore, err := s.GetChore(ctx, choreID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get the users available on the due date
 	users, err := s.users.ListActive(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("database error: %w", err)
 	}
+	users, err = s.availableAssignees(ctx, chore.ID, dueDate, users)
+	if err != nil {
+		return nil, err
+	}
 
 	if len(users) == 0 {
-		return nil, errors.New("no active users to assign chore to")
+		return nil, errors.New("no available users to assign chore to")
+	}
+
+	recentPoints, _, err := s.recentChorePoints(ctx, chore.ID, dueDate)
+	if err != nil {
+		return nil, err
 	}
 
 	// Calculate workload for each user (pending chores + their difficulty)
@@ -494,6 +536,7 @@ func (s *ChoreService) AutoAssignChore(ctx context.Context, choreID string, dueD
 		UserID   string
 		Workload int // Sum of difficulty points from pending chores
 		Count    int // Number of pending chores
+		Recent   int // Points of this chore's recent assignments
 	}
 
 	workloads := make([]userWorkload, 0, len(users))
@@ -518,13 +561,23 @@ func (s *ChoreService) AutoAssignChore(ctx context.Context, choreID string, dueD
 			UserID:   user.ID,
 			Workload: totalWorkload,
 			Count:    len(pendingAssignments),
+			Recent:   recentPoints[user.ID],
 		})
 	}
 
-	// Find user with minimum workload (prioritize by difficulty sum, then by count)
+	// Find user with minimum workload (prioritize by difficulty sum, then by count, then by
+	// recent points so users back from an absence catch up)
 	minWorkload := workloads[0]
 	for _, wl := range workloads[1:] {
-		if wl.Workload < minWorkload.Workload || (wl.Workload == minWorkload.Workload && wl.Count < minWorkload.Count) {
+		if wl.Workload != minWorkload.Workload {
+			if wl.Workload < minWorkload.Workload {
+				minWorkload = wl
+			}
+		} else if wl.Count != minWorkload.Count {
+			if wl.Count < minWorkload.Count {
+				minWorkload = wl
+			}
+		} else if wl.Recent < minWorkload.Recent {
 			minWorkload = wl
 		}
 	}
@@ -557,16 +610,20 @@ func (s *ChoreService) GetUserLeaderboard(ctx context.Context) ([]UserStats, err
 		// Filter completed
 		completedAssignments := []models.ChoreAssignment{}
 		pendingCount := 0
+		penaltyPoints := 0
 		for _, a := range allAssignments {
 			if a.Status == "done" {
 				completedAssignments = append(completedAssignments, a)
 			} else if a.Status == "pending" {
 				pendingCount++
+			} else if a.Status == "overdue" && a.Points < 0 {
+				// Deducted for missing the due date, counts before it is done
+				penaltyPoints += a.Points
 			}
 		}
 
 		// Calculate stats
-		totalPoints := 0
+		totalPoints := penaltyPoints
 		onTimeCount := 0
 		for _, assignment := range completedAssignments {
 			totalPoints += assignment.Points
@@ -604,26 +661,61 @@ func (s *ChoreService) GetUserLeaderboard(ctx context.Context) ([]UserStats, err
 
 // DeleteChore deletes a chore and all its assignments
 func (s *ChoreService) DeleteChore(ctx context.Context, choreID string) error {
+	deleted, err := deleteChoreWithAssignments(ctx, s.chores, s.choreAssignments, choreID)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("[CHORE] Deleted: ID=%s (including %d assignments)", choreID, deleted)
+
+	return nil
+}
+
+// ExecuteApprovedDelete is the approval executor for "chore.delete" requests
+func (s *ChoreService) ExecuteApprovedDelete(ctx context.Context, repos *repository.Repositories, request *models.ApprovalRequest) (map[string]interface{}, error) {
+	if request.ResourceID == nil {
+		return nil, errors.New("approval request has no chore ID")
+	}
+
+	chore, err := repos.Chores.GetByID(ctx, *request.ResourceID)
+	if err != nil || chore == nil {
+		return nil, errors.New("chore not found")
+	}
+
+	deleted, err := deleteChoreWithAssignments(ctx, repos.Chores, repos.ChoreAssignments, chore.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[CHORE] Deleted after approval: ID=%s (including %d assignments, request %s)", chore.ID, deleted, request.ID)
+
+	return map[string]interface{}{
+		"choreId":            chore.ID,
+		"choreName":          chore.Name,
+		"deletedAssignments": deleted,
+	}, nil
+}
+
+// deleteChoreWithAssignments deletes a chore and all of its assignments, returning the number of assignments removed
+func deleteChoreWithAssignments(ctx context.Context, chores repository.ChoreRepository, choreAssignments repository.ChoreAssignmentRepository, choreID string) (int, error) {
 	// Delete all assignments for this chore
-	assignments, err := s.choreAssignments.ListByChoreID(ctx, choreID)
+	assignments, err := choreAssignments.ListByChoreID(ctx, choreID)
 	if err != nil {
-		return fmt.Errorf("failed to list chore assignments: %w", err)
+		return 0, fmt.Errorf("failed to list chore assignments: %w", err)
 	}
 
 	for _, assignment := range assignments {
-		if err := s.choreAssignments.Delete(ctx, assignment.ID); err != nil {
-			return fmt.Errorf("failed to delete chore assignment: %w", err)
+		if err := choreAssignments.Delete(ctx, assignment.ID); err != nil {
+			return 0, fmt.Errorf("failed to delete chore assignment: %w", err)
 		}
 	}
 
 	// Delete the chore
-	if err := s.chores.Delete(ctx, choreID); err != nil {
-		return fmt.Errorf("failed to delete chore: %w", err)
+	if err := chores.Delete(ctx, choreID); err != nil {
+		return 0, fmt.Errorf("failed to delete chore: %w", err)
 	}
 
-	log.Printf("[CHORE] Deleted: ID=%s (including %d assignments)", choreID, len(assignments))
-
-	return nil
+	return len(assignments), nil
 }
 
 // UpdateChore updates an existing chore
@@ -656,6 +748,16 @@ func (s *ChoreService) UpdateChore(ctx context.Context, choreID string, req Upda
 	if req.CustomInterval != nil {
 		chore.CustomInterval = req.CustomInterval
 	}
+	if req.RecurrenceRule != nil {
+		if *req.RecurrenceRule == "" {
+			chore.RecurrenceRule = nil
+		} else {
+			chore.RecurrenceRule = req.RecurrenceRule
+			if _, err := choreRecurrence(chore); err != nil {
+				return nil, fmt.Errorf("invalid recurrence rule: %w", err)
+			}
+		}
+	}
 	if req.Difficulty != nil {
 		if *req.Difficulty < 1 || *req.Difficulty > 5 {
 			return nil, errors.New("difficulty must be between 1 and 5")
@@ -670,13 +772,25 @@ func (s *ChoreService) UpdateChore(ctx context.Context, choreID string, req Upda
 	}
 	if req.AssignmentMode != nil {
 		validModes := map[string]bool{
-			"manual": true, "round_robin": true, "random": true,
+			"manual": true, "round_robin": true, "random": true, "fair": true,
 		}
 		if !validModes[*req.AssignmentMode] {
 			return nil, errors.New("invalid assignment mode")
 		}
 		chore.AssignmentMode = *req.AssignmentMode
 	}
+	if req.OverduePolicy != nil {
+		if !validOverduePolicies[*req.OverduePolicy] {
+			return nil, errors.New("invalid overdue policy")
+		}
+		chore.OverduePolicy = *req.OverduePolicy
+	}
+	if req.RequirePhoto != nil {
+		chore.RequirePhoto = *req.RequirePhoto
+	}
+	if req.RequireConfirmation != nil {
+		chore.RequireConfirmation = *req.RequireConfirmation
+	}
 	if req.NotificationsEnabled != nil {
 		chore.NotificationsEnabled = *req.NotificationsEnabled
 	}
@@ -789,43 +903,39 @@ func (s *ChoreService) ReassignChoreAssignment(ctx context.Context, assignmentID
 // RandomAssignChore randomly assigns a chore to one of the eligible users
 func (s *ChoreService) RandomAssignChore(ctx context.Context, choreID string, req RandomAssignRequest) (*models.ChoreAssignment, error) {
 	// Verify chore exists
-	_, err := s.GetChore(ctx, choreID)
+	chore, err := s.GetChore(ctx, choreID)
 	if err != nil {
 		return nil, err
 	}
 
 	// If no eligible users provided, use all active users
-	eligibleUserIDs := req.EligibleUserIDs
-	if len(eligibleUserIDs) == 0 {
-		users, err := s.users.ListActive(ctx)
+	var eligibleUsers []models.User
+	if len(req.EligibleUserIDs) == 0 {
+		eligibleUsers, err = s.users.ListActive(ctx)
 		if err != nil {
 			return nil, fmt.Errorf("failed to get active users: %w", err)
 		}
-		for _, u := range users {
-			eligibleUserIDs = append(eligibleUserIDs, u.ID)
-		}
-	}
-
-	if len(eligibleUserIDs) == 0 {
-		return nil, errors.New("no eligible users to assign chore to")
 	}
 
 	// Verify all eligible users exist
-	for _, userID := range eligibleUserIDs {
+	for _, userID := range req.EligibleUserIDs {
 		user, err := s.users.GetByID(ctx, userID)
 		if err != nil || user == nil {
 			return nil, fmt.Errorf("user %s not found", userID)
 		}
+		eligibleUsers = append(eligibleUsers, *user)
 	}
 
-	// Pick a random user using crypto/rand for secure randomness
-	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(eligibleUserIDs))))
+	if len(eligibleUsers) == 0 {
+		return nil, errors.New("no eligible users to assign chore to")
+	}
+
+	randomUserID, err := s.randomAssignee(ctx, chore, req.DueDate, eligibleUsers)
 	if err != nil {
-		return nil, fmt.Errorf("failed to generate random number: %w", err)
+		return nil, err
 	}
-	randomUserID := eligibleUserIDs[n.Int64()]
 
-	log.Printf("[CHORE] Random assignment: selected user %s from %d eligible users", randomUserID, len(eligibleUserIDs))
+	log.Printf("[CHORE] Random assignment: selected user %s from %d eligible users", randomUserID, len(eligibleUsers))
 
 	// Assign to random user
 	return s.AssignChore(ctx, AssignChoreRequest{
@@ -1109,3 +1219,1290 @@ func (s *ChoreService) GetSwapRequest(ctx context.Context, requestID string) (*m
 	}
 	return request, nil
 }
+
+// ============================================
+// SCHEDULED OCCURRENCES
+// ============================================
+
+// choreRecurrence parses the chore's recurrence rule. Rules without a DTSTART start on
+// the day the chore was created.
+func choreRecurrence(chore *models.Chore) (*utils.Recurrence, error) {
+	created := chore.CreatedAt.UTC()
+	start := time.Date(created.Year(), created.Month(), created.Day(), 0, 0, 0, 0, time.UTC)
+	return utils.ParseRecurrence(*chore.RecurrenceRule, start)
+}
+
+// nextChoreDueDate returns the due date following due for the chore's recurrence rule or
+// frequency. Monthly chores fall on anchorDay, the day of the month the schedule started
+// on, or the last day of shorter months. It returns false for irregular chores, custom
+// chores without an interval and rules that have no further occurrences.
+func nextChoreDueDate(chore *models.Chore, due time.Time, anchorDay int) (time.Time, bool) {
+	if chore.RecurrenceRule != nil {
+		rule, err := choreRecurrence(chore)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return rule.After(due, false)
+	}
+
+	switch chore.Frequency {
+	case "daily":
+		return due.AddDate(0, 0, 1), true
+	case "weekly":
+		return due.AddDate(0, 0, 7), true
+	case "monthly":
+		// Clamp to the last day of the next month instead of overflowing into the one after,
+		// and go back to the anchor day once the month is long enough again
+		year, month, day := due.Date()
+		if anchorDay > 0 {
+			day = anchorDay
+		}
+		lastDay := time.Date(year, month+2, 0, 0, 0, 0, 0, due.Location()).Day()
+		if day > lastDay {
+			day = lastDay
+		}
+		return time.Date(year, month+1, day, due.Hour(), due.Minute(), due.Second(), 0, due.Location()), true
+	case "custom":
+		if chore.CustomInterval == nil || *chore.CustomInterval < 1 {
+			return time.Time{}, false
+		}
+		return due.AddDate(0, 0, *chore.CustomInterval), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// firstChoreDueDate returns the first due date on or after today. A frequency schedule
+// continues from the latest assignment, or starts today; a recurrence rule has fixed dates.
+func firstChoreDueDate(chore *models.Chore, latest *models.ChoreAssignment, anchorDay int, today time.Time) (time.Time, bool) {
+	if chore.RecurrenceRule != nil {
+		rule, err := choreRecurrence(chore)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return rule.After(today, true)
+	}
+	if latest == nil {
+		_, ok := nextChoreDueDate(chore, today, anchorDay)
+		return today, ok
+	}
+	due, ok := nextChoreDueDate(chore, latest.DueDate, anchorDay)
+	for ok && due.Before(today) {
+		due, ok = nextChoreDueDate(chore, due, anchorDay)
+	}
+	return due, ok
+}
+
+// GenerateUpcomingAssignments creates the assignments of every active scheduled chore
+// that fall due within the configured number of days. Each chore's schedule continues
+// from its latest assignment, or starts today; occurrences already in the past are
+// skipped rather than created late. Running it again creates nothing new.
+func (s *ChoreService) GenerateUpcomingAssignments(ctx context.Context, now time.Time) (int, error) {
+	if s.scheduleDays <= 0 {
+		return 0, nil
+	}
+
+	chores, err := s.chores.ListActive(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list chores: %w", err)
+	}
+
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	horizon := today.AddDate(0, 0, s.scheduleDays)
+	created := 0
+
+	for i := range chores {
+		chore := &chores[i]
+		// Irregular chores and rules without further occurrences are not scheduled
+		if _, ok := firstChoreDueDate(chore, nil, 0, today); !ok {
+			continue
+		}
+
+		existing, err := s.choreAssignments.ListByChoreID(ctx, chore.ID)
+		if err != nil {
+			return created, fmt.Errorf("failed to list assignments: %w", err)
+		}
+		scheduled := make(map[string]bool, len(existing))
+		var first, latest *models.ChoreAssignment
+		for j := range existing {
+			scheduled[existing[j].DueDate.UTC().Format("2006-01-02")] = true
+			if first == nil || existing[j].DueDate.Before(first.DueDate) {
+				first = &existing[j]
+			}
+			if latest == nil || existing[j].DueDate.After(latest.DueDate) {
+				latest = &existing[j]
+			}
+		}
+		// Monthly schedules keep the day of the month of their first assignment
+		anchorDay := today.Day()
+		if first != nil {
+			anchorDay = first.DueDate.UTC().Day()
+		}
+
+		due, ok := firstChoreDueDate(chore, latest, anchorDay, today)
+		for ; ok && due.Before(horizon); due, ok = nextChoreDueDate(chore, due, anchorDay) {
+			if scheduled[due.UTC().Format("2006-01-02")] {
+				continue
+			}
+			if _, err := s.assignOccurrence(ctx, chore, due); err != nil {
+				log.Printf("[CHORE] Could not schedule %q for %s: %v", chore.Name, due.Format("2006-01-02"), err)
+				break
+			}
+			created++
+		}
+	}
+
+	if created > 0 {
+		log.Printf("[CHORE] Scheduled %d upcoming assignments", created)
+	}
+	return created, nil
+}
+
+// assignOccurrence assigns one scheduled occurrence according to the chore's assignment mode
+func (s *ChoreService) assignOccurrence(ctx context.Context, chore *models.Chore, due time.Time) (*models.ChoreAssignment, error) {
+	userID, err := s.pickAssignee(ctx, chore, due)
+	if err != nil {
+		return nil, err
+	}
+	return s.AssignChore(ctx, AssignChoreRequest{ChoreID: chore.ID, AssigneeUserID: userID, DueDate: due})
+}
+
+// ============================================
+// AVAILABILITY
+// ============================================
+
+// choreFairnessDays is how many days of a chore's assignments before a due date are
+// compared when picking the assignee, so users back from an absence catch up
+const choreFairnessDays = 28
+
+type CreateAbsenceRequest struct {
+	UserID    string    `json:"userId"` // Defaults to the current user
+	StartDate time.Time `json:"startDate"`
+	EndDate   time.Time `json:"endDate"` // Inclusive
+	Reason    *string   `json:"reason,omitempty"`
+}
+
+// choreDay returns the calendar day of a due date
+func choreDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// choreDeadline returns when an assignment due on a day stops being on time: the end of
+// that day, so a chore done at any time on its due day counts
+func choreDeadline(due time.Time) time.Time {
+	return choreDay(due).AddDate(0, 0, 1)
+}
+
+// pickAssignee chooses who takes the chore on the due date according to its assignment
+// mode. A manual chore whose assignee is away that day falls back to the rotation.
+func (s *ChoreService) pickAssignee(ctx context.Context, chore *models.Chore, due time.Time) (string, error) {
+	switch chore.AssignmentMode {
+	case "manual":
+		if chore.ManualAssigneeID == nil || *chore.ManualAssigneeID == "" {
+			return "", errors.New("manual chore has no assignee")
+		}
+		user, err := s.users.GetByID(ctx, *chore.ManualAssigneeID)
+		if err != nil || user == nil {
+			return "", errors.New("assignee not found")
+		}
+		if !user.IsActive {
+			return "", errors.New("assignee is inactive")
+		}
+		absent, err := s.absentUserIDs(ctx, due)
+		if err != nil {
+			return "", err
+		}
+		if !absent[user.ID] {
+			return user.ID, nil
+		}
+		log.Printf("[CHORE] %q: assignee %s is away on %s, using the rotation", chore.Name, user.ID, due.Format("2006-01-02"))
+		return s.rotationAssignee(ctx, chore, due, "")
+	case "fair":
+		explanation, err := s.explainFairAssignment(ctx, chore, due, "")
+		if err != nil {
+			return "", err
+		}
+		log.Printf("[CHORE] Fair assignment of %q on %s: %s", chore.Name, due.Format("2006-01-02"), explanation.Reason)
+		return explanation.SelectedUserID, nil
+	case "random":
+		users, err := s.users.ListActive(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to get active users: %w", err)
+		}
+		return s.randomAssignee(ctx, chore, due, users)
+	default:
+		return s.rotationAssignee(ctx, chore, due, "")
+	}
+}
+
+// rotationAssignee picks the available user with the fewest points from the chore over
+// the fairness window. Ties go to whoever comes first in the rotation order after the
+// previous assignee, so without absences this is plain round robin. skipUserID, when set,
+// is never picked.
+func (s *ChoreService) rotationAssignee(ctx context.Context, chore *models.Chore, due time.Time, skipUserID string) (string, error) {
+	users, err := s.users.ListActive(ctx)
+	if err != nil {
+		return "", fmt.Errorf("database error: %w", err)
+	}
+	eligible := make([]models.User, 0, len(users))
+	for _, u := range users {
+		if u.ID != skipUserID {
+			eligible = append(eligible, u)
+		}
+	}
+	candidates, err := s.availableAssignees(ctx, chore.ID, due, eligible)
+	if err != nil {
+		return "", err
+	}
+	if len(candidates) == 0 {
+		return "", errors.New("no available users to assign chore to")
+	}
+
+	recentPoints, previous, err := s.recentChorePoints(ctx, chore.ID, due)
+	if err != nil {
+		return "", err
+	}
+
+	turn := rotationTurns(users, previous)
+	next := candidates[0]
+	for _, u := range candidates[1:] {
+		if recentPoints[u.ID] < recentPoints[next.ID] ||
+			(recentPoints[u.ID] == recentPoints[next.ID] && turn[u.ID] < turn[next.ID]) {
+			next = u
+		}
+	}
+	return next.ID, nil
+}
+
+// rotationTurns numbers users by their position in the rotation order, counted from the
+// user after the previous assignee
+func rotationTurns(users []models.User, previous *models.ChoreAssignment) map[string]int {
+	previousIndex := -1
+	for i, u := range users {
+		if previous != nil && u.ID == previous.AssigneeUserID {
+			previousIndex = i
+			break
+		}
+	}
+	turn := make(map[string]int, len(users))
+	for i, u := range users {
+		turn[u.ID] = (i - previousIndex - 1 + len(users)) % len(users)
+	}
+	return turn
+}
+
+// randomAssignee picks a random available user among those with the fewest points from
+// the chore over the fairness window
+func (s *ChoreService) randomAssignee(ctx context.Context, chore *models.Chore, due time.Time, users []models.User) (string, error) {
+	candidates, err := s.availableAssignees(ctx, chore.ID, due, users)
+	if err != nil {
+		return "", err
+	}
+	if len(candidates) == 0 {
+		return "", errors.New("no available users to assign chore to")
+	}
+
+	recentPoints, _, err := s.recentChorePoints(ctx, chore.ID, due)
+	if err != nil {
+		return "", err
+	}
+	var pool []string
+	for _, u := range candidates {
+		if len(pool) > 0 && recentPoints[u.ID] > recentPoints[pool[0]] {
+			continue
+		}
+		if len(pool) > 0 && recentPoints[u.ID] < recentPoints[pool[0]] {
+			pool = pool[:0]
+		}
+		pool = append(pool, u.ID)
+	}
+
+	// Pick a random user using crypto/rand for secure randomness
+	n, err := rand.Int(rand.Reader, big.NewInt(int64(len(pool))))
+	if err != nil {
+		return "", fmt.Errorf("failed to generate random number: %w", err)
+	}
+	return pool[n.Int64()], nil
+}
+
+// availableAssignees filters users down to those who can take the chore on the due date:
+// active, not away that day and not excluded from the chore. When some of them prefer
+// the chore, only those are returned. The order of users is kept.
+func (s *ChoreService) availableAssignees(ctx context.Context, choreID string, due time.Time, users []models.User) ([]models.User, error) {
+	available, _, err := s.assigneeAvailability(ctx, choreID, due, users)
+	return available, err
+}
+
+// assigneeAvailability is availableAssignees that also reports why the other users were
+// left out
+func (s *ChoreService) assigneeAvailability(ctx context.Context, choreID string, due time.Time, users []models.User) ([]models.User, []SkippedAssignee, error) {
+	absent, err := s.absentUserIDs(ctx, due)
+	if err != nil {
+		return nil, nil, err
+	}
+	preferences, err := s.chorePreferences.ListByChoreID(ctx, choreID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get chore preferences: %w", err)
+	}
+	preference := make(map[string]string, len(preferences))
+	for _, p := range preferences {
+		preference[p.UserID] = p.Preference
+	}
+
+	var available, preferred []models.User
+	var skipped, notPreferred []SkippedAssignee
+	for _, u := range users {
+		switch {
+		case !u.IsActive:
+			skipped = append(skipped, SkippedAssignee{UserID: u.ID, UserName: u.Name, Reason: "inactive"})
+		case absent[u.ID]:
+			skipped = append(skipped, SkippedAssignee{UserID: u.ID, UserName: u.Name, Reason: "absent"})
+		case preference[u.ID] == "excluded":
+			skipped = append(skipped, SkippedAssignee{UserID: u.ID, UserName: u.Name, Reason: "excluded"})
+		default:
+			available = append(available, u)
+			if preference[u.ID] == "preferred" {
+				preferred = append(preferred, u)
+			} else {
+				notPreferred = append(notPreferred, SkippedAssignee{UserID: u.ID, UserName: u.Name, Reason: "not_preferred"})
+			}
+		}
+	}
+	if len(preferred) > 0 {
+		return preferred, append(skipped, notPreferred...), nil
+	}
+	return available, skipped, nil
+}
+
+// absentUserIDs returns the users who are away on the due date
+func (s *ChoreService) absentUserIDs(ctx context.Context, due time.Time) (map[string]bool, error) {
+	day := choreDay(due)
+	absences, err := s.userAbsences.ListOverlapping(ctx, day, day)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get absences: %w", err)
+	}
+	absent := make(map[string]bool, len(absences))
+	for _, a := range absences {
+		absent[a.UserID] = true
+	}
+	return absent, nil
+}
+
+// recentChorePoints sums the points of the chore's assignments per user over the fairness
+// window before the due date, and returns the last of those assignments
+func (s *ChoreService) recentChorePoints(ctx context.Context, choreID string, due time.Time) (map[string]int, *models.ChoreAssignment, error) {
+	assignments, err := s.choreAssignments.ListByChoreID(ctx, choreID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list assignments: %w", err)
+	}
+
+	day := choreDay(due)
+	since := day.AddDate(0, 0, -choreFairnessDays)
+	points := make(map[string]int)
+	var previous *models.ChoreAssignment
+	for i := range assignments {
+		a := &assignments[i]
+		if !a.DueDate.Before(day) {
+			continue
+		}
+		if previous == nil || a.DueDate.After(previous.DueDate) {
+			previous = a
+		}
+		if !a.DueDate.Before(since) {
+			points[a.AssigneeUserID] += a.Points
+		}
+	}
+	return points, previous, nil
+}
+
+// reassignOpenAssignments hands the user's pending and in-progress assignments due between
+// from and to (of one chore, or of every chore when choreID is empty) to someone available
+func (s *ChoreService) reassignOpenAssignments(ctx context.Context, userID, choreID string, from, to time.Time) int {
+	assignments, err := s.choreAssignments.ListByAssigneeID(ctx, userID)
+	if err != nil {
+		log.Printf("[CHORE] Could not list assignments of user %s: %v", userID, err)
+		return 0
+	}
+
+	reassigned := 0
+	for _, assignment := range assignments {
+		day := choreDay(assignment.DueDate)
+		if assignment.Status != "pending" && assignment.Status != "in_progress" {
+			continue
+		}
+		if day.Before(choreDay(from)) || day.After(choreDay(to)) || (choreID != "" && assignment.ChoreID != choreID) {
+			continue
+		}
+		chore, err := s.GetChore(ctx, assignment.ChoreID)
+		if err != nil {
+			continue
+		}
+		newUserID, err := s.pickAssignee(ctx, chore, assignment.DueDate)
+		if err != nil || newUserID == userID {
+			log.Printf("[CHORE] Could not reassign assignment %s of user %s: no one else is available", assignment.ID, userID)
+			continue
+		}
+		if _, err := s.ReassignChoreAssignment(ctx, assignment.ID, ReassignChoreRequest{NewAssigneeUserID: newUserID}); err != nil {
+			log.Printf("[CHORE] Could not reassign assignment %s of user %s: %v", assignment.ID, userID, err)
+			continue
+		}
+		reassigned++
+	}
+	return reassigned
+}
+
+// CreateAbsence records a period in which the user gets no chores. Their open assignments
+// due in that period are handed to someone else.
+func (s *ChoreService) CreateAbsence(ctx context.Context, req CreateAbsenceRequest) (*models.UserAbsence, error) {
+	user, err := s.users.GetByID(ctx, req.UserID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	if req.StartDate.IsZero() || req.EndDate.IsZero() {
+		return nil, errors.New("start and end date are required")
+	}
+	start, end := choreDay(req.StartDate), choreDay(req.EndDate)
+	if end.Before(start) {
+		return nil, errors.New("end date must not be before start date")
+	}
+	if req.Reason != nil && len(*req.Reason) > 200 {
+		return nil, errors.New("reason must be at most 200 characters")
+	}
+
+	absence := models.UserAbsence{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		StartDate: start,
+		EndDate:   end,
+		Reason:    req.Reason,
+		CreatedAt: time.Now(),
+	}
+	if err := s.userAbsences.Create(ctx, &absence); err != nil {
+		return nil, fmt.Errorf("failed to create absence: %w", err)
+	}
+
+	reassigned := s.reassignOpenAssignments(ctx, user.ID, "", start, end)
+	log.Printf("[CHORE] Absence recorded: user %s from %s to %s (%d assignments reassigned)",
+		user.ID, start.Format("2006-01-02"), end.Format("2006-01-02"), reassigned)
+
+	return &absence, nil
+}
+
+// GetAbsences lists absences, optionally of one user
+func (s *ChoreService) GetAbsences(ctx context.Context, userID *string) ([]models.UserAbsence, error) {
+	if userID != nil {
+		return s.userAbsences.ListByUserID(ctx, *userID)
+	}
+	return s.userAbsences.List(ctx)
+}
+
+// GetAbsence retrieves an absence by ID
+func (s *ChoreService) GetAbsence(ctx context.Context, absenceID string) (*models.UserAbsence, error) {
+	absence, err := s.userAbsences.GetByID(ctx, absenceID)
+	if err != nil || absence == nil {
+		return nil, errors.New("absence not found")
+	}
+	return absence, nil
+}
+
+// DeleteAbsence deletes an absence. Assignments already handed to others stay with them.
+func (s *ChoreService) DeleteAbsence(ctx context.Context, absenceID string) error {
+	if err := s.userAbsences.Delete(ctx, absenceID); err != nil {
+		return fmt.Errorf("failed to delete absence: %w", err)
+	}
+	log.Printf("[CHORE] Absence deleted: %s", absenceID)
+	return nil
+}
+
+// GetChorePreferences lists the preferences recorded for a chore
+func (s *ChoreService) GetChorePreferences(ctx context.Context, choreID string) ([]models.ChorePreference, error) {
+	if _, err := s.GetChore(ctx, choreID); err != nil {
+		return nil, err
+	}
+	return s.chorePreferences.ListByChoreID(ctx, choreID)
+}
+
+// SetChorePreference records that the user prefers ("preferred") or must not get
+// ("excluded") the chore. Excluding a user hands their open assignments of the chore to
+// someone else.
+func (s *ChoreService) SetChorePreference(ctx context.Context, choreID, userID, preference string) (*models.ChorePreference, error) {
+	if preference != "preferred" && preference != "excluded" {
+		return nil, errors.New("preference must be preferred or excluded")
+	}
+	if _, err := s.GetChore(ctx, choreID); err != nil {
+		return nil, err
+	}
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	chorePreference := models.ChorePreference{
+		ID:         uuid.New().String(),
+		ChoreID:    choreID,
+		UserID:     userID,
+		Preference: preference,
+		UpdatedAt:  time.Now(),
+	}
+	if err := s.chorePreferences.Set(ctx, &chorePreference); err != nil {
+		return nil, fmt.Errorf("failed to set chore preference: %w", err)
+	}
+
+	log.Printf("[CHORE] Preference set: user %s %s for chore %s", userID, preference, choreID)
+	if preference == "excluded" {
+		s.reassignOpenAssignments(ctx, userID, choreID, time.Now(), time.Now().AddDate(100, 0, 0))
+	}
+
+	return &chorePreference, nil
+}
+
+// RemoveChorePreference removes the user's preference for a chore
+func (s *ChoreService) RemoveChorePreference(ctx context.Context, choreID, userID string) error {
+	if err := s.chorePreferences.Delete(ctx, choreID, userID); err != nil {
+		return fmt.Errorf("failed to remove chore preference: %w", err)
+	}
+	log.Printf("[CHORE] Preference removed: user %s for chore %s", userID, choreID)
+	return nil
+}
+
+// ============================================
+// FAIR ASSIGNMENT
+// ============================================
+
+// FairCandidate is an available user's standing in a fair assignment
+type FairCandidate struct {
+	UserID      string  `json:"userId"`
+	UserName    string  `json:"userName"`
+	Load        float64 `json:"load"`        // Difficulty of the chores the user completed in the window, decayed by age at the due date
+	Assignments int     `json:"assignments"` // Number of completed assignments counted in the load
+}
+
+// SkippedAssignee is a user left out of an assignment
+type SkippedAssignee struct {
+	UserID   string `json:"userId"`
+	UserName string `json:"userName"`
+	Reason   string `json:"reason"` // inactive, absent, excluded, not_preferred
+}
+
+// FairAssignmentExplanation shows how the fair assignment mode picks the assignee
+type FairAssignmentExplanation struct {
+	ChoreID        string            `json:"choreId"`
+	DueDate        time.Time         `json:"dueDate"`
+	WindowDays     int               `json:"windowDays"`   // Days before the due date whose completions count, 0 counts every one
+	HalfLifeDays   int               `json:"halfLifeDays"` // 0 means no decay
+	SelectedUserID string            `json:"selectedUserId"`
+	Reason         string            `json:"reason"`
+	Candidates     []FairCandidate   `json:"candidates"` // Lowest load first, the selected user leads
+	Skipped        []SkippedAssignee `json:"skipped"`
+}
+
+// explainFairAssignment ranks the available users by the difficulty of the chores they
+// completed before the due date and picks the lowest. Ties go to whoever is next in the rotation. The
+// assignment with ID excludeID, if any, is left out of the loads.
+func (s *ChoreService) explainFairAssignment(ctx context.Context, chore *models.Chore, due time.Time, excludeID string) (*FairAssignmentExplanation, error) {
+	users, err := s.users.ListActive(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	available, skipped, err := s.assigneeAvailability(ctx, chore.ID, due, users)
+	if err != nil {
+		return nil, err
+	}
+	if len(available) == 0 {
+		return nil, errors.New("no available users to assign chore to")
+	}
+
+	loads, counts, err := s.difficultyLoads(ctx, due, excludeID)
+	if err != nil {
+		return nil, err
+	}
+	_, previous, err := s.recentChorePoints(ctx, chore.ID, due)
+	if err != nil {
+		return nil, err
+	}
+	turn := rotationTurns(users, previous)
+
+	candidates := make([]FairCandidate, len(available))
+	for i, u := range available {
+		candidates[i] = FairCandidate{
+			UserID:      u.ID,
+			UserName:    u.Name,
+			Load:        math.Round(loads[u.ID]*100) / 100,
+			Assignments: counts[u.ID],
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Load != candidates[j].Load {
+			return candidates[i].Load < candidates[j].Load
+		}
+		return turn[candidates[i].UserID] < turn[candidates[j].UserID]
+	})
+
+	selected := candidates[0]
+	var reason string
+	switch {
+	case len(candidates) == 1:
+		reason = fmt.Sprintf("%s is the only available user", selected.UserName)
+	case candidates[1].Load == selected.Load:
+		reason = fmt.Sprintf("%s shares the lowest load (%.2f) and is next in the rotation", selected.UserName, selected.Load)
+	default:
+		reason = fmt.Sprintf("%s has the lowest load (%.2f, next lowest %.2f for %s)",
+			selected.UserName, selected.Load, candidates[1].Load, candidates[1].UserName)
+	}
+
+	return &FairAssignmentExplanation{
+		ChoreID:        chore.ID,
+		DueDate:        due,
+		WindowDays:     s.fairWindowDays,
+		HalfLifeDays:   s.fairHalfLifeDays,
+		SelectedUserID: selected.UserID,
+		Reason:         reason,
+		Candidates:     candidates,
+		Skipped:        skipped,
+	}, nil
+}
+
+// difficultyLoads sums the chore difficulty of the assignments every user completed within
+// the fairness window before the due date, and counts them. Pending and overdue assignments
+// don't count, so letting chores slide doesn't earn fewer new ones. Each completion counts
+// half as much for every half-life it lies before the due date.
+func (s *ChoreService) difficultyLoads(ctx context.Context, due time.Time, excludeID string) (map[string]float64, map[string]int, error) {
+	chores, err := s.chores.List(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list chores: %w", err)
+	}
+	difficulty := make(map[string]int, len(chores))
+	for _, chore := range chores {
+		difficulty[chore.ID] = chore.Difficulty
+	}
+
+	assignments, err := s.choreAssignments.List(ctx)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to list assignments: %w", err)
+	}
+
+	day := choreDay(due)
+	loads := make(map[string]float64)
+	counts := make(map[string]int)
+	for _, a := range assignments {
+		if a.ID == excludeID {
+			continue
+		}
+		if a.Status != "done" || a.CompletedAt == nil {
+			continue
+		}
+		distance := day.Sub(choreDay(*a.CompletedAt)).Hours() / 24
+		if distance < 0 || (s.fairWindowDays > 0 && distance > float64(s.fairWindowDays)) {
+			continue
+		}
+
+		weight := float64(difficulty[a.ChoreID])
+		if s.fairHalfLifeDays > 0 {
+			weight *= math.Pow(0.5, distance/float64(s.fairHalfLifeDays))
+		}
+		loads[a.AssigneeUserID] += weight
+		counts[a.AssigneeUserID]++
+	}
+	return loads, counts, nil
+}
+
+// ExplainFairAssignment previews whom the fair assignment mode would pick for the chore
+// on the due date, and why
+func (s *ChoreService) ExplainFairAssignment(ctx context.Context, choreID string, dueDate time.Time) (*FairAssignmentExplanation, error) {
+	chore, err := s.GetChore(ctx, choreID)
+	if err != nil {
+		return nil, err
+	}
+	return s.explainFairAssignment(ctx, chore, dueDate, "")
+}
+
+// ExplainChoreAssignment explains why the assignee of a fair chore was picked, from the
+// current loads with the assignment itself left out
+func (s *ChoreService) ExplainChoreAssignment(ctx context.Context, assignmentID string) (*FairAssignmentExplanation, error) {
+	assignment, err := s.GetChoreAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	chore, err := s.GetChore(ctx, assignment.ChoreID)
+	if err != nil {
+		return nil, err
+	}
+	if chore.AssignmentMode != "fair" {
+		return nil, errors.New("chore does not use fair assignment")
+	}
+	return s.explainFairAssignment(ctx, chore, assignment.DueDate, assignment.ID)
+}
+
+// FairAssignChore assigns the chore to the user with the lowest difficulty load (ADMIN only)
+func (s *ChoreService) FairAssignChore(ctx context.Context, choreID string, dueDate time.Time) (*models.ChoreAssignment, *FairAssignmentExplanation, error) {
+	explanation, err := s.ExplainFairAssignment(ctx, choreID, dueDate)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	assignment, err := s.AssignChore(ctx, AssignChoreRequest{
+		ChoreID:        choreID,
+		AssigneeUserID: explanation.SelectedUserID,
+		DueDate:        dueDate,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return assignment, explanation, nil
+}
+
+// ============================================
+// OVERDUE ASSIGNMENTS
+// ============================================
+
+const (
+	defaultOverdueEscalationHours = 24
+	// overdueReassignDays is how long the new assignee has for a reassigned overdue assignment
+	overdueReassignDays = 1
+)
+
+var validOverduePolicies = map[string]bool{"keep": true, "reassign": true}
+
+var validOverduePenalties = map[string]bool{"none": true, "withhold": true, "deduct": true}
+
+type UpdateChoreSettingsRequest struct {
+	OverduePenalty         *string `json:"overduePenalty,omitempty"`         // none, withhold, deduct
+	OverdueEscalationHours *int    `json:"overdueEscalationHours,omitempty"` // hours after the end of the due day
+}
+
+// getOrCreateChoreSettings loads the settings singleton, creating the defaults on first use
+func getOrCreateChoreSettings(ctx context.Context, choreSettings repository.ChoreSettingsRepository) (*models.ChoreSettings, error) {
+	settings, err := choreSettings.Get(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if settings != nil {
+		return settings, nil
+	}
+
+	settings = &models.ChoreSettings{
+		ID:                     "singleton",
+		DefaultAssignmentMode:  "round_robin",
+		GlobalNotifications:    true,
+		DefaultReminderHours:   24,
+		PointsEnabled:          true,
+		PointsMultiplier:       1,
+		OverduePenalty:         "none",
+		OverdueEscalationHours: defaultOverdueEscalationHours,
+		UpdatedAt:              time.Now(),
+	}
+	if err := choreSettings.Upsert(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to create default chore settings: %w", err)
+	}
+	return settings, nil
+}
+
+// GetChoreSettings retrieves the household-wide chore settings
+func (s *ChoreService) GetChoreSettings(ctx context.Context) (*models.ChoreSettings, error) {
+	return getOrCreateChoreSettings(ctx, s.choreSettings)
+}
+
+// UpdateChoreSettings changes how overdue assignments are handled (ADMIN only)
+func (s *ChoreService) UpdateChoreSettings(ctx context.Context, req UpdateChoreSettingsRequest) (*models.ChoreSettings, error) {
+	settings, err := s.GetChoreSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.OverduePenalty != nil {
+		if !validOverduePenalties[*req.OverduePenalty] {
+			return nil, errors.New("invalid overdue penalty")
+		}
+		settings.OverduePenalty = *req.OverduePenalty
+	}
+	if req.OverdueEscalationHours != nil {
+		if *req.OverdueEscalationHours < 0 {
+			return nil, errors.New("escalation delay cannot be negative")
+		}
+		settings.OverdueEscalationHours = *req.OverdueEscalationHours
+	}
+	settings.UpdatedAt = time.Now()
+
+	if err := s.choreSettings.Upsert(ctx, settings); err != nil {
+		return nil, fmt.Errorf("failed to update chore settings: %w", err)
+	}
+
+	log.Printf("[CHORE] Settings updated: overdue penalty %s, escalation after %dh", settings.OverduePenalty, settings.OverdueEscalationHours)
+
+	return settings, nil
+}
+
+// overduePoints applies the overdue penalty to an assignment's points. Applying it again
+// changes nothing.
+func overduePoints(points int, penalty string) int {
+	switch penalty {
+	case "withhold":
+		return 0
+	case "deduct":
+		if points > 0 {
+			return -points
+		}
+	}
+	return points
+}
+
+// ProcessOverdueAssignments marks pending assignments past their due day as overdue and
+// applies the overdue penalty to their points. Assignments overdue for longer than the
+// escalation delay are reported to whoever assigned them, or to the admins, and handed to
+// someone else when the chore's overdue policy is "reassign". Returns the number of
+// assignments marked overdue.
+func (s *ChoreService) ProcessOverdueAssignments(ctx context.Context, now time.Time) (int, error) {
+	settings, err := s.GetChoreSettings(ctx)
+	if err != nil {
+		return 0, err
+	}
+
+	pending, err := s.choreAssignments.ListByStatus(ctx, "pending")
+	if err != nil {
+		return 0, fmt.Errorf("failed to list pending assignments: %w", err)
+	}
+
+	marked := 0
+	for i := range pending {
+		assignment := &pending[i]
+		if now.Before(choreDeadline(assignment.DueDate)) {
+			continue
+		}
+		chore, err := s.chores.GetByID(ctx, assignment.ChoreID)
+		if err != nil || chore == nil {
+			continue
+		}
+
+		assignment.Status = "overdue"
+		assignment.Points = overduePoints(assignment.Points, settings.OverduePenalty)
+		if err := s.choreAssignments.Update(ctx, assignment); err != nil {
+			log.Printf("[CHORE] Could not mark assignment %s overdue: %v", assignment.ID, err)
+			continue
+		}
+		marked++
+
+		log.Printf("[CHORE] Overdue: assignment %s of chore %q (user %s, due: %s, points: %d)", assignment.ID, chore.Name, assignment.AssigneeUserID, assignment.DueDate.Format("2006-01-02"), assignment.Points)
+
+		if chore.NotificationsEnabled {
+			s.notifyChoreUser(ctx, assignment.AssigneeUserID, "Zaległe zadanie",
+				fmt.Sprintf("Minął termin zadania: %s (termin: %s)", chore.Name, assignment.DueDate.Format("2006-01-02")))
+		}
+	}
+
+	overdue, err := s.choreAssignments.ListByStatus(ctx, "overdue")
+	if err != nil {
+		return marked, fmt.Errorf("failed to list overdue assignments: %w", err)
+	}
+
+	delay := time.Duration(settings.OverdueEscalationHours) * time.Hour
+	escalated := 0
+	for i := range overdue {
+		assignment := &overdue[i]
+		if now.Before(choreDeadline(assignment.DueDate).Add(delay)) {
+			continue
+		}
+
+		// Escalate once per assignee, so a reassigned assignment can escalate again
+		exists, err := s.sentReminders.Exists(ctx, assignment.AssigneeUserID, "chore_assignment", assignment.ID, "overdue_escalation")
+		if err != nil || exists {
+			continue
+		}
+		chore, err := s.chores.GetByID(ctx, assignment.ChoreID)
+		if err != nil || chore == nil {
+			continue
+		}
+
+		lateUserID := assignment.AssigneeUserID
+		s.escalateOverdueAssignment(ctx, chore, assignment, now)
+
+		reminder := &models.SentReminder{
+			UserID:       lateUserID,
+			ResourceType: "chore_assignment",
+			ResourceID:   assignment.ID,
+			ReminderType: "overdue_escalation",
+		}
+		if err := s.sentReminders.Create(ctx, reminder); err != nil {
+			log.Printf("[CHORE] Failed to record escalation of assignment %s: %v", assignment.ID, err)
+		}
+		escalated++
+	}
+
+	if marked > 0 || escalated > 0 {
+		log.Printf("[CHORE] Marked %d assignments overdue, escalated %d", marked, escalated)
+	}
+	return marked, nil
+}
+
+// escalateOverdueAssignment reassigns the assignment under the "reassign" policy and tells
+// the assigner or the admins about it
+func (s *ChoreService) escalateOverdueAssignment(ctx context.Context, chore *models.Chore, assignment *models.ChoreAssignment, now time.Time) {
+	lateUserName := assignment.AssigneeUserID
+	if user, err := s.users.GetByID(ctx, assignment.AssigneeUserID); err == nil && user != nil {
+		lateUserName = user.Name
+	}
+	recipients := s.escalationRecipients(ctx, assignment)
+
+	body := fmt.Sprintf("Zadanie %s (%s) jest zaległe od %s", chore.Name, lateUserName, assignment.DueDate.Format("2006-01-02"))
+	if chore.OverduePolicy == "reassign" {
+		newUser, err := s.reassignOverdueAssignment(ctx, chore, assignment, now)
+		if err != nil {
+			log.Printf("[CHORE] Could not reassign overdue assignment %s: %v", assignment.ID, err)
+		} else {
+			body += fmt.Sprintf(". Przekazano je: %s", newUser.Name)
+		}
+	}
+
+	for _, userID := range recipients {
+		s.notifyChoreUser(ctx, userID, "Zaległe zadanie", body)
+	}
+}
+
+// escalationRecipients returns who hears about an overdue assignment: the user who assigned
+// it, or every active admin when it was scheduled or self-assigned
+func (s *ChoreService) escalationRecipients(ctx context.Context, assignment *models.ChoreAssignment) []string {
+	if assignment.AssignedByUserID != nil && *assignment.AssignedByUserID != assignment.AssigneeUserID {
+		assigner, err := s.users.GetByID(ctx, *assignment.AssignedByUserID)
+		if err == nil && assigner != nil && assigner.IsActive {
+			return []string{assigner.ID}
+		}
+	}
+
+	users, err := s.users.ListActive(ctx)
+	if err != nil {
+		log.Printf("[CHORE] Could not list admins: %v", err)
+		return nil
+	}
+	var admins []string
+	for _, u := range users {
+		if u.Role == "ADMIN" {
+			admins = append(admins, u.ID)
+		}
+	}
+	return admins
+}
+
+// reassignOverdueAssignment hands an overdue assignment to someone other than its assignee,
+// with a new due date and full points
+func (s *ChoreService) reassignOverdueAssignment(ctx context.Context, chore *models.Chore, assignment *models.ChoreAssignment, now time.Time) (*models.User, error) {
+	due := now.AddDate(0, 0, overdueReassignDays)
+	newUserID, err := s.pickAssignee(ctx, chore, due)
+	if err != nil || newUserID == assignment.AssigneeUserID {
+		newUserID, err = s.rotationAssignee(ctx, chore, due, assignment.AssigneeUserID)
+	}
+	if err != nil {
+		return nil, err
+	}
+	newUser, err := s.users.GetByID(ctx, newUserID)
+	if err != nil || newUser == nil {
+		return nil, errors.New("new assignee not found")
+	}
+
+	previousUserID := assignment.AssigneeUserID
+	assignment.AssigneeUserID = newUserID
+	assignment.DueDate = due
+	assignment.Status = "pending"
+	assignment.Points = chore.Difficulty * 10
+
+	if err := s.choreAssignments.Update(ctx, assignment); err != nil {
+		return nil, fmt.Errorf("failed to reassign chore: %w", err)
+	}
+
+	log.Printf("[CHORE] Reassigned overdue: assignment %s from user %s to user %s, due: %s", assignment.ID, previousUserID, newUserID, due.Format("2006-01-02"))
+
+	s.notifyChoreUser(ctx, newUserID, "Przypisano zadanie",
+		fmt.Sprintf("Przypisano Ci zaległe zadanie: %s (termin: %s)", chore.Name, due.Format("2006-01-02")))
+	s.notifyChoreUser(ctx, previousUserID, "Zadanie przekazane",
+		fmt.Sprintf("Zaległe zadanie %s przekazano innej osobie", chore.Name))
+
+	return newUser, nil
+}
+
+// notifyChoreUser sends an in-app chore notification to the user
+func (s *ChoreService) notifyChoreUser(ctx context.Context, userID, title, body string) {
+	if s.notificationService == nil {
+		return
+	}
+	now := time.Now()
+	s.notificationService.CreateNotification(ctx, &models.Notification{
+		ID:           uuid.New().String(),
+		UserID:       &userID,
+		Channel:      "app",
+		TemplateID:   "chore",
+		ScheduledFor: now,
+		SentAt:       &now,
+		Status:       "sent",
+		Title:        title,
+		Body:         body,
+	})
+}
+
+// ============================================
+// COMPLETION VERIFICATION
+// ============================================
+
+// completionPhotoTypes maps the accepted completion photo types to their file extensions
+var completionPhotoTypes = map[string]string{
+	"image/jpeg": ".jpg",
+	"image/png":  ".png",
+	"image/gif":  ".gif",
+	"image/webp": ".webp",
+}
+
+type ReviewCompletionRequest struct {
+	Comment *string `json:"comment,omitempty"`
+}
+
+// completeAssignment marks the assignment done at completedAt. It earns the chore's base
+// points, with a 50% bonus for completion by the end of the due day. A late completion
+// lifts any overdue penalty but earns no bonus.
+func completeAssignment(assignment *models.ChoreAssignment, chore *models.Chore, completedAt time.Time) {
+	assignment.Status = "done"
+	assignment.CompletedAt = &completedAt
+	assignment.IsOnTime = completedAt.Before(choreDeadline(assignment.DueDate))
+	assignment.Points = chore.Difficulty * 10
+	if assignment.IsOnTime {
+		assignment.Points = int(float64(assignment.Points) * 1.5)
+	}
+}
+
+// CompletionPhotoMaxBytes returns the size of the largest accepted completion photo
+func (s *ChoreService) CompletionPhotoMaxBytes() int {
+	return s.photoMaxBytes
+}
+
+// SubmitCompletion reports an assignment as done, optionally with a photo of the result.
+// Chores that require a photo reject submissions without one. Chores that require
+// confirmation wait for another resident before the assignment is done and earns points.
+func (s *ChoreService) SubmitCompletion(ctx context.Context, assignmentID, userID string, photo []byte) (*models.ChoreAssignment, error) {
+	assignment, err := s.GetChoreAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, err
+	}
+	chore, err := s.GetChore(ctx, assignment.ChoreID)
+	if err != nil {
+		return nil, err
+	}
+	return s.submitCompletion(ctx, chore, assignment, userID, photo)
+}
+
+func (s *ChoreService) submitCompletion(ctx context.Context, chore *models.Chore, assignment *models.ChoreAssignment, userID string, photo []byte) (*models.ChoreAssignment, error) {
+	switch assignment.Status {
+	case "done":
+		return nil, errors.New("chore assignment is already done")
+	case "awaiting_confirmation":
+		return nil, errors.New("chore completion is already waiting for confirmation")
+	}
+	if chore.RequirePhoto && len(photo) == 0 {
+		return nil, errors.New("this chore requires a photo of the result")
+	}
+
+	now := time.Now()
+	if len(photo) == 0 && !chore.RequireConfirmation {
+		completeAssignment(assignment, chore, now)
+		if err := s.choreAssignments.Update(ctx, assignment); err != nil {
+			return nil, fmt.Errorf("failed to update chore assignment: %w", err)
+		}
+		return assignment, nil
+	}
+
+	completion := &models.ChoreCompletion{
+		ID:                uuid.New().String(),
+		AssignmentID:      assignment.ID,
+		SubmittedByUserID: userID,
+		Status:            "confirmed",
+		SubmittedAt:       now,
+	}
+	if len(photo) > 0 {
+		photoPath, contentType, err := s.saveCompletionPhoto(assignment.ID, completion.ID, photo)
+		if err != nil {
+			return nil, err
+		}
+		completion.PhotoPath = &photoPath
+		completion.PhotoContentType = &contentType
+	}
+	if chore.RequireConfirmation {
+		completion.Status = "awaiting_confirmation"
+		assignment.Status = "awaiting_confirmation"
+	} else {
+		completeAssignment(assignment, chore, now)
+	}
+
+	if err := s.choreCompletions.Create(ctx, completion); err != nil {
+		if completion.PhotoPath != nil {
+			os.Remove(filepath.Join(s.photoDir, *completion.PhotoPath))
+		}
+		return nil, fmt.Errorf("failed to record chore completion: %w", err)
+	}
+	if err := s.choreAssignments.Update(ctx, assignment); err != nil {
+		return nil, fmt.Errorf("failed to update chore assignment: %w", err)
+	}
+
+	log.Printf("[CHORE] Completion submitted: assignment %s by user %s (status: %s, photo: %v)", assignment.ID, userID, assignment.Status, completion.PhotoPath != nil)
+
+	if assignment.Status == "awaiting_confirmation" {
+		users, err := s.users.ListActive(ctx)
+		if err == nil {
+			for _, u := range users {
+				if u.ID != assignment.AssigneeUserID && u.ID != userID {
+					s.notifyChoreUser(ctx, u.ID, "Potwierdź wykonanie zadania",
+						fmt.Sprintf("Zadanie %s czeka na potwierdzenie wykonania", chore.Name))
+				}
+			}
+		}
+	}
+
+	return assignment, nil
+}
+
+// saveCompletionPhoto checks the size and type of a photo and writes it to the photo
+// directory. Returns the path relative to the directory and the content type.
+func (s *ChoreService) saveCompletionPhoto(assignmentID, completionID string, photo []byte) (string, string, error) {
+	if len(photo) > s.photoMaxBytes {
+		return "", "", fmt.Errorf("photo is larger than %d KB", s.photoMaxBytes/1024)
+	}
+	contentType := http.DetectContentType(photo)
+	ext, ok := completionPhotoTypes[contentType]
+	if !ok {
+		return "", "", errors.New("photo must be a JPEG, PNG, GIF or WebP image")
+	}
+
+	photoPath := filepath.Join(assignmentID, completionID+ext)
+	fullPath := filepath.Join(s.photoDir, photoPath)
+	if err := os.MkdirAll(filepath.Dir(fullPath), 0700); err != nil {
+		return "", "", fmt.Errorf("failed to create photo directory: %w", err)
+	}
+	if err := os.WriteFile(fullPath, photo, 0600); err != nil {
+		return "", "", fmt.Errorf("failed to save photo: %w", err)
+	}
+	return photoPath, contentType, nil
+}
+
+// ConfirmCompletion accepts the completion waiting for confirmation. The assignment is done
+// as of when the completion was submitted, so a late review costs no points.
+func (s *ChoreService) ConfirmCompletion(ctx context.Context, assignmentID, reviewerID string, req ReviewCompletionRequest) (*models.ChoreAssignment, error) {
+	assignment, completion, err := s.completionForReview(ctx, assignmentID, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+	chore, err := s.GetChore(ctx, assignment.ChoreID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	completion.Status = "confirmed"
+	completion.ReviewerUserID = &reviewerID
+	completion.ReviewComment = req.Comment
+	completion.ReviewedAt = &now
+	if err := s.choreCompletions.Update(ctx, completion); err != nil {
+		return nil, fmt.Errorf("failed to update chore completion: %w", err)
+	}
+
+	completeAssignment(assignment, chore, completion.SubmittedAt)
+	if err := s.choreAssignments.Update(ctx, assignment); err != nil {
+		return nil, fmt.Errorf("failed to update chore assignment: %w", err)
+	}
+
+	log.Printf("[CHORE] Completion confirmed: assignment %s by user %s, points=%d, on_time=%v", assignment.ID, reviewerID, assignment.Points, assignment.IsOnTime)
+
+	if chore, err := s.GetChore(ctx, assignment.ChoreID); err == nil {
+		s.notifyChoreUser(ctx, assignment.AssigneeUserID, "Potwierdzono wykonanie",
+			fmt.Sprintf("Potwierdzono wykonanie zadania: %s", chore.Name))
+	}
+
+	return assignment, nil
+}
+
+// DisputeCompletion rejects the completion waiting for confirmation and sends the
+// assignment back to in_progress
+func (s *ChoreService) DisputeCompletion(ctx context.Context, assignmentID, reviewerID string, req ReviewCompletionRequest) (*models.ChoreAssignment, error) {
+	assignment, completion, err := s.completionForReview(ctx, assignmentID, reviewerID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	completion.Status = "disputed"
+	completion.ReviewerUserID = &reviewerID
+	completion.ReviewComment = req.Comment
+	completion.ReviewedAt = &now
+	if err := s.choreCompletions.Update(ctx, completion); err != nil {
+		return nil, fmt.Errorf("failed to update chore completion: %w", err)
+	}
+
+	assignment.Status = "in_progress"
+	if err := s.choreAssignments.Update(ctx, assignment); err != nil {
+		return nil, fmt.Errorf("failed to update chore assignment: %w", err)
+	}
+
+	log.Printf("[CHORE] Completion disputed: assignment %s by user %s", assignment.ID, reviewerID)
+
+	if chore, err := s.GetChore(ctx, assignment.ChoreID); err == nil {
+		body := fmt.Sprintf("Zakwestionowano wykonanie zadania: %s", chore.Name)
+		if req.Comment != nil && *req.Comment != "" {
+			body += fmt.Sprintf(" (%s)", *req.Comment)
+		}
+		s.notifyChoreUser(ctx, assignment.AssigneeUserID, "Zakwestionowano wykonanie", body)
+	}
+
+	return assignment, nil
+}
+
+// completionForReview returns the assignment and its completion waiting for confirmation.
+// Neither the assignee nor whoever submitted the completion may review it.
+func (s *ChoreService) completionForReview(ctx context.Context, assignmentID, reviewerID string) (*models.ChoreAssignment, *models.ChoreCompletion, error) {
+	assignment, err := s.GetChoreAssignment(ctx, assignmentID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if assignment.Status != "awaiting_confirmation" {
+		return nil, nil, errors.New("chore assignment is not waiting for confirmation")
+	}
+	completion, err := s.choreCompletions.GetLatestByAssignmentID(ctx, assignmentID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("database error: %w", err)
+	}
+	if completion == nil || completion.Status != "awaiting_confirmation" {
+		return nil, nil, errors.New("chore assignment is not waiting for confirmation")
+	}
+	if reviewerID == assignment.AssigneeUserID || reviewerID == completion.SubmittedByUserID {
+		return nil, nil, errors.New("completion must be reviewed by another resident")
+	}
+	return assignment, completion, nil
+}
+
+// GetCompletions retrieves the completions submitted for an assignment, oldest first
+func (s *ChoreService) GetCompletions(ctx context.Context, assignmentID string) ([]models.ChoreCompletion, error) {
+	if _, err := s.GetChoreAssignment(ctx, assignmentID); err != nil {
+		return nil, err
+	}
+	completions, err := s.choreCompletions.ListByAssignmentID(ctx, assignmentID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return completions, nil
+}
+
+// GetCompletionPhoto reads the photo of a completion of the assignment and returns it with
+// its content type
+func (s *ChoreService) GetCompletionPhoto(ctx context.Context, assignmentID, completionID string) ([]byte, string, error) {
+	completions, err := s.GetCompletions(ctx, assignmentID)
+	if err != nil {
+		return nil, "", err
+	}
+	for _, completion := range completions {
+		if completion.ID != completionID {
+			continue
+		}
+		if completion.PhotoPath == nil || completion.PhotoContentType == nil {
+			return nil, "", errors.New("completion has no photo")
+		}
+		photo, err := os.ReadFile(filepath.Join(s.photoDir, *completion.PhotoPath))
+		if err != nil {
+			return nil, "", errors.New("photo not found")
+		}
+		return photo, *completion.PhotoContentType, nil
+	}
+	return nil, "", errors.New("completion not found")
+}
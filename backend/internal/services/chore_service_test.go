@@ -0,0 +1,788 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/config"
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryChores struct {
+	repository.ChoreRepository
+	chores []models.Chore
+}
+
+func (m *memoryChores) GetByID(ctx context.Context, id string) (*models.Chore, error) {
+	for _, chore := range m.chores {
+		if chore.ID == id {
+			return &chore, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryChores) List(ctx context.Context) ([]models.Chore, error) {
+	return m.chores, nil
+}
+
+func (m *memoryChores) ListActive(ctx context.Context) ([]models.Chore, error) {
+	var chores []models.Chore
+	for _, chore := range m.chores {
+		if chore.IsActive {
+			chores = append(chores, chore)
+		}
+	}
+	return chores, nil
+}
+
+type memoryChoreAssignments struct {
+	repository.ChoreAssignmentRepository
+	assignments []models.ChoreAssignment
+}
+
+func (m *memoryChoreAssignments) Create(ctx context.Context, assignment *models.ChoreAssignment) error {
+	m.assignments = append(m.assignments, *assignment)
+	return nil
+}
+
+func (m *memoryChoreAssignments) List(ctx context.Context) ([]models.ChoreAssignment, error) {
+	return m.assignments, nil
+}
+
+func (m *memoryChoreAssignments) ListByChoreID(ctx context.Context, choreID string) ([]models.ChoreAssignment, error) {
+	var assignments []models.ChoreAssignment
+	for _, assignment := range m.assignments {
+		if assignment.ChoreID == choreID {
+			assignments = append(assignments, assignment)
+		}
+	}
+	return assignments, nil
+}
+
+func (m *memoryChoreAssignments) GetLatestByChoreID(ctx context.Context, choreID string) (*models.ChoreAssignment, error) {
+	var latest *models.ChoreAssignment
+	for i, assignment := range m.assignments {
+		if assignment.ChoreID == choreID && (latest == nil || assignment.DueDate.After(latest.DueDate)) {
+			latest = &m.assignments[i]
+		}
+	}
+	return latest, nil
+}
+
+func (m *memoryChoreAssignments) GetByID(ctx context.Context, id string) (*models.ChoreAssignment, error) {
+	for _, assignment := range m.assignments {
+		if assignment.ID == id {
+			return &assignment, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryChoreAssignments) Update(ctx context.Context, assignment *models.ChoreAssignment) error {
+	for i := range m.assignments {
+		if m.assignments[i].ID == assignment.ID {
+			m.assignments[i] = *assignment
+		}
+	}
+	return nil
+}
+
+func (m *memoryChoreAssignments) ListByStatus(ctx context.Context, status string) ([]models.ChoreAssignment, error) {
+	var assignments []models.ChoreAssignment
+	for _, assignment := range m.assignments {
+		if assignment.Status == status {
+			assignments = append(assignments, assignment)
+		}
+	}
+	return assignments, nil
+}
+
+type memoryChoreSettings struct {
+	repository.ChoreSettingsRepository
+	settings *models.ChoreSettings
+}
+
+func (m *memoryChoreSettings) Get(ctx context.Context) (*models.ChoreSettings, error) {
+	return m.settings, nil
+}
+
+func (m *memoryChoreSettings) Upsert(ctx context.Context, settings *models.ChoreSettings) error {
+	m.settings = settings
+	return nil
+}
+
+type memoryChoreCompletions struct {
+	repository.ChoreCompletionRepository
+	completions []models.ChoreCompletion
+}
+
+func (m *memoryChoreCompletions) Create(ctx context.Context, completion *models.ChoreCompletion) error {
+	m.completions = append(m.completions, *completion)
+	return nil
+}
+
+func (m *memoryChoreCompletions) Update(ctx context.Context, completion *models.ChoreCompletion) error {
+	for i := range m.completions {
+		if m.completions[i].ID == completion.ID {
+			m.completions[i] = *completion
+		}
+	}
+	return nil
+}
+
+func (m *memoryChoreCompletions) ListByAssignmentID(ctx context.Context, assignmentID string) ([]models.ChoreCompletion, error) {
+	var completions []models.ChoreCompletion
+	for _, completion := range m.completions {
+		if completion.AssignmentID == assignmentID {
+			completions = append(completions, completion)
+		}
+	}
+	return completions, nil
+}
+
+func (m *memoryChoreCompletions) GetLatestByAssignmentID(ctx context.Context, assignmentID string) (*models.ChoreCompletion, error) {
+	completions, _ := m.ListByAssignmentID(ctx, assignmentID)
+	if len(completions) == 0 {
+		return nil, nil
+	}
+	return &completions[len(completions)-1], nil
+}
+
+type memorySentReminders struct {
+	repository.SentReminderRepository
+	reminders []models.SentReminder
+}
+
+func (m *memorySentReminders) Create(ctx context.Context, reminder *models.SentReminder) error {
+	m.reminders = append(m.reminders, *reminder)
+	return nil
+}
+
+func (m *memorySentReminders) Exists(ctx context.Context, userID, resourceType, resourceID, reminderType string) (bool, error) {
+	for _, r := range m.reminders {
+		if r.UserID == userID && r.ResourceType == resourceType && r.ResourceID == resourceID && r.ReminderType == reminderType {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+type memoryUserAbsences struct {
+	repository.UserAbsenceRepository
+	absences []models.UserAbsence
+}
+
+func (m *memoryUserAbsences) Create(ctx context.Context, absence *models.UserAbsence) error {
+	m.absences = append(m.absences, *absence)
+	return nil
+}
+
+func (m *memoryUserAbsences) ListOverlapping(ctx context.Context, from, to time.Time) ([]models.UserAbsence, error) {
+	var absences []models.UserAbsence
+	for _, absence := range m.absences {
+		if !absence.StartDate.After(to) && !absence.EndDate.Before(from) {
+			absences = append(absences, absence)
+		}
+	}
+	return absences, nil
+}
+
+type memoryChorePreferences struct {
+	repository.ChorePreferenceRepository
+	preferences []models.ChorePreference
+}
+
+func (m *memoryChorePreferences) Set(ctx context.Context, preference *models.ChorePreference) error {
+	for i := range m.preferences {
+		if m.preferences[i].ChoreID == preference.ChoreID && m.preferences[i].UserID == preference.UserID {
+			m.preferences[i].Preference = preference.Preference
+			return nil
+		}
+	}
+	m.preferences = append(m.preferences, *preference)
+	return nil
+}
+
+func (m *memoryChorePreferences) ListByChoreID(ctx context.Context, choreID string) ([]models.ChorePreference, error) {
+	var preferences []models.ChorePreference
+	for _, preference := range m.preferences {
+		if preference.ChoreID == choreID {
+			preferences = append(preferences, preference)
+		}
+	}
+	return preferences, nil
+}
+
+func (m *memoryUsers) ListActive(ctx context.Context) ([]models.User, error) {
+	var users []models.User
+	for _, user := range m.users {
+		if user.IsActive {
+			users = append(users, user)
+		}
+	}
+	return users, nil
+}
+
+func TestNextChoreDueDate(t *testing.T) {
+	jan31 := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+	interval := 3
+
+	tests := []struct {
+		name  string
+		chore models.Chore
+		want  time.Time
+		ok    bool
+	}{
+		{"daily", models.Chore{Frequency: "daily"}, time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC), true},
+		{"weekly", models.Chore{Frequency: "weekly"}, time.Date(2026, 2, 7, 0, 0, 0, 0, time.UTC), true},
+		{"monthly clamps to the end of the month", models.Chore{Frequency: "monthly"}, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), true},
+		{"custom", models.Chore{Frequency: "custom", CustomInterval: &interval}, time.Date(2026, 2, 3, 0, 0, 0, 0, time.UTC), true},
+		{"custom without interval", models.Chore{Frequency: "custom"}, time.Time{}, false},
+		{"irregular", models.Chore{Frequency: "irregular"}, time.Time{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := nextChoreDueDate(&tt.chore, jan31, jan31.Day())
+			assert.Equal(t, tt.ok, ok)
+			assert.True(t, tt.want.Equal(got), "got %s, want %s", got, tt.want)
+		})
+	}
+}
+
+func TestNextChoreDueDate_MonthlyKeepsAnchorDay(t *testing.T) {
+	chore := &models.Chore{Frequency: "monthly"}
+	jan31 := time.Date(2026, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	feb, ok := nextChoreDueDate(chore, jan31, 31)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 2, 28, 0, 0, 0, 0, time.UTC), feb)
+
+	mar, ok := nextChoreDueDate(chore, feb, 31)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), mar)
+
+	apr, ok := nextChoreDueDate(chore, mar, 31)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2026, 4, 30, 0, 0, 0, 0, time.UTC), apr)
+
+	// The scheduler anchors on the chore's first assignment
+	ctx := context.Background()
+	chores := &memoryChores{chores: []models.Chore{
+		{ID: "rent", Name: "Rent", Frequency: "monthly", AssignmentMode: "round_robin", Difficulty: 1, IsActive: true},
+	}}
+	assignments := &memoryChoreAssignments{assignments: []models.ChoreAssignment{
+		{ID: "r1", ChoreID: "rent", AssigneeUserID: "anna", DueDate: jan31, Status: "done"},
+		{ID: "r2", ChoreID: "rent", AssigneeUserID: "anna", DueDate: feb, Status: "done"},
+	}}
+	users := &memoryUsers{users: []models.User{{ID: "anna", Name: "Anna", IsActive: true}}}
+	cfg := &config.Config{Chores: config.ChoreConfig{ScheduleDays: 31}}
+	s := NewChoreService(chores, assignments, nil, &memoryUserAbsences{}, &memoryChorePreferences{}, nil, &memoryChoreSettings{}, &memorySentReminders{}, users, nil, cfg)
+
+	created, err := s.GenerateUpcomingAssignments(ctx, time.Date(2026, 3, 1, 8, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	require.Equal(t, 1, created)
+	latest, _ := assignments.GetLatestByChoreID(ctx, "rent")
+	assert.Equal(t, mar, latest.DueDate)
+}
+
+func TestGenerateUpcomingAssignmentsWithRecurrenceRule(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 8, 0, 0, 0, time.UTC) // a Tuesday
+	created := time.Date(2026, 3, 3, 12, 0, 0, 0, time.UTC)
+	everyOtherTuesday := "FREQ=WEEKLY;INTERVAL=2;BYDAY=TU"
+	twoTrashDays := "RRULE:FREQ=WEEKLY;BYDAY=MO,TH;COUNT=3\nEXDATE:20260312"
+
+	chores := &memoryChores{chores: []models.Chore{
+		// Due on the 3rd, 17th and 31st, counting from the week the chore was created
+		{ID: "fridge", Name: "Fridge", Frequency: "irregular", RecurrenceRule: &everyOtherTuesday, AssignmentMode: "round_robin", IsActive: true, CreatedAt: created},
+		// Thursday the 5th and Monday the 9th are in the past and the 12th is excluded
+		{ID: "trash", Name: "Trash", Frequency: "daily", RecurrenceRule: &twoTrashDays, AssignmentMode: "round_robin", IsActive: true, CreatedAt: created},
+	}}
+	assignments := &memoryChoreAssignments{}
+	users := &memoryUsers{users: []models.User{{ID: "anna", Name: "Anna", IsActive: true}}}
+
+	cfg := &config.Config{Chores: config.ChoreConfig{ScheduleDays: 14}}
+	s := NewChoreService(chores, assignments, nil, &memoryUserAbsences{}, &memoryChorePreferences{}, nil, nil, nil, users, nil, cfg)
+
+	count, err := s.GenerateUpcomingAssignments(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	fridge, _ := assignments.ListByChoreID(ctx, "fridge")
+	require.Len(t, fridge, 1)
+	assert.True(t, time.Date(2026, 3, 17, 0, 0, 0, 0, time.UTC).Equal(fridge[0].DueDate))
+
+	trash, _ := assignments.ListByChoreID(ctx, "trash")
+	assert.Empty(t, trash)
+
+	// Invalid rules are rejected
+	invalid := "FREQ=WEEKLY;BYDAY=XX"
+	_, err = s.UpdateChore(ctx, "fridge", UpdateChoreRequest{RecurrenceRule: &invalid})
+	assert.ErrorContains(t, err, "invalid recurrence rule")
+}
+
+func TestGenerateUpcomingAssignments(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	anna := "anna"
+
+	chores := &memoryChores{chores: []models.Chore{
+		{ID: "dishes", Name: "Dishes", Frequency: "daily", AssignmentMode: "round_robin", Difficulty: 1, IsActive: true},
+		{ID: "bathroom", Name: "Bathroom", Frequency: "weekly", AssignmentMode: "manual", ManualAssigneeID: &anna, Difficulty: 3, IsActive: true},
+		{ID: "windows", Name: "Windows", Frequency: "irregular", AssignmentMode: "round_robin", IsActive: true},
+		{ID: "garden", Name: "Garden", Frequency: "daily", AssignmentMode: "round_robin", IsActive: false},
+	}}
+	assignments := &memoryChoreAssignments{assignments: []models.ChoreAssignment{
+		// The bathroom was last due eight days ago: yesterday's occurrence was missed and the next is in six days
+		{ID: "old", ChoreID: "bathroom", AssigneeUserID: "anna", DueDate: today.AddDate(0, 0, -8), Status: "done"},
+	}}
+	users := &memoryUsers{users: []models.User{
+		{ID: "anna", Name: "Anna", IsActive: true},
+		{ID: "bartek", Name: "Bartek", IsActive: true},
+		{ID: "celina", Name: "Celina", IsActive: false},
+	}}
+
+	cfg := &config.Config{Chores: config.ChoreConfig{ScheduleDays: 7}}
+	s := NewChoreService(chores, assignments, nil, &memoryUserAbsences{}, &memoryChorePreferences{}, nil, nil, nil, users, nil, cfg)
+
+	created, err := s.GenerateUpcomingAssignments(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 8, created) // seven days of dishes and one bathroom
+
+	dishes, _ := assignments.ListByChoreID(ctx, "dishes")
+	require.Len(t, dishes, 7)
+	for i, assignment := range dishes {
+		assert.True(t, today.AddDate(0, 0, i).Equal(assignment.DueDate))
+		assert.NotEqual(t, "celina", assignment.AssigneeUserID, "inactive users are skipped")
+	}
+	assert.Equal(t, "anna", dishes[0].AssigneeUserID)
+	assert.Equal(t, "bartek", dishes[1].AssigneeUserID)
+	assert.Equal(t, "anna", dishes[2].AssigneeUserID)
+
+	bathroom, _ := assignments.ListByChoreID(ctx, "bathroom")
+	require.Len(t, bathroom, 2)
+	assert.True(t, today.AddDate(0, 0, 6).Equal(bathroom[1].DueDate))
+	assert.Equal(t, "anna", bathroom[1].AssigneeUserID)
+
+	// Running again, even later the same day, creates nothing
+	created, err = s.GenerateUpcomingAssignments(ctx, now.Add(5*time.Hour))
+	require.NoError(t, err)
+	assert.Zero(t, created)
+
+	// The next day extends the schedule by one day
+	created, err = s.GenerateUpcomingAssignments(ctx, now.AddDate(0, 0, 1))
+	require.NoError(t, err)
+	assert.Equal(t, 1, created)
+}
+
+func TestScheduledAssignmentsAreNotOverdueOnTheirDueDay(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 15, 30, 0, 0, time.UTC)
+
+	chores := &memoryChores{chores: []models.Chore{
+		{ID: "dishes", Name: "Dishes", Frequency: "daily", AssignmentMode: "round_robin", Difficulty: 1, IsActive: true},
+	}}
+	assignments := &memoryChoreAssignments{}
+	users := &memoryUsers{users: []models.User{{ID: "anna", Name: "Anna", IsActive: true}}}
+	cfg := &config.Config{Chores: config.ChoreConfig{ScheduleDays: 3}}
+	s := NewChoreService(chores, assignments, nil, &memoryUserAbsences{}, &memoryChorePreferences{}, nil, &memoryChoreSettings{}, &memorySentReminders{}, users, nil, cfg)
+
+	deduct := "deduct"
+	_, err := s.UpdateChoreSettings(ctx, UpdateChoreSettingsRequest{OverduePenalty: &deduct})
+	require.NoError(t, err)
+
+	// The scheduler generates and then processes overdue assignments in the same tick
+	created, err := s.GenerateUpcomingAssignments(ctx, now)
+	require.NoError(t, err)
+	require.Equal(t, 3, created)
+	marked, err := s.ProcessOverdueAssignments(ctx, now)
+	require.NoError(t, err)
+	assert.Zero(t, marked)
+
+	dishes, _ := assignments.ListByChoreID(ctx, "dishes")
+	for _, assignment := range dishes {
+		assert.Equal(t, "pending", assignment.Status)
+		assert.Equal(t, 10, assignment.Points)
+	}
+
+	// Done late on its due day is still on time
+	todays := dishes[0]
+	completeAssignment(&todays, &chores.chores[0], time.Date(2026, 3, 10, 23, 59, 0, 0, time.UTC))
+	assert.True(t, todays.IsOnTime)
+	assert.Equal(t, 15, todays.Points)
+
+	// The first minute of the next day it is overdue
+	marked, err = s.ProcessOverdueAssignments(ctx, time.Date(2026, 3, 11, 0, 0, 0, 0, time.UTC))
+	require.NoError(t, err)
+	assert.Equal(t, 1, marked)
+}
+
+func TestCompletingOverdueAssignment(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+
+	for penalty, overduePoints := range map[string]int{"none": 40, "withhold": 0, "deduct": -40} {
+		t.Run(penalty, func(t *testing.T) {
+			chores := &memoryChores{chores: []models.Chore{
+				{ID: "bathroom", Name: "Bathroom", Frequency: "weekly", AssignmentMode: "round_robin", Difficulty: 4, OverduePolicy: "keep", IsActive: true},
+			}}
+			assignments := &memoryChoreAssignments{assignments: []models.ChoreAssignment{
+				{ID: "b1", ChoreID: "bathroom", AssigneeUserID: "anna", DueDate: now.AddDate(0, 0, -1), Status: "pending", Points: 40},
+			}}
+			users := &memoryUsers{users: []models.User{{ID: "anna", Name: "Anna", Role: "RESIDENT", IsActive: true}}}
+			s := NewChoreService(chores, assignments, nil, &memoryUserAbsences{}, &memoryChorePreferences{}, nil, &memoryChoreSettings{}, &memorySentReminders{}, users, nil, &config.Config{})
+
+			_, err := s.UpdateChoreSettings(ctx, UpdateChoreSettingsRequest{OverduePenalty: &penalty})
+			require.NoError(t, err)
+			marked, err := s.ProcessOverdueAssignments(ctx, now)
+			require.NoError(t, err)
+			require.Equal(t, 1, marked)
+			overdue, _ := assignments.GetByID(ctx, "b1")
+			assert.Equal(t, overduePoints, overdue.Points)
+
+			// Done late, the chore earns its base points without the on-time bonus
+			require.NoError(t, s.UpdateChoreAssignment(ctx, "b1", UpdateChoreAssignmentRequest{Status: "done", UserID: "anna"}))
+			done, _ := assignments.GetByID(ctx, "b1")
+			assert.Equal(t, "done", done.Status)
+			assert.False(t, done.IsOnTime)
+			assert.Equal(t, 40, done.Points)
+		})
+	}
+}
+
+func TestChoreAssignmentAvailability(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 9, 0, 0, 0, time.UTC)
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+
+	chores := &memoryChores{chores: []models.Chore{
+		{ID: "dishes", Name: "Dishes", Frequency: "daily", AssignmentMode: "round_robin", Difficulty: 1, IsActive: true},
+		{ID: "trash", Name: "Trash", Frequency: "irregular", AssignmentMode: "round_robin", Difficulty: 1, IsActive: true},
+	}}
+	assignments := &memoryChoreAssignments{}
+	users := &memoryUsers{users: []models.User{
+		{ID: "anna", Name: "Anna", IsActive: true},
+		{ID: "bartek", Name: "Bartek", IsActive: true},
+		{ID: "celina", Name: "Celina", IsActive: true},
+	}}
+	absences := &memoryUserAbsences{absences: []models.UserAbsence{
+		{ID: "away", UserID: "bartek", StartDate: today.AddDate(0, 0, 2), EndDate: today.AddDate(0, 0, 3)},
+	}}
+	preferences := &memoryChorePreferences{}
+
+	cfg := &config.Config{Chores: config.ChoreConfig{ScheduleDays: 7}}
+	s := NewChoreService(chores, assignments, nil, absences, preferences, nil, nil, nil, users, nil, cfg)
+
+	_, err := s.SetChorePreference(ctx, "dishes", "celina", "excluded")
+	require.NoError(t, err)
+
+	_, err = s.GenerateUpcomingAssignments(ctx, now)
+	require.NoError(t, err)
+
+	dishes, _ := assignments.ListByChoreID(ctx, "dishes")
+	var assignees []string
+	for _, assignment := range dishes {
+		assignees = append(assignees, assignment.AssigneeUserID)
+	}
+	// Celina is excluded and Bartek is away on days 2 and 3, then catches up
+	assert.Equal(t, []string{"anna", "bartek", "anna", "anna", "bartek", "bartek", "anna"}, assignees)
+
+	// Preferred users go first while they are available
+	_, err = s.SetChorePreference(ctx, "trash", "celina", "preferred")
+	require.NoError(t, err)
+	trash, err := s.RotateChore(ctx, "trash", today)
+	require.NoError(t, err)
+	assert.Equal(t, "celina", trash.AssigneeUserID)
+
+	// Recording an absence hands open assignments in that period to someone else
+	_, err = s.CreateAbsence(ctx, CreateAbsenceRequest{UserID: "anna", StartDate: today.AddDate(0, 0, 6), EndDate: today.AddDate(0, 0, 8)})
+	require.NoError(t, err)
+	dishes, _ = assignments.ListByChoreID(ctx, "dishes")
+	assert.Equal(t, "bartek", dishes[6].AssigneeUserID)
+
+	// No one left
+	_, err = s.CreateAbsence(ctx, CreateAbsenceRequest{UserID: "bartek", StartDate: today.AddDate(0, 0, 8), EndDate: today.AddDate(0, 0, 8)})
+	require.NoError(t, err)
+	_, err = s.AutoAssignChore(ctx, "dishes", today.AddDate(0, 0, 8))
+	assert.ErrorContains(t, err, "no available users")
+
+	_, err = s.CreateAbsence(ctx, CreateAbsenceRequest{UserID: "anna", StartDate: today, EndDate: today.AddDate(0, 0, -1)})
+	assert.Error(t, err, "the end date must not be before the start date")
+}
+
+func TestFairAssignment(t *testing.T) {
+	ctx := context.Background()
+	day := func(month time.Month, d int) time.Time { return time.Date(2026, month, d, 0, 0, 0, 0, time.UTC) }
+	completed := func(month time.Month, d int) *time.Time { t := day(month, d).Add(18 * time.Hour); return &t }
+
+	chores := &memoryChores{chores: []models.Chore{
+		{ID: "bathroom", Name: "Bathroom", Frequency: "weekly", AssignmentMode: "fair", Difficulty: 5, IsActive: true},
+		{ID: "dishes", Name: "Dishes", Frequency: "daily", AssignmentMode: "round_robin", Difficulty: 1, IsActive: true},
+	}}
+	assignments := &memoryChoreAssignments{assignments: []models.ChoreAssignment{
+		// Anna cleaned the bathroom twice recently
+		{ID: "a1", ChoreID: "bathroom", AssigneeUserID: "anna", DueDate: day(3, 7), Status: "done", CompletedAt: completed(3, 7)},
+		{ID: "a2", ChoreID: "bathroom", AssigneeUserID: "anna", DueDate: day(2, 28), Status: "done", CompletedAt: completed(2, 28)},
+		// Bartek only did the easy dishes
+		{ID: "b1", ChoreID: "dishes", AssigneeUserID: "bartek", DueDate: day(3, 9), Status: "done", CompletedAt: completed(3, 9)},
+		{ID: "b2", ChoreID: "dishes", AssigneeUserID: "bartek", DueDate: day(3, 8), Status: "done", CompletedAt: completed(3, 8)},
+		{ID: "b3", ChoreID: "dishes", AssigneeUserID: "bartek", DueDate: day(3, 5), Status: "done", CompletedAt: completed(3, 5)},
+		// Outside the window
+		{ID: "c1", ChoreID: "bathroom", AssigneeUserID: "celina", DueDate: day(1, 2), Status: "done", CompletedAt: completed(1, 2)},
+	}}
+	users := &memoryUsers{users: []models.User{
+		{ID: "anna", Name: "Anna", IsActive: true},
+		{ID: "bartek", Name: "Bartek", IsActive: true},
+		{ID: "celina", Name: "Celina", IsActive: true},
+	}}
+	absences := &memoryUserAbsences{absences: []models.UserAbsence{
+		{ID: "away", UserID: "celina", StartDate: day(3, 10), EndDate: day(3, 11)},
+	}}
+
+	cfg := &config.Config{Chores: config.ChoreConfig{FairWindowDays: 56, FairHalfLifeDays: 14}}
+	s := NewChoreService(chores, assignments, nil, absences, &memoryChorePreferences{}, nil, nil, nil, users, nil, cfg)
+
+	assignment, explanation, err := s.FairAssignChore(ctx, "bathroom", day(3, 10))
+	require.NoError(t, err)
+	assert.Equal(t, "bartek", assignment.AssigneeUserID)
+	assert.Equal(t, "bartek", explanation.SelectedUserID)
+	require.Len(t, explanation.Candidates, 2)
+	assert.Equal(t, "anna", explanation.Candidates[1].UserID)
+	assert.Less(t, explanation.Candidates[0].Load, explanation.Candidates[1].Load)
+	assert.Equal(t, 3, explanation.Candidates[0].Assignments)
+	assert.Contains(t, explanation.Reason, "Bartek has the lowest load")
+	assert.Equal(t, []SkippedAssignee{{UserID: "celina", UserName: "Celina", Reason: "absent"}}, explanation.Skipped)
+
+	// The hard chore counts towards Bartek's load once he has done it
+	assignment.Status, assignment.CompletedAt = "done", completed(3, 10)
+	require.NoError(t, assignments.Update(ctx, assignment))
+	preview, err := s.ExplainFairAssignment(ctx, "bathroom", day(3, 11))
+	require.NoError(t, err)
+	assert.Equal(t, "anna", preview.SelectedUserID)
+
+	// The explanation of an existing assignment leaves the assignment itself out
+	explained, err := s.ExplainChoreAssignment(ctx, assignment.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "bartek", explained.SelectedUserID)
+	assert.Equal(t, explanation.Candidates, explained.Candidates)
+
+	// Without decay or window, Celina's old bathroom counts in full once she is back
+	s = NewChoreService(chores, assignments, nil, absences, &memoryChorePreferences{}, nil, nil, nil, users, nil, &config.Config{})
+	preview, err = s.ExplainFairAssignment(ctx, "bathroom", day(3, 12))
+	require.NoError(t, err)
+	loads := map[string]float64{}
+	for _, c := range preview.Candidates {
+		loads[c.UserID] = c.Load
+	}
+	assert.Equal(t, map[string]float64{"anna": 10, "bartek": 8, "celina": 5}, loads)
+	assert.Equal(t, "celina", preview.SelectedUserID)
+}
+
+func TestFairAssignment_IgnoresUnfinishedChores(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 0, 0, 0, 0, time.UTC) }
+	completed := func(d int) *time.Time { t := day(d).Add(18 * time.Hour); return &t }
+
+	chores := &memoryChores{chores: []models.Chore{
+		{ID: "bathroom", Name: "Bathroom", Frequency: "weekly", AssignmentMode: "fair", Difficulty: 5, IsActive: true},
+		{ID: "dishes", Name: "Dishes", Frequency: "daily", AssignmentMode: "round_robin", Difficulty: 1, IsActive: true},
+	}}
+	assignments := &memoryChoreAssignments{assignments: []models.ChoreAssignment{
+		// Anna let the bathroom go overdue and hasn't done the next one yet
+		{ID: "a1", ChoreID: "bathroom", AssigneeUserID: "anna", DueDate: day(3), Status: "overdue"},
+		{ID: "a2", ChoreID: "bathroom", AssigneeUserID: "anna", DueDate: day(12), Status: "pending"},
+		// Bartek did the dishes, once more after the due date in question
+		{ID: "b1", ChoreID: "dishes", AssigneeUserID: "bartek", DueDate: day(8), Status: "done", CompletedAt: completed(8)},
+		{ID: "b2", ChoreID: "dishes", AssigneeUserID: "bartek", DueDate: day(11), Status: "done", CompletedAt: completed(11)},
+	}}
+	users := &memoryUsers{users: []models.User{
+		{ID: "anna", Name: "Anna", IsActive: true},
+		{ID: "bartek", Name: "Bartek", IsActive: true},
+	}}
+
+	cfg := &config.Config{Chores: config.ChoreConfig{FairWindowDays: 56, FairHalfLifeDays: 14}}
+	s := NewChoreService(chores, assignments, nil, &memoryUserAbsences{}, &memoryChorePreferences{}, nil, nil, nil, users, nil, cfg)
+
+	explanation, err := s.ExplainFairAssignment(ctx, "bathroom", day(10))
+	require.NoError(t, err)
+	assert.Equal(t, "anna", explanation.SelectedUserID)
+	require.Len(t, explanation.Candidates, 2)
+	assert.Zero(t, explanation.Candidates[0].Load)
+	assert.Zero(t, explanation.Candidates[0].Assignments)
+	assert.Equal(t, 1, explanation.Candidates[1].Assignments)
+}
+
+func TestProcessOverdueAssignments(t *testing.T) {
+	ctx := context.Background()
+	now := time.Date(2026, 3, 10, 12, 0, 0, 0, time.UTC)
+	bartek := "bartek"
+
+	chores := &memoryChores{chores: []models.Chore{
+		{ID: "trash", Name: "Trash", Frequency: "weekly", AssignmentMode: "round_robin", Difficulty: 2, OverduePolicy: "keep", IsActive: true},
+		{ID: "bathroom", Name: "Bathroom", Frequency: "weekly", AssignmentMode: "round_robin", Difficulty: 4, OverduePolicy: "reassign", IsActive: true},
+	}}
+	assignments := &memoryChoreAssignments{assignments: []models.ChoreAssignment{
+		{ID: "t1", ChoreID: "trash", AssigneeUserID: "anna", DueDate: now.AddDate(0, 0, -1), Status: "pending", Points: 20, AssignedByUserID: &bartek},
+		{ID: "b1", ChoreID: "bathroom", AssigneeUserID: "anna", DueDate: now.AddDate(0, 0, -2), Status: "pending", Points: 40},
+		{ID: "d1", ChoreID: "trash", AssigneeUserID: "bartek", DueDate: now.AddDate(0, 0, 2), Status: "pending", Points: 20},
+		// Due earlier today, still on time until midnight
+		{ID: "t2", ChoreID: "trash", AssigneeUserID: "bartek", DueDate: now.Add(-4 * time.Hour), Status: "pending", Points: 20},
+	}}
+	users := &memoryUsers{users: []models.User{
+		{ID: "admin", Name: "Admin", Role: "ADMIN", IsActive: true},
+		{ID: "anna", Name: "Anna", Role: "RESIDENT", IsActive: true},
+		{ID: "bartek", Name: "Bartek", Role: "RESIDENT", IsActive: true},
+	}}
+	preferences := &memoryChorePreferences{preferences: []models.ChorePreference{
+		{ID: "p1", ChoreID: "bathroom", UserID: "admin", Preference: "excluded"},
+	}}
+	reminders := &memorySentReminders{}
+
+	s := NewChoreService(chores, assignments, nil, &memoryUserAbsences{}, preferences, nil, &memoryChoreSettings{}, reminders, users, nil, &config.Config{})
+
+	settings, err := s.GetChoreSettings(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "none", settings.OverduePenalty)
+	assert.Equal(t, 24, settings.OverdueEscalationHours)
+
+	deduct := "deduct"
+	_, err = s.UpdateChoreSettings(ctx, UpdateChoreSettingsRequest{OverduePenalty: &deduct})
+	require.NoError(t, err)
+	invalid := "fine"
+	_, err = s.UpdateChoreSettings(ctx, UpdateChoreSettingsRequest{OverduePenalty: &invalid})
+	assert.Error(t, err)
+
+	// Both assignments due on earlier days go overdue, the bathroom one is past the escalation delay
+	marked, err := s.ProcessOverdueAssignments(ctx, now)
+	require.NoError(t, err)
+	assert.Equal(t, 2, marked)
+	today, _ := assignments.GetByID(ctx, "t2")
+	assert.Equal(t, "pending", today.Status)
+	assert.Equal(t, 20, today.Points)
+
+	trash, _ := assignments.GetByID(ctx, "t1")
+	assert.Equal(t, "overdue", trash.Status)
+	assert.Equal(t, -20, trash.Points)
+	assert.Equal(t, []string{"bartek"}, s.escalationRecipients(ctx, trash))
+
+	bathroom, _ := assignments.GetByID(ctx, "b1")
+	assert.Equal(t, "pending", bathroom.Status)
+	assert.Equal(t, "bartek", bathroom.AssigneeUserID)
+	assert.Equal(t, now.AddDate(0, 0, 1), bathroom.DueDate)
+	assert.Equal(t, 40, bathroom.Points)
+	assert.Equal(t, []string{"admin"}, s.escalationRecipients(ctx, bathroom))
+	require.Len(t, reminders.reminders, 1)
+	assert.Equal(t, "anna", reminders.reminders[0].UserID)
+
+	// Early two days later the trash escalates but stays with Anna, and Bartek missed the
+	// bathroom as well as the trash due that first day, which is now past its escalation too
+	marked, err = s.ProcessOverdueAssignments(ctx, now.Add(37*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 2, marked)
+
+	trash, _ = assignments.GetByID(ctx, "t1")
+	assert.Equal(t, "overdue", trash.Status)
+	assert.Equal(t, "anna", trash.AssigneeUserID)
+	bathroom, _ = assignments.GetByID(ctx, "b1")
+	assert.Equal(t, "overdue", bathroom.Status)
+	assert.Equal(t, -40, bathroom.Points)
+	assert.Len(t, reminders.reminders, 3)
+
+	// Running again changes nothing
+	marked, err = s.ProcessOverdueAssignments(ctx, now.Add(38*time.Hour))
+	require.NoError(t, err)
+	assert.Equal(t, 0, marked)
+	assert.Len(t, reminders.reminders, 3)
+
+	leaderboard, err := s.GetUserLeaderboard(ctx)
+	require.NoError(t, err)
+	points := map[string]int{}
+	for _, stats := range leaderboard {
+		points[stats.UserID] = stats.TotalPoints
+	}
+	assert.Equal(t, map[string]int{"admin": 0, "anna": -20, "bartek": -60}, points)
+}
+
+func TestCompletionVerification(t *testing.T) {
+	ctx := context.Background()
+	due := time.Now().Add(24 * time.Hour)
+	png := append([]byte("\x89PNG\r\n\x1a\n"), make([]byte, 64)...)
+
+	chores := &memoryChores{chores: []models.Chore{
+		{ID: "bathroom", Name: "Bathroom", Frequency: "weekly", AssignmentMode: "manual", Difficulty: 4, RequirePhoto: true, RequireConfirmation: true, IsActive: true},
+		{ID: "dishes", Name: "Dishes", Frequency: "daily", AssignmentMode: "manual", Difficulty: 1, RequireConfirmation: true, IsActive: true},
+	}}
+	assignments := &memoryChoreAssignments{assignments: []models.ChoreAssignment{
+		{ID: "b1", ChoreID: "bathroom", AssigneeUserID: "anna", DueDate: due, Status: "pending", Points: 40},
+		{ID: "d1", ChoreID: "dishes", AssigneeUserID: "anna", DueDate: due, Status: "pending", Points: 10},
+	}}
+	users := &memoryUsers{users: []models.User{
+		{ID: "anna", Name: "Anna", Role: "RESIDENT", IsActive: true},
+		{ID: "bartek", Name: "Bartek", Role: "RESIDENT", IsActive: true},
+	}}
+	completions := &memoryChoreCompletions{}
+	cfg := &config.Config{}
+	cfg.Chores.PhotoDir = t.TempDir()
+	cfg.Chores.PhotoMaxBytes = 1024
+
+	s := NewChoreService(chores, assignments, nil, &memoryUserAbsences{}, &memoryChorePreferences{}, completions, nil, nil, users, nil, cfg)
+
+	// The bathroom needs a small image as proof
+	_, err := s.SubmitCompletion(ctx, "b1", "anna", nil)
+	assert.Error(t, err)
+	_, err = s.SubmitCompletion(ctx, "b1", "anna", []byte("not an image"))
+	assert.Error(t, err)
+	_, err = s.SubmitCompletion(ctx, "b1", "anna", append(png, make([]byte, 1024)...))
+	assert.Error(t, err)
+	assert.Empty(t, completions.completions)
+
+	assignment, err := s.SubmitCompletion(ctx, "b1", "anna", png)
+	require.NoError(t, err)
+	assert.Equal(t, "awaiting_confirmation", assignment.Status)
+	assert.Equal(t, 40, assignment.Points)
+	_, err = s.SubmitCompletion(ctx, "b1", "anna", png)
+	assert.Error(t, err)
+
+	photo, contentType, err := s.GetCompletionPhoto(ctx, "b1", completions.completions[0].ID)
+	require.NoError(t, err)
+	assert.Equal(t, png, photo)
+	assert.Equal(t, "image/png", contentType)
+
+	// Anna cannot confirm her own work, Bartek disputes it
+	_, err = s.ConfirmCompletion(ctx, "b1", "anna", ReviewCompletionRequest{})
+	assert.Error(t, err)
+	comment := "Mirror is still dirty"
+	assignment, err = s.DisputeCompletion(ctx, "b1", "bartek", ReviewCompletionRequest{Comment: &comment})
+	require.NoError(t, err)
+	assert.Equal(t, "in_progress", assignment.Status)
+	assert.Equal(t, "disputed", completions.completions[0].Status)
+
+	// The second attempt is confirmed with the on-time bonus
+	_, err = s.SubmitCompletion(ctx, "b1", "anna", png)
+	require.NoError(t, err)
+	assignment, err = s.ConfirmCompletion(ctx, "b1", "bartek", ReviewCompletionRequest{})
+	require.NoError(t, err)
+	assert.Equal(t, "done", assignment.Status)
+	assert.True(t, assignment.IsOnTime)
+	assert.Equal(t, 60, assignment.Points)
+
+	history, err := s.GetCompletions(ctx, "b1")
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, "disputed", history[0].Status)
+	assert.Equal(t, "confirmed", history[1].Status)
+	assert.Equal(t, "bartek", *history[1].ReviewerUserID)
+
+	// Marking a chore done goes through confirmation as well
+	require.NoError(t, s.UpdateChoreAssignment(ctx, "d1", UpdateChoreAssignmentRequest{Status: "done", UserID: "anna"}))
+	dishes, _ := assignments.GetByID(ctx, "d1")
+	assert.Equal(t, "awaiting_confirmation", dishes.Status)
+	assert.Nil(t, dishes.CompletedAt)
+}
@@ -4,6 +4,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
+	"math"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -14,21 +18,38 @@ import (
 
 var ErrNoPreviousReading = errors.New("no previous meter reading found")
 
+const (
+	// anomalyMinHistory is the number of earlier readings needed before usage can be judged
+	anomalyMinHistory = 3
+	// anomalyHistorySize is the number of latest readings the rolling baseline covers
+	anomalyHistorySize = 12
+	// anomalyDeviations is how many standard deviations from the expected usage are tolerated
+	anomalyDeviations = 3.0
+	// anomalyMinRelativeChange keeps small changes of very steady usage from being flagged
+	anomalyMinRelativeChange = 0.5
+)
+
 type ConsumptionService struct {
-	consumptions repository.ConsumptionRepository
-	bills        repository.BillRepository
-	users        repository.UserRepository
+	consumptions      repository.ConsumptionRepository
+	meters            repository.MeterRepository
+	meterReplacements repository.MeterReplacementRepository
+	bills             repository.BillRepository
+	users             repository.UserRepository
 }
 
 func NewConsumptionService(
 	consumptions repository.ConsumptionRepository,
+	meters repository.MeterRepository,
+	meterReplacements repository.MeterReplacementRepository,
 	bills repository.BillRepository,
 	users repository.UserRepository,
 ) *ConsumptionService {
 	return &ConsumptionService{
-		consumptions: consumptions,
-		bills:        bills,
-		users:        users,
+		consumptions:      consumptions,
+		meters:            meters,
+		meterReplacements: meterReplacements,
+		bills:             bills,
+		users:             users,
 	}
 }
 
@@ -37,6 +58,8 @@ type CreateConsumptionRequest struct {
 	UserID     string    `json:"userId"`
 	Units      float64   `json:"units"`
 	MeterValue *float64  `json:"meterValue,omitempty"`
+	MeterID    *string   `json:"meterId,omitempty"`
+	Register   *string   `json:"register,omitempty"` // register of a multi-register meter, e.g. day or night
 	RecordedAt time.Time `json:"recordedAt"`
 }
 
@@ -67,6 +90,45 @@ func (s *ConsumptionService) CreateConsumption(ctx context.Context, req CreateCo
 		subjectID = *user.GroupID
 	}
 
+	// Readings of a meter belong to the meter's owner
+	if req.MeterID != nil && *req.MeterID != "" {
+		meter, err := s.meters.GetByID(ctx, *req.MeterID)
+		if err != nil {
+			return nil, fmt.Errorf("database error: %w", err)
+		}
+		if meter == nil {
+			return nil, errors.New("meter not found")
+		}
+		if !meter.IsActive {
+			return nil, errors.New("meter is not active")
+		}
+		if source != "admin" && (meter.SubjectType != subjectType || meter.SubjectID != subjectID) {
+			return nil, errors.New("you can only record readings of your own meters")
+		}
+		if req.MeterValue == nil {
+			return nil, errors.New("meterValue is required for meter readings")
+		}
+		if err := validateMeterValue(meter, *req.MeterValue); err != nil {
+			return nil, err
+		}
+		register, err := validateMeterRegister(meter, req.Register)
+		if err != nil {
+			return nil, err
+		}
+		req.Register = register
+		subjectType = meter.SubjectType
+		subjectID = meter.SubjectID
+	} else {
+		req.MeterID = nil
+		if req.Register != nil {
+			register := strings.ToLower(strings.TrimSpace(*req.Register))
+			req.Register = &register
+			if register == "" {
+				req.Register = nil
+			}
+		}
+	}
+
 	unitsValue := req.Units
 
 	if unitsValue <= 0 {
@@ -74,7 +136,7 @@ func (s *ConsumptionService) CreateConsumption(ctx context.Context, req CreateCo
 			return nil, errors.New("units must be greater than zero when no meter reading is provided")
 		}
 
-		computedUnits, err := s.calculateUnitsFromMeter(ctx, subjectID, subjectType, *req.MeterValue, req.RecordedAt)
+		computedUnits, err := s.calculateUnitsFromMeter(ctx, subjectID, subjectType, req.MeterID, req.Register, *req.MeterValue, req.RecordedAt)
 		switch {
 		case err == nil:
 			unitsValue = computedUnits
@@ -88,13 +150,27 @@ func (s *ConsumptionService) CreateConsumption(ctx context.Context, req CreateCo
 	unitsDec := utils.FloatToDecimalString(unitsValue)
 
 	consumption := &models.Consumption{
-		ID:          uuid.New().String(),
-		BillID:      req.BillID,
-		SubjectType: subjectType,
-		SubjectID:   subjectID,
-		Units:       unitsDec,
-		RecordedAt:  req.RecordedAt,
-		Source:      source,
+		ID:            uuid.New().String(),
+		BillID:        req.BillID,
+		SubjectType:   subjectType,
+		SubjectID:     subjectID,
+		Units:         unitsDec,
+		MeterID:       req.MeterID,
+		Register:      req.Register,
+		RecordedAt:    req.RecordedAt,
+		Source:        source,
+		AnomalyStatus: "none",
+	}
+
+	// Compare the usage with the subject's history, suspicious readings must be confirmed
+	history, err := s.readingHistory(ctx, subjectType, subjectID, req.MeterID, req.Register)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if reason := detectConsumptionAnomaly(history, unitsValue, req.RecordedAt); reason != "" {
+		consumption.AnomalyStatus = "suspicious"
+		consumption.AnomalyReason = &reason
+		log.Printf("[CONSUMPTION] Suspicious reading for %s %s: %s", subjectType, subjectID, reason)
 	}
 
 	if req.MeterValue != nil {
@@ -202,38 +278,146 @@ func (s *ConsumptionService) MarkConsumptionInvalid(ctx context.Context, consump
 	return s.consumptions.Update(ctx, consumption)
 }
 
-// calculateUnitsFromMeter derives consumption units based on the previous meter reading
-func (s *ConsumptionService) calculateUnitsFromMeter(ctx context.Context, subjectID string, subjectType string, currentMeter float64, recordedAt time.Time) (float64, error) {
-	// Get all consumptions for this subject and find the most recent one before recordedAt
-	consumptions, err := s.consumptions.ListBySubject(ctx, subjectType, subjectID)
+// ConfirmConsumption confirms a suspicious reading (user or their group must own it, admins may confirm any)
+func (s *ConsumptionService) ConfirmConsumption(ctx context.Context, consumptionID, userID string, isAdmin bool) (*models.Consumption, error) {
+	consumption, err := s.consumptions.GetByID(ctx, consumptionID)
+	if err != nil || consumption == nil {
+		return nil, errors.New("consumption not found")
+	}
+
+	if !isAdmin {
+		user, err := s.users.GetByID(ctx, userID)
+		if err != nil {
+			return nil, fmt.Errorf("user not found: %w", err)
+		}
+
+		isOwner := false
+		if consumption.SubjectType == "user" && consumption.SubjectID == userID {
+			isOwner = true
+		} else if consumption.SubjectType == "group" && user.GroupID != nil && consumption.SubjectID == *user.GroupID {
+			isOwner = true
+		}
+
+		if !isOwner {
+			return nil, errors.New("you can only confirm your own readings")
+		}
+	}
+
+	if consumption.AnomalyStatus != "suspicious" {
+		return nil, errors.New("reading is not marked as suspicious")
+	}
+
+	consumption.AnomalyStatus = "confirmed"
+	if err := s.consumptions.Update(ctx, consumption); err != nil {
+		return nil, fmt.Errorf("failed to confirm consumption: %w", err)
+	}
+
+	return consumption, nil
+}
+
+// readingHistory returns the earlier readings a new reading is compared with: those of the
+// same meter, or the subject's readings without a meter, of the same register
+func (s *ConsumptionService) readingHistory(ctx context.Context, subjectType, subjectID string, meterID, register *string) ([]models.Consumption, error) {
+	var readings []models.Consumption
+	var err error
+	if meterID != nil {
+		readings, err = s.consumptions.ListByMeterID(ctx, *meterID)
+	} else {
+		readings, err = s.consumptions.ListBySubject(ctx, subjectType, subjectID)
+	}
 	if err != nil {
-		return 0, fmt.Errorf("failed to fetch consumptions: %w", err)
+		return nil, err
+	}
+
+	history := make([]models.Consumption, 0, len(readings))
+	for _, reading := range readings {
+		if (meterID != nil || reading.MeterID == nil) && sameOptional(reading.Register, register) {
+			history = append(history, reading)
+		}
+	}
+	return history, nil
+}
+
+// detectConsumptionAnomaly compares the usage of a new reading with earlier usage. The expected
+// usage is the rolling mean of the latest readings, scaled by how readings of the same calendar
+// month in earlier years compare with the overall mean. Returns why the usage looks suspicious,
+// or an empty string when it does not.
+func detectConsumptionAnomaly(history []models.Consumption, units float64, recordedAt time.Time) string {
+	if units <= 0 {
+		return ""
 	}
 
-	var previous *models.Consumption
-	for i := range consumptions {
-		c := &consumptions[i]
-		if c.MeterValue == nil {
+	// Invalid and unconfirmed suspicious readings do not count as usage history
+	samples := make([]models.Consumption, 0, len(history))
+	for _, reading := range history {
+		if reading.Source == "invalid" || reading.AnomalyStatus == "suspicious" {
 			continue
 		}
-		if !c.RecordedAt.Before(recordedAt) {
+		if !reading.RecordedAt.Before(recordedAt) || utils.DecimalStringToFloat(reading.Units) <= 0 {
 			continue
 		}
-		if previous == nil || c.RecordedAt.After(previous.RecordedAt) {
-			previous = c
+		samples = append(samples, reading)
+	}
+	if len(samples) < anomalyMinHistory {
+		return ""
+	}
+
+	sort.Slice(samples, func(i, j int) bool {
+		return samples[i].RecordedAt.Before(samples[j].RecordedAt)
+	})
+
+	var all, sameMonth []float64
+	for _, sample := range samples {
+		value := utils.DecimalStringToFloat(sample.Units)
+		all = append(all, value)
+		if sample.RecordedAt.Month() == recordedAt.Month() && sample.RecordedAt.Year() < recordedAt.Year() {
+			sameMonth = append(sameMonth, value)
 		}
 	}
 
-	if previous == nil || previous.MeterValue == nil {
-		return 0, ErrNoPreviousReading
+	recent := all[max(0, len(all)-anomalyHistorySize):]
+	expected, deviation := meanAndDeviation(recent)
+
+	// Seasonality: scale the baseline by the month's usage relative to the average month
+	if overall, _ := meanAndDeviation(all); len(sameMonth) > 0 && overall > 0 {
+		seasonal, _ := meanAndDeviation(sameMonth)
+		expected *= seasonal / overall
+		deviation *= seasonal / overall
 	}
 
-	prevValue := utils.DecimalStringToFloat(*previous.MeterValue)
+	tolerance := math.Max(anomalyDeviations*deviation, anomalyMinRelativeChange*expected)
+	if math.Abs(units-expected) <= tolerance {
+		return ""
+	}
 
-	units := currentMeter - prevValue
-	if units < 0 {
-		return 0, errors.New("meter reading cannot be lower than previous reading")
+	direction := "above"
+	if units < expected {
+		direction = "below"
 	}
+	return fmt.Sprintf("usage of %.2f is far %s the expected %.2f", units, direction, expected)
+}
 
-	return units, nil
+// meanAndDeviation returns the mean and population standard deviation of the values
+func meanAndDeviation(values []float64) (float64, float64) {
+	if len(values) == 0 {
+		return 0, 0
+	}
+
+	var sum float64
+	for _, value := range values {
+		sum += value
+	}
+	mean := sum / float64(len(values))
+
+	var squares float64
+	for _, value := range values {
+		squares += (value - mean) * (value - mean)
+	}
+	return mean, math.Sqrt(squares / float64(len(values)))
+}
+
+// calculateUnitsFromMeter derives consumption units based on the previous meter reading
+func (s *ConsumptionService) calculateUnitsFromMeter(ctx context.Context, subjectID string, subjectType string, meterID, register *string, currentMeter float64, recordedAt time.Time) (float64, error) {
+	sources := meterSources{consumptions: s.consumptions, meters: s.meters, replacements: s.meterReplacements}
+	return sources.readingUnits(ctx, subjectType, subjectID, meterID, register, currentMeter, recordedAt)
 }
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func (m *memoryConsumptions) GetByID(ctx context.Context, id string) (*models.Consumption, error) {
+	for _, c := range m.consumptions {
+		if c.ID == id {
+			return &c, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryConsumptions) Update(ctx context.Context, consumption *models.Consumption) error {
+	for i := range m.consumptions {
+		if m.consumptions[i].ID == consumption.ID {
+			m.consumptions[i] = *consumption
+		}
+	}
+	return nil
+}
+
+func (m *memoryConsumptions) ListByBillID(ctx context.Context, billID string) ([]models.Consumption, error) {
+	var consumptions []models.Consumption
+	for _, c := range m.consumptions {
+		if c.BillID == billID {
+			consumptions = append(consumptions, c)
+		}
+	}
+	return consumptions, nil
+}
+
+func TestDetectConsumptionAnomaly(t *testing.T) {
+	month := func(year int, m time.Month) time.Time { return time.Date(year, m, 15, 12, 0, 0, 0, time.UTC) }
+	reading := func(at time.Time, units string) models.Consumption {
+		return models.Consumption{Units: units, RecordedAt: at, Source: "user", AnomalyStatus: "none"}
+	}
+
+	history := []models.Consumption{
+		reading(month(2026, 1), "100.00"),
+		reading(month(2026, 2), "110.00"),
+		reading(month(2026, 3), "95.00"),
+		reading(month(2026, 4), "105.00"),
+	}
+
+	// Too little history to judge
+	assert.Empty(t, detectConsumptionAnomaly(history[:2], 900, month(2026, 3)))
+
+	assert.Empty(t, detectConsumptionAnomaly(history, 120, month(2026, 5)))
+	assert.Contains(t, detectConsumptionAnomaly(history, 400, month(2026, 5)), "above")
+	assert.Contains(t, detectConsumptionAnomaly(history, 20, month(2026, 5)), "below")
+
+	// Invalid and unconfirmed readings are left out of the baseline
+	spikes := append([]models.Consumption{}, history...)
+	spikes = append(spikes, reading(month(2026, 4), "5000.00"), reading(month(2026, 4), "4000.00"))
+	spikes[4].Source = "invalid"
+	spikes[5].AnomalyStatus = "suspicious"
+	assert.Contains(t, detectConsumptionAnomaly(spikes, 400, month(2026, 5)), "above")
+
+	// A month that was high in earlier years is expected to be high again
+	seasonal := []models.Consumption{
+		reading(month(2025, 1), "300.00"),
+		reading(month(2025, 4), "100.00"),
+		reading(month(2025, 7), "100.00"),
+		reading(month(2025, 10), "100.00"),
+	}
+	assert.Empty(t, detectConsumptionAnomaly(seasonal, 300, month(2026, 1)))
+	assert.NotEmpty(t, detectConsumptionAnomaly(seasonal, 300, month(2026, 7)))
+}
+
+func TestSuspiciousReadingsBlockPosting(t *testing.T) {
+	ctx := context.Background()
+	groupID := "flat"
+	reason := "usage of 400.00 is far above the expected 100.00"
+
+	users := &memoryUsers{users: []models.User{
+		{ID: "anna", GroupID: &groupID},
+		{ID: "piotr"},
+	}}
+	consumptions := &memoryConsumptions{consumptions: []models.Consumption{
+		{ID: "c1", BillID: "b1", SubjectType: "group", SubjectID: groupID, Units: "400.00", Source: "user", AnomalyStatus: "suspicious", AnomalyReason: &reason},
+		{ID: "c2", BillID: "b1", SubjectType: "user", SubjectID: "piotr", Units: "900.00", Source: "invalid", AnomalyStatus: "suspicious"},
+	}}
+	bills := NewBillService(nil, consumptions, nil, nil, nil, nil, nil, nil, users, nil, nil, nil, nil, nil, nil, nil)
+	readings := NewConsumptionService(consumptions, nil, nil, nil, users)
+
+	suspicious, err := bills.GetSuspiciousReadings(ctx, "b1")
+	require.NoError(t, err)
+	require.Len(t, suspicious, 1)
+	assert.Equal(t, "c1", suspicious[0].ID)
+	assert.ErrorIs(t, bills.PostBill(ctx, "b1"), ErrSuspiciousReadings)
+	assert.ErrorIs(t, bills.CloseBill(ctx, "b1"), ErrSuspiciousReadings)
+
+	// Only the owners of a reading, or an admin, may confirm it
+	_, err = readings.ConfirmConsumption(ctx, "c1", "piotr", false)
+	assert.Error(t, err)
+	confirmed, err := readings.ConfirmConsumption(ctx, "c1", "anna", false)
+	require.NoError(t, err)
+	assert.Equal(t, "confirmed", confirmed.AnomalyStatus)
+	_, err = readings.ConfirmConsumption(ctx, "c1", "piotr", true)
+	assert.Error(t, err, "already confirmed")
+
+	assert.NoError(t, bills.requireConfirmedReadings(ctx, "b1"))
+}
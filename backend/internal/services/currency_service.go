@@ -0,0 +1,219 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/sainaif/holy-home/internal/utils"
+)
+
+// DefaultBaseCurrency is the base currency of households that never configured one
+const DefaultBaseCurrency = "PLN"
+
+// ErrExchangeRateMissing is returned when no rate exists on or before a transaction date
+var ErrExchangeRateMissing = errors.New("no exchange rate")
+
+var currencyCodePattern = regexp.MustCompile(`^[A-Z]{3}$`)
+
+// NormalizeCurrency upper-cases and validates an ISO 4217 currency code
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if !currencyCodePattern.MatchString(code) {
+		return "", fmt.Errorf("invalid currency code: %q", code)
+	}
+	return code, nil
+}
+
+// CurrencyService converts amounts to the household base currency using locally stored rates
+type CurrencyService struct {
+	exchangeRates repository.ExchangeRateRepository
+	appSettings   repository.AppSettingsRepository
+	txManager     repository.TxManager
+}
+
+func NewCurrencyService(exchangeRates repository.ExchangeRateRepository, appSettings repository.AppSettingsRepository, txManager repository.TxManager) *CurrencyService {
+	return &CurrencyService{exchangeRates: exchangeRates, appSettings: appSettings, txManager: txManager}
+}
+
+// ConvertedAmount is an amount in the base currency together with what was entered
+type ConvertedAmount struct {
+	Amount         utils.Money // in the base currency
+	Currency       string
+	OriginalAmount *string // set only for foreign currencies
+	ExchangeRate   *string // set only for foreign currencies
+}
+
+// BaseCurrency returns the currency balances are kept in
+func (s *CurrencyService) BaseCurrency(ctx context.Context) (string, error) {
+	settings, err := s.appSettings.Get(ctx)
+	if err != nil {
+		return "", fmt.Errorf("database error: %w", err)
+	}
+	if settings == nil || settings.BaseCurrency == "" {
+		return DefaultBaseCurrency, nil
+	}
+	return settings.BaseCurrency, nil
+}
+
+// ToBase converts an amount entered in currency to the base currency using the rate
+// effective on date. An empty currency means the base currency.
+func (s *CurrencyService) ToBase(ctx context.Context, amount utils.Money, currency string, date time.Time) (*ConvertedAmount, error) {
+	base, err := s.BaseCurrency(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if currency == "" {
+		return &ConvertedAmount{Amount: amount, Currency: base}, nil
+	}
+	currency, err = NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	if currency == base {
+		return &ConvertedAmount{Amount: amount, Currency: base}, nil
+	}
+
+	rate, err := s.exchangeRates.GetEffective(ctx, currency, base, date)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if rate == nil {
+		return nil, fmt.Errorf("%w for %s/%s on or before %s", ErrExchangeRateMissing, currency, base, date.Format("2006-01-02"))
+	}
+
+	converted, err := amount.Convert(rate.Rate)
+	if err != nil {
+		return nil, err
+	}
+	original := amount.String()
+	return &ConvertedAmount{
+		Amount:         converted,
+		Currency:       currency,
+		OriginalAmount: &original,
+		ExchangeRate:   &rate.Rate,
+	}, nil
+}
+
+// SetRate stores the rate of currency to the base currency on a day, replacing any rate for that day
+func (s *CurrencyService) SetRate(ctx context.Context, currency string, date time.Time, rate string) (*models.ExchangeRate, error) {
+	exchangeRate, err := s.newRate(ctx, currency, date, rate, "manual")
+	if err != nil {
+		return nil, err
+	}
+	if err := s.exchangeRates.Upsert(ctx, exchangeRate); err != nil {
+		return nil, fmt.Errorf("failed to save exchange rate: %w", err)
+	}
+	return exchangeRate, nil
+}
+
+// ImportRatesCSV imports rates from CSV rows of date (YYYY-MM-DD), currency and rate.
+// A header row is skipped, and semicolon-separated files may use decimal commas.
+// Nothing is imported if any row is invalid or cannot be saved.
+func (s *CurrencyService) ImportRatesCSV(ctx context.Context, data []byte) (int, error) {
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = 3
+	reader.TrimLeadingSpace = true
+	decimalComma := false
+	if firstLine, _, _ := strings.Cut(string(data), "\n"); strings.Contains(firstLine, ";") {
+		reader.Comma = ';'
+		decimalComma = true
+	}
+
+	var rates []*models.ExchangeRate
+	for line := 1; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return 0, fmt.Errorf("line %d: %w", line, err)
+		}
+
+		date, err := time.Parse("2006-01-02", strings.TrimSpace(record[0]))
+		if err != nil {
+			if line == 1 {
+				continue // header
+			}
+			return 0, fmt.Errorf("line %d: invalid date %q", line, record[0])
+		}
+		rate := strings.TrimSpace(record[2])
+		if decimalComma {
+			rate = strings.Replace(rate, ",", ".", 1)
+		}
+
+		exchangeRate, err := s.newRate(ctx, record[1], date, rate, "csv")
+		if err != nil {
+			return 0, fmt.Errorf("line %d: %w", line, err)
+		}
+		rates = append(rates, exchangeRate)
+	}
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		for _, rate := range rates {
+			if err := repos.ExchangeRates.Upsert(ctx, rate); err != nil {
+				return fmt.Errorf("failed to save exchange rate: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	return len(rates), nil
+}
+
+// ListRates returns all stored rates to the current base currency
+func (s *CurrencyService) ListRates(ctx context.Context) ([]models.ExchangeRate, error) {
+	base, err := s.BaseCurrency(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return s.exchangeRates.List(ctx, base)
+}
+
+// DeleteRate removes a stored rate
+func (s *CurrencyService) DeleteRate(ctx context.Context, id string) error {
+	rate, err := s.exchangeRates.GetByID(ctx, id)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if rate == nil {
+		return errors.New("exchange rate not found")
+	}
+	return s.exchangeRates.Delete(ctx, id)
+}
+
+func (s *CurrencyService) newRate(ctx context.Context, currency string, date time.Time, rate, source string) (*models.ExchangeRate, error) {
+	currency, err := NormalizeCurrency(currency)
+	if err != nil {
+		return nil, err
+	}
+	base, err := s.BaseCurrency(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if currency == base {
+		return nil, fmt.Errorf("%s is the base currency", currency)
+	}
+	if value, ok := new(big.Rat).SetString(rate); !ok || value.Sign() <= 0 {
+		return nil, fmt.Errorf("rate must be a positive decimal number, got %q", rate)
+	}
+
+	return &models.ExchangeRate{
+		Currency:     currency,
+		BaseCurrency: base,
+		RateDate:     date,
+		Rate:         rate,
+		Source:       source,
+	}, nil
+}
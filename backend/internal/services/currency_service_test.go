@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/sainaif/holy-home/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryExchangeRates is an in-memory exchange rate repository keyed by currency pair and day
+type memoryExchangeRates struct {
+	repository.ExchangeRateRepository
+	rates  []models.ExchangeRate
+	failOn string // currency whose rates cannot be saved
+}
+
+func (m *memoryExchangeRates) Upsert(ctx context.Context, rate *models.ExchangeRate) error {
+	if rate.Currency == m.failOn {
+		return errors.New("disk full")
+	}
+	for i, existing := range m.rates {
+		if existing.Currency == rate.Currency && existing.BaseCurrency == rate.BaseCurrency && existing.RateDate.Equal(rate.RateDate) {
+			m.rates[i] = *rate
+			return nil
+		}
+	}
+	m.rates = append(m.rates, *rate)
+	return nil
+}
+
+func (m *memoryExchangeRates) GetEffective(ctx context.Context, currency, baseCurrency string, date time.Time) (*models.ExchangeRate, error) {
+	var effective *models.ExchangeRate
+	for i, rate := range m.rates {
+		if rate.Currency != currency || rate.BaseCurrency != baseCurrency || rate.RateDate.After(date) {
+			continue
+		}
+		if effective == nil || rate.RateDate.After(effective.RateDate) {
+			effective = &m.rates[i]
+		}
+	}
+	return effective, nil
+}
+
+// memoryExchangeRateTxManager runs work against the rates and restores them when it fails
+type memoryExchangeRateTxManager struct {
+	rates *memoryExchangeRates
+}
+
+func (m *memoryExchangeRateTxManager) WithTx(ctx context.Context, fn func(ctx context.Context, repos *repository.Repositories) error) error {
+	rates := append([]models.ExchangeRate(nil), m.rates.rates...)
+	if err := fn(ctx, &repository.Repositories{ExchangeRates: m.rates}); err != nil {
+		m.rates.rates = rates
+		return err
+	}
+	return nil
+}
+
+type memoryAppSettings struct {
+	repository.AppSettingsRepository
+	settings *models.AppSettings
+}
+
+func (m *memoryAppSettings) Get(ctx context.Context) (*models.AppSettings, error) {
+	return m.settings, nil
+}
+
+func day(s string) time.Time {
+	t, _ := time.Parse("2006-01-02", s)
+	return t
+}
+
+func TestCurrencyToBase(t *testing.T) {
+	rates := &memoryExchangeRates{rates: []models.ExchangeRate{
+		{Currency: "EUR", BaseCurrency: "PLN", RateDate: day("2026-03-01"), Rate: "4.30"},
+		{Currency: "EUR", BaseCurrency: "PLN", RateDate: day("2026-03-10"), Rate: "4.25"},
+	}}
+	service := NewCurrencyService(rates, &memoryAppSettings{}, &memoryExchangeRateTxManager{rates: rates})
+	ctx := context.Background()
+
+	converted, err := service.ToBase(ctx, utils.MoneyFromString("10.00"), "", day("2026-03-05"))
+	require.NoError(t, err)
+	assert.Equal(t, "PLN", converted.Currency)
+	assert.Equal(t, utils.MoneyFromString("10.00"), converted.Amount)
+	assert.Nil(t, converted.OriginalAmount)
+
+	// The latest rate on or before the transaction date applies
+	converted, err = service.ToBase(ctx, utils.MoneyFromString("10.00"), "eur", day("2026-03-09"))
+	require.NoError(t, err)
+	assert.Equal(t, "EUR", converted.Currency)
+	assert.Equal(t, utils.MoneyFromString("43.00"), converted.Amount)
+	require.NotNil(t, converted.OriginalAmount)
+	assert.Equal(t, "10.00", *converted.OriginalAmount)
+	assert.Equal(t, "4.30", *converted.ExchangeRate)
+
+	converted, err = service.ToBase(ctx, utils.MoneyFromString("10.00"), "EUR", day("2026-03-10"))
+	require.NoError(t, err)
+	assert.Equal(t, utils.MoneyFromString("42.50"), converted.Amount)
+
+	_, err = service.ToBase(ctx, utils.MoneyFromString("10.00"), "EUR", day("2026-02-28"))
+	assert.ErrorIs(t, err, ErrExchangeRateMissing)
+
+	_, err = service.ToBase(ctx, utils.MoneyFromString("10.00"), "EURO", day("2026-03-10"))
+	assert.Error(t, err)
+}
+
+func TestImportRatesCSV(t *testing.T) {
+	ctx := context.Background()
+
+	rates := &memoryExchangeRates{}
+	service := NewCurrencyService(rates, &memoryAppSettings{settings: &models.AppSettings{BaseCurrency: "PLN"}}, &memoryExchangeRateTxManager{rates: rates})
+	imported, err := service.ImportRatesCSV(ctx, []byte("date,currency,rate\n2026-03-01,EUR,4.30\n2026-03-01,usd,3.95\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 2, imported)
+	assert.Equal(t, "USD", rates.rates[1].Currency)
+	assert.Equal(t, "csv", rates.rates[1].Source)
+
+	// Semicolon-separated files may use decimal commas
+	rates = &memoryExchangeRates{}
+	service = NewCurrencyService(rates, &memoryAppSettings{}, &memoryExchangeRateTxManager{rates: rates})
+	imported, err = service.ImportRatesCSV(ctx, []byte("2026-03-01;EUR;4,3012\n"))
+	require.NoError(t, err)
+	assert.Equal(t, 1, imported)
+	assert.Equal(t, "4.3012", rates.rates[0].Rate)
+
+	// One invalid row imports nothing
+	rates = &memoryExchangeRates{}
+	service = NewCurrencyService(rates, &memoryAppSettings{}, &memoryExchangeRateTxManager{rates: rates})
+	_, err = service.ImportRatesCSV(ctx, []byte("2026-03-01,EUR,4.30\n2026-03-02,EUR,-1\n"))
+	assert.ErrorContains(t, err, "line 2")
+	assert.Empty(t, rates.rates)
+
+	_, err = service.ImportRatesCSV(ctx, []byte("2026-03-01,PLN,1\n"))
+	assert.ErrorContains(t, err, "base currency")
+
+	// A row that cannot be saved leaves the stored rates as they were
+	rates = &memoryExchangeRates{rates: []models.ExchangeRate{
+		{Currency: "EUR", BaseCurrency: "PLN", RateDate: day("2026-03-01"), Rate: "4.30"},
+	}, failOn: "USD"}
+	service = NewCurrencyService(rates, &memoryAppSettings{}, &memoryExchangeRateTxManager{rates: rates})
+	_, err = service.ImportRatesCSV(ctx, []byte("2026-03-01,EUR,4.31\n2026-03-02,EUR,4.32\n2026-03-02,USD,3.95\n"))
+	assert.ErrorContains(t, err, "disk full")
+	assert.Equal(t, []models.ExchangeRate{{Currency: "EUR", BaseCurrency: "PLN", RateDate: day("2026-03-01"), Rate: "4.30"}}, rates.rates)
+}
@@ -65,7 +65,7 @@ func (s *ExportService) ExportBillsCSV(ctx context.Context, billType *string, fr
 
 	// Write bill rows
 	for _, bill := range bills {
-		amount := utils.DecimalStringToFloat(bill.TotalAmountPLN)
+		amount := utils.MoneyFromString(bill.TotalAmountPLN)
 
 		var units float64
 		if bill.TotalUnits != "" {
@@ -82,7 +82,7 @@ func (s *ExportService) ExportBillsCSV(ctx context.Context, billType *string, fr
 			bill.Type,
 			bill.PeriodStart.Format("2006-01-02"),
 			bill.PeriodEnd.Format("2006-01-02"),
-			fmt.Sprintf("%.2f", amount),
+			amount.String(),
 			fmt.Sprintf("%.3f", units),
 			bill.Status,
 			notes,
@@ -123,13 +123,12 @@ func (s *ExportService) ExportBalancesCSV(ctx context.Context) ([]byte, error) {
 	}
 
 	// Get payments for each loan
-	loanPaymentsMap := make(map[string]float64)
+	loanPaymentsMap := make(map[string]utils.Money)
 	for _, loan := range loans {
 		payments, err := s.loanPayments.ListByLoanID(ctx, loan.ID)
 		if err == nil {
 			for _, payment := range payments {
-				amount := utils.DecimalStringToFloat(payment.AmountPLN)
-				loanPaymentsMap[loan.ID] += amount
+				loanPaymentsMap[loan.ID] += utils.MoneyFromString(payment.AmountPLN)
 			}
 		}
 	}
@@ -149,7 +148,7 @@ func (s *ExportService) ExportBalancesCSV(ctx context.Context) ([]byte, error) {
 		lenderEmail := userMap[loan.LenderID]
 		borrowerEmail := userMap[loan.BorrowerID]
 
-		originalAmount := utils.DecimalStringToFloat(loan.AmountPLN)
+		originalAmount := utils.MoneyFromString(loan.AmountPLN)
 		paidAmount := loanPaymentsMap[loan.ID]
 		remaining := originalAmount - paidAmount
 
@@ -157,9 +156,9 @@ func (s *ExportService) ExportBalancesCSV(ctx context.Context) ([]byte, error) {
 			loan.ID,
 			lenderEmail,
 			borrowerEmail,
-			fmt.Sprintf("%.2f", originalAmount),
-			fmt.Sprintf("%.2f", paidAmount),
-			fmt.Sprintf("%.2f", remaining),
+			originalAmount.String(),
+			paidAmount.String(),
+			remaining.String(),
 			loan.Status,
 			loan.CreatedAt.Format("2006-01-02"),
 		}
@@ -13,16 +13,18 @@ import (
 )
 
 type GroupService struct {
-	groups      repository.GroupRepository
-	users       repository.UserRepository
-	allocations repository.AllocationRepository
+	groups        repository.GroupRepository
+	weightChanges repository.GroupWeightChangeRepository
+	users         repository.UserRepository
+	allocations   repository.AllocationRepository
 }
 
-func NewGroupService(groups repository.GroupRepository, users repository.UserRepository, allocations repository.AllocationRepository) *GroupService {
+func NewGroupService(groups repository.GroupRepository, weightChanges repository.GroupWeightChangeRepository, users repository.UserRepository, allocations repository.AllocationRepository) *GroupService {
 	return &GroupService{
-		groups:      groups,
-		users:       users,
-		allocations: allocations,
+		groups:        groups,
+		weightChanges: weightChanges,
+		users:         users,
+		allocations:   allocations,
 	}
 }
 
@@ -34,6 +36,8 @@ type CreateGroupRequest struct {
 type UpdateGroupRequest struct {
 	Name   *string  `json:"name,omitempty"`
 	Weight *float64 `json:"weight,omitempty"`
+	// Day the new weight applies from, today when not set. Bill periods before it keep the old weight.
+	WeightEffectiveFrom *time.Time `json:"weightEffectiveFrom,omitempty"`
 }
 
 // CreateGroup creates a new household group (ADMIN only)
@@ -79,11 +83,12 @@ func (s *GroupService) GetGroup(ctx context.Context, groupID string) (*models.Gr
 	return group, nil
 }
 
-// UpdateGroup updates a group (ADMIN only)
+// UpdateGroup updates a group (ADMIN only). A new weight is recorded as a change taking
+// effect on a day, so allocations of earlier bill periods keep the old weight.
 func (s *GroupService) UpdateGroup(ctx context.Context, groupID string, req UpdateGroupRequest) error {
 	// Get the existing group first
 	group, err := s.groups.GetByID(ctx, groupID)
-	if err != nil {
+	if err != nil || group == nil {
 		return errors.New("group not found")
 	}
 
@@ -98,6 +103,11 @@ func (s *GroupService) UpdateGroup(ctx context.Context, groupID string, req Upda
 		if *req.Weight <= 0 {
 			return errors.New("weight must be positive")
 		}
+		if *req.Weight != group.Weight {
+			if err := s.recordWeightChange(ctx, group, *req.Weight, req.WeightEffectiveFrom); err != nil {
+				return err
+			}
+		}
 		group.Weight = *req.Weight
 	}
 
@@ -108,6 +118,48 @@ func (s *GroupService) UpdateGroup(ctx context.Context, groupID string, req Upda
 	return nil
 }
 
+// recordWeightChange records the group's weight changing to newWeight from the effective day
+func (s *GroupService) recordWeightChange(ctx context.Context, group *models.Group, newWeight float64, effectiveFrom *time.Time) error {
+	day := residencyDay(time.Now())
+	if effectiveFrom != nil {
+		day = residencyDay(*effectiveFrom)
+	}
+
+	// Changes are chained by their old weight, so they can only be added in order
+	changes, err := s.weightChanges.ListByGroupID(ctx, group.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get weight history: %w", err)
+	}
+	if len(changes) > 0 && day.Before(changes[len(changes)-1].EffectiveFrom) {
+		return fmt.Errorf("weight change cannot take effect before the last one on %s",
+			changes[len(changes)-1].EffectiveFrom.Format("2006-01-02"))
+	}
+
+	change := models.GroupWeightChange{
+		ID:            uuid.New().String(),
+		GroupID:       group.ID,
+		OldWeight:     group.Weight,
+		NewWeight:     newWeight,
+		EffectiveFrom: day,
+		CreatedAt:     time.Now(),
+	}
+	if err := s.weightChanges.Create(ctx, &change); err != nil {
+		return fmt.Errorf("failed to record weight change: %w", err)
+	}
+
+	log.Printf("[INFO] Group %s weight changes from %g to %g on %s", group.ID, group.Weight, newWeight, day.Format("2006-01-02"))
+	return nil
+}
+
+// GetWeightHistory lists the weight changes of a group, oldest first
+func (s *GroupService) GetWeightHistory(ctx context.Context, groupID string) ([]models.GroupWeightChange, error) {
+	group, err := s.groups.GetByID(ctx, groupID)
+	if err != nil || group == nil {
+		return nil, errors.New("group not found")
+	}
+	return s.weightChanges.ListByGroupID(ctx, groupID)
+}
+
 // DeleteGroup deletes a group (ADMIN only)
 // Note: Should check if any users are still assigned to this group
 func (s *GroupService) DeleteGroup(ctx context.Context, groupID string) error {
@@ -0,0 +1,685 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/sainaif/holy-home/internal/utils"
+)
+
+// Ledger source types: the kind of record a ledger transaction was posted for
+const (
+	LedgerSourceBill               = "bill"         // allocations of a posted bill
+	LedgerSourcePayment            = "payment"      // bill payment
+	LedgerSourceLoan               = "loan"         // money lent between residents
+	LedgerSourceLoanPayment        = "loan_payment" // loan repayment
+	LedgerSourceSupplyContribution = "supply_contribution"
+	LedgerSourceSupplyPurchase     = "supply_purchase" // restock paid out of pocket, awaiting refund
+	LedgerSourceSupplyRefund       = "supply_refund"
+	LedgerSourceSupplyAdjustment   = "supply_adjustment"
+	LedgerSourceSettlementTransfer = "settlement_transfer" // transfer carrying out a settlement plan
+)
+
+// LedgerSourceTypes lists every valid ledger source type
+var LedgerSourceTypes = []string{
+	LedgerSourceBill,
+	LedgerSourcePayment,
+	LedgerSourceLoan,
+	LedgerSourceLoanPayment,
+	LedgerSourceSupplyContribution,
+	LedgerSourceSupplyPurchase,
+	LedgerSourceSupplyRefund,
+	LedgerSourceSupplyAdjustment,
+	LedgerSourceSettlementTransfer,
+}
+
+// Household accounts. "bills" is owed the unpaid part of posted bills, "supplies" is
+// the supply budget (a negative balance is money it holds for residents) and
+// "adjustments" balances manual budget corrections.
+const (
+	LedgerAccountHousehold   = "household"
+	ledgerHouseholdBills     = "bills"
+	ledgerHouseholdSupplies  = "supplies"
+	ledgerHouseholdAdjusting = "adjustments"
+)
+
+// LedgerAccount identifies a ledger account
+type LedgerAccount struct {
+	Type string `json:"accountType"` // user, group, household
+	ID   string `json:"accountId"`
+}
+
+// LedgerPosting is one account's side of a ledger transaction
+type LedgerPosting struct {
+	Account LedgerAccount
+	Amount  utils.Money
+}
+
+// ledgerRecord is what a source record moves between accounts
+type ledgerRecord struct {
+	sourceType  string
+	sourceID    string
+	description string
+	occurredAt  time.Time
+	postings    []LedgerPosting
+}
+
+// reversal returns the record with nothing left to move, so syncing it reverses the source
+func (r ledgerRecord) reversal() ledgerRecord {
+	r.postings = nil
+	return r
+}
+
+func userLedgerAccount(userID string) LedgerAccount {
+	return LedgerAccount{Type: "user", ID: userID}
+}
+
+func householdLedgerAccount(id string) LedgerAccount {
+	return LedgerAccount{Type: LedgerAccountHousehold, ID: id}
+}
+
+// postLedger appends a transaction for an event, such as a supply refund, that can happen
+// more than once for the same source
+func postLedger(ctx context.Context, entries repository.LedgerEntryRepository, record ledgerRecord) error {
+	if err := validateLedgerPostings(record.postings); err != nil {
+		return err
+	}
+	return entries.CreateBatch(ctx, ledgerTransaction(record, record.postings))
+}
+
+// syncLedger brings the ledger in line with the current state of a source record by
+// posting the difference between what the record moves now and what was already posted
+// for it. Posting an unchanged record is a no-op; a record without postings, e.g. a
+// deleted one, is reversed.
+func syncLedger(ctx context.Context, entries repository.LedgerEntryRepository, record ledgerRecord) error {
+	if err := validateLedgerPostings(record.postings); err != nil {
+		return err
+	}
+
+	existing, err := entries.ListBySource(ctx, record.sourceType, record.sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to read ledger: %w", err)
+	}
+
+	delta := make(map[LedgerAccount]utils.Money)
+	for _, entry := range existing {
+		delta[LedgerAccount{Type: entry.AccountType, ID: entry.AccountID}] -= utils.MoneyFromString(entry.AmountPLN)
+	}
+	for _, posting := range record.postings {
+		delta[posting.Account] += posting.Amount
+	}
+
+	var postings []LedgerPosting
+	for account, amount := range delta {
+		if amount != 0 {
+			postings = append(postings, LedgerPosting{Account: account, Amount: amount})
+		}
+	}
+	if len(postings) == 0 {
+		return nil
+	}
+
+	if len(record.postings) == 0 {
+		record.description = "Storno: " + record.description
+		record.occurredAt = time.Now()
+	} else if len(existing) > 0 {
+		record.description = "Korekta: " + record.description
+		record.occurredAt = time.Now()
+	}
+
+	return entries.CreateBatch(ctx, ledgerTransaction(record, postings))
+}
+
+func validateLedgerPostings(postings []LedgerPosting) error {
+	var sum utils.Money
+	for _, posting := range postings {
+		sum += posting.Amount
+	}
+	if sum != 0 {
+		return fmt.Errorf("unbalanced ledger transaction: postings sum to %s", sum)
+	}
+	return nil
+}
+
+func ledgerTransaction(record ledgerRecord, postings []LedgerPosting) []models.LedgerEntry {
+	sort.Slice(postings, func(i, j int) bool {
+		if postings[i].Account.Type != postings[j].Account.Type {
+			return postings[i].Account.Type < postings[j].Account.Type
+		}
+		return postings[i].Account.ID < postings[j].Account.ID
+	})
+
+	transactionID := uuid.New().String()
+	entries := make([]models.LedgerEntry, 0, len(postings))
+	for _, posting := range postings {
+		if posting.Amount == 0 {
+			continue
+		}
+		entries = append(entries, models.LedgerEntry{
+			ID:            uuid.New().String(),
+			TransactionID: transactionID,
+			AccountType:   posting.Account.Type,
+			AccountID:     posting.Account.ID,
+			AmountPLN:     posting.Amount.String(),
+			SourceType:    record.sourceType,
+			SourceID:      record.sourceID,
+			Description:   record.description,
+			OccurredAt:    record.occurredAt,
+		})
+	}
+	return entries
+}
+
+// billLedgerRecord charges every allocation of a posted bill; a draft bill charges nothing
+func billLedgerRecord(bill *models.Bill, allocations []AllocationBreakdown) ledgerRecord {
+	record := ledgerRecord{
+		sourceType:  LedgerSourceBill,
+		sourceID:    bill.ID,
+		description: fmt.Sprintf("Rachunek %s %s", billTypeLabel(bill), bill.PeriodStart.Format("2006-01")),
+		occurredAt:  bill.CreatedAt,
+	}
+	if bill.Status == "draft" {
+		return record
+	}
+
+	var total utils.Money
+	for _, alloc := range allocations {
+		if alloc.Amount == 0 {
+			continue
+		}
+		record.postings = append(record.postings, LedgerPosting{
+			Account: LedgerAccount{Type: alloc.SubjectType, ID: alloc.SubjectID},
+			Amount:  -alloc.Amount,
+		})
+		total += alloc.Amount
+	}
+	if total != 0 {
+		record.postings = append(record.postings, LedgerPosting{Account: householdLedgerAccount(ledgerHouseholdBills), Amount: total})
+	}
+	return record
+}
+
+func billTypeLabel(bill *models.Bill) string {
+	if bill.CustomType != nil && *bill.CustomType != "" {
+		return *bill.CustomType
+	}
+	return bill.Type
+}
+
+// paymentLedgerRecord credits the payer for a bill payment
+func paymentLedgerRecord(payment *models.Payment) ledgerRecord {
+	amount := utils.MoneyFromString(payment.AmountPLN)
+	return ledgerRecord{
+		sourceType:  LedgerSourcePayment,
+		sourceID:    payment.ID,
+		description: "Płatność za rachunek",
+		occurredAt:  payment.PaidAt,
+		postings: []LedgerPosting{
+			{Account: userLedgerAccount(payment.PayerUserID), Amount: amount},
+			{Account: householdLedgerAccount(ledgerHouseholdBills), Amount: -amount},
+		},
+	}
+}
+
+// loanLedgerRecord makes the borrower owe the lender the loan amount
+func loanLedgerRecord(loan *models.Loan) ledgerRecord {
+	amount := utils.MoneyFromString(loan.AmountPLN)
+	description := "Pożyczka"
+	if loan.Note != nil && *loan.Note != "" {
+		description = "Pożyczka: " + *loan.Note
+	}
+	return ledgerRecord{
+		sourceType:  LedgerSourceLoan,
+		sourceID:    loan.ID,
+		description: description,
+		occurredAt:  loan.CreatedAt,
+		postings: []LedgerPosting{
+			{Account: userLedgerAccount(loan.LenderID), Amount: amount},
+			{Account: userLedgerAccount(loan.BorrowerID), Amount: -amount},
+		},
+	}
+}
+
+// loanPaymentLedgerRecord moves a loan repayment from the borrower to the lender. A payment
+// that a settlement made moves nothing: the money went through the settlement's transfers.
+func loanPaymentLedgerRecord(loan *models.Loan, payment *models.LoanPayment) ledgerRecord {
+	amount := utils.MoneyFromString(payment.AmountPLN)
+	description := "Spłata pożyczki"
+	if payment.Note != nil && *payment.Note != "" {
+		description = "Spłata pożyczki: " + *payment.Note
+	}
+	record := ledgerRecord{
+		sourceType:  LedgerSourceLoanPayment,
+		sourceID:    payment.ID,
+		description: description,
+		occurredAt:  payment.PaidAt,
+	}
+	if payment.SettlementFingerprint != nil {
+		return record
+	}
+	record.postings = []LedgerPosting{
+		{Account: userLedgerAccount(loan.BorrowerID), Amount: amount},
+		{Account: userLedgerAccount(loan.LenderID), Amount: -amount},
+	}
+	return record
+}
+
+// settlementTransferLedgerRecord moves a settlement transfer from the payer to the payee
+func settlementTransferLedgerRecord(transfer *models.SettlementTransfer) ledgerRecord {
+	amount := utils.MoneyFromString(transfer.AmountPLN)
+	return ledgerRecord{
+		sourceType:  LedgerSourceSettlementTransfer,
+		sourceID:    transfer.ID,
+		description: "Rozliczenie",
+		occurredAt:  transfer.CreatedAt,
+		postings: []LedgerPosting{
+			{Account: userLedgerAccount(transfer.FromUserID), Amount: amount},
+			{Account: userLedgerAccount(transfer.ToUserID), Amount: -amount},
+		},
+	}
+}
+
+// supplyContributionLedgerRecord moves a contribution into the supply budget
+func supplyContributionLedgerRecord(contribution *models.SupplyContribution) ledgerRecord {
+	amount := utils.MoneyFromString(contribution.AmountPLN)
+	return ledgerRecord{
+		sourceType:  LedgerSourceSupplyContribution,
+		sourceID:    contribution.ID,
+		description: "Składka na zapasy",
+		occurredAt:  contribution.CreatedAt,
+		postings: []LedgerPosting{
+			{Account: userLedgerAccount(contribution.UserID), Amount: amount},
+			{Account: householdLedgerAccount(ledgerHouseholdSupplies), Amount: -amount},
+		},
+	}
+}
+
+// supplyPurchaseLedgerRecord makes the supply budget owe a resident for a restock they paid for
+func supplyPurchaseLedgerRecord(item *models.SupplyItem, userID string, amount utils.Money, at time.Time) ledgerRecord {
+	return ledgerRecord{
+		sourceType:  LedgerSourceSupplyPurchase,
+		sourceID:    item.ID,
+		description: "Zakup do zwrotu: " + item.Name,
+		occurredAt:  at,
+		postings: []LedgerPosting{
+			{Account: userLedgerAccount(userID), Amount: amount},
+			{Account: householdLedgerAccount(ledgerHouseholdSupplies), Amount: -amount},
+		},
+	}
+}
+
+// supplyRefundLedgerRecord pays a resident back out of the supply budget
+func supplyRefundLedgerRecord(item *models.SupplyItem, userID string, amount utils.Money, at time.Time) ledgerRecord {
+	return ledgerRecord{
+		sourceType:  LedgerSourceSupplyRefund,
+		sourceID:    item.ID,
+		description: "Zwrot za zakup: " + item.Name,
+		occurredAt:  at,
+		postings: []LedgerPosting{
+			{Account: userLedgerAccount(userID), Amount: -amount},
+			{Account: householdLedgerAccount(ledgerHouseholdSupplies), Amount: amount},
+		},
+	}
+}
+
+// supplyAdjustmentLedgerRecord records a manual change of the supply budget
+func supplyAdjustmentLedgerRecord(settingsID string, adjustment utils.Money, notes string, at time.Time) ledgerRecord {
+	description := "Korekta budżetu zapasów"
+	if notes != "" {
+		description += ": " + notes
+	}
+	return ledgerRecord{
+		sourceType:  LedgerSourceSupplyAdjustment,
+		sourceID:    settingsID,
+		description: description,
+		occurredAt:  at,
+		postings: []LedgerPosting{
+			{Account: householdLedgerAccount(ledgerHouseholdSupplies), Amount: -adjustment},
+			{Account: householdLedgerAccount(ledgerHouseholdAdjusting), Amount: adjustment},
+		},
+	}
+}
+
+// LedgerService reads balances and statements from the ledger
+type LedgerService struct {
+	entries   repository.LedgerEntryRepository
+	users     repository.UserRepository
+	groups    repository.GroupRepository
+	txManager repository.TxManager
+}
+
+func NewLedgerService(
+	entries repository.LedgerEntryRepository,
+	users repository.UserRepository,
+	groups repository.GroupRepository,
+	txManager repository.TxManager,
+) *LedgerService {
+	return &LedgerService{
+		entries:   entries,
+		users:     users,
+		groups:    groups,
+		txManager: txManager,
+	}
+}
+
+// LedgerBalance is the balance of one ledger account
+type LedgerBalance struct {
+	AccountType string      `json:"accountType"`
+	AccountID   string      `json:"accountId"`
+	Name        string      `json:"name"`
+	Balance     utils.Money `json:"balance"` // positive: the account is owed money, negative: it owes money
+}
+
+// LedgerStatementFilter narrows the lines of a statement
+type LedgerStatementFilter struct {
+	From        *time.Time
+	To          *time.Time
+	SourceTypes []string
+}
+
+// LedgerStatementLine is one ledger entry with the account balance after it
+type LedgerStatementLine struct {
+	models.LedgerEntry
+	Amount         utils.Money `json:"amount"`
+	RunningBalance utils.Money `json:"runningBalance"`
+}
+
+// LedgerStatement lists the entries of an account over a period
+type LedgerStatement struct {
+	AccountType    string                `json:"accountType"`
+	AccountID      string                `json:"accountId"`
+	Name           string                `json:"name"`
+	From           *time.Time            `json:"from,omitempty"`
+	To             *time.Time            `json:"to,omitempty"`
+	SourceTypes    []string              `json:"sourceTypes,omitempty"`
+	OpeningBalance utils.Money           `json:"openingBalance"` // balance before the first day of the period
+	ClosingBalance utils.Money           `json:"closingBalance"` // balance at the end of the period
+	TotalIn        utils.Money           `json:"totalIn"`        // sum of listed positive amounts
+	TotalOut       utils.Money           `json:"totalOut"`       // sum of listed negative amounts
+	Lines          []LedgerStatementLine `json:"lines"`
+}
+
+// GetBalances returns the balance of every ledger account with a non-zero balance
+func (s *LedgerService) GetBalances(ctx context.Context) ([]LedgerBalance, error) {
+	entries, err := s.entries.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ledger: %w", err)
+	}
+
+	totals := make(map[LedgerAccount]utils.Money)
+	for _, entry := range entries {
+		totals[LedgerAccount{Type: entry.AccountType, ID: entry.AccountID}] += utils.MoneyFromString(entry.AmountPLN)
+	}
+
+	balances := make([]LedgerBalance, 0, len(totals))
+	for account, total := range totals {
+		if total == 0 {
+			continue
+		}
+		balances = append(balances, LedgerBalance{
+			AccountType: account.Type,
+			AccountID:   account.ID,
+			Name:        s.accountName(ctx, account),
+			Balance:     total,
+		})
+	}
+	sort.Slice(balances, func(i, j int) bool {
+		if balances[i].AccountType != balances[j].AccountType {
+			return balances[i].AccountType < balances[j].AccountType
+		}
+		return balances[i].Name < balances[j].Name
+	})
+
+	return balances, nil
+}
+
+// GetStatement returns an account's entries with running balances. Running balances
+// always include every entry of the account, so filtering by source type only hides lines.
+func (s *LedgerService) GetStatement(ctx context.Context, account LedgerAccount, filter LedgerStatementFilter) (*LedgerStatement, error) {
+	if account.Type != "user" && account.Type != "group" && account.Type != LedgerAccountHousehold {
+		return nil, errors.New("account type must be user, group or household")
+	}
+	if filter.From != nil && filter.To != nil && filter.To.Before(*filter.From) {
+		return nil, errors.New("'to' must not be before 'from'")
+	}
+
+	sources := make(map[string]bool, len(filter.SourceTypes))
+	for _, sourceType := range filter.SourceTypes {
+		valid := false
+		for _, known := range LedgerSourceTypes {
+			if sourceType == known {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			return nil, fmt.Errorf("unknown source type: %s", sourceType)
+		}
+		sources[sourceType] = true
+	}
+
+	entries, err := s.entries.ListByAccount(ctx, account.Type, account.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ledger: %w", err)
+	}
+
+	statement := &LedgerStatement{
+		AccountType: account.Type,
+		AccountID:   account.ID,
+		Name:        s.accountName(ctx, account),
+		From:        filter.From,
+		To:          filter.To,
+		SourceTypes: filter.SourceTypes,
+		Lines:       []LedgerStatementLine{},
+	}
+
+	var balance utils.Money
+	for _, entry := range entries {
+		if filter.To != nil && entry.OccurredAt.After(*filter.To) {
+			break
+		}
+
+		amount := utils.MoneyFromString(entry.AmountPLN)
+		balance += amount
+
+		if filter.From != nil && entry.OccurredAt.Before(*filter.From) {
+			statement.OpeningBalance = balance
+			continue
+		}
+		if len(sources) > 0 && !sources[entry.SourceType] {
+			continue
+		}
+
+		statement.Lines = append(statement.Lines, LedgerStatementLine{
+			LedgerEntry:    entry,
+			Amount:         amount,
+			RunningBalance: balance,
+		})
+		if amount > 0 {
+			statement.TotalIn += amount
+		} else {
+			statement.TotalOut += amount
+		}
+	}
+	statement.ClosingBalance = balance
+
+	return statement, nil
+}
+
+func (s *LedgerService) accountName(ctx context.Context, account LedgerAccount) string {
+	switch account.Type {
+	case "user":
+		if user, err := s.users.GetByID(ctx, account.ID); err == nil && user != nil {
+			return user.Name
+		}
+	case "group":
+		if group, err := s.groups.GetByID(ctx, account.ID); err == nil && group != nil {
+			return group.Name
+		}
+	case LedgerAccountHousehold:
+		switch account.ID {
+		case ledgerHouseholdBills:
+			return "Rachunki"
+		case ledgerHouseholdSupplies:
+			return "Budżet zapasów"
+		case ledgerHouseholdAdjusting:
+			return "Korekty"
+		}
+	}
+	return account.ID
+}
+
+// Backfill posts every existing bill, payment, loan, settlement transfer, contribution and
+// pending supply refund to an empty ledger, so databases created before the ledger start
+// balanced. It does nothing once the ledger has entries. The backfill is a single
+// transaction: if any bill cannot be allocated, every such bill is reported and nothing
+// is posted, so the next start tries again once they are fixed.
+func (s *LedgerService) Backfill(ctx context.Context) error {
+	count, err := s.entries.Count(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to count ledger entries: %w", err)
+	}
+	if count > 0 {
+		return nil
+	}
+
+	records := 0
+	err = s.txManager.WithTx(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		records, err = backfillLedger(ctx, repos)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+
+	if records > 0 {
+		log.Printf("[LEDGER] Backfilled ledger from %d existing records", records)
+	}
+	return nil
+}
+
+// backfillLedger posts every existing record with the given repositories and returns how
+// many records were posted. Bills that cannot be allocated do not stop the backfill; they
+// are collected and returned together as its error.
+func backfillLedger(ctx context.Context, repos *repository.Repositories) (int, error) {
+	allocationService := NewAllocationService(repos.Users, repos.Groups, repos.Residencies, repos.GroupWeightChanges, repos.GroupMembershipChanges, repos.Consumptions, repos.Meters, repos.MeterReplacements, repos.Tariffs, repos.Allocations, repos.Bills)
+	records := 0
+	post := func(record ledgerRecord) error {
+		if len(record.postings) == 0 {
+			return nil
+		}
+		records++
+		return syncLedger(ctx, repos.LedgerEntries, record)
+	}
+
+	bills, err := repos.Bills.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch bills: %w", err)
+	}
+	var unallocated []error
+	for i := range bills {
+		bill := &bills[i]
+		if bill.Status == "draft" {
+			continue
+		}
+		breakdown, err := allocationService.GetAllocationBreakdown(ctx, bill.ID)
+		if err != nil {
+			unallocated = append(unallocated, fmt.Errorf("bill %s (%s %s): %w", bill.ID, billTypeLabel(bill), bill.PeriodStart.Format("2006-01"), err))
+			continue
+		}
+		if err := post(billLedgerRecord(bill, breakdown)); err != nil {
+			return 0, err
+		}
+	}
+
+	payments, err := repos.Payments.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch payments: %w", err)
+	}
+	for i := range payments {
+		if err := post(paymentLedgerRecord(&payments[i])); err != nil {
+			return 0, err
+		}
+	}
+
+	loans, err := repos.Loans.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch loans: %w", err)
+	}
+	for i := range loans {
+		loan := &loans[i]
+		if err := post(loanLedgerRecord(loan)); err != nil {
+			return 0, err
+		}
+		loanPayments, err := repos.LoanPayments.ListByLoanID(ctx, loan.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch loan payments: %w", err)
+		}
+		for j := range loanPayments {
+			if err := post(loanPaymentLedgerRecord(loan, &loanPayments[j])); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	transfers, err := repos.Settlements.ListTransfers(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch settlement transfers: %w", err)
+	}
+	for i := range transfers {
+		if err := post(settlementTransferLedgerRecord(&transfers[i])); err != nil {
+			return 0, err
+		}
+	}
+
+	users, err := repos.Users.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch users: %w", err)
+	}
+	for _, user := range users {
+		contributions, err := repos.SupplyContributions.ListByUserID(ctx, user.ID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch contributions: %w", err)
+		}
+		for i := range contributions {
+			if err := post(supplyContributionLedgerRecord(&contributions[i])); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	items, err := repos.SupplyItems.List(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch supply items: %w", err)
+	}
+	for i := range items {
+		item := &items[i]
+		if !item.NeedsRefund || item.LastRestockAmountPLN == nil || item.LastRestockedByUserID == nil {
+			continue
+		}
+		at := item.AddedAt
+		if item.LastRestockedAt != nil {
+			at = *item.LastRestockedAt
+		}
+		amount := utils.MoneyFromString(*item.LastRestockAmountPLN)
+		if amount <= 0 {
+			continue
+		}
+		records++
+		if err := postLedger(ctx, repos.LedgerEntries, supplyPurchaseLedgerRecord(item, *item.LastRestockedByUserID, amount, at)); err != nil {
+			return 0, err
+		}
+	}
+
+	if len(unallocated) > 0 {
+		return 0, fmt.Errorf("ledger backfill rolled back, %d bills cannot be allocated: %w", len(unallocated), errors.Join(unallocated...))
+	}
+	return records, nil
+}
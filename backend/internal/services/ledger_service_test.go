@@ -0,0 +1,266 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/sainaif/holy-home/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryLedgerEntries is an in-memory ledger entry repository
+type memoryLedgerEntries struct {
+	repository.LedgerEntryRepository
+	entries []models.LedgerEntry
+}
+
+func (m *memoryLedgerEntries) CreateBatch(ctx context.Context, entries []models.LedgerEntry) error {
+	m.entries = append(m.entries, entries...)
+	return nil
+}
+
+func (m *memoryLedgerEntries) ListByAccount(ctx context.Context, accountType, accountID string) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	for _, entry := range m.entries {
+		if entry.AccountType == accountType && entry.AccountID == accountID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (m *memoryLedgerEntries) ListBySource(ctx context.Context, sourceType, sourceID string) ([]models.LedgerEntry, error) {
+	var entries []models.LedgerEntry
+	for _, entry := range m.entries {
+		if entry.SourceType == sourceType && entry.SourceID == sourceID {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+type memoryUsers struct {
+	repository.UserRepository
+	users []models.User
+}
+
+func (m *memoryUsers) GetByID(ctx context.Context, id string) (*models.User, error) {
+	for _, user := range m.users {
+		if user.ID == id {
+			return &user, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryLedgerEntries) balance(accountType, accountID string) utils.Money {
+	var total utils.Money
+	for _, entry := range m.entries {
+		if entry.AccountType == accountType && entry.AccountID == accountID {
+			total += utils.MoneyFromString(entry.AmountPLN)
+		}
+	}
+	return total
+}
+
+func TestSyncLedger(t *testing.T) {
+	ctx := context.Background()
+	ledger := &memoryLedgerEntries{}
+	loan := &models.Loan{ID: "loan-1", LenderID: "anna", BorrowerID: "bartek", AmountPLN: "120.00", CreatedAt: time.Now()}
+
+	require.NoError(t, syncLedger(ctx, ledger, loanLedgerRecord(loan)))
+	assert.Len(t, ledger.entries, 2)
+	assert.Equal(t, utils.MoneyFromString("120"), ledger.balance("user", "anna"))
+	assert.Equal(t, utils.MoneyFromString("-120"), ledger.balance("user", "bartek"))
+
+	// Posting the same record again changes nothing
+	require.NoError(t, syncLedger(ctx, ledger, loanLedgerRecord(loan)))
+	assert.Len(t, ledger.entries, 2)
+
+	// A changed record is corrected by its difference
+	loan.AmountPLN = "100.00"
+	require.NoError(t, syncLedger(ctx, ledger, loanLedgerRecord(loan)))
+	assert.Len(t, ledger.entries, 4)
+	assert.Equal(t, utils.MoneyFromString("100"), ledger.balance("user", "anna"))
+	assert.Equal(t, utils.MoneyFromString("-20"), utils.MoneyFromString(ledger.entries[2].AmountPLN))
+
+	// A reversal brings every account back to zero
+	require.NoError(t, syncLedger(ctx, ledger, loanLedgerRecord(loan).reversal()))
+	assert.Zero(t, ledger.balance("user", "anna").Minor())
+	assert.Zero(t, ledger.balance("user", "bartek").Minor())
+	assert.Contains(t, ledger.entries[len(ledger.entries)-1].Description, "Storno")
+}
+
+func TestSyncLedger_RejectsUnbalancedTransaction(t *testing.T) {
+	ledger := &memoryLedgerEntries{}
+	err := syncLedger(context.Background(), ledger, ledgerRecord{
+		sourceType: LedgerSourceLoan,
+		sourceID:   "loan-1",
+		postings:   []LedgerPosting{{Account: userLedgerAccount("anna"), Amount: utils.MoneyFromString("10")}},
+	})
+	assert.Error(t, err)
+	assert.Empty(t, ledger.entries)
+}
+
+func TestBillLedgerRecord(t *testing.T) {
+	bill := &models.Bill{ID: "bill-1", Type: "internet", Status: "posted"}
+	allocations := []AllocationBreakdown{
+		{SubjectType: "user", SubjectID: "anna", Amount: utils.MoneyFromString("33.34")},
+		{SubjectType: "group", SubjectID: "couple", Amount: utils.MoneyFromString("66.66")},
+	}
+
+	record := billLedgerRecord(bill, allocations)
+	require.NoError(t, validateLedgerPostings(record.postings))
+	assert.Len(t, record.postings, 3)
+
+	bill.Status = "draft"
+	assert.Empty(t, billLedgerRecord(bill, allocations).postings)
+}
+
+func TestGetStatement(t *testing.T) {
+	ctx := context.Background()
+	ledger := &memoryLedgerEntries{}
+	day := func(d int) time.Time { return time.Date(2026, 3, d, 12, 0, 0, 0, time.UTC) }
+
+	loan := &models.Loan{ID: "loan-1", LenderID: "anna", BorrowerID: "bartek", AmountPLN: "100.00", CreatedAt: day(1)}
+	require.NoError(t, syncLedger(ctx, ledger, loanLedgerRecord(loan)))
+	require.NoError(t, syncLedger(ctx, ledger, paymentLedgerRecord(&models.Payment{ID: "pay-1", PayerUserID: "anna", AmountPLN: "50.00", PaidAt: day(10)})))
+	require.NoError(t, syncLedger(ctx, ledger, loanPaymentLedgerRecord(loan, &models.LoanPayment{ID: "lp-1", LoanID: loan.ID, AmountPLN: "30.00", PaidAt: day(20)})))
+
+	service := &LedgerService{entries: ledger, users: &memoryUsers{users: []models.User{{ID: "anna", Name: "Anna"}}}}
+	from, to := day(5), day(25)
+
+	statement, err := service.GetStatement(ctx, userLedgerAccount("anna"), LedgerStatementFilter{From: &from, To: &to})
+	require.NoError(t, err)
+	assert.Equal(t, "Anna", statement.Name)
+	assert.Equal(t, utils.MoneyFromString("100"), statement.OpeningBalance)
+	assert.Equal(t, utils.MoneyFromString("120"), statement.ClosingBalance)
+	require.Len(t, statement.Lines, 2)
+	assert.Equal(t, utils.MoneyFromString("150"), statement.Lines[0].RunningBalance)
+	assert.Equal(t, utils.MoneyFromString("120"), statement.Lines[1].RunningBalance)
+	assert.Equal(t, utils.MoneyFromString("50"), statement.TotalIn)
+	assert.Equal(t, utils.MoneyFromString("-30"), statement.TotalOut)
+
+	// Filtering by source hides lines but keeps the account's running balance
+	statement, err = service.GetStatement(ctx, userLedgerAccount("anna"), LedgerStatementFilter{SourceTypes: []string{LedgerSourceLoanPayment}})
+	require.NoError(t, err)
+	require.Len(t, statement.Lines, 1)
+	assert.Equal(t, utils.MoneyFromString("120"), statement.Lines[0].RunningBalance)
+
+	_, err = service.GetStatement(ctx, userLedgerAccount("anna"), LedgerStatementFilter{SourceTypes: []string{"bogus"}})
+	assert.Error(t, err)
+}
+
+func (m *memoryLedgerEntries) Count(ctx context.Context) (int, error) {
+	return len(m.entries), nil
+}
+
+// memoryLedgerTxManager runs work against fixed repositories and drops the bills,
+// allocations and ledger entries written by work that fails
+type memoryLedgerTxManager struct {
+	repos *repository.Repositories
+}
+
+func (m *memoryLedgerTxManager) WithTx(ctx context.Context, fn func(ctx context.Context, repos *repository.Repositories) error) error {
+	ledger := m.repos.LedgerEntries.(*memoryLedgerEntries)
+	entries := append([]models.LedgerEntry(nil), ledger.entries...)
+	bills, _ := m.repos.Bills.(*memoryBills)
+	var billRows []models.Bill
+	if bills != nil {
+		billRows = append(billRows, bills.bills...)
+	}
+	allocations, _ := m.repos.Allocations.(*memoryAllocations)
+	var allocationRows []repository.Allocation
+	if allocations != nil {
+		allocationRows = append(allocationRows, allocations.allocations...)
+	}
+
+	if err := fn(ctx, m.repos); err != nil {
+		ledger.entries = entries
+		if bills != nil {
+			bills.bills = billRows
+		}
+		if allocations != nil {
+			allocations.allocations = allocationRows
+		}
+		return err
+	}
+	return nil
+}
+
+func (m *memoryBills) List(ctx context.Context) ([]models.Bill, error) {
+	return m.bills, nil
+}
+
+func (m *memoryPayments) List(ctx context.Context) ([]models.Payment, error) {
+	return m.payments, nil
+}
+
+func (m *memoryLoans) List(ctx context.Context) ([]models.Loan, error) {
+	return m.loans, nil
+}
+
+type memorySupplyContributions struct {
+	repository.SupplyContributionRepository
+	contributions []models.SupplyContribution
+}
+
+func (m *memorySupplyContributions) ListByUserID(ctx context.Context, userID string) ([]models.SupplyContribution, error) {
+	var contributions []models.SupplyContribution
+	for _, contribution := range m.contributions {
+		if contribution.UserID == userID {
+			contributions = append(contributions, contribution)
+		}
+	}
+	return contributions, nil
+}
+
+type memorySupplyItems struct {
+	repository.SupplyItemRepository
+	items []models.SupplyItem
+}
+
+func (m *memorySupplyItems) List(ctx context.Context) ([]models.SupplyItem, error) {
+	return m.items, nil
+}
+
+func TestBackfill_IsAllOrNothing(t *testing.T) {
+	ctx := context.Background()
+	metered := "metered"
+	bills := &memoryBills{bills: []models.Bill{
+		{ID: "internet", Type: "internet", Status: "posted", TotalAmountPLN: "100.00"},
+		// A metered bill without units cannot be allocated
+		{ID: "power", Type: "electricity", AllocationType: &metered, Status: "posted", TotalAmountPLN: "200.00"},
+	}}
+	ledger := &memoryLedgerEntries{}
+	repos := &repository.Repositories{
+		Users:               &memoryUsers{users: []models.User{{ID: "anna", Name: "Anna", IsActive: true}}},
+		Groups:              &memoryGroups{},
+		Bills:               bills,
+		Tariffs:             &memoryTariffs{},
+		Allocations:         &memoryAllocations{allocations: []repository.Allocation{{BillID: "internet", SubjectType: "user", SubjectID: "anna", AllocatedPLN: "100.00"}}},
+		Payments:            &memoryPayments{payments: []models.Payment{{ID: "pay-1", BillID: "internet", PayerUserID: "anna", AmountPLN: "100.00"}}},
+		Loans:               &memoryLoans{},
+		SupplyContributions: &memorySupplyContributions{contributions: []models.SupplyContribution{{ID: "c1", UserID: "anna", AmountPLN: "50.00"}}},
+		SupplyItems:         &memorySupplyItems{},
+		Settlements:         &memorySettlements{},
+		LedgerEntries:       ledger,
+	}
+	service := NewLedgerService(ledger, repos.Users, repos.Groups, &memoryLedgerTxManager{repos: repos})
+
+	err := service.Backfill(ctx)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "bill power")
+	assert.Empty(t, ledger.entries, "nothing is posted while a bill cannot be allocated")
+
+	// Once the bill is fixed the next start backfills everything
+	bills.bills[1].Status = "draft"
+	require.NoError(t, service.Backfill(ctx))
+	assert.Equal(t, utils.MoneyFromString("50"), ledger.balance("user", "anna"))
+	assert.Zero(t, ledger.balance("household", "bills").Minor())
+	assert.Equal(t, utils.MoneyFromString("-50"), ledger.balance("household", "supplies"))
+}
@@ -19,7 +19,10 @@ type LoanService struct {
 	loanPayments        repository.LoanPaymentRepository
 	users               repository.UserRepository
 	groups              repository.GroupRepository
+	ledgerEntries       repository.LedgerEntryRepository
+	currencyService     *CurrencyService
 	notificationService *NotificationService
+	txManager           repository.TxManager
 }
 
 func NewLoanService(
@@ -27,41 +30,62 @@ func NewLoanService(
 	loanPayments repository.LoanPaymentRepository,
 	users repository.UserRepository,
 	groups repository.GroupRepository,
+	ledgerEntries repository.LedgerEntryRepository,
+	currencyService *CurrencyService,
 	notificationService *NotificationService,
+	txManager repository.TxManager,
 ) *LoanService {
 	return &LoanService{
 		loans:               loans,
 		loanPayments:        loanPayments,
 		users:               users,
 		groups:              groups,
+		ledgerEntries:       ledgerEntries,
+		currencyService:     currencyService,
 		notificationService: notificationService,
+		txManager:           txManager,
 	}
 }
 
+// inTx runs fn with a copy of the service bound to the transaction's repositories, so
+// loans, their payments and their ledger entries are written together or not at all
+func (s *LoanService) inTx(ctx context.Context, fn func(ctx context.Context, tx *LoanService) error) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		tx := *s
+		tx.loans = repos.Loans
+		tx.loanPayments = repos.LoanPayments
+		tx.users = repos.Users
+		tx.groups = repos.Groups
+		tx.ledgerEntries = repos.LedgerEntries
+		return fn(ctx, &tx)
+	})
+}
+
 type CreateLoanRequest struct {
-	LenderID   string     `json:"lenderId"`
-	BorrowerID string     `json:"borrowerId"`
-	AmountPLN  float64    `json:"amountPLN"`
-	Note       *string    `json:"note,omitempty"`
-	DueDate    *time.Time `json:"dueDate,omitempty"`
+	LenderID   string      `json:"lenderId"`
+	BorrowerID string      `json:"borrowerId"`
+	AmountPLN  utils.Money `json:"amountPLN"`          // in Currency
+	Currency   string      `json:"currency,omitempty"` // defaults to the base currency
+	Note       *string     `json:"note,omitempty"`
+	DueDate    *time.Time  `json:"dueDate,omitempty"`
 }
 
 type CreateLoanPaymentRequest struct {
-	LoanID    string    `json:"loanId"`
-	AmountPLN float64   `json:"amountPLN"`
-	PaidAt    time.Time `json:"paidAt"`
-	Note      *string   `json:"note,omitempty"`
+	LoanID    string      `json:"loanId"`
+	AmountPLN utils.Money `json:"amountPLN"`
+	PaidAt    time.Time   `json:"paidAt"`
+	Note      *string     `json:"note,omitempty"`
 }
 
 type CompensationResult struct {
-	CompensationsPerformed int     `json:"compensationsPerformed"`
-	TotalAmountCompensated float64 `json:"totalAmountCompensated"`
+	CompensationsPerformed int         `json:"compensationsPerformed"`
+	TotalAmountCompensated utils.Money `json:"totalAmountCompensated"`
 }
 
 type Balance struct {
-	UserID string  `json:"userId"`
-	Owed   float64 `json:"owed"`  // Money this user owes to others
-	Owing  float64 `json:"owing"` // Money others owe to this user
+	UserID string      `json:"userId"`
+	Owed   utils.Money `json:"owed"`  // Money this user owes to others
+	Owing  utils.Money `json:"owing"` // Money others owe to this user
 }
 
 type PairwiseBalance struct {
@@ -86,6 +110,13 @@ func (s *LoanService) CreateLoan(ctx context.Context, req CreateLoanRequest) (*m
 		return nil, errors.New("loan amount must be positive")
 	}
 
+	// Foreign currency loans are converted at today's rate; offsetting works in the base currency
+	converted, err := s.currencyService.ToBase(ctx, req.AmountPLN, req.Currency, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	req.AmountPLN = converted.Amount
+
 	// Get user names for logging
 	lender, _ := s.users.GetByID(ctx, req.LenderID)
 	borrower, _ := s.users.GetByID(ctx, req.BorrowerID)
@@ -102,7 +133,7 @@ func (s *LoanService) CreateLoan(ctx context.Context, req CreateLoanRequest) (*m
 	if req.Note != nil {
 		noteStr = *req.Note
 	}
-	log.Printf("[LOAN] Creating loan: %s → %s, amount: %.2f PLN, note: %q", lenderName, borrowerName, req.AmountPLN, noteStr)
+	log.Printf("[LOAN] Creating loan: %s → %s, amount: %s PLN, note: %q", lenderName, borrowerName, req.AmountPLN, noteStr)
 
 	// Verify users exist
 	for _, userID := range []string{req.LenderID, req.BorrowerID} {
@@ -112,13 +143,40 @@ func (s *LoanService) CreateLoan(ctx context.Context, req CreateLoanRequest) (*m
 		}
 	}
 
+	var loan *models.Loan
+	err = s.inTx(ctx, func(ctx context.Context, tx *LoanService) error {
+		var err error
+		loan, err = tx.offsetAndCreateLoan(ctx, req, converted, lenderName, borrowerName, noteStr)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Notify borrower about new loan
+	if loan.Status == "open" && s.notificationService != nil {
+		borrowerID := req.BorrowerID
+		_ = s.notificationService.CreateNotification(ctx, &models.Notification{
+			UserID:     &borrowerID,
+			TemplateID: "loan_created",
+			Title:      "Nowa pożyczka",
+			Body:       fmt.Sprintf("%s pożyczył/a Ci %s zł", lenderName, loan.AmountPLN),
+		})
+	}
+
+	return loan, nil
+}
+
+// offsetAndCreateLoan offsets the new loan against debts running the other way and saves
+// what is left of it, or a settled loan when nothing is left
+func (s *LoanService) offsetAndCreateLoan(ctx context.Context, req CreateLoanRequest, converted *ConvertedAmount, lenderName, borrowerName, noteStr string) (*models.Loan, error) {
 	// Perform group compensation on existing loans first
-	compResult, err := s.PerformGroupCompensation(ctx)
+	compResult, err := s.performGroupCompensation(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("group compensation failed: %w", err)
 	}
 	if compResult.CompensationsPerformed > 0 {
-		log.Printf("[LOAN] Group compensation performed: %d compensations, total %.2f PLN", compResult.CompensationsPerformed, compResult.TotalAmountCompensated)
+		log.Printf("[LOAN] Group compensation performed: %d compensations, total %s PLN", compResult.CompensationsPerformed, compResult.TotalAmountCompensated)
 	}
 
 	// Check for reverse debt (borrower owes lender)
@@ -140,7 +198,7 @@ func (s *LoanService) CreateLoan(ctx context.Context, req CreateLoanRequest) (*m
 		}
 
 		// Calculate how much is remaining on the reverse loan
-		reverseLoanAmount := utils.DecimalStringToFloat(reverseLoan.AmountPLN)
+		reverseLoanAmount := utils.MoneyFromString(reverseLoan.AmountPLN)
 		totalPaid, err := s.getTotalPaidForLoan(ctx, reverseLoan.ID)
 		if err != nil {
 			return nil, err
@@ -161,14 +219,14 @@ func (s *LoanService) CreateLoan(ctx context.Context, req CreateLoanRequest) (*m
 		if reverseLoan.Note != nil {
 			reverseLoanNote = *reverseLoan.Note
 		}
-		log.Printf("[LOAN] Offsetting %.2f PLN against reverse loan %q (original: %.2f PLN, remaining before: %.2f PLN)",
+		log.Printf("[LOAN] Offsetting %s PLN against reverse loan %q (original: %s PLN, remaining before: %s PLN)",
 			offsetAmount, reverseLoanNote, reverseLoanAmount, reverseRemaining)
 
 		// Create a payment to offset the reverse loan
 		payment := models.LoanPayment{
 			ID:        uuid.New().String(),
 			LoanID:    reverseLoan.ID,
-			AmountPLN: utils.FloatToDecimalString(offsetAmount),
+			AmountPLN: offsetAmount.String(),
 			PaidAt:    time.Now(),
 			Note:      getStringPtr("Automatyczne rozliczenie długów"),
 		}
@@ -176,6 +234,9 @@ func (s *LoanService) CreateLoan(ctx context.Context, req CreateLoanRequest) (*m
 		if err := s.loanPayments.Create(ctx, &payment); err != nil {
 			return nil, fmt.Errorf("failed to create offset payment: %w", err)
 		}
+		if err := syncLedger(ctx, s.ledgerEntries, loanPaymentLedgerRecord(&reverseLoan, &payment)); err != nil {
+			return nil, fmt.Errorf("failed to post offset payment to ledger: %w", err)
+		}
 
 		// Update reverse loan status
 		newTotalPaid := totalPaid + offsetAmount
@@ -185,7 +246,7 @@ func (s *LoanService) CreateLoan(ctx context.Context, req CreateLoanRequest) (*m
 			log.Printf("[LOAN] Reverse loan %q is now fully settled", reverseLoanNote)
 		} else {
 			newStatus = "partial"
-			log.Printf("[LOAN] Reverse loan %q is now partial (remaining: %.2f PLN)", reverseLoanNote, reverseLoanAmount-newTotalPaid)
+			log.Printf("[LOAN] Reverse loan %q is now partial (remaining: %s PLN)", reverseLoanNote, reverseLoanAmount-newTotalPaid)
 		}
 
 		reverseLoan.Status = newStatus
@@ -199,43 +260,38 @@ func (s *LoanService) CreateLoan(ctx context.Context, req CreateLoanRequest) (*m
 	// If there's still remaining amount, create the new loan
 	if remainingAmount > 0 {
 		if remainingAmount < req.AmountPLN {
-			log.Printf("[LOAN] After offsetting, creating loan for reduced amount: %.2f PLN (original: %.2f PLN, offset: %.2f PLN)",
+			log.Printf("[LOAN] After offsetting, creating loan for reduced amount: %s PLN (original: %s PLN, offset: %s PLN)",
 				remainingAmount, req.AmountPLN, req.AmountPLN-remainingAmount)
 		}
 
 		loan := models.Loan{
-			ID:         uuid.New().String(),
-			LenderID:   req.LenderID,
-			BorrowerID: req.BorrowerID,
-			AmountPLN:  utils.FloatToDecimalString(remainingAmount),
-			Note:       req.Note,
-			DueDate:    req.DueDate,
-			Status:     "open",
-			CreatedAt:  time.Now(),
+			ID:             uuid.New().String(),
+			LenderID:       req.LenderID,
+			BorrowerID:     req.BorrowerID,
+			AmountPLN:      remainingAmount.String(),
+			Currency:       converted.Currency,
+			OriginalAmount: converted.OriginalAmount,
+			ExchangeRate:   converted.ExchangeRate,
+			Note:           req.Note,
+			DueDate:        req.DueDate,
+			Status:         "open",
+			CreatedAt:      time.Now(),
 		}
 
 		if err := s.loans.Create(ctx, &loan); err != nil {
 			return nil, fmt.Errorf("failed to create loan: %w", err)
 		}
-
-		log.Printf("[LOAN] Created loan: %s → %s, %.2f PLN, note: %q", lenderName, borrowerName, remainingAmount, noteStr)
-
-		// Notify borrower about new loan
-		if s.notificationService != nil {
-			borrowerID := req.BorrowerID
-			_ = s.notificationService.CreateNotification(ctx, &models.Notification{
-				UserID:     &borrowerID,
-				TemplateID: "loan_created",
-				Title:      "Nowa pożyczka",
-				Body:       fmt.Sprintf("%s pożyczył/a Ci %.2f zł", lenderName, remainingAmount),
-			})
+		if err := syncLedger(ctx, s.ledgerEntries, loanLedgerRecord(&loan)); err != nil {
+			return nil, fmt.Errorf("failed to post loan to ledger: %w", err)
 		}
 
+		log.Printf("[LOAN] Created loan: %s → %s, %s PLN, note: %q", lenderName, borrowerName, remainingAmount, noteStr)
+
 		return &loan, nil
 	}
 
 	// All debt was offset, save settled loan to database
-	log.Printf("[LOAN] Entire loan amount (%.2f PLN) was offset against reverse debts - creating as settled", req.AmountPLN)
+	log.Printf("[LOAN] Entire loan amount (%s PLN) was offset against reverse debts - creating as settled", req.AmountPLN)
 
 	// Append offset message to user's note if they provided one
 	var settledNote *string
@@ -247,21 +303,27 @@ func (s *LoanService) CreateLoan(ctx context.Context, req CreateLoanRequest) (*m
 	}
 
 	settledLoan := models.Loan{
-		ID:         uuid.New().String(),
-		LenderID:   req.LenderID,
-		BorrowerID: req.BorrowerID,
-		AmountPLN:  utils.FloatToDecimalString(req.AmountPLN),
-		Note:       settledNote,
-		DueDate:    req.DueDate,
-		Status:     "settled",
-		CreatedAt:  time.Now(),
+		ID:             uuid.New().String(),
+		LenderID:       req.LenderID,
+		BorrowerID:     req.BorrowerID,
+		AmountPLN:      req.AmountPLN.String(),
+		Currency:       converted.Currency,
+		OriginalAmount: converted.OriginalAmount,
+		ExchangeRate:   converted.ExchangeRate,
+		Note:           settledNote,
+		DueDate:        req.DueDate,
+		Status:         "settled",
+		CreatedAt:      time.Now(),
 	}
 
 	if err := s.loans.Create(ctx, &settledLoan); err != nil {
 		return nil, fmt.Errorf("failed to create settled loan: %w", err)
 	}
+	if err := syncLedger(ctx, s.ledgerEntries, loanLedgerRecord(&settledLoan)); err != nil {
+		return nil, fmt.Errorf("failed to post loan to ledger: %w", err)
+	}
 
-	log.Printf("[LOAN] Created settled loan (fully offset): %s → %s, %.2f PLN, note: %q", lenderName, borrowerName, req.AmountPLN, noteStr)
+	log.Printf("[LOAN] Created settled loan (fully offset): %s → %s, %s PLN, note: %q", lenderName, borrowerName, req.AmountPLN, noteStr)
 
 	return &settledLoan, nil
 }
@@ -274,6 +336,19 @@ func getStringPtr(s string) *string {
 // When GroupMember1 owes External and External owes GroupMember2 (same group),
 // the debts are offset without creating internal group debt
 func (s *LoanService) PerformGroupCompensation(ctx context.Context) (*CompensationResult, error) {
+	var result *CompensationResult
+	err := s.inTx(ctx, func(ctx context.Context, tx *LoanService) error {
+		var err error
+		result, err = tx.performGroupCompensation(ctx)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func (s *LoanService) performGroupCompensation(ctx context.Context) (*CompensationResult, error) {
 	// Get all users with their group memberships
 	users, err := s.users.List(ctx)
 	if err != nil {
@@ -305,12 +380,12 @@ func (s *LoanService) PerformGroupCompensation(ctx context.Context) (*Compensati
 	// Calculate remaining amounts for each loan
 	type loanWithRemaining struct {
 		loan      models.Loan
-		remaining float64
+		remaining utils.Money
 	}
 
 	loansWithRemaining := []loanWithRemaining{}
 	for _, loan := range loans {
-		loanAmount := utils.DecimalStringToFloat(loan.AmountPLN)
+		loanAmount := utils.MoneyFromString(loan.AmountPLN)
 		totalPaid, err := s.getTotalPaidForLoan(ctx, loan.ID)
 		if err != nil {
 			return nil, err
@@ -325,7 +400,7 @@ func (s *LoanService) PerformGroupCompensation(ctx context.Context) (*Compensati
 	}
 
 	compensationsPerformed := 0
-	totalAmountCompensated := 0.0
+	var totalAmountCompensated utils.Money
 
 	// Find compensation opportunities
 	// Pattern: GroupMemberA owes External, External owes GroupMemberB (same group)
@@ -374,10 +449,7 @@ func (s *LoanService) PerformGroupCompensation(ctx context.Context) (*Compensati
 			}
 
 			// Found a compensation opportunity!
-			compensationAmount := loansWithRemaining[i].remaining
-			if loansWithRemaining[j].remaining < compensationAmount {
-				compensationAmount = loansWithRemaining[j].remaining
-			}
+			compensationAmount := utils.MinMoney(loansWithRemaining[i].remaining, loansWithRemaining[j].remaining)
 
 			// Get names for logging
 			externalUser, _ := s.users.GetByID(ctx, external)
@@ -405,9 +477,9 @@ func (s *LoanService) PerformGroupCompensation(ctx context.Context) (*Compensati
 				loan2Note = *loan2.Note
 			}
 
-			log.Printf("[GROUP COMPENSATION] Found opportunity: %.2f PLN", compensationAmount)
-			log.Printf("[GROUP COMPENSATION]   Loan1: %s owes %s %.2f PLN (%q)", groupMemberAName, externalName, loansWithRemaining[i].remaining, loan1Note)
-			log.Printf("[GROUP COMPENSATION]   Loan2: %s owes %s %.2f PLN (%q)", externalName, groupMemberBName, loansWithRemaining[j].remaining, loan2Note)
+			log.Printf("[GROUP COMPENSATION] Found opportunity: %s PLN", compensationAmount)
+			log.Printf("[GROUP COMPENSATION]   Loan1: %s owes %s %s PLN (%q)", groupMemberAName, externalName, loansWithRemaining[i].remaining, loan1Note)
+			log.Printf("[GROUP COMPENSATION]   Loan2: %s owes %s %s PLN (%q)", externalName, groupMemberBName, loansWithRemaining[j].remaining, loan2Note)
 
 			// Create payments with compensation note
 			compensationNote := getStringPtr("Kompensacja grupowa")
@@ -416,7 +488,7 @@ func (s *LoanService) PerformGroupCompensation(ctx context.Context) (*Compensati
 			payment1 := models.LoanPayment{
 				ID:        uuid.New().String(),
 				LoanID:    loan1.ID,
-				AmountPLN: utils.FloatToDecimalString(compensationAmount),
+				AmountPLN: compensationAmount.String(),
 				PaidAt:    time.Now(),
 				Note:      compensationNote,
 			}
@@ -424,17 +496,20 @@ func (s *LoanService) PerformGroupCompensation(ctx context.Context) (*Compensati
 			if err := s.loanPayments.Create(ctx, &payment1); err != nil {
 				return nil, fmt.Errorf("failed to create compensation payment 1: %w", err)
 			}
+			if err := syncLedger(ctx, s.ledgerEntries, loanPaymentLedgerRecord(&loan1, &payment1)); err != nil {
+				return nil, fmt.Errorf("failed to post compensation payment 1 to ledger: %w", err)
+			}
 
 			// Update loan1 status
 			newTotalPaid1, _ := s.getTotalPaidForLoan(ctx, loan1.ID)
-			loanAmount1 := utils.DecimalStringToFloat(loan1.AmountPLN)
+			loanAmount1 := utils.MoneyFromString(loan1.AmountPLN)
 			var newStatus1 string
 			if newTotalPaid1 >= loanAmount1 {
 				newStatus1 = "settled"
 				log.Printf("[GROUP COMPENSATION]   Loan1 %q is now settled", loan1Note)
 			} else {
 				newStatus1 = "partial"
-				log.Printf("[GROUP COMPENSATION]   Loan1 %q is now partial (remaining: %.2f PLN)", loan1Note, loanAmount1-newTotalPaid1)
+				log.Printf("[GROUP COMPENSATION]   Loan1 %q is now partial (remaining: %s PLN)", loan1Note, loanAmount1-newTotalPaid1)
 			}
 
 			loan1.Status = newStatus1
@@ -446,7 +521,7 @@ func (s *LoanService) PerformGroupCompensation(ctx context.Context) (*Compensati
 			payment2 := models.LoanPayment{
 				ID:        uuid.New().String(),
 				LoanID:    loan2.ID,
-				AmountPLN: utils.FloatToDecimalString(compensationAmount),
+				AmountPLN: compensationAmount.String(),
 				PaidAt:    time.Now(),
 				Note:      compensationNote,
 			}
@@ -454,17 +529,20 @@ func (s *LoanService) PerformGroupCompensation(ctx context.Context) (*Compensati
 			if err := s.loanPayments.Create(ctx, &payment2); err != nil {
 				return nil, fmt.Errorf("failed to create compensation payment 2: %w", err)
 			}
+			if err := syncLedger(ctx, s.ledgerEntries, loanPaymentLedgerRecord(&loan2, &payment2)); err != nil {
+				return nil, fmt.Errorf("failed to post compensation payment 2 to ledger: %w", err)
+			}
 
 			// Update loan2 status
 			newTotalPaid2, _ := s.getTotalPaidForLoan(ctx, loan2.ID)
-			loanAmount2 := utils.DecimalStringToFloat(loan2.AmountPLN)
+			loanAmount2 := utils.MoneyFromString(loan2.AmountPLN)
 			var newStatus2 string
 			if newTotalPaid2 >= loanAmount2 {
 				newStatus2 = "settled"
 				log.Printf("[GROUP COMPENSATION]   Loan2 %q is now settled", loan2Note)
 			} else {
 				newStatus2 = "partial"
-				log.Printf("[GROUP COMPENSATION]   Loan2 %q is now partial (remaining: %.2f PLN)", loan2Note, loanAmount2-newTotalPaid2)
+				log.Printf("[GROUP COMPENSATION]   Loan2 %q is now partial (remaining: %s PLN)", loan2Note, loanAmount2-newTotalPaid2)
 			}
 
 			loan2.Status = newStatus2
@@ -514,25 +592,21 @@ func (s *LoanService) CreateLoanPayment(ctx context.Context, req CreateLoanPayme
 		return nil, err
 	}
 
-	loanAmount := utils.DecimalStringToFloat(loan.AmountPLN)
+	loanAmount := utils.MoneyFromString(loan.AmountPLN)
 	remaining := loanAmount - totalPaid
 
 	if req.AmountPLN > remaining {
-		return nil, fmt.Errorf("payment amount (%.2f) exceeds remaining balance (%.2f)", req.AmountPLN, remaining)
+		return nil, fmt.Errorf("payment amount (%s) exceeds remaining balance (%s)", req.AmountPLN, remaining)
 	}
 
 	payment := models.LoanPayment{
 		ID:        uuid.New().String(),
 		LoanID:    req.LoanID,
-		AmountPLN: utils.FloatToDecimalString(req.AmountPLN),
+		AmountPLN: req.AmountPLN.String(),
 		PaidAt:    req.PaidAt,
 		Note:      req.Note,
 	}
 
-	if err := s.loanPayments.Create(ctx, &payment); err != nil {
-		return nil, fmt.Errorf("failed to create loan payment: %w", err)
-	}
-
 	// Update loan status
 	newTotalPaid := totalPaid + req.AmountPLN
 	var newStatus string
@@ -541,10 +615,22 @@ func (s *LoanService) CreateLoanPayment(ctx context.Context, req CreateLoanPayme
 	} else {
 		newStatus = "partial"
 	}
-
 	loan.Status = newStatus
-	if err := s.loans.Update(ctx, loan); err != nil {
-		return nil, fmt.Errorf("failed to update loan status: %w", err)
+
+	err = s.inTx(ctx, func(ctx context.Context, tx *LoanService) error {
+		if err := tx.loanPayments.Create(ctx, &payment); err != nil {
+			return fmt.Errorf("failed to create loan payment: %w", err)
+		}
+		if err := syncLedger(ctx, tx.ledgerEntries, loanPaymentLedgerRecord(loan, &payment)); err != nil {
+			return fmt.Errorf("failed to post loan payment to ledger: %w", err)
+		}
+		if err := tx.loans.Update(ctx, loan); err != nil {
+			return fmt.Errorf("failed to update loan status: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	// Notify lender about payment received
@@ -559,7 +645,7 @@ func (s *LoanService) CreateLoanPayment(ctx context.Context, req CreateLoanPayme
 			UserID:     &lenderID,
 			TemplateID: "loan_payment_received",
 			Title:      "Otrzymano spłatę pożyczki",
-			Body:       fmt.Sprintf("%s spłacił/a %.2f zł", borrowerName, req.AmountPLN),
+			Body:       fmt.Sprintf("%s spłacił/a %s zł", borrowerName, req.AmountPLN),
 		})
 	}
 
@@ -575,14 +661,14 @@ func (s *LoanService) GetBalances(ctx context.Context) ([]PairwiseBalance, error
 	}
 
 	// Calculate net balances
-	balances := make(map[string]float64) // key: "lenderID-borrowerID"
+	balances := make(map[string]utils.Money) // key: "borrowerID-lenderID"
 
 	for _, loan := range loans {
 		if loan.Status == "settled" {
 			continue
 		}
 
-		loanAmount := utils.DecimalStringToFloat(loan.AmountPLN)
+		loanAmount := utils.MoneyFromString(loan.AmountPLN)
 		totalPaid, err := s.getTotalPaidForLoan(ctx, loan.ID)
 		if err != nil {
 			return nil, err
@@ -649,7 +735,7 @@ func (s *LoanService) GetBalances(ctx context.Context) ([]PairwiseBalance, error
 				ToUserId:     toID,
 				FromUserName: userMap[fromID],
 				ToUserName:   userMap[toID],
-				NetAmount:    utils.FloatToDecimalString(amount),
+				NetAmount:    amount.String(),
 			}
 
 			// Add group information if user belongs to a group
@@ -734,7 +820,7 @@ func (s *LoanService) GetLoansWithOptions(ctx context.Context, opts GetLoansOpti
 	result := make([]LoanWithNames, len(loans))
 	for i, loan := range loans {
 		// Calculate remaining amount
-		loanAmount := utils.DecimalStringToFloat(loan.AmountPLN)
+		loanAmount := utils.MoneyFromString(loan.AmountPLN)
 		totalPaid, err := s.getTotalPaidForLoan(ctx, loan.ID)
 		if err != nil {
 			totalPaid = 0
@@ -745,7 +831,7 @@ func (s *LoanService) GetLoansWithOptions(ctx context.Context, opts GetLoansOpti
 			Loan:         loan,
 			FromUserName: userMap[loan.LenderID],
 			ToUserName:   userMap[loan.BorrowerID],
-			RemainingPLN: utils.FloatToDecimalString(remaining),
+			RemainingPLN: remaining.String(),
 		}
 
 		// Add group information if user belongs to a group
@@ -772,8 +858,8 @@ func (s *LoanService) GetLoansWithOptions(ctx context.Context, opts GetLoansOpti
 	switch opts.SortBy {
 	case "amountPLN":
 		sort.Slice(result, func(i, j int) bool {
-			amtI := utils.DecimalStringToFloat(result[i].AmountPLN)
-			amtJ := utils.DecimalStringToFloat(result[j].AmountPLN)
+			amtI := utils.MoneyFromString(result[i].AmountPLN)
+			amtJ := utils.MoneyFromString(result[j].AmountPLN)
 			if sortOrder == 1 {
 				return amtI < amtJ
 			}
@@ -788,8 +874,8 @@ func (s *LoanService) GetLoansWithOptions(ctx context.Context, opts GetLoansOpti
 		})
 	case "remainingPLN":
 		sort.Slice(result, func(i, j int) bool {
-			remI := utils.DecimalStringToFloat(result[i].RemainingPLN)
-			remJ := utils.DecimalStringToFloat(result[j].RemainingPLN)
+			remI := utils.MoneyFromString(result[i].RemainingPLN)
+			remJ := utils.MoneyFromString(result[j].RemainingPLN)
 			if sortOrder == 1 {
 				return remI < remJ
 			}
@@ -841,38 +927,80 @@ func (s *LoanService) GetUserBalance(ctx context.Context, userID string) ([]Pair
 // DeleteLoan deletes a loan and all its payments
 func (s *LoanService) DeleteLoan(ctx context.Context, loanID string) error {
 	// Check if loan exists
-	_, err := s.loans.GetByID(ctx, loanID)
-	if err != nil {
+	loan, err := s.loans.GetByID(ctx, loanID)
+	if err != nil || loan == nil {
 		return errors.New("loan not found")
 	}
 
+	return s.inTx(ctx, func(ctx context.Context, tx *LoanService) error {
+		_, err := deleteLoanWithPayments(ctx, tx.loans, tx.loanPayments, tx.ledgerEntries, loan)
+		return err
+	})
+}
+
+// ExecuteApprovedDelete is the approval executor for "loan.delete" requests
+func (s *LoanService) ExecuteApprovedDelete(ctx context.Context, repos *repository.Repositories, request *models.ApprovalRequest) (map[string]interface{}, error) {
+	if request.ResourceID == nil {
+		return nil, errors.New("approval request has no loan ID")
+	}
+
+	loan, err := repos.Loans.GetByID(ctx, *request.ResourceID)
+	if err != nil || loan == nil {
+		return nil, errors.New("loan not found")
+	}
+
+	deleted, err := deleteLoanWithPayments(ctx, repos.Loans, repos.LoanPayments, repos.LedgerEntries, loan)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[LOAN] Deleted after approval: ID=%s (including %d payments, request %s)", loan.ID, deleted, request.ID)
+
+	return map[string]interface{}{
+		"loanId":          loan.ID,
+		"lenderId":        loan.LenderID,
+		"borrowerId":      loan.BorrowerID,
+		"amountPLN":       loan.AmountPLN,
+		"deletedPayments": deleted,
+	}, nil
+}
+
+// deleteLoanWithPayments deletes a loan and all of its payments, reversing them in the ledger,
+// and returns the number of payments removed
+func deleteLoanWithPayments(ctx context.Context, loans repository.LoanRepository, loanPayments repository.LoanPaymentRepository, ledgerEntries repository.LedgerEntryRepository, loan *models.Loan) (int, error) {
 	// Delete all payments for this loan - we need to list and delete each
-	payments, err := s.loanPayments.ListByLoanID(ctx, loanID)
+	payments, err := loanPayments.ListByLoanID(ctx, loan.ID)
 	if err != nil {
-		return fmt.Errorf("failed to list loan payments: %w", err)
+		return 0, fmt.Errorf("failed to list loan payments: %w", err)
 	}
 
-	for _, payment := range payments {
-		if err := s.loanPayments.Delete(ctx, payment.ID); err != nil {
-			return fmt.Errorf("failed to delete loan payment: %w", err)
+	for i := range payments {
+		if err := loanPayments.Delete(ctx, payments[i].ID); err != nil {
+			return 0, fmt.Errorf("failed to delete loan payment: %w", err)
+		}
+		if err := syncLedger(ctx, ledgerEntries, loanPaymentLedgerRecord(loan, &payments[i]).reversal()); err != nil {
+			return 0, fmt.Errorf("failed to reverse loan payment in ledger: %w", err)
 		}
 	}
 
 	// Delete the loan
-	if err := s.loans.Delete(ctx, loanID); err != nil {
-		return fmt.Errorf("failed to delete loan: %w", err)
+	if err := loans.Delete(ctx, loan.ID); err != nil {
+		return 0, fmt.Errorf("failed to delete loan: %w", err)
+	}
+	if err := syncLedger(ctx, ledgerEntries, loanLedgerRecord(loan).reversal()); err != nil {
+		return 0, fmt.Errorf("failed to reverse loan in ledger: %w", err)
 	}
 
-	return nil
+	return len(payments), nil
 }
 
 // Helper functions
-func (s *LoanService) getTotalPaidForLoan(ctx context.Context, loanID string) (float64, error) {
+func (s *LoanService) getTotalPaidForLoan(ctx context.Context, loanID string) (utils.Money, error) {
 	sumStr, err := s.loanPayments.SumByLoanID(ctx, loanID)
 	if err != nil {
 		return 0, fmt.Errorf("database error: %w", err)
 	}
-	return utils.DecimalStringToFloat(sumStr), nil
+	return utils.MoneyFromString(sumStr), nil
 }
 
 // GetLoanPayments retrieves all payments for a specific loan
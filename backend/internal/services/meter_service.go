@@ -0,0 +1,499 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/sainaif/holy-home/internal/utils"
+)
+
+var validMeterUtilityTypes = map[string]bool{"electricity": true, "gas": true, "water": true, "heating": true, "inne": true}
+
+type MeterService struct {
+	meters       repository.MeterRepository
+	replacements repository.MeterReplacementRepository
+	consumptions repository.ConsumptionRepository
+	users        repository.UserRepository
+	groups       repository.GroupRepository
+}
+
+func NewMeterService(
+	meters repository.MeterRepository,
+	replacements repository.MeterReplacementRepository,
+	consumptions repository.ConsumptionRepository,
+	users repository.UserRepository,
+	groups repository.GroupRepository,
+) *MeterService {
+	return &MeterService{
+		meters:       meters,
+		replacements: replacements,
+		consumptions: consumptions,
+		users:        users,
+		groups:       groups,
+	}
+}
+
+type CreateMeterRequest struct {
+	SubjectType  string   `json:"subjectType"`
+	SubjectID    string   `json:"subjectId"`
+	UtilityType  string   `json:"utilityType"`
+	SerialNumber *string  `json:"serialNumber,omitempty"`
+	Unit         string   `json:"unit"`
+	RolloverAt   *float64 `json:"rolloverAt,omitempty"` // value shown as zero again, e.g. 100000 for a five digit counter
+	Registers    []string `json:"registers,omitempty"`  // e.g. day and night, empty for a single register
+}
+
+type UpdateMeterRequest struct {
+	UtilityType  *string   `json:"utilityType,omitempty"`
+	SerialNumber *string   `json:"serialNumber,omitempty"`
+	Unit         *string   `json:"unit,omitempty"`
+	RolloverAt   *float64  `json:"rolloverAt,omitempty"` // 0 removes the rollover value
+	Registers    *[]string `json:"registers,omitempty"`  // only while the meter has no readings
+	IsActive     *bool     `json:"isActive,omitempty"`
+}
+
+type ReplaceMeterRequest struct {
+	ReplacedAt      time.Time `json:"replacedAt"`
+	OldFinalValue   float64   `json:"oldFinalValue"`
+	NewInitialValue float64   `json:"newInitialValue"`
+	NewSerialNumber *string   `json:"newSerialNumber,omitempty"`
+	Register        *string   `json:"register,omitempty"` // required for multi-register meters, one replacement per register
+}
+
+// CreateMeter registers a meter of a user or group
+func (s *MeterService) CreateMeter(ctx context.Context, req CreateMeterRequest) (*models.Meter, error) {
+	switch req.SubjectType {
+	case "user":
+		user, err := s.users.GetByID(ctx, req.SubjectID)
+		if err != nil || user == nil {
+			return nil, errors.New("user not found")
+		}
+	case "group":
+		group, err := s.groups.GetByID(ctx, req.SubjectID)
+		if err != nil || group == nil {
+			return nil, errors.New("group not found")
+		}
+	default:
+		return nil, errors.New("subjectType must be user or group")
+	}
+	if !validMeterUtilityTypes[req.UtilityType] {
+		return nil, errors.New("invalid utility type")
+	}
+	if req.Unit == "" {
+		return nil, errors.New("unit is required")
+	}
+	registers, err := normalizeMeterRegisters(req.Registers)
+	if err != nil {
+		return nil, err
+	}
+
+	meter := &models.Meter{
+		ID:           uuid.New().String(),
+		SubjectType:  req.SubjectType,
+		SubjectID:    req.SubjectID,
+		UtilityType:  req.UtilityType,
+		SerialNumber: req.SerialNumber,
+		Unit:         req.Unit,
+		Registers:    registers,
+		IsActive:     true,
+		CreatedAt:    time.Now(),
+	}
+	if req.RolloverAt != nil {
+		if *req.RolloverAt <= 0 {
+			return nil, errors.New("rollover value must be positive")
+		}
+		rolloverAt := utils.FloatToDecimalString(*req.RolloverAt)
+		meter.RolloverAt = &rolloverAt
+	}
+
+	if err := s.meters.Create(ctx, meter); err != nil {
+		return nil, fmt.Errorf("failed to create meter: %w", err)
+	}
+
+	return meter, nil
+}
+
+// GetMeters retrieves all meters
+func (s *MeterService) GetMeters(ctx context.Context) ([]models.Meter, error) {
+	meters, err := s.meters.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return meters, nil
+}
+
+// GetMeter retrieves a meter by ID
+func (s *MeterService) GetMeter(ctx context.Context, meterID string) (*models.Meter, error) {
+	meter, err := s.meters.GetByID(ctx, meterID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if meter == nil {
+		return nil, errors.New("meter not found")
+	}
+	return meter, nil
+}
+
+// UpdateMeter updates a meter. The owner cannot change because the readings belong to it.
+func (s *MeterService) UpdateMeter(ctx context.Context, meterID string, req UpdateMeterRequest) (*models.Meter, error) {
+	meter, err := s.GetMeter(ctx, meterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.UtilityType != nil {
+		if !validMeterUtilityTypes[*req.UtilityType] {
+			return nil, errors.New("invalid utility type")
+		}
+		meter.UtilityType = *req.UtilityType
+	}
+	if req.SerialNumber != nil {
+		meter.SerialNumber = req.SerialNumber
+	}
+	if req.Unit != nil {
+		if *req.Unit == "" {
+			return nil, errors.New("unit cannot be empty")
+		}
+		meter.Unit = *req.Unit
+	}
+	if req.RolloverAt != nil {
+		switch {
+		case *req.RolloverAt < 0:
+			return nil, errors.New("rollover value must be positive")
+		case *req.RolloverAt == 0:
+			meter.RolloverAt = nil
+		default:
+			rolloverAt := utils.FloatToDecimalString(*req.RolloverAt)
+			meter.RolloverAt = &rolloverAt
+		}
+	}
+	if req.Registers != nil {
+		registers, err := normalizeMeterRegisters(*req.Registers)
+		if err != nil {
+			return nil, err
+		}
+		readings, err := s.consumptions.ListByMeterID(ctx, meterID)
+		if err != nil {
+			return nil, fmt.Errorf("database error: %w", err)
+		}
+		if len(readings) > 0 {
+			return nil, errors.New("cannot change registers of a meter with readings")
+		}
+		meter.Registers = registers
+	}
+	if req.IsActive != nil {
+		meter.IsActive = *req.IsActive
+	}
+
+	if err := s.meters.Update(ctx, meter); err != nil {
+		return nil, fmt.Errorf("failed to update meter: %w", err)
+	}
+
+	return meter, nil
+}
+
+// DeleteMeter deletes a meter without readings. Meters with readings can only be deactivated.
+func (s *MeterService) DeleteMeter(ctx context.Context, meterID string) error {
+	if _, err := s.GetMeter(ctx, meterID); err != nil {
+		return err
+	}
+
+	readings, err := s.consumptions.ListByMeterID(ctx, meterID)
+	if err != nil {
+		return fmt.Errorf("database error: %w", err)
+	}
+	if len(readings) > 0 {
+		return errors.New("cannot delete meter: it has readings, deactivate it instead")
+	}
+
+	return s.meters.Delete(ctx, meterID)
+}
+
+// ReplaceMeter records the counter behind a meter being swapped. Readings after the
+// replacement continue from the new counter's initial value.
+func (s *MeterService) ReplaceMeter(ctx context.Context, meterID string, req ReplaceMeterRequest) (*models.MeterReplacement, error) {
+	meter, err := s.GetMeter(ctx, meterID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.ReplacedAt.IsZero() {
+		req.ReplacedAt = time.Now()
+	}
+	register, err := validateMeterRegister(meter, req.Register)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateMeterValue(meter, req.OldFinalValue); err != nil {
+		return nil, fmt.Errorf("old final value: %w", err)
+	}
+	if err := validateMeterValue(meter, req.NewInitialValue); err != nil {
+		return nil, fmt.Errorf("new initial value: %w", err)
+	}
+
+	// The old counter's final value must follow on from its last reading
+	sources := meterSources{consumptions: s.consumptions, meters: s.meters, replacements: s.replacements}
+	if _, err := sources.meterUnits(ctx, meter, register, req.OldFinalValue, req.ReplacedAt.Add(-time.Second)); err != nil {
+		return nil, fmt.Errorf("old final value: %w", err)
+	}
+
+	replacement := &models.MeterReplacement{
+		ID:              uuid.New().String(),
+		MeterID:         meter.ID,
+		ReplacedAt:      req.ReplacedAt,
+		OldFinalValue:   utils.FloatToDecimalString(req.OldFinalValue),
+		NewInitialValue: utils.FloatToDecimalString(req.NewInitialValue),
+		OldSerialNumber: meter.SerialNumber,
+		NewSerialNumber: req.NewSerialNumber,
+		Register:        register,
+		CreatedAt:       time.Now(),
+	}
+	if err := s.replacements.Create(ctx, replacement); err != nil {
+		return nil, fmt.Errorf("failed to record meter replacement: %w", err)
+	}
+
+	if req.NewSerialNumber != nil {
+		meter.SerialNumber = req.NewSerialNumber
+		if err := s.meters.Update(ctx, meter); err != nil {
+			return nil, fmt.Errorf("failed to update meter: %w", err)
+		}
+	}
+
+	return replacement, nil
+}
+
+// GetReplacements retrieves the replacements of a meter, oldest first
+func (s *MeterService) GetReplacements(ctx context.Context, meterID string) ([]models.MeterReplacement, error) {
+	if _, err := s.GetMeter(ctx, meterID); err != nil {
+		return nil, err
+	}
+	replacements, err := s.replacements.ListByMeterID(ctx, meterID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return replacements, nil
+}
+
+// GetReadings retrieves the readings of a meter, newest first
+func (s *MeterService) GetReadings(ctx context.Context, meterID string) ([]models.Consumption, error) {
+	if _, err := s.GetMeter(ctx, meterID); err != nil {
+		return nil, err
+	}
+	readings, err := s.consumptions.ListByMeterID(ctx, meterID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return readings, nil
+}
+
+// validateMeterValue checks that a value can be shown by the meter's counter
+func validateMeterValue(meter *models.Meter, value float64) error {
+	if value < 0 {
+		return errors.New("meter value cannot be negative")
+	}
+	if meter.RolloverAt != nil && value >= utils.DecimalStringToFloat(*meter.RolloverAt) {
+		return fmt.Errorf("meter value must be below %s, where the counter rolls over to zero", *meter.RolloverAt)
+	}
+	return nil
+}
+
+// normalizeMeterRegisters trims and lowercases register names, which must be distinct
+func normalizeMeterRegisters(registers []string) ([]string, error) {
+	if len(registers) == 0 {
+		return nil, nil
+	}
+
+	normalized := make([]string, 0, len(registers))
+	seen := make(map[string]bool, len(registers))
+	for _, register := range registers {
+		register = strings.ToLower(strings.TrimSpace(register))
+		if register == "" {
+			return nil, errors.New("register names cannot be empty")
+		}
+		if seen[register] {
+			return nil, fmt.Errorf("duplicate register %q", register)
+		}
+		seen[register] = true
+		normalized = append(normalized, register)
+	}
+	return normalized, nil
+}
+
+// validateMeterRegister checks the register of a reading or replacement against the meter.
+// Multi-register meters need one of their registers, single register meters none.
+func validateMeterRegister(meter *models.Meter, register *string) (*string, error) {
+	if register != nil {
+		name := strings.ToLower(strings.TrimSpace(*register))
+		register = &name
+		if name == "" {
+			register = nil
+		}
+	}
+
+	if len(meter.Registers) == 0 {
+		if register != nil {
+			return nil, errors.New("meter has a single register")
+		}
+		return nil, nil
+	}
+	if register == nil {
+		return nil, fmt.Errorf("register is required, one of: %s", strings.Join(meter.Registers, ", "))
+	}
+	for _, name := range meter.Registers {
+		if name == *register {
+			return register, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown register %q, expected one of: %s", *register, strings.Join(meter.Registers, ", "))
+}
+
+// sameOptional reports whether two optional values are equal, such as the registers of two readings
+func sameOptional(a, b *string) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	return *a == *b
+}
+
+// meterSources are the repositories needed to derive units from meter readings
+type meterSources struct {
+	consumptions repository.ConsumptionRepository
+	meters       repository.MeterRepository
+	replacements repository.MeterReplacementRepository
+}
+
+// readingUnits derives the units consumed up to a meter value recorded at recordedAt.
+// Readings of a meter are compared with the meter's previous reading, legacy readings
+// without a meter with the subject's previous legacy reading. Each register counts separately.
+func (m meterSources) readingUnits(ctx context.Context, subjectType, subjectID string, meterID, register *string, value float64, recordedAt time.Time) (float64, error) {
+	if meterID != nil {
+		meter, err := m.meters.GetByID(ctx, *meterID)
+		if err != nil {
+			return 0, fmt.Errorf("failed to fetch meter: %w", err)
+		}
+		if meter == nil {
+			return 0, errors.New("meter not found")
+		}
+		return m.meterUnits(ctx, meter, register, value, recordedAt)
+	}
+
+	consumptions, err := m.consumptions.ListBySubject(ctx, subjectType, subjectID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch consumptions: %w", err)
+	}
+
+	var legacy []models.Consumption
+	for _, c := range consumptions {
+		if c.MeterID == nil && sameOptional(c.Register, register) {
+			legacy = append(legacy, c)
+		}
+	}
+
+	previous := previousMeterReading(legacy, recordedAt)
+	if previous == nil {
+		return 0, ErrNoPreviousReading
+	}
+
+	units := value - utils.DecimalStringToFloat(*previous.MeterValue)
+	if units < 0 {
+		return 0, errors.New("meter reading cannot be lower than previous reading")
+	}
+
+	return units, nil
+}
+
+// meterUnits returns the units consumed on a meter register between its last reading before
+// recordedAt and value. Replacements in between close the old counter at its final value and
+// continue from the new counter's initial value. The first reading of a register is its baseline.
+func (m meterSources) meterUnits(ctx context.Context, meter *models.Meter, register *string, value float64, recordedAt time.Time) (float64, error) {
+	meterReadings, err := m.consumptions.ListByMeterID(ctx, meter.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch meter readings: %w", err)
+	}
+	meterReplacements, err := m.replacements.ListByMeterID(ctx, meter.ID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to fetch meter replacements: %w", err)
+	}
+
+	var readings []models.Consumption
+	for _, reading := range meterReadings {
+		if sameOptional(reading.Register, register) {
+			readings = append(readings, reading)
+		}
+	}
+	var replacements []models.MeterReplacement
+	for _, replacement := range meterReplacements {
+		if sameOptional(replacement.Register, register) {
+			replacements = append(replacements, replacement)
+		}
+	}
+
+	var from time.Time
+	start := value
+	counting := false
+	if previous := previousMeterReading(readings, recordedAt); previous != nil {
+		from = previous.RecordedAt
+		start = utils.DecimalStringToFloat(*previous.MeterValue)
+		counting = true
+	}
+
+	units := 0.0
+	for _, r := range replacements {
+		if !r.ReplacedAt.After(from) || r.ReplacedAt.After(recordedAt) {
+			continue
+		}
+		if counting {
+			delta, err := meterDelta(meter, start, utils.DecimalStringToFloat(r.OldFinalValue))
+			if err != nil {
+				return 0, err
+			}
+			units += delta
+		}
+		start = utils.DecimalStringToFloat(r.NewInitialValue)
+		counting = true
+	}
+
+	delta, err := meterDelta(meter, start, value)
+	if err != nil {
+		return 0, err
+	}
+
+	return units + delta, nil
+}
+
+// meterDelta returns how far a counter advanced from previous to current. A lower current
+// value means the counter rolled over, which only meters with a rollover value do: from
+// 99999 to 0 on a counter rolling over at 100000 is one unit.
+func meterDelta(meter *models.Meter, previous, current float64) (float64, error) {
+	if current >= previous {
+		return current - previous, nil
+	}
+	if meter.RolloverAt == nil {
+		return 0, errors.New("meter reading cannot be lower than previous reading")
+	}
+	return utils.DecimalStringToFloat(*meter.RolloverAt) - previous + current, nil
+}
+
+// previousMeterReading returns the latest reading with a meter value recorded before the given time
+func previousMeterReading(readings []models.Consumption, before time.Time) *models.Consumption {
+	var previous *models.Consumption
+	for i := range readings {
+		c := &readings[i]
+		if c.MeterValue == nil {
+			continue
+		}
+		if !c.RecordedAt.Before(before) {
+			continue
+		}
+		if previous == nil || c.RecordedAt.After(previous.RecordedAt) {
+			previous = c
+		}
+	}
+	return previous
+}
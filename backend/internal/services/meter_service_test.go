@@ -0,0 +1,182 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryMeters struct {
+	repository.MeterRepository
+	meters []models.Meter
+}
+
+func (m *memoryMeters) Create(ctx context.Context, meter *models.Meter) error {
+	m.meters = append(m.meters, *meter)
+	return nil
+}
+
+func (m *memoryMeters) GetByID(ctx context.Context, id string) (*models.Meter, error) {
+	for _, meter := range m.meters {
+		if meter.ID == id {
+			return &meter, nil
+		}
+	}
+	return nil, nil
+}
+
+func (m *memoryMeters) Update(ctx context.Context, meter *models.Meter) error {
+	for i := range m.meters {
+		if m.meters[i].ID == meter.ID {
+			m.meters[i] = *meter
+		}
+	}
+	return nil
+}
+
+type memoryMeterReplacements struct {
+	repository.MeterReplacementRepository
+	replacements []models.MeterReplacement
+}
+
+func (m *memoryMeterReplacements) Create(ctx context.Context, replacement *models.MeterReplacement) error {
+	m.replacements = append(m.replacements, *replacement)
+	return nil
+}
+
+func (m *memoryMeterReplacements) ListByMeterID(ctx context.Context, meterID string) ([]models.MeterReplacement, error) {
+	var replacements []models.MeterReplacement
+	for _, replacement := range m.replacements {
+		if replacement.MeterID == meterID {
+			replacements = append(replacements, replacement)
+		}
+	}
+	return replacements, nil
+}
+
+type memoryConsumptions struct {
+	repository.ConsumptionRepository
+	consumptions []models.Consumption
+}
+
+func (m *memoryConsumptions) ListBySubject(ctx context.Context, subjectType, subjectID string) ([]models.Consumption, error) {
+	var consumptions []models.Consumption
+	for _, c := range m.consumptions {
+		if c.SubjectType == subjectType && c.SubjectID == subjectID {
+			consumptions = append(consumptions, c)
+		}
+	}
+	return consumptions, nil
+}
+
+func (m *memoryConsumptions) ListByMeterID(ctx context.Context, meterID string) ([]models.Consumption, error) {
+	var consumptions []models.Consumption
+	for _, c := range m.consumptions {
+		if c.MeterID != nil && *c.MeterID == meterID {
+			consumptions = append(consumptions, c)
+		}
+	}
+	return consumptions, nil
+}
+
+func TestMeterReadingUnits(t *testing.T) {
+	ctx := context.Background()
+	day := func(d int) time.Time { return time.Date(2026, 1, d, 12, 0, 0, 0, time.UTC) }
+	value := func(v string) *string { return &v }
+	meterID := "main"
+	reading := func(id string, d int, v string) models.Consumption {
+		return models.Consumption{ID: id, SubjectType: "group", SubjectID: "flat", MeterID: &meterID, MeterValue: value(v), Units: "0.00", RecordedAt: day(d)}
+	}
+
+	meters := &memoryMeters{meters: []models.Meter{
+		{ID: meterID, SubjectType: "group", SubjectID: "flat", UtilityType: "electricity", Unit: "kWh", RolloverAt: value("100000.00"), IsActive: true},
+	}}
+	replacements := &memoryMeterReplacements{}
+	consumptions := &memoryConsumptions{consumptions: []models.Consumption{
+		reading("r1", 1, "99900.00"),
+		// Legacy readings of the same subject are compared among themselves
+		{ID: "l1", SubjectType: "group", SubjectID: "flat", MeterValue: value("500.00"), Units: "0.00", RecordedAt: day(2)},
+	}}
+	s := NewMeterService(meters, replacements, consumptions, nil, nil)
+	sources := meterSources{consumptions: consumptions, meters: meters, replacements: replacements}
+
+	// The first reading of a meter is its baseline
+	units, err := sources.readingUnits(ctx, "group", "flat", &meterID, nil, 99900, day(1))
+	require.NoError(t, err)
+	assert.Equal(t, 0.0, units)
+
+	// The counter rolls over at its rollover value
+	units, err = sources.readingUnits(ctx, "group", "flat", &meterID, nil, 150, day(5))
+	require.NoError(t, err)
+	assert.InDelta(t, 250.0, units, 0.001)
+	consumptions.consumptions = append(consumptions.consumptions, reading("r2", 5, "150.00"))
+
+	units, err = sources.readingUnits(ctx, "group", "flat", nil, nil, 620, day(6))
+	require.NoError(t, err)
+	assert.InDelta(t, 120.0, units, 0.001)
+
+	// Values the counter cannot show are rejected
+	_, err = s.ReplaceMeter(ctx, meterID, ReplaceMeterRequest{ReplacedAt: day(10), OldFinalValue: 100000, NewInitialValue: 0})
+	assert.Error(t, err)
+	replacement, err := s.ReplaceMeter(ctx, meterID, ReplaceMeterRequest{ReplacedAt: day(10), OldFinalValue: 400, NewInitialValue: 10, NewSerialNumber: value("NEW-2")})
+	require.NoError(t, err)
+	assert.Equal(t, "400.00", replacement.OldFinalValue)
+	meter, _ := meters.GetByID(ctx, meterID)
+	assert.Equal(t, "NEW-2", *meter.SerialNumber)
+
+	// 250 units on the old counter, then 90 on the new one
+	units, err = sources.readingUnits(ctx, "group", "flat", &meterID, nil, 100, day(12))
+	require.NoError(t, err)
+	assert.InDelta(t, 340.0, units, 0.001)
+	consumptions.consumptions = append(consumptions.consumptions, reading("r3", 12, "100.00"))
+
+	// Allocation derives the same units from the stored reading
+	allocations := NewAllocationService(nil, nil, nil, nil, nil, consumptions, meters, replacements, nil, nil, nil)
+	units, err = allocations.deriveUnitsFromMeter(ctx, consumptions.consumptions[3])
+	require.NoError(t, err)
+	assert.InDelta(t, 340.0, units, 0.001)
+
+	// Without a rollover value a lower reading is still rejected
+	meters.meters[0].RolloverAt = nil
+	_, err = sources.readingUnits(ctx, "group", "flat", &meterID, nil, 50, day(15))
+	assert.Error(t, err)
+
+	// Each register of a day/night meter counts from its own previous reading
+	dualID := "dual"
+	meters.meters = append(meters.meters, models.Meter{ID: dualID, SubjectType: "group", SubjectID: "flat", Registers: []string{"day", "night"}, IsActive: true})
+	dual, _ := meters.GetByID(ctx, dualID)
+	_, err = validateMeterRegister(dual, nil)
+	assert.Error(t, err)
+	night, err := validateMeterRegister(dual, value(" Night "))
+	require.NoError(t, err)
+	consumptions.consumptions = append(consumptions.consumptions,
+		models.Consumption{ID: "d1", MeterID: &dualID, Register: value("day"), MeterValue: value("1000.00"), RecordedAt: day(1)},
+		models.Consumption{ID: "n1", MeterID: &dualID, Register: night, MeterValue: value("400.00"), RecordedAt: day(1)},
+	)
+	units, err = sources.readingUnits(ctx, "group", "flat", &dualID, night, 460, day(20))
+	require.NoError(t, err)
+	assert.InDelta(t, 60.0, units, 0.001)
+}
+
+func TestMeterRolloverBoundary(t *testing.T) {
+	rolloverAt := "100000.00"
+	meter := &models.Meter{ID: "main", RolloverAt: &rolloverAt}
+
+	// A five digit counter shows 99999 but never 100000
+	assert.NoError(t, validateMeterValue(meter, 99999))
+	assert.Error(t, validateMeterValue(meter, 100000))
+
+	// Rolling over from 99999 to 0 is a single unit
+	delta, err := meterDelta(meter, 99999, 0)
+	require.NoError(t, err)
+	assert.InDelta(t, 1.0, delta, 0.001)
+
+	delta, err = meterDelta(meter, 99998, 2)
+	require.NoError(t, err)
+	assert.InDelta(t, 4.0, delta, 0.001)
+}
@@ -15,25 +15,32 @@ import (
 type PaymentService struct {
 	payments             repository.PaymentRepository
 	bills                repository.BillRepository
+	currencyService      *CurrencyService
 	recurringBillService *RecurringBillService
+	txManager            repository.TxManager
 }
 
 func NewPaymentService(
 	payments repository.PaymentRepository,
 	bills repository.BillRepository,
+	currencyService *CurrencyService,
 	recurringBillService *RecurringBillService,
+	txManager repository.TxManager,
 ) *PaymentService {
 	return &PaymentService{
 		payments:             payments,
 		bills:                bills,
+		currencyService:      currencyService,
 		recurringBillService: recurringBillService,
+		txManager:            txManager,
 	}
 }
 
 type RecordPaymentRequest struct {
-	BillID string  `json:"billId"`
-	Amount float64 `json:"amount"`
-	Method *string `json:"method,omitempty"`
+	BillID   string      `json:"billId"`
+	Amount   utils.Money `json:"amount"`             // in Currency
+	Currency string      `json:"currency,omitempty"` // defaults to the base currency
+	Method   *string     `json:"method,omitempty"`
 }
 
 // RecordPayment records a payment made by a user for a bill
@@ -56,21 +63,40 @@ func (s *PaymentService) RecordPayment(ctx context.Context, req RecordPaymentReq
 		return nil, fmt.Errorf("can only record payments for posted or closed bills (current status: %s)", bill.Status)
 	}
 
+	// Foreign currency payments are converted at the rate of the payment day
+	paidAt := time.Now()
+	converted, err := s.currencyService.ToBase(ctx, req.Amount, req.Currency, paidAt)
+	if err != nil {
+		return nil, err
+	}
+
 	// Create payment record
 	payment := &models.Payment{
-		ID:          uuid.New().String(),
-		BillID:      req.BillID,
-		PayerUserID: userID,
-		AmountPLN:   utils.FloatToDecimalString(req.Amount),
-		PaidAt:      time.Now(),
-		Method:      req.Method,
+		ID:             uuid.New().String(),
+		BillID:         req.BillID,
+		PayerUserID:    userID,
+		AmountPLN:      converted.Amount.String(),
+		Currency:       converted.Currency,
+		OriginalAmount: converted.OriginalAmount,
+		ExchangeRate:   converted.ExchangeRate,
+		PaidAt:         paidAt,
+		Method:         req.Method,
 	}
 
-	if err := s.payments.Create(ctx, payment); err != nil {
-		return nil, fmt.Errorf("failed to record payment: %w", err)
+	err = s.txManager.WithTx(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		if err := repos.Payments.Create(ctx, payment); err != nil {
+			return fmt.Errorf("failed to record payment: %w", err)
+		}
+		if err := syncLedger(ctx, repos.LedgerEntries, paymentLedgerRecord(payment)); err != nil {
+			return fmt.Errorf("failed to post payment to ledger: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	log.Printf("[PAYMENT] Recorded: %.2f PLN for bill %s by user %s (payment ID: %s)", req.Amount, req.BillID, userID, payment.ID)
+	log.Printf("[PAYMENT] Recorded: %s %s for bill %s by user %s (payment ID: %s)", req.Amount, converted.Currency, req.BillID, userID, payment.ID)
 
 	// Check if this payment completes a recurring bill and generate next bill if needed
 	if s.recurringBillService != nil {
@@ -41,6 +41,7 @@ func (s *PermissionService) InitializeDefaultPermissions(ctx context.Context) er
 		{ID: uuid.New().String(), Name: "bills.delete", Description: "Usuń rachunki", Category: "bills"},
 		{ID: uuid.New().String(), Name: "bills.post", Description: "Opublikuj rachunki", Category: "bills"},
 		{ID: uuid.New().String(), Name: "bills.close", Description: "Zamknij rachunki", Category: "bills"},
+		{ID: uuid.New().String(), Name: "tariffs.manage", Description: "Zarządzaj taryfami rachunków", Category: "bills"},
 
 		// Chore management
 		{ID: uuid.New().String(), Name: "chores.create", Description: "Twórz nowe obowiązki", Category: "chores"},
@@ -63,6 +64,7 @@ func (s *PermissionService) InitializeDefaultPermissions(ctx context.Context) er
 
 		// Approval management
 		{ID: uuid.New().String(), Name: "approvals.review", Description: "Przeglądaj i zatwierdź/odrzuć oczekujące akcje", Category: "approvals"},
+		{ID: uuid.New().String(), Name: "approvals.manage", Description: "Zarządzaj zasadami wymagającymi zatwierdzenia", Category: "approvals"},
 
 		// Audit logs
 		{ID: uuid.New().String(), Name: "audit.read", Description: "Przeglądaj logi audytu", Category: "audit"},
@@ -79,10 +81,12 @@ func (s *PermissionService) InitializeDefaultPermissions(ctx context.Context) er
 
 		// Reading management
 		{ID: uuid.New().String(), Name: "readings.delete", Description: "Usuń odczyty liczników", Category: "readings"},
+		{ID: uuid.New().String(), Name: "meters.manage", Description: "Zarządzaj licznikami i ich wymianą", Category: "readings"},
 
 		// Backup management
 		{ID: uuid.New().String(), Name: "backup.export", Description: "Eksportuj kopię zapasową", Category: "backup"},
 		{ID: uuid.New().String(), Name: "backup.import", Description: "Importuj kopię zapasową", Category: "backup"},
+		{ID: uuid.New().String(), Name: "backup.manage", Description: "Usuwaj zapisane kopie zapasowe", Category: "backup"},
 
 		// App settings
 		{ID: uuid.New().String(), Name: "settings.app.update", Description: "Zmień ustawienia aplikacji", Category: "settings"},
@@ -146,16 +150,16 @@ func (s *RoleService) InitializeDefaultRoles(ctx context.Context) error {
 	adminPermissions := []string{
 		"users.create", "users.read", "users.update", "users.delete",
 		"groups.create", "groups.read", "groups.update", "groups.delete",
-		"bills.create", "bills.read", "bills.update", "bills.delete", "bills.post", "bills.close",
+		"bills.create", "bills.read", "bills.update", "bills.delete", "bills.post", "bills.close", "tariffs.manage",
 		"chores.create", "chores.read", "chores.update", "chores.delete", "chores.assign",
 		"supplies.create", "supplies.read", "supplies.update", "supplies.delete",
 		"roles.create", "roles.read", "roles.update", "roles.delete",
-		"approvals.review",
+		"approvals.review", "approvals.manage",
 		"audit.read",
 		"loans.create", "loans.read", "loans.update", "loans.delete",
 		"loan-payments.create", "loan-payments.read", "loan-payments.update", "loan-payments.delete",
-		"readings.delete",
-		"backup.export", "backup.import",
+		"readings.delete", "meters.manage",
+		"backup.export", "backup.import", "backup.manage",
 		"settings.app.update",
 		"reminders.send",
 	}
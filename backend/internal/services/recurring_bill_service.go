@@ -21,6 +21,7 @@ type RecurringBillService struct {
 	bills               repository.BillRepository
 	allocations         repository.AllocationRepository
 	payments            repository.PaymentRepository
+	settlements         repository.SettlementRepository
 	users               repository.UserRepository
 	cfg                 *config.Config
 }
@@ -31,6 +32,7 @@ func NewRecurringBillService(
 	bills repository.BillRepository,
 	allocations repository.AllocationRepository,
 	payments repository.PaymentRepository,
+	settlements repository.SettlementRepository,
 	users repository.UserRepository,
 	cfg *config.Config,
 ) *RecurringBillService {
@@ -40,6 +42,7 @@ func NewRecurringBillService(
 		bills:               bills,
 		allocations:         allocations,
 		payments:            payments,
+		settlements:         settlements,
 		users:               users,
 		cfg:                 cfg,
 	}
@@ -79,6 +82,22 @@ func (s *RecurringBillService) CreateTemplate(ctx context.Context, template *mod
 		template.NextDueDate = s.calculateNextDueDate(template.NextDueDate, template.DayOfMonth, template.Frequency)
 	}
 
+	// A recurrence rule replaces the day of month and decides every due date, including the first
+	if template.RecurrenceRule != nil && *template.RecurrenceRule == "" {
+		template.RecurrenceRule = nil
+	}
+	if template.RecurrenceRule != nil {
+		rule, err := templateRecurrence(template)
+		if err != nil {
+			return fmt.Errorf("invalid recurrence rule: %w", err)
+		}
+		first, ok := rule.After(rule.Start, true)
+		if !ok {
+			return errors.New("recurrence rule has no occurrences")
+		}
+		template.NextDueDate = first
+	}
+
 	if err := s.templates.Create(ctx, template); err != nil {
 		return err
 	}
@@ -183,6 +202,24 @@ func (s *RecurringBillService) UpdateTemplate(ctx context.Context, id string, up
 			template.Notes = &notesStr
 		}
 	}
+	if rawRule, ok := updates["recurrenceRule"]; ok {
+		ruleStr, _ := rawRule.(string)
+		if ruleStr == "" {
+			template.RecurrenceRule = nil
+		} else {
+			template.RecurrenceRule = &ruleStr
+			rule, err := templateRecurrence(template)
+			if err != nil {
+				return fmt.Errorf("invalid recurrence rule: %w", err)
+			}
+			// Continue from the pending due date so no bill is generated twice
+			next, ok := rule.After(template.NextDueDate, true)
+			if !ok {
+				return errors.New("recurrence rule has no further occurrences")
+			}
+			template.NextDueDate = next
+		}
+	}
 
 	template.UpdatedAt = time.Now()
 
@@ -300,8 +337,19 @@ func (s *RecurringBillService) generateBillFromTemplate(ctx context.Context, tem
 
 	now := time.Now()
 
-	// Calculate period based on frequency
+	// Calculate period based on frequency, or from the previous occurrence of the recurrence rule
 	periodStart, periodEnd := s.calculatePeriod(template.NextDueDate, template.Frequency)
+	var rule *utils.Recurrence
+	if template.RecurrenceRule != nil {
+		var err error
+		rule, err = templateRecurrence(template)
+		if err != nil {
+			return fmt.Errorf("invalid recurrence rule: %w", err)
+		}
+		if previous, ok := rule.Before(template.NextDueDate, false); ok {
+			periodStart = previous
+		}
+	}
 
 	// Create the bill
 	allocationType := "simple"
@@ -326,41 +374,63 @@ func (s *RecurringBillService) generateBillFromTemplate(ctx context.Context, tem
 		return err
 	}
 
-	// Create allocations based on template
-	for _, allocTemplate := range template.Allocations {
-		var allocatedAmount string
+	// Create allocations based on template. Percentage and fraction shares are split
+	// together with the largest remainder method so they add up exactly to the amount.
+	amount := utils.MoneyFromString(template.Amount)
+	allocatedAmounts := make([]utils.Money, len(template.Allocations))
+	var shareIndexes []int
+	var shareWeights []float64
+	for i, allocTemplate := range template.Allocations {
+		switch allocTemplate.AllocationType {
+		case "fixed":
+			allocatedAmounts[i] = utils.MoneyFromString(*allocTemplate.FixedAmount)
+		case "percentage":
+			shareIndexes = append(shareIndexes, i)
+			shareWeights = append(shareWeights, *allocTemplate.Percentage/100.0)
+		case "fraction":
+			shareIndexes = append(shareIndexes, i)
+			shareWeights = append(shareWeights, float64(*allocTemplate.FractionNum)/float64(*allocTemplate.FractionDenom))
+		}
+	}
+	if len(shareIndexes) > 0 {
+		for j, share := range amount.Allocate(shareWeights) {
+			allocatedAmounts[shareIndexes[j]] = share
+		}
+	}
+
+	for i, allocTemplate := range template.Allocations {
+		allocatedAmount := allocatedAmounts[i]
 
 		// Debug logging to track allocation creation
 		log.Printf("[RECURRING BILL] Creating allocation - Type: %s, SubjectType: %s", allocTemplate.AllocationType, allocTemplate.SubjectType)
 
 		switch allocTemplate.AllocationType {
 		case "fixed":
-			allocatedAmount = *allocTemplate.FixedAmount
-			amountFloat := utils.DecimalStringToFloat(allocatedAmount)
-			log.Printf("[RECURRING BILL] Fixed allocation: %.2f PLN", amountFloat)
+			log.Printf("[RECURRING BILL] Fixed allocation: %s PLN", allocatedAmount)
 		case "percentage":
-			amountFloat := utils.DecimalStringToFloat(template.Amount)
-			percentage := *allocTemplate.Percentage / 100.0
-			allocatedFloat := amountFloat * percentage
-			roundedAmount := utils.RoundPLN(allocatedFloat)
-			allocatedAmount = utils.FloatToDecimalString(roundedAmount)
-			log.Printf("[RECURRING BILL] Percentage allocation: %.2f%% of %.2f = %.2f PLN", *allocTemplate.Percentage, amountFloat, roundedAmount)
+			log.Printf("[RECURRING BILL] Percentage allocation: %.2f%% of %s = %s PLN", *allocTemplate.Percentage, amount, allocatedAmount)
 		case "fraction":
-			amountFloat := utils.DecimalStringToFloat(template.Amount)
-			fraction := float64(*allocTemplate.FractionNum) / float64(*allocTemplate.FractionDenom)
-			allocatedFloat := amountFloat * fraction
-			roundedAmount := utils.RoundPLN(allocatedFloat)
-			allocatedAmount = utils.FloatToDecimalString(roundedAmount)
-			log.Printf("[RECURRING BILL] Fraction allocation: %d/%d of %.2f = %.2f PLN", *allocTemplate.FractionNum, *allocTemplate.FractionDenom, amountFloat, roundedAmount)
+			log.Printf("[RECURRING BILL] Fraction allocation: %d/%d of %s = %s PLN", *allocTemplate.FractionNum, *allocTemplate.FractionDenom, amount, allocatedAmount)
 		}
 
-		if err := s.allocations.Create(ctx, billID, allocTemplate.SubjectType, allocTemplate.SubjectID, allocatedAmount); err != nil {
+		if err := s.allocations.Create(ctx, billID, allocTemplate.SubjectType, allocTemplate.SubjectID, allocatedAmount.String(), nil); err != nil {
 			return fmt.Errorf("failed to create allocation: %w", err)
 		}
 	}
 
 	// Update template's next due date, current bill ID, and last generated timestamp
 	nextDueDate := s.calculateNextDueDate(template.NextDueDate, template.DayOfMonth, template.Frequency)
+	if rule != nil {
+		next, ok := rule.After(template.NextDueDate, false)
+		if ok {
+			nextDueDate = next
+		} else {
+			// The series has ended (COUNT or UNTIL), so no more bills are generated
+			nextDueDate = template.NextDueDate
+			template.IsActive = false
+			log.Printf("[RECURRING BILL] Recurrence of template %q has ended, deactivating it", template.CustomType)
+		}
+	}
 	template.CurrentBillID = &billID
 	template.NextDueDate = nextDueDate
 	template.LastGeneratedAt = &now
@@ -376,6 +446,13 @@ func (s *RecurringBillService) generateBillFromTemplate(ctx context.Context, tem
 	return nil
 }
 
+// templateRecurrence parses the template's recurrence rule. Rules without a DTSTART start
+// on the template's start date.
+func templateRecurrence(template *models.RecurringBillTemplate) (*utils.Recurrence, error) {
+	start := template.StartDate.UTC()
+	return utils.ParseRecurrence(*template.RecurrenceRule, time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, time.UTC))
+}
+
 // calculateNextDueDate calculates the next due date based on frequency
 func (s *RecurringBillService) calculateNextDueDate(from time.Time, dayOfMonth int, frequency string) time.Time {
 	var next time.Time
@@ -469,27 +546,19 @@ func (s *RecurringBillService) CheckAndGenerateNextBill(ctx context.Context, bil
 		return err
 	}
 
-	// Get all payments for this bill
-	payments, err := s.payments.ListByBillID(ctx, billID)
+	// Build a map of total amount paid by each user
+	paymentMap, err := billPaidByUser(ctx, s.payments, s.settlements, billID)
 	if err != nil {
 		return err
 	}
 
-	// Build a map of total amount paid by each user
-	paymentMap := make(map[string]float64)
-	for _, payment := range payments {
-		paidFloat := utils.DecimalStringToFloat(payment.AmountPLN)
-		paymentMap[payment.PayerUserID] += paidFloat
-	}
-
 	// Check if all users with allocations have paid their full amount
 	allPaid := true
 	for _, alloc := range storedAllocations {
-		allocFloat := utils.DecimalStringToFloat(alloc.AllocatedPLN)
+		allocated := utils.MoneyFromString(alloc.AllocatedPLN)
 
 		if alloc.SubjectType == "user" {
-			paidFloat := paymentMap[alloc.SubjectID]
-			if paidFloat < allocFloat-0.01 { // Allow 1 cent tolerance for rounding
+			if paymentMap[alloc.SubjectID] < allocated {
 				allPaid = false
 				break
 			}
@@ -501,12 +570,12 @@ func (s *RecurringBillService) CheckAndGenerateNextBill(ctx context.Context, bil
 			}
 
 			// Calculate total paid by all group members
-			totalPaidFloat := 0.0
+			var totalPaid utils.Money
 			for _, user := range groupUsers {
-				totalPaidFloat += paymentMap[user.ID]
+				totalPaid += paymentMap[user.ID]
 			}
 
-			if totalPaidFloat < allocFloat-0.01 { // Allow 1 cent tolerance for rounding
+			if totalPaid < allocated {
 				allPaid = false
 				break
 			}
@@ -91,7 +91,7 @@ func (s *ReminderService) SendDebtReminder(ctx context.Context, targetUserID, se
 			UserID:     &targetUserID,
 			TemplateID: "debt_reminder",
 			Title:      "Przypomnienie o zadłużeniu",
-			Body:       fmt.Sprintf("%s przypomina o spłacie %.2f zł", sender.Name, debt),
+			Body:       fmt.Sprintf("%s przypomina o spłacie %s zł", sender.Name, debt),
 		})
 	}
 
@@ -251,13 +251,13 @@ func (s *ReminderService) SendLowSuppliesReminder(ctx context.Context, senderUse
 }
 
 // calculateDebt calculates how much borrowerID owes to lenderID
-func (s *ReminderService) calculateDebt(ctx context.Context, borrowerID, lenderID string) (float64, error) {
+func (s *ReminderService) calculateDebt(ctx context.Context, borrowerID, lenderID string) (utils.Money, error) {
 	loans, err := s.loans.ListByBorrowerID(ctx, borrowerID)
 	if err != nil {
 		return 0, err
 	}
 
-	totalDebt := 0.0
+	var totalDebt utils.Money
 	for _, loan := range loans {
 		if loan.LenderID != lenderID {
 			continue
@@ -266,12 +266,12 @@ func (s *ReminderService) calculateDebt(ctx context.Context, borrowerID, lenderI
 			continue
 		}
 
-		loanAmount := utils.DecimalStringToFloat(loan.AmountPLN)
+		loanAmount := utils.MoneyFromString(loan.AmountPLN)
 		sumStr, err := s.loanPayments.SumByLoanID(ctx, loan.ID)
 		if err != nil {
 			continue
 		}
-		totalPaid := utils.DecimalStringToFloat(sumStr)
+		totalPaid := utils.MoneyFromString(sumStr)
 		remaining := loanAmount - totalPaid
 		if remaining > 0 {
 			totalDebt += remaining
@@ -281,16 +281,16 @@ func (s *SchedulerService) CheckLoanReminders(ctx context.Context) error {
 
 		// Calculate remaining amount
 		totalPaidStr, _ := s.loanPayments.SumByLoanID(ctx, loan.ID)
-		totalPaid := utils.DecimalStringToFloat(totalPaidStr)
-		loanAmount := utils.DecimalStringToFloat(loan.AmountPLN)
+		totalPaid := utils.MoneyFromString(totalPaidStr)
+		loanAmount := utils.MoneyFromString(loan.AmountPLN)
 		remaining := loanAmount - totalPaid
 
 		// Create notification
 		if s.notificationService != nil {
 			daysLeft := int(time.Until(*loan.DueDate).Hours() / 24)
-			body := fmt.Sprintf("Pożyczka od %s (%.2f zł) - termin za %d dni", lenderName, remaining, daysLeft)
+			body := fmt.Sprintf("Pożyczka od %s (%s zł) - termin za %d dni", lenderName, remaining, daysLeft)
 			if daysLeft <= 0 {
-				body = fmt.Sprintf("Pożyczka od %s (%.2f zł) - termin minął!", lenderName, remaining)
+				body = fmt.Sprintf("Pożyczka od %s (%s zł) - termin minął!", lenderName, remaining)
 			}
 
 			_ = s.notificationService.CreateNotification(ctx, &models.Notification{
@@ -0,0 +1,787 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/bits"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/sainaif/holy-home/internal/utils"
+)
+
+// ErrSettlementPlanStale is returned when balances changed between computing and applying a plan
+var ErrSettlementPlanStale = errors.New("balances changed since the settlement plan was computed")
+
+// ErrNothingToSettle is returned when applying a plan with no open balances
+var ErrNothingToSettle = errors.New("nothing to settle")
+
+// maxExactSettlementParties bounds the exact minimum-transfer search (2^n subsets).
+// Larger households fall back to the greedy largest-debtor/largest-creditor plan.
+const maxExactSettlementParties = 16
+
+// SettlementService computes the minimum set of transfers that clears all open
+// loans and all bill shares that one resident fronted for another.
+type SettlementService struct {
+	users              repository.UserRepository
+	groups             repository.GroupRepository
+	residencies        repository.ResidencyRepository
+	groupWeightChanges repository.GroupWeightChangeRepository
+	membershipChanges  repository.GroupMembershipChangeRepository
+	bills              repository.BillRepository
+	consumptions       repository.ConsumptionRepository
+	meters             repository.MeterRepository
+	meterReplacements  repository.MeterReplacementRepository
+	tariffs            repository.TariffRepository
+	allocations        repository.AllocationRepository
+	payments           repository.PaymentRepository
+	loans              repository.LoanRepository
+	loanPayments       repository.LoanPaymentRepository
+	settlements        repository.SettlementRepository
+	txManager          repository.TxManager
+}
+
+func NewSettlementService(
+	users repository.UserRepository,
+	groups repository.GroupRepository,
+	residencies repository.ResidencyRepository,
+	groupWeightChanges repository.GroupWeightChangeRepository,
+	membershipChanges repository.GroupMembershipChangeRepository,
+	bills repository.BillRepository,
+	consumptions repository.ConsumptionRepository,
+	meters repository.MeterRepository,
+	meterReplacements repository.MeterReplacementRepository,
+	tariffs repository.TariffRepository,
+	allocations repository.AllocationRepository,
+	payments repository.PaymentRepository,
+	loans repository.LoanRepository,
+	loanPayments repository.LoanPaymentRepository,
+	settlements repository.SettlementRepository,
+	txManager repository.TxManager,
+) *SettlementService {
+	return &SettlementService{
+		users:              users,
+		groups:             groups,
+		residencies:        residencies,
+		groupWeightChanges: groupWeightChanges,
+		membershipChanges:  membershipChanges,
+		bills:              bills,
+		consumptions:       consumptions,
+		meters:             meters,
+		meterReplacements:  meterReplacements,
+		tariffs:            tariffs,
+		allocations:        allocations,
+		payments:           payments,
+		loans:              loans,
+		loanPayments:       loanPayments,
+		settlements:        settlements,
+		txManager:          txManager,
+	}
+}
+
+// SettlementParty is a group, whose members settle as one, or a user outside any group
+type SettlementParty struct {
+	SubjectType string      `json:"subjectType"` // "user" or "group"
+	SubjectID   string      `json:"subjectId"`
+	Name        string      `json:"name"`
+	Balance     utils.Money `json:"balance"` // positive: is owed money, negative: owes money
+}
+
+// SettlementTransfer is a single payment in a settlement plan. A group pays and is paid
+// through one of its members: the one whose own debts or claims weigh most.
+type SettlementTransfer struct {
+	FromSubjectType string      `json:"fromSubjectType"`
+	FromSubjectID   string      `json:"fromSubjectId"`
+	FromName        string      `json:"fromName"`
+	FromUserID      string      `json:"fromUserId"`
+	ToSubjectType   string      `json:"toSubjectType"`
+	ToSubjectID     string      `json:"toSubjectId"`
+	ToName          string      `json:"toName"`
+	ToUserID        string      `json:"toUserId"`
+	Amount          utils.Money `json:"amount"`
+}
+
+// SettlementPlan lists the transfers that settle every open balance in the household.
+// Debts between members of the same group are settled directly between them.
+type SettlementPlan struct {
+	Transfers         []SettlementTransfer `json:"transfers"`
+	Parties           []SettlementParty    `json:"parties"`
+	OpenLoans         int                  `json:"openLoans"`
+	BillsWithBalances int                  `json:"billsWithBalances"`
+	// Unpaid bill shares that nobody fronted are still owed to the biller, not to residents
+	OutstandingBillsPLN utils.Money `json:"outstandingBillsPLN"`
+	// Fingerprint identifies the balances the plan was computed from; pass it back to apply the plan
+	Fingerprint string    `json:"fingerprint"`
+	GeneratedAt time.Time `json:"generatedAt"`
+
+	loanSettlements []loanSettlement
+	billAdjustments []billAdjustment
+}
+
+// SettlementResult describes an applied settlement plan
+type SettlementResult struct {
+	Plan              *SettlementPlan `json:"plan"`
+	TransfersRecorded int             `json:"transfersRecorded"`
+	LoansSettled      int             `json:"loansSettled"`
+	BillSharesSettled int             `json:"billSharesSettled"`
+}
+
+// loanSettlement is the remaining amount of an open loan
+type loanSettlement struct {
+	loan   models.Loan
+	amount utils.Money
+}
+
+// billAdjustment moves a fronted bill share between residents: a positive amount is
+// covered for the resident who underpaid, a negative amount is paid back to the one who
+// overpaid
+type billAdjustment struct {
+	billID string
+	userID string
+	amount utils.Money
+}
+
+// settlementSources are the repositories a plan is computed from
+type settlementSources struct {
+	users             repository.UserRepository
+	groups            repository.GroupRepository
+	membershipChanges repository.GroupMembershipChangeRepository
+	bills             repository.BillRepository
+	allocations       *AllocationService
+	payments          repository.PaymentRepository
+	loans             repository.LoanRepository
+	loanPayments      repository.LoanPaymentRepository
+	settlements       repository.SettlementRepository
+}
+
+// GetPlan computes the settlement plan for the current balances
+func (s *SettlementService) GetPlan(ctx context.Context) (*SettlementPlan, error) {
+	return buildSettlementPlan(ctx, settlementSources{
+		users:             s.users,
+		groups:            s.groups,
+		membershipChanges: s.membershipChanges,
+		bills:             s.bills,
+		allocations:       NewAllocationService(s.users, s.groups, s.residencies, s.groupWeightChanges, s.membershipChanges, s.consumptions, s.meters, s.meterReplacements, s.tariffs, s.allocations, s.bills),
+		payments:          s.payments,
+		loans:             s.loans,
+		loanPayments:      s.loanPayments,
+		settlements:       s.settlements,
+	})
+}
+
+// ApplyPlan records the plan identified by fingerprint in one transaction. Its transfers
+// are stored under the fingerprint and posted to the ledger; the debts they clear are
+// closed by pointing at them: every open loan gets a LoanPayment for its remaining amount
+// that names the settlement, and fronted bill shares are recorded as settled. Returns
+// ErrSettlementPlanStale if balances changed in the meantime.
+func (s *SettlementService) ApplyPlan(ctx context.Context, fingerprint string) (*SettlementResult, error) {
+	var result *SettlementResult
+
+	err := s.txManager.WithTx(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		plan, err := buildSettlementPlan(ctx, settlementSources{
+			users:             repos.Users,
+			groups:            repos.Groups,
+			membershipChanges: repos.GroupMembershipChanges,
+			bills:             repos.Bills,
+			allocations:       NewAllocationService(repos.Users, repos.Groups, repos.Residencies, repos.GroupWeightChanges, repos.GroupMembershipChanges, repos.Consumptions, repos.Meters, repos.MeterReplacements, repos.Tariffs, repos.Allocations, repos.Bills),
+			payments:          repos.Payments,
+			loans:             repos.Loans,
+			loanPayments:      repos.LoanPayments,
+			settlements:       repos.Settlements,
+		})
+		if err != nil {
+			return err
+		}
+
+		if plan.Fingerprint != fingerprint {
+			return ErrSettlementPlanStale
+		}
+		if len(plan.loanSettlements) == 0 && len(plan.billAdjustments) == 0 {
+			return ErrNothingToSettle
+		}
+
+		now := time.Now()
+		note := fmt.Sprintf("Rozliczenie %s", now.Format("2006-01-02"))
+
+		for _, t := range plan.Transfers {
+			if t.FromUserID == "" || t.ToUserID == "" {
+				return fmt.Errorf("transfer from %s to %s has no member to carry it out", t.FromName, t.ToName)
+			}
+			transfer := models.SettlementTransfer{
+				ID:          uuid.New().String(),
+				Fingerprint: plan.Fingerprint,
+				FromUserID:  t.FromUserID,
+				ToUserID:    t.ToUserID,
+				AmountPLN:   t.Amount.String(),
+				CreatedAt:   now,
+			}
+			if err := repos.Settlements.CreateTransfer(ctx, &transfer); err != nil {
+				return fmt.Errorf("failed to record settlement transfer: %w", err)
+			}
+			if err := syncLedger(ctx, repos.LedgerEntries, settlementTransferLedgerRecord(&transfer)); err != nil {
+				return fmt.Errorf("failed to post settlement transfer to ledger: %w", err)
+			}
+		}
+
+		for _, settlement := range plan.loanSettlements {
+			payment := models.LoanPayment{
+				ID:                    uuid.New().String(),
+				LoanID:                settlement.loan.ID,
+				AmountPLN:             settlement.amount.String(),
+				PaidAt:                now,
+				Note:                  &note,
+				SettlementFingerprint: &plan.Fingerprint,
+			}
+			if err := repos.LoanPayments.Create(ctx, &payment); err != nil {
+				return fmt.Errorf("failed to create loan payment: %w", err)
+			}
+
+			loan := settlement.loan
+			loan.Status = "settled"
+			if err := repos.Loans.Update(ctx, &loan); err != nil {
+				return fmt.Errorf("failed to update loan status: %w", err)
+			}
+		}
+
+		for _, adjustment := range plan.billAdjustments {
+			share := models.SettlementBillShare{
+				ID:          uuid.New().String(),
+				Fingerprint: plan.Fingerprint,
+				BillID:      adjustment.billID,
+				UserID:      adjustment.userID,
+				AmountPLN:   adjustment.amount.String(),
+				CreatedAt:   now,
+			}
+			if err := repos.Settlements.CreateBillShare(ctx, &share); err != nil {
+				return fmt.Errorf("failed to record settled bill share: %w", err)
+			}
+		}
+
+		result = &SettlementResult{
+			Plan:              plan,
+			TransfersRecorded: len(plan.Transfers),
+			LoansSettled:      len(plan.loanSettlements),
+			BillSharesSettled: len(plan.billAdjustments),
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("[SETTLEMENT] Applied plan %s: %d transfers, %d loans, %d bill shares",
+		fingerprint[:12], result.TransfersRecorded, result.LoansSettled, result.BillSharesSettled)
+
+	return result, nil
+}
+
+func buildSettlementPlan(ctx context.Context, src settlementSources) (*SettlementPlan, error) {
+	users, err := src.users.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch users: %w", err)
+	}
+	groups, err := src.groups.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch groups: %w", err)
+	}
+
+	parties := make(map[string]*SettlementParty)
+	userParty := make(map[string]string)
+	userNames := make(map[string]string)
+	groupMembers := make(map[string][]string)
+	for _, g := range groups {
+		parties[settlementPartyKey("group", g.ID)] = &SettlementParty{SubjectType: "group", SubjectID: g.ID, Name: g.Name}
+	}
+	for _, u := range users {
+		userNames[u.ID] = u.Name
+		if u.GroupID != nil {
+			if _, ok := parties[settlementPartyKey("group", *u.GroupID)]; ok {
+				userParty[u.ID] = settlementPartyKey("group", *u.GroupID)
+				groupMembers[*u.GroupID] = append(groupMembers[*u.GroupID], u.ID)
+				continue
+			}
+		}
+		key := settlementPartyKey("user", u.ID)
+		userParty[u.ID] = key
+		parties[key] = &SettlementParty{SubjectType: "user", SubjectID: u.ID, Name: u.Name}
+	}
+	for _, members := range groupMembers {
+		sort.Strings(members)
+	}
+
+	partyOf := func(subjectType, subjectID string) string {
+		if subjectType == "group" {
+			return settlementPartyKey("group", subjectID)
+		}
+		if key, ok := userParty[subjectID]; ok {
+			return key
+		}
+		return settlementPartyKey("user", subjectID)
+	}
+
+	// Bills are settled between the groups of their period, so a user who changed groups
+	// since still pays and is paid back for the group they belonged to then
+	membershipChanges, err := src.membershipChanges.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch group history: %w", err)
+	}
+	membershipChangesByUser := make(map[string][]models.GroupMembershipChange)
+	for _, c := range membershipChanges {
+		membershipChangesByUser[c.UserID] = append(membershipChangesByUser[c.UserID], c)
+	}
+	partiesOn := func(day time.Time) (func(subjectType, subjectID string) string, map[string][]string) {
+		billParty := make(map[string]string)
+		billMembers := make(map[string][]string)
+		for _, u := range users {
+			groupID := groupOn(u, membershipChangesByUser[u.ID], day)
+			if groupID != nil {
+				if _, ok := parties[settlementPartyKey("group", *groupID)]; ok {
+					billParty[u.ID] = settlementPartyKey("group", *groupID)
+					billMembers[*groupID] = append(billMembers[*groupID], u.ID)
+					continue
+				}
+			}
+			billParty[u.ID] = settlementPartyKey("user", u.ID)
+		}
+		for _, members := range billMembers {
+			sort.Strings(members)
+		}
+		return func(subjectType, subjectID string) string {
+			if subjectType == "group" {
+				return settlementPartyKey("group", subjectID)
+			}
+			if key, ok := billParty[subjectID]; ok {
+				return key
+			}
+			return settlementPartyKey("user", subjectID)
+		}, billMembers
+	}
+
+	plan := &SettlementPlan{Parties: []SettlementParty{}, Transfers: []SettlementTransfer{}, GeneratedAt: time.Now()}
+	nets := make(map[string]utils.Money)
+	userNets := make(map[string]utils.Money)      // each user's part of their party's net
+	intraGroup := make(map[[2]string]utils.Money) // [debtor, creditor] user IDs within one group
+
+	// Open loans
+	loans, err := src.loans.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch loans: %w", err)
+	}
+	sort.Slice(loans, func(i, j int) bool { return loans[i].ID < loans[j].ID })
+	for _, loan := range loans {
+		if loan.Status == "settled" {
+			continue
+		}
+		paid, err := src.loanPayments.SumByLoanID(ctx, loan.ID)
+		if err != nil {
+			return nil, fmt.Errorf("database error: %w", err)
+		}
+		remaining := utils.MoneyFromString(loan.AmountPLN) - utils.MoneyFromString(paid)
+		if remaining <= 0 {
+			continue
+		}
+
+		plan.loanSettlements = append(plan.loanSettlements, loanSettlement{loan: loan, amount: remaining})
+		plan.OpenLoans++
+
+		borrower := partyOf("user", loan.BorrowerID)
+		lender := partyOf("user", loan.LenderID)
+		if borrower == lender {
+			intraGroup[[2]string{loan.BorrowerID, loan.LenderID}] += remaining
+			continue
+		}
+		nets[borrower] -= remaining
+		nets[lender] += remaining
+		userNets[loan.BorrowerID] -= remaining
+		userNets[loan.LenderID] += remaining
+	}
+
+	// Bill shares fronted by other residents
+	bills, err := src.bills.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch bills: %w", err)
+	}
+	sort.Slice(bills, func(i, j int) bool { return bills[i].ID < bills[j].ID })
+	for _, bill := range bills {
+		if bill.Status == "draft" {
+			continue
+		}
+		billPartyOf, billMembers := partiesOn(residencyDay(bill.PeriodStart))
+		adjustments, outstanding, err := settleBill(ctx, src, bill.ID, billPartyOf, billMembers)
+		if err != nil {
+			return nil, err
+		}
+		plan.OutstandingBillsPLN += outstanding
+		if len(adjustments) == 0 {
+			continue
+		}
+
+		plan.BillsWithBalances++
+		for _, adjustment := range adjustments {
+			nets[partyOf("user", adjustment.userID)] -= adjustment.amount
+			userNets[adjustment.userID] -= adjustment.amount
+		}
+		plan.billAdjustments = append(plan.billAdjustments, adjustments...)
+	}
+
+	// Minimum transfers between parties
+	balances := make([]partyBalance, 0, len(nets))
+	for key, amount := range nets {
+		if amount == 0 {
+			continue
+		}
+		balances = append(balances, partyBalance{key: key, amount: amount})
+		party := parties[key]
+		if party == nil {
+			subjectType, subjectID, _ := strings.Cut(key, ":")
+			party = &SettlementParty{SubjectType: subjectType, SubjectID: subjectID, Name: userNames[subjectID]}
+			parties[key] = party
+		}
+		party.Balance = amount
+		plan.Parties = append(plan.Parties, *party)
+	}
+	sort.Slice(plan.Parties, func(i, j int) bool {
+		return settlementPartyKey(plan.Parties[i].SubjectType, plan.Parties[i].SubjectID) < settlementPartyKey(plan.Parties[j].SubjectType, plan.Parties[j].SubjectID)
+	})
+
+	// A group pays through the member who owes most and is paid through the one owed most
+	member := func(party *SettlementParty, paying bool) string {
+		if party.SubjectType == "user" {
+			return party.SubjectID
+		}
+		best, bestNet := "", utils.Money(0)
+		for _, userID := range groupMembers[party.SubjectID] {
+			net := userNets[userID]
+			if paying {
+				net = -net
+			}
+			if best == "" || net > bestNet {
+				best, bestNet = userID, net
+			}
+		}
+		return best
+	}
+
+	for _, t := range minimumTransfers(balances) {
+		from, to := parties[t.from], parties[t.to]
+		plan.Transfers = append(plan.Transfers, SettlementTransfer{
+			FromSubjectType: from.SubjectType,
+			FromSubjectID:   from.SubjectID,
+			FromName:        from.Name,
+			FromUserID:      member(from, true),
+			ToSubjectType:   to.SubjectType,
+			ToSubjectID:     to.SubjectID,
+			ToName:          to.Name,
+			ToUserID:        member(to, false),
+			Amount:          t.amount,
+		})
+	}
+
+	// Debts inside a group are settled directly between the members, netted per pair
+	pairs := make([][2]string, 0, len(intraGroup))
+	for pair := range intraGroup {
+		if pair[0] < pair[1] {
+			pairs = append(pairs, pair)
+		} else if _, ok := intraGroup[[2]string{pair[1], pair[0]}]; !ok {
+			pairs = append(pairs, [2]string{pair[1], pair[0]})
+		}
+	}
+	sort.Slice(pairs, func(i, j int) bool {
+		return pairs[i][0] < pairs[j][0] || (pairs[i][0] == pairs[j][0] && pairs[i][1] < pairs[j][1])
+	})
+	for _, pair := range pairs {
+		from, to := pair[0], pair[1]
+		amount := intraGroup[[2]string{from, to}] - intraGroup[[2]string{to, from}]
+		if amount == 0 {
+			continue
+		}
+		if amount < 0 {
+			from, to, amount = to, from, -amount
+		}
+		plan.Transfers = append(plan.Transfers, SettlementTransfer{
+			FromSubjectType: "user",
+			FromSubjectID:   from,
+			FromName:        userNames[from],
+			FromUserID:      from,
+			ToSubjectType:   "user",
+			ToSubjectID:     to,
+			ToName:          userNames[to],
+			ToUserID:        to,
+			Amount:          amount,
+		})
+	}
+
+	plan.Fingerprint = settlementFingerprint(plan)
+	return plan, nil
+}
+
+// settleBill works out who fronted whose share of a bill. Each party's net is what its
+// members paid, plus shares earlier settlements cleared, minus its allocation; the fronted amount is the smaller of total overpaid
+// and total underpaid, split proportionally on both sides. The rest of the underpayment
+// is still owed to the biller and returned as outstanding.
+func settleBill(ctx context.Context, src settlementSources, billID string, partyOf func(subjectType, subjectID string) string, groupMembers map[string][]string) ([]billAdjustment, utils.Money, error) {
+	allocations, err := src.allocations.GetAllocationBreakdown(ctx, billID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch allocations: %w", err)
+	}
+	if len(allocations) == 0 {
+		return nil, 0, nil
+	}
+	payments, err := src.payments.ListByBillID(ctx, billID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch payments: %w", err)
+	}
+
+	shares, err := src.settlements.ListBillSharesByBillID(ctx, billID)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to fetch settled bill shares: %w", err)
+	}
+
+	nets := make(map[string]utils.Money)
+	paidByUser := make(map[string]map[string]utils.Money) // party -> user -> paid
+	for _, alloc := range allocations {
+		nets[partyOf(alloc.SubjectType, alloc.SubjectID)] -= alloc.Amount
+	}
+	paid := func(userID string, amount utils.Money) {
+		party := partyOf("user", userID)
+		nets[party] += amount
+		if paidByUser[party] == nil {
+			paidByUser[party] = make(map[string]utils.Money)
+		}
+		paidByUser[party][userID] += amount
+	}
+	for _, payment := range payments {
+		paid(payment.PayerUserID, utils.MoneyFromString(payment.AmountPLN))
+	}
+	for _, share := range shares {
+		paid(share.UserID, utils.MoneyFromString(share.AmountPLN))
+	}
+
+	// The member who paid most acts for a group; otherwise its first member
+	representative := func(party string) string {
+		subjectType, subjectID, _ := strings.Cut(party, ":")
+		if subjectType == "user" {
+			return subjectID
+		}
+		best := ""
+		for userID, paid := range paidByUser[party] {
+			if best == "" || paid > paidByUser[party][best] || (paid == paidByUser[party][best] && userID < best) {
+				best = userID
+			}
+		}
+		if best == "" && len(groupMembers[subjectID]) > 0 {
+			best = groupMembers[subjectID][0]
+		}
+		return best
+	}
+
+	var creditors, debtors []partyBalance
+	var overpaid, underpaid utils.Money
+	for party, net := range nets {
+		switch {
+		case net > 0:
+			creditors = append(creditors, partyBalance{key: party, amount: net})
+			overpaid += net
+		case net < 0:
+			if representative(party) == "" {
+				continue // nobody left to collect from
+			}
+			debtors = append(debtors, partyBalance{key: party, amount: -net})
+			underpaid -= net
+		}
+	}
+
+	fronted := utils.MinMoney(overpaid, underpaid)
+	outstanding := underpaid - fronted
+	if fronted <= 0 {
+		return nil, outstanding, nil
+	}
+
+	sort.Slice(creditors, func(i, j int) bool { return creditors[i].key < creditors[j].key })
+	sort.Slice(debtors, func(i, j int) bool { return debtors[i].key < debtors[j].key })
+
+	var adjustments []billAdjustment
+	for i, share := range fronted.Allocate(balanceWeights(debtors)) {
+		if share > 0 {
+			adjustments = append(adjustments, billAdjustment{billID: billID, userID: representative(debtors[i].key), amount: share})
+		}
+	}
+	for i, share := range fronted.Allocate(balanceWeights(creditors)) {
+		if share > 0 {
+			adjustments = append(adjustments, billAdjustment{billID: billID, userID: representative(creditors[i].key), amount: -share})
+		}
+	}
+
+	return adjustments, outstanding, nil
+}
+
+// partyBalance is a party's net position: positive is owed money, negative owes money
+type partyBalance struct {
+	key    string
+	amount utils.Money
+}
+
+type settlementTransfer struct {
+	from, to string
+	amount   utils.Money
+}
+
+// minimumTransfers returns the fewest transfers that bring every balance to zero.
+// The balances must sum to zero. Any set of n balances can be settled in n-1 transfers,
+// and every subset that sums to zero on its own saves one more, so the optimum is
+// n minus the largest number of disjoint zero-sum subsets, found by a subset DP.
+func minimumTransfers(balances []partyBalance) []settlementTransfer {
+	var nonZero []partyBalance
+	for _, b := range balances {
+		if b.amount != 0 {
+			nonZero = append(nonZero, b)
+		}
+	}
+	sort.Slice(nonZero, func(i, j int) bool { return nonZero[i].key < nonZero[j].key })
+
+	n := len(nonZero)
+	if n == 0 {
+		return nil
+	}
+	if n > maxExactSettlementParties || utils.SumMoney(balanceAmounts(nonZero)...) != 0 {
+		return greedyTransfers(nonZero)
+	}
+
+	size := 1 << n
+	sums := make([]utils.Money, size)
+	zeroSubsets := make([]int, size) // most disjoint zero-sum subsets a mask splits into
+	for mask := 1; mask < size; mask++ {
+		lowest := bits.TrailingZeros(uint(mask))
+		sums[mask] = sums[mask&(mask-1)] + nonZero[lowest].amount
+
+		best := 0
+		for m := mask; m != 0; m &= m - 1 {
+			if v := zeroSubsets[mask&^(1<<bits.TrailingZeros(uint(m)))]; v > best {
+				best = v
+			}
+		}
+		if sums[mask] == 0 {
+			best++
+		}
+		zeroSubsets[mask] = best
+	}
+
+	// Walk back from the full set; each time the remaining set sums to zero,
+	// the members removed since the previous such point form one subset
+	var transfers []settlementTransfer
+	var subset []partyBalance
+	for mask := size - 1; mask != 0; {
+		bonus := 0
+		if sums[mask] == 0 {
+			bonus = 1
+		}
+		for m := mask; m != 0; m &= m - 1 {
+			i := bits.TrailingZeros(uint(m))
+			if zeroSubsets[mask&^(1<<i)]+bonus == zeroSubsets[mask] {
+				subset = append(subset, nonZero[i])
+				mask &^= 1 << i
+				break
+			}
+		}
+		if mask == 0 || sums[mask] == 0 {
+			transfers = append(transfers, greedyTransfers(subset)...)
+			subset = nil
+		}
+	}
+	return transfers
+}
+
+// greedyTransfers repeatedly pays the largest creditor from the largest debtor
+func greedyTransfers(balances []partyBalance) []settlementTransfer {
+	remaining := make([]partyBalance, len(balances))
+	copy(remaining, balances)
+
+	var transfers []settlementTransfer
+	for {
+		debtor, creditor := -1, -1
+		for i, b := range remaining {
+			if b.amount < 0 && (debtor == -1 || b.amount < remaining[debtor].amount) {
+				debtor = i
+			}
+			if b.amount > 0 && (creditor == -1 || b.amount > remaining[creditor].amount) {
+				creditor = i
+			}
+		}
+		if debtor == -1 || creditor == -1 {
+			return transfers
+		}
+
+		amount := utils.MinMoney(-remaining[debtor].amount, remaining[creditor].amount)
+		transfers = append(transfers, settlementTransfer{from: remaining[debtor].key, to: remaining[creditor].key, amount: amount})
+		remaining[debtor].amount += amount
+		remaining[creditor].amount -= amount
+	}
+}
+
+func balanceAmounts(balances []partyBalance) []utils.Money {
+	amounts := make([]utils.Money, len(balances))
+	for i, b := range balances {
+		amounts[i] = b.amount
+	}
+	return amounts
+}
+
+func balanceWeights(balances []partyBalance) []float64 {
+	weights := make([]float64, len(balances))
+	for i, b := range balances {
+		weights[i] = float64(b.amount.Abs().Minor())
+	}
+	return weights
+}
+
+func settlementPartyKey(subjectType, subjectID string) string {
+	return subjectType + ":" + subjectID
+}
+
+// settlementFingerprint hashes everything ApplyPlan would write
+func settlementFingerprint(plan *SettlementPlan) string {
+	lines := make([]string, 0, len(plan.loanSettlements)+len(plan.billAdjustments)+len(plan.Transfers))
+	for _, s := range plan.loanSettlements {
+		lines = append(lines, fmt.Sprintf("loan|%s|%s", s.loan.ID, s.amount))
+	}
+	for _, a := range plan.billAdjustments {
+		lines = append(lines, fmt.Sprintf("bill|%s|%s|%s", a.billID, a.userID, a.amount))
+	}
+	for _, t := range plan.Transfers {
+		lines = append(lines, fmt.Sprintf("transfer|%s|%s|%s", t.FromUserID, t.ToUserID, t.Amount))
+	}
+	sort.Strings(lines)
+
+	sum := sha256.Sum256([]byte(strings.Join(lines, "\n")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// billPaidByUser sums what each user paid towards a bill. Shares a settlement cleared count
+// as paid by the resident whose share was covered and are taken off the one who fronted it.
+func billPaidByUser(ctx context.Context, payments repository.PaymentRepository, settlements repository.SettlementRepository, billID string) (map[string]utils.Money, error) {
+	billPayments, err := payments.ListByBillID(ctx, billID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list payments: %w", err)
+	}
+	shares, err := settlements.ListBillSharesByBillID(ctx, billID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list settled bill shares: %w", err)
+	}
+
+	paid := make(map[string]utils.Money)
+	for _, payment := range billPayments {
+		paid[payment.PayerUserID] += utils.MoneyFromString(payment.AmountPLN)
+	}
+	for _, share := range shares {
+		paid[share.UserID] += utils.MoneyFromString(share.AmountPLN)
+	}
+	return paid, nil
+}
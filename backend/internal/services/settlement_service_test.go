@@ -0,0 +1,415 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/database"
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/sainaif/holy-home/internal/repository/sqlite"
+	"github.com/sainaif/holy-home/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func applyTransfers(balances []partyBalance, transfers []settlementTransfer) map[string]utils.Money {
+	result := make(map[string]utils.Money)
+	for _, b := range balances {
+		result[b.key] = b.amount
+	}
+	for _, t := range transfers {
+		result[t.from] += t.amount
+		result[t.to] -= t.amount
+	}
+	return result
+}
+
+func TestMinimumTransfers(t *testing.T) {
+	m := utils.MoneyFromString
+
+	tests := []struct {
+		name      string
+		balances  []partyBalance
+		transfers int
+	}{
+		{"Nothing to settle", nil, 0},
+		{"Single debt", []partyBalance{{"a", m("-50")}, {"b", m("50")}}, 1},
+		{"Chain collapses to one transfer", []partyBalance{{"a", m("-30")}, {"b", m("0")}, {"c", m("30")}}, 1},
+		{"Five residents", []partyBalance{
+			{"a", m("-120.50")}, {"b", m("-35.25")}, {"c", m("80")}, {"d", m("60.75")}, {"e", m("15")},
+		}, 4},
+		{"Independent pairs are not mixed", []partyBalance{
+			{"a", m("-10")}, {"b", m("-25")}, {"c", m("-40")}, {"d", m("40")}, {"e", m("10")}, {"f", m("25")},
+		}, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			transfers := minimumTransfers(tt.balances)
+			assert.Len(t, transfers, tt.transfers)
+			for key, amount := range applyTransfers(tt.balances, transfers) {
+				assert.Zero(t, amount.Minor(), "%s left with %s", key, amount)
+			}
+			for _, transfer := range transfers {
+				assert.Greater(t, int64(transfer.amount), int64(0))
+			}
+		})
+	}
+}
+
+func TestMinimumTransfers_GreedyBeyondExactLimit(t *testing.T) {
+	var balances []partyBalance
+	for i := 0; i < maxExactSettlementParties+2; i += 2 {
+		key := string(rune('a' + i))
+		balances = append(balances, partyBalance{key + "-debtor", utils.MoneyFromMinor(-int64(i + 1))}, partyBalance{key + "-creditor", utils.MoneyFromMinor(int64(i + 1))})
+	}
+
+	transfers := minimumTransfers(balances)
+	for key, amount := range applyTransfers(balances, transfers) {
+		assert.Zero(t, amount.Minor(), "%s left with %s", key, amount)
+	}
+	assert.Less(t, len(transfers), len(balances))
+}
+
+func (m *memoryLoans) Update(ctx context.Context, loan *models.Loan) error {
+	for i := range m.loans {
+		if m.loans[i].ID == loan.ID {
+			m.loans[i] = *loan
+		}
+	}
+	return nil
+}
+
+type memoryLoanPayments struct {
+	repository.LoanPaymentRepository
+	payments []models.LoanPayment
+}
+
+func (m *memoryLoanPayments) Create(ctx context.Context, payment *models.LoanPayment) error {
+	m.payments = append(m.payments, *payment)
+	return nil
+}
+
+func (m *memoryLoanPayments) SumByLoanID(ctx context.Context, loanID string) (string, error) {
+	var sum utils.Money
+	for _, payment := range m.payments {
+		if payment.LoanID == loanID {
+			sum += utils.MoneyFromString(payment.AmountPLN)
+		}
+	}
+	return sum.String(), nil
+}
+
+type memorySettlements struct {
+	transfers  []models.SettlementTransfer
+	billShares []models.SettlementBillShare
+	failShares bool
+}
+
+func (m *memorySettlements) CreateTransfer(ctx context.Context, transfer *models.SettlementTransfer) error {
+	m.transfers = append(m.transfers, *transfer)
+	return nil
+}
+
+func (m *memorySettlements) ListTransfers(ctx context.Context) ([]models.SettlementTransfer, error) {
+	return m.transfers, nil
+}
+
+func (m *memorySettlements) ListTransfersByFingerprint(ctx context.Context, fingerprint string) ([]models.SettlementTransfer, error) {
+	var transfers []models.SettlementTransfer
+	for _, transfer := range m.transfers {
+		if transfer.Fingerprint == fingerprint {
+			transfers = append(transfers, transfer)
+		}
+	}
+	return transfers, nil
+}
+
+func (m *memorySettlements) CreateBillShare(ctx context.Context, share *models.SettlementBillShare) error {
+	if m.failShares {
+		return errors.New("disk full")
+	}
+	m.billShares = append(m.billShares, *share)
+	return nil
+}
+
+func (m *memorySettlements) ListBillShares(ctx context.Context) ([]models.SettlementBillShare, error) {
+	return m.billShares, nil
+}
+
+func (m *memorySettlements) ListBillSharesByBillID(ctx context.Context, billID string) ([]models.SettlementBillShare, error) {
+	var shares []models.SettlementBillShare
+	for _, share := range m.billShares {
+		if share.BillID == billID {
+			shares = append(shares, share)
+		}
+	}
+	return shares, nil
+}
+
+// memorySettlementTxManager runs work against fixed repositories and restores the loans,
+// loan payments, payments, settlement records and ledger when the work fails
+type memorySettlementTxManager struct {
+	repos *repository.Repositories
+}
+
+func (m *memorySettlementTxManager) WithTx(ctx context.Context, fn func(ctx context.Context, repos *repository.Repositories) error) error {
+	loans := m.repos.Loans.(*memoryLoans)
+	loanPayments := m.repos.LoanPayments.(*memoryLoanPayments)
+	payments := m.repos.Payments.(*memoryPayments)
+	settlements := m.repos.Settlements.(*memorySettlements)
+	ledger := m.repos.LedgerEntries.(*memoryLedgerEntries)
+
+	loanRows := append([]models.Loan(nil), loans.loans...)
+	loanPaymentRows := append([]models.LoanPayment(nil), loanPayments.payments...)
+	paymentRows := append([]models.Payment(nil), payments.payments...)
+	transferRows := append([]models.SettlementTransfer(nil), settlements.transfers...)
+	shareRows := append([]models.SettlementBillShare(nil), settlements.billShares...)
+	entries := append([]models.LedgerEntry(nil), ledger.entries...)
+
+	if err := fn(ctx, m.repos); err != nil {
+		loans.loans = loanRows
+		loanPayments.payments = loanPaymentRows
+		payments.payments = paymentRows
+		settlements.transfers = transferRows
+		settlements.billShares = shareRows
+		ledger.entries = entries
+		return err
+	}
+	return nil
+}
+
+// newSettlementHousehold has Anna and Bartek on their own and Celina and Dawid as a couple.
+// Anna lent Celina 100, Dawid lent Bartek 40, Celina lent Dawid 30 within the couple and
+// Anna paid the whole 90 of a gas bill split three ways.
+func newSettlementHousehold() (*SettlementService, *repository.Repositories) {
+	coupleID := "couple"
+	repos := &repository.Repositories{
+		Users: &memoryUsers{users: []models.User{
+			{ID: "anna", Name: "Anna", IsActive: true},
+			{ID: "bartek", Name: "Bartek", IsActive: true},
+			{ID: "celina", Name: "Celina", GroupID: &coupleID, IsActive: true},
+			{ID: "dawid", Name: "Dawid", GroupID: &coupleID, IsActive: true},
+		}},
+		Groups:                 &memoryGroups{groups: []models.Group{{ID: coupleID, Name: "Couple", Weight: 1}}},
+		Residencies:            &memoryResidencies{},
+		GroupWeightChanges:     &memoryGroupWeightChanges{},
+		GroupMembershipChanges: &memoryMembershipChanges{},
+		Tariffs:                &memoryTariffs{},
+		Bills:                  &memoryBills{bills: []models.Bill{{ID: "gas", Type: "gas", Status: "posted", TotalAmountPLN: "90.00"}}},
+		Allocations: &memoryAllocations{allocations: []repository.Allocation{
+			{BillID: "gas", SubjectType: "user", SubjectID: "anna", AllocatedPLN: "30.00"},
+			{BillID: "gas", SubjectType: "user", SubjectID: "bartek", AllocatedPLN: "30.00"},
+			{BillID: "gas", SubjectType: "group", SubjectID: coupleID, AllocatedPLN: "30.00"},
+		}},
+		Payments: &memoryPayments{payments: []models.Payment{{ID: "p1", BillID: "gas", PayerUserID: "anna", AmountPLN: "90.00"}}},
+		Loans: &memoryLoans{loans: []models.Loan{
+			{ID: "l1", LenderID: "anna", BorrowerID: "celina", AmountPLN: "100.00", Status: "open"},
+			{ID: "l2", LenderID: "dawid", BorrowerID: "bartek", AmountPLN: "40.00", Status: "open"},
+			{ID: "l3", LenderID: "celina", BorrowerID: "dawid", AmountPLN: "30.00", Status: "open"},
+		}},
+		LoanPayments:  &memoryLoanPayments{},
+		Settlements:   &memorySettlements{},
+		LedgerEntries: &memoryLedgerEntries{},
+	}
+	service := NewSettlementService(repos.Users, repos.Groups, repos.Residencies, repos.GroupWeightChanges, repos.GroupMembershipChanges,
+		repos.Bills, nil, nil, nil, repos.Tariffs, repos.Allocations, repos.Payments, repos.Loans, repos.LoanPayments, repos.Settlements,
+		&memorySettlementTxManager{repos: repos})
+	return service, repos
+}
+
+func TestBuildSettlementPlan_WithGroups(t *testing.T) {
+	service, _ := newSettlementHousehold()
+	m := utils.MoneyFromString
+
+	plan, err := service.GetPlan(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, 3, plan.OpenLoans)
+	assert.Equal(t, 1, plan.BillsWithBalances)
+	assert.Zero(t, plan.OutstandingBillsPLN.Minor())
+
+	// The couple settles as one party; the loan inside it is left to its members
+	require.Len(t, plan.Parties, 3)
+	assert.Equal(t, []string{"couple", "anna", "bartek"}, []string{plan.Parties[0].SubjectID, plan.Parties[1].SubjectID, plan.Parties[2].SubjectID})
+	assert.Equal(t, m("-90"), plan.Parties[0].Balance)
+	assert.Equal(t, m("160"), plan.Parties[1].Balance)
+	assert.Equal(t, m("-70"), plan.Parties[2].Balance)
+
+	require.Len(t, plan.Transfers, 3)
+	byFrom := make(map[string]SettlementTransfer)
+	for _, transfer := range plan.Transfers {
+		byFrom[transfer.FromSubjectID] = transfer
+	}
+	// Celina owes the couple's share of Anna's loan and the gas bill, so she pays for it
+	assert.Equal(t, SettlementTransfer{
+		FromSubjectType: "group", FromSubjectID: "couple", FromName: "Couple", FromUserID: "celina",
+		ToSubjectType: "user", ToSubjectID: "anna", ToName: "Anna", ToUserID: "anna",
+		Amount: m("90"),
+	}, byFrom["couple"])
+	assert.Equal(t, "anna", byFrom["bartek"].ToUserID)
+	assert.Equal(t, m("70"), byFrom["bartek"].Amount)
+	assert.Equal(t, "celina", byFrom["dawid"].ToUserID)
+	assert.Equal(t, m("30"), byFrom["dawid"].Amount)
+}
+
+func TestBuildSettlementPlan_UsesGroupOfBillPeriod(t *testing.T) {
+	coupleID := "couple"
+	january := func(d int) time.Time { return time.Date(2026, 1, d, 0, 0, 0, 0, time.UTC) }
+	repos := &repository.Repositories{
+		Users: &memoryUsers{users: []models.User{
+			{ID: "anna", Name: "Anna", IsActive: true},
+			{ID: "celina", Name: "Celina", IsActive: true},
+			{ID: "dawid", Name: "Dawid", GroupID: &coupleID, IsActive: true},
+		}},
+		Groups:      &memoryGroups{groups: []models.Group{{ID: coupleID, Name: "Couple", Weight: 1}}},
+		Residencies: &memoryResidencies{},
+		// Celina left the couple in February
+		GroupMembershipChanges: &memoryMembershipChanges{changes: []models.GroupMembershipChange{
+			{ID: "c1", UserID: "celina", OldGroupID: &coupleID, EffectiveFrom: time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC)},
+		}},
+		GroupWeightChanges: &memoryGroupWeightChanges{},
+		Tariffs:            &memoryTariffs{},
+		Bills: &memoryBills{bills: []models.Bill{
+			{ID: "gas", Type: "gas", Status: "posted", TotalAmountPLN: "90.00", PeriodStart: january(1), PeriodEnd: january(31)},
+		}},
+		Allocations: &memoryAllocations{allocations: []repository.Allocation{
+			{BillID: "gas", SubjectType: "user", SubjectID: "anna", AllocatedPLN: "30.00"},
+			{BillID: "gas", SubjectType: "group", SubjectID: coupleID, AllocatedPLN: "60.00"},
+		}},
+		Payments:     &memoryPayments{payments: []models.Payment{{ID: "p1", BillID: "gas", PayerUserID: "celina", AmountPLN: "90.00"}}},
+		Loans:        &memoryLoans{},
+		LoanPayments: &memoryLoanPayments{},
+		Settlements:  &memorySettlements{},
+	}
+	service := NewSettlementService(repos.Users, repos.Groups, repos.Residencies, repos.GroupWeightChanges, repos.GroupMembershipChanges,
+		repos.Bills, nil, nil, nil, repos.Tariffs, repos.Allocations, repos.Payments, repos.Loans, repos.LoanPayments, repos.Settlements, nil)
+
+	plan, err := service.GetPlan(context.Background())
+	require.NoError(t, err)
+
+	// Celina paid for the couple she was part of in January, so only Anna's share was fronted
+	require.Len(t, plan.Transfers, 1)
+	assert.Equal(t, "anna", plan.Transfers[0].FromUserID)
+	assert.Equal(t, "celina", plan.Transfers[0].ToUserID)
+	assert.Equal(t, utils.MoneyFromString("30"), plan.Transfers[0].Amount)
+}
+
+func TestApplyPlan(t *testing.T) {
+	ctx := context.Background()
+	service, repos := newSettlementHousehold()
+	settlements := repos.Settlements.(*memorySettlements)
+	ledger := repos.LedgerEntries.(*memoryLedgerEntries)
+
+	plan, err := service.GetPlan(ctx)
+	require.NoError(t, err)
+
+	// A plan computed before a new payment no longer matches
+	payments := repos.Payments.(*memoryPayments)
+	payments.payments = append(payments.payments, models.Payment{ID: "p2", BillID: "gas", PayerUserID: "bartek", AmountPLN: "10.00"})
+	_, err = service.ApplyPlan(ctx, plan.Fingerprint)
+	assert.ErrorIs(t, err, ErrSettlementPlanStale)
+	assert.Empty(t, settlements.transfers)
+	payments.payments = payments.payments[:1]
+
+	result, err := service.ApplyPlan(ctx, plan.Fingerprint)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.TransfersRecorded)
+	assert.Equal(t, 3, result.LoansSettled)
+	assert.Equal(t, 3, result.BillSharesSettled)
+
+	// The transfers are the only money moved in the ledger
+	require.Len(t, settlements.transfers, 3)
+	for _, transfer := range settlements.transfers {
+		assert.Equal(t, plan.Fingerprint, transfer.Fingerprint)
+	}
+	assert.Equal(t, utils.MoneyFromString("-160"), ledger.balance("user", "anna"))
+	assert.Equal(t, utils.MoneyFromString("60"), ledger.balance("user", "celina"))
+	assert.Equal(t, utils.MoneyFromString("30"), ledger.balance("user", "dawid"))
+	assert.Equal(t, utils.MoneyFromString("70"), ledger.balance("user", "bartek"))
+
+	// Debts are closed through records that point at the settlement; no bill payments are made up
+	for _, payment := range repos.LoanPayments.(*memoryLoanPayments).payments {
+		require.NotNil(t, payment.SettlementFingerprint)
+		assert.Equal(t, plan.Fingerprint, *payment.SettlementFingerprint)
+	}
+	for _, loan := range repos.Loans.(*memoryLoans).loans {
+		assert.Equal(t, "settled", loan.Status)
+	}
+	assert.Len(t, payments.payments, 1)
+	assert.Len(t, settlements.billShares, 3)
+
+	// Nothing is left to settle and the bill counts as paid by everyone
+	after, err := service.GetPlan(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, after.Transfers)
+	_, err = service.ApplyPlan(ctx, after.Fingerprint)
+	assert.ErrorIs(t, err, ErrNothingToSettle)
+	paid, err := billPaidByUser(ctx, repos.Payments, repos.Settlements, "gas")
+	require.NoError(t, err)
+	assert.Equal(t, utils.MoneyFromString("30"), paid["anna"])
+	assert.Equal(t, utils.MoneyFromString("30"), paid["bartek"])
+}
+
+func TestApplyPlan_RollsBackOnFailure(t *testing.T) {
+	ctx := context.Background()
+	service, repos := newSettlementHousehold()
+	settlements := repos.Settlements.(*memorySettlements)
+
+	plan, err := service.GetPlan(ctx)
+	require.NoError(t, err)
+
+	settlements.failShares = true
+	_, err = service.ApplyPlan(ctx, plan.Fingerprint)
+	require.Error(t, err)
+
+	assert.Empty(t, settlements.transfers)
+	assert.Empty(t, repos.LoanPayments.(*memoryLoanPayments).payments)
+	assert.Empty(t, repos.LedgerEntries.(*memoryLedgerEntries).entries)
+	for _, loan := range repos.Loans.(*memoryLoans).loans {
+		assert.Equal(t, "open", loan.Status)
+	}
+
+	// The same plan still applies once the failure is gone
+	settlements.failShares = false
+	_, err = service.ApplyPlan(ctx, plan.Fingerprint)
+	require.NoError(t, err)
+}
+
+func TestApplyPlan_ParticipantCanBeDeleted(t *testing.T) {
+	ctx := context.Background()
+	db, err := database.OpenSQLiteDB(filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	defer db.Close()
+	_, err = db.Migrate(ctx)
+	require.NoError(t, err)
+
+	repos := sqlite.NewRepositories(db.DB)
+	anna := &models.User{Email: "anna@example.com", Name: "Anna", PasswordHash: "x", Role: "RESIDENT", IsActive: true}
+	bartek := &models.User{Email: "bartek@example.com", Name: "Bartek", PasswordHash: "x", Role: "RESIDENT", IsActive: true}
+	require.NoError(t, repos.Users.Create(ctx, anna))
+	require.NoError(t, repos.Users.Create(ctx, bartek))
+	anna, err = repos.Users.GetByEmail(ctx, anna.Email)
+	require.NoError(t, err)
+	bartek, err = repos.Users.GetByEmail(ctx, bartek.Email)
+	require.NoError(t, err)
+	require.NoError(t, repos.Loans.Create(ctx, &models.Loan{LenderID: anna.ID, BorrowerID: bartek.ID, AmountPLN: "50.00", Status: "open"}))
+
+	service := NewSettlementService(repos.Users, repos.Groups, repos.Residencies, repos.GroupWeightChanges, repos.GroupMembershipChanges,
+		repos.Bills, repos.Consumptions, repos.Meters, repos.MeterReplacements, repos.Tariffs, repos.Allocations, repos.Payments,
+		repos.Loans, repos.LoanPayments, repos.Settlements, sqlite.NewTxManager(db.DB))
+	plan, err := service.GetPlan(ctx)
+	require.NoError(t, err)
+	_, err = service.ApplyPlan(ctx, plan.Fingerprint)
+	require.NoError(t, err)
+
+	// The settlement records go with the resident, like their loans and payments
+	require.NoError(t, repos.Users.Delete(ctx, bartek.ID))
+	transfers, err := repos.Settlements.ListTransfers(ctx)
+	require.NoError(t, err)
+	assert.Empty(t, transfers)
+}
@@ -0,0 +1,185 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/utils"
+)
+
+var statementTemplate = template.Must(template.New("statement").Funcs(template.FuncMap{
+	"pln":     formatPLN,
+	"section": statementSection,
+	"date":    func(t time.Time) string { return t.Format("2006-01-02") },
+	"units": func(u *float64) string {
+		if u == nil {
+			return ""
+		}
+		return fmt.Sprintf("%.2f", *u)
+	},
+	"optpln": func(m *utils.Money) string {
+		if m == nil {
+			return ""
+		}
+		return formatPLN(*m)
+	},
+}).Parse(`<!DOCTYPE html>
+<html lang="pl">
+<head>
+<meta charset="utf-8">
+<title>Zestawienie konta - {{.UserName}}</title>
+<style>
+body { font-family: sans-serif; font-size: 13px; margin: 2em; color: #222; }
+h1 { font-size: 20px; margin-bottom: 0; }
+h2 { font-size: 15px; margin-top: 1.5em; border-bottom: 1px solid #ccc; }
+table { border-collapse: collapse; width: 100%; }
+th, td { padding: 3px 6px; text-align: left; border-bottom: 1px solid #eee; }
+td.num, th.num { text-align: right; white-space: nowrap; }
+.muted { color: #777; }
+</style>
+</head>
+<body>
+<h1>Zestawienie konta</h1>
+<p>{{.UserName}} &lt;{{.Email}}&gt;{{if .GroupName}}, grupa: {{.GroupName}}{{end}}<br>
+Okres: {{date .From}} - {{date .To}}<br>
+<span class="muted">Wygenerowano: {{.GeneratedAt.Format "2006-01-02 15:04"}}</span></p>
+
+<table>
+<tr><td>Saldo początkowe</td><td class="num">{{pln .OpeningBalance}}</td></tr>
+<tr><td><strong>Saldo końcowe</strong></td><td class="num"><strong>{{pln .ClosingBalance}}</strong></td></tr>
+{{if .GroupClosingBalance}}<tr><td>Saldo końcowe grupy {{.GroupName}}</td><td class="num">{{optpln .GroupClosingBalance}}</td></tr>{{end}}
+</table>
+<p class="muted">Saldo dodatnie oznacza kwotę należną Tobie, ujemne - kwotę do zapłaty.</p>
+
+<h2>Udziały w rachunkach</h2>
+{{if .BillShares}}<table>
+<tr><th>Rachunek</th><th>Okres</th><th class="num">Kwota rachunku</th><th class="num">Zużycie</th><th class="num">Część osobista</th><th class="num">Część wspólna</th><th class="num">Udział</th></tr>
+{{range .BillShares}}<tr><td>{{.Name}}{{if .ViaGroup}} <span class="muted">(grupa)</span>{{end}}</td><td>{{date .PeriodStart}} - {{date .PeriodEnd}}</td><td class="num">{{pln .BillTotal}}</td><td class="num">{{units .Units}}</td><td class="num">{{optpln .PersonalAmount}}</td><td class="num">{{optpln .SharedAmount}}</td><td class="num">{{pln .Amount}}</td></tr>
+{{end}}<tr><th colspan="6">Razem</th><th class="num">{{pln .TotalBillShares}}</th></tr>
+</table>{{else}}<p class="muted">Brak</p>{{end}}
+
+{{template "entries" (section "Płatności" .Payments)}}
+{{template "entries" (section "Udzielone pożyczki" .LoansGiven)}}
+{{template "entries" (section "Otrzymane pożyczki" .LoansTaken)}}
+{{template "entries" (section "Spłaty pożyczek" .LoanRepaymentsMade)}}
+{{template "entries" (section "Otrzymane spłaty" .LoanRepaymentsReceived)}}
+{{template "entries" (section "Zapasy" .Supplies)}}
+</body>
+</html>
+{{define "entries"}}<h2>{{.Title}}</h2>
+{{if .Entries}}<table>
+<tr><th>Data</th><th>Opis</th><th>Osoba</th><th class="num">Kwota</th></tr>
+{{range .Entries}}<tr><td>{{date .Date}}</td><td>{{.Description}}</td><td>{{.Counterparty}}</td><td class="num">{{pln .Amount}}</td></tr>
+{{end}}</table>{{else}}<p class="muted">Brak</p>{{end}}{{end}}`))
+
+// statementEntrySection pairs a heading with its entries
+type statementEntrySection struct {
+	Title   string
+	Entries []StatementEntry
+}
+
+func statementSection(title string, entries []StatementEntry) statementEntrySection {
+	return statementEntrySection{Title: title, Entries: entries}
+}
+
+func formatPLN(m utils.Money) string {
+	return m.String() + " zł"
+}
+
+// RenderStatementHTML renders a statement as a standalone HTML page
+func RenderStatementHTML(statement *UserStatement) ([]byte, error) {
+	var buf bytes.Buffer
+	if err := statementTemplate.Execute(&buf, statement); err != nil {
+		return nil, fmt.Errorf("failed to render statement: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// RenderStatementPDF renders a statement as a PDF document
+func RenderStatementPDF(statement *UserStatement) []byte {
+	doc := utils.NewPDF("Zestawienie konta - " + statement.UserName)
+
+	doc.Heading("Zestawienie konta")
+	who := fmt.Sprintf("%s <%s>", statement.UserName, statement.Email)
+	if statement.GroupName != "" {
+		who += ", grupa: " + statement.GroupName
+	}
+	doc.Text(who)
+	doc.Text(fmt.Sprintf("Okres: %s - %s", statement.From.Format("2006-01-02"), statement.To.Format("2006-01-02")))
+	doc.Text("Wygenerowano: " + statement.GeneratedAt.Format("2006-01-02 15:04"))
+	doc.Space()
+	doc.Text(pdfRow("Saldo początkowe", formatPLN(statement.OpeningBalance)))
+	doc.BoldText(pdfRow("Saldo końcowe", formatPLN(statement.ClosingBalance)))
+	if statement.GroupClosingBalance != nil {
+		doc.Text(pdfRow("Saldo końcowe grupy "+statement.GroupName, formatPLN(*statement.GroupClosingBalance)))
+	}
+	doc.Text("Saldo dodatnie oznacza kwotę należną Tobie, ujemne - kwotę do zapłaty.")
+
+	doc.Space()
+	doc.BoldText("Udziały w rachunkach")
+	doc.Rule()
+	if len(statement.BillShares) == 0 {
+		doc.Text("Brak")
+	}
+	for _, share := range statement.BillShares {
+		name := share.Name
+		if share.ViaGroup {
+			name += " (grupa)"
+		}
+		doc.Text(pdfRow(fmt.Sprintf("%s, %s - %s", name, share.PeriodStart.Format("2006-01-02"), share.PeriodEnd.Format("2006-01-02")), formatPLN(share.Amount)))
+
+		details := []string{"kwota rachunku " + formatPLN(share.BillTotal)}
+		if share.Units != nil {
+			details = append(details, fmt.Sprintf("zużycie %.2f", *share.Units))
+		}
+		if share.PersonalAmount != nil {
+			details = append(details, "część osobista "+formatPLN(*share.PersonalAmount))
+		}
+		if share.SharedAmount != nil {
+			details = append(details, "część wspólna "+formatPLN(*share.SharedAmount))
+		}
+		doc.Text("  " + strings.Join(details, ", "))
+	}
+	if len(statement.BillShares) > 0 {
+		doc.BoldText(pdfRow("Razem", formatPLN(statement.TotalBillShares)))
+	}
+
+	sections := []statementEntrySection{
+		statementSection("Płatności", statement.Payments),
+		statementSection("Udzielone pożyczki", statement.LoansGiven),
+		statementSection("Otrzymane pożyczki", statement.LoansTaken),
+		statementSection("Spłaty pożyczek", statement.LoanRepaymentsMade),
+		statementSection("Otrzymane spłaty", statement.LoanRepaymentsReceived),
+		statementSection("Zapasy", statement.Supplies),
+	}
+	for _, section := range sections {
+		doc.Space()
+		doc.BoldText(section.Title)
+		doc.Rule()
+		if len(section.Entries) == 0 {
+			doc.Text("Brak")
+		}
+		for _, entry := range section.Entries {
+			label := entry.Date.Format("2006-01-02") + "  " + entry.Description
+			if entry.Counterparty != "" {
+				label += " (" + entry.Counterparty + ")"
+			}
+			doc.Text(pdfRow(label, formatPLN(entry.Amount)))
+		}
+	}
+
+	return doc.Bytes()
+}
+
+// pdfRow lays out a label with a right-aligned amount on one PDF line
+func pdfRow(label, amount string) string {
+	width := utils.PDFLineWidth - len([]rune(amount)) - 1
+	runes := []rune(label)
+	if len(runes) > width {
+		runes = append(runes[:width-3], []rune("...")...)
+	}
+	return string(runes) + strings.Repeat(" ", width-len(runes)+1) + amount
+}
@@ -0,0 +1,65 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testUserStatement() *UserStatement {
+	personal := utils.MoneyFromString("30.00")
+	shared := utils.MoneyFromString("10.50")
+	units := 120.0
+	return &UserStatement{
+		UserName:       "Ania <script>",
+		Email:          "ania@example.com",
+		From:           time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+		To:             time.Date(2026, 3, 31, 23, 59, 59, 0, time.UTC),
+		GeneratedAt:    time.Date(2026, 4, 1, 8, 0, 0, 0, time.UTC),
+		OpeningBalance: utils.MoneyFromString("5.00"),
+		ClosingBalance: utils.MoneyFromString("-35.50"),
+		BillShares: []StatementBillShare{{
+			Name: "electricity", BillTotal: utils.MoneyFromString("200.00"),
+			Amount: personal + shared, PersonalAmount: &personal, SharedAmount: &shared, Units: &units,
+		}},
+		TotalBillShares: personal + shared,
+		LoansGiven: []StatementEntry{{
+			Date: time.Date(2026, 3, 5, 0, 0, 0, 0, time.UTC), Description: "Pożyczka: zakupy", Counterparty: "Bartek", Amount: utils.MoneyFromString("20.00"),
+		}},
+	}
+}
+
+func TestRenderStatementHTML(t *testing.T) {
+	html, err := RenderStatementHTML(testUserStatement())
+	require.NoError(t, err)
+
+	out := string(html)
+	assert.Contains(t, out, "Ania &lt;script&gt;")
+	assert.NotContains(t, out, "<script>")
+	assert.Contains(t, out, "40.50 zł")
+	assert.Contains(t, out, "30.00 zł")
+	assert.Contains(t, out, "-35.50 zł")
+	assert.Contains(t, out, "Bartek")
+	assert.Contains(t, out, "Otrzymane pożyczki</h2>\n<p class=\"muted\">Brak</p>")
+}
+
+func TestRenderStatementPDF(t *testing.T) {
+	pdf := RenderStatementPDF(testUserStatement())
+
+	assert.True(t, strings.HasPrefix(string(pdf), "%PDF-"))
+	assert.Contains(t, string(pdf), "-35.50 z\\207")
+}
+
+func TestPDFRow(t *testing.T) {
+	row := pdfRow("Rachunek", "10.00 zł")
+	assert.Len(t, []rune(row), utils.PDFLineWidth)
+	assert.True(t, strings.HasSuffix(row, " 10.00 zł"))
+
+	row = pdfRow(strings.Repeat("x", 200), "10.00 zł")
+	assert.Len(t, []rune(row), utils.PDFLineWidth)
+	assert.Contains(t, row, "... 10.00 zł")
+}
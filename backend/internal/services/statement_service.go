@@ -0,0 +1,268 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/sainaif/holy-home/internal/utils"
+)
+
+// StatementService builds personal account statements from the ledger
+type StatementService struct {
+	users             repository.UserRepository
+	groups            repository.GroupRepository
+	bills             repository.BillRepository
+	payments          repository.PaymentRepository
+	loans             repository.LoanRepository
+	loanPayments      repository.LoanPaymentRepository
+	allocationService *AllocationService
+	ledgerService     *LedgerService
+}
+
+func NewStatementService(
+	users repository.UserRepository,
+	groups repository.GroupRepository,
+	bills repository.BillRepository,
+	payments repository.PaymentRepository,
+	loans repository.LoanRepository,
+	loanPayments repository.LoanPaymentRepository,
+	allocationService *AllocationService,
+	ledgerService *LedgerService,
+) *StatementService {
+	return &StatementService{
+		users:             users,
+		groups:            groups,
+		bills:             bills,
+		payments:          payments,
+		loans:             loans,
+		loanPayments:      loanPayments,
+		allocationService: allocationService,
+		ledgerService:     ledgerService,
+	}
+}
+
+// StatementBillShare is the user's (or their group's) share of one bill
+type StatementBillShare struct {
+	BillID         string       `json:"billId"`
+	Name           string       `json:"name"`
+	PeriodStart    time.Time    `json:"periodStart"`
+	PeriodEnd      time.Time    `json:"periodEnd"`
+	BillTotal      utils.Money  `json:"billTotal"`
+	ViaGroup       bool         `json:"viaGroup"` // the share is charged to the user's group
+	Weight         float64      `json:"weight"`
+	Amount         utils.Money  `json:"amount"`
+	PersonalAmount *utils.Money `json:"personalAmount,omitempty"` // metered bills only
+	SharedAmount   *utils.Money `json:"sharedAmount,omitempty"`   // metered bills only
+	Units          *float64     `json:"units,omitempty"`
+}
+
+// StatementEntry is one money movement on a statement
+type StatementEntry struct {
+	Date         time.Time   `json:"date"`
+	Description  string      `json:"description"`
+	Counterparty string      `json:"counterparty,omitempty"`
+	Amount       utils.Money `json:"amount"` // positive: credited to the user, negative: charged
+}
+
+// UserStatement is a user's account statement for a period
+type UserStatement struct {
+	UserID                 string               `json:"userId"`
+	UserName               string               `json:"userName"`
+	Email                  string               `json:"email"`
+	GroupName              string               `json:"groupName,omitempty"`
+	From                   time.Time            `json:"from"`
+	To                     time.Time            `json:"to"`
+	GeneratedAt            time.Time            `json:"generatedAt"`
+	OpeningBalance         utils.Money          `json:"openingBalance"`
+	ClosingBalance         utils.Money          `json:"closingBalance"`
+	GroupClosingBalance    *utils.Money         `json:"groupClosingBalance,omitempty"`
+	BillShares             []StatementBillShare `json:"billShares"`
+	Payments               []StatementEntry     `json:"payments"`
+	LoansGiven             []StatementEntry     `json:"loansGiven"`
+	LoansTaken             []StatementEntry     `json:"loansTaken"`
+	LoanRepaymentsMade     []StatementEntry     `json:"loanRepaymentsMade"`
+	LoanRepaymentsReceived []StatementEntry     `json:"loanRepaymentsReceived"`
+	Supplies               []StatementEntry     `json:"supplies"`
+	TotalBillShares        utils.Money          `json:"totalBillShares"`
+	TotalPayments          utils.Money          `json:"totalPayments"`
+}
+
+// GetUserStatement builds a user's statement for the period [from, to]. Balances come from
+// the user's ledger account; bill shares charged to the user's group are listed separately
+// and reflected in the group's balance instead.
+func (s *StatementService) GetUserStatement(ctx context.Context, userID string, from, to time.Time) (*UserStatement, error) {
+	if to.Before(from) {
+		return nil, errors.New("'to' must not be before 'from'")
+	}
+
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	filter := LedgerStatementFilter{From: &from, To: &to}
+	ledger, err := s.ledgerService.GetStatement(ctx, userLedgerAccount(userID), filter)
+	if err != nil {
+		return nil, err
+	}
+
+	statement := &UserStatement{
+		UserID:                 user.ID,
+		UserName:               user.Name,
+		Email:                  user.Email,
+		From:                   from,
+		To:                     to,
+		GeneratedAt:            time.Now(),
+		OpeningBalance:         ledger.OpeningBalance,
+		ClosingBalance:         ledger.ClosingBalance,
+		BillShares:             []StatementBillShare{},
+		Payments:               []StatementEntry{},
+		LoansGiven:             []StatementEntry{},
+		LoansTaken:             []StatementEntry{},
+		LoanRepaymentsMade:     []StatementEntry{},
+		LoanRepaymentsReceived: []StatementEntry{},
+		Supplies:               []StatementEntry{},
+	}
+
+	// Bills charged in the period, to the user directly or to their group
+	type chargedBill struct {
+		id       string
+		viaGroup bool
+	}
+	var charged []chargedBill
+	seen := make(map[string]bool)
+	for _, line := range ledger.Lines {
+		if line.SourceType == LedgerSourceBill && !seen[line.SourceID] {
+			seen[line.SourceID] = true
+			charged = append(charged, chargedBill{id: line.SourceID})
+		}
+	}
+
+	if user.GroupID != nil {
+		if group, err := s.groups.GetByID(ctx, *user.GroupID); err == nil && group != nil {
+			statement.GroupName = group.Name
+		}
+		groupLedger, err := s.ledgerService.GetStatement(ctx, LedgerAccount{Type: "group", ID: *user.GroupID}, filter)
+		if err != nil {
+			return nil, err
+		}
+		statement.GroupClosingBalance = &groupLedger.ClosingBalance
+		for _, line := range groupLedger.Lines {
+			if line.SourceType == LedgerSourceBill && !seen[line.SourceID] {
+				seen[line.SourceID] = true
+				charged = append(charged, chargedBill{id: line.SourceID, viaGroup: true})
+			}
+		}
+	}
+
+	for _, c := range charged {
+		share, err := s.billShare(ctx, c.id, userID, user.GroupID, c.viaGroup)
+		if err != nil {
+			return nil, err
+		}
+		if share == nil {
+			continue
+		}
+		statement.BillShares = append(statement.BillShares, *share)
+		statement.TotalBillShares += share.Amount
+	}
+
+	for _, line := range ledger.Lines {
+		entry := StatementEntry{Date: line.OccurredAt, Description: line.Description, Amount: line.Amount}
+
+		switch line.SourceType {
+		case LedgerSourcePayment:
+			if payment, err := s.payments.GetByID(ctx, line.SourceID); err == nil && payment != nil {
+				if bill, err := s.bills.GetByID(ctx, payment.BillID); err == nil && bill != nil {
+					entry.Description = fmt.Sprintf("%s: %s %s", line.Description, billTypeLabel(bill), bill.PeriodStart.Format("2006-01"))
+				}
+			}
+			statement.Payments = append(statement.Payments, entry)
+			statement.TotalPayments += line.Amount
+		case LedgerSourceLoan:
+			if loan, err := s.loans.GetByID(ctx, line.SourceID); err == nil && loan != nil {
+				entry.Counterparty = s.counterparty(ctx, loan.LenderID, loan.BorrowerID, userID)
+			}
+			if line.Amount > 0 {
+				statement.LoansGiven = append(statement.LoansGiven, entry)
+			} else {
+				statement.LoansTaken = append(statement.LoansTaken, entry)
+			}
+		case LedgerSourceLoanPayment:
+			if payment, err := s.loanPayments.GetByID(ctx, line.SourceID); err == nil && payment != nil {
+				if loan, err := s.loans.GetByID(ctx, payment.LoanID); err == nil && loan != nil {
+					entry.Counterparty = s.counterparty(ctx, loan.LenderID, loan.BorrowerID, userID)
+				}
+			}
+			// The borrower is credited for repaying, the lender charged for being repaid
+			if line.Amount > 0 {
+				statement.LoanRepaymentsMade = append(statement.LoanRepaymentsMade, entry)
+			} else {
+				statement.LoanRepaymentsReceived = append(statement.LoanRepaymentsReceived, entry)
+			}
+		case LedgerSourceSupplyContribution, LedgerSourceSupplyPurchase, LedgerSourceSupplyRefund:
+			statement.Supplies = append(statement.Supplies, entry)
+		}
+	}
+
+	return statement, nil
+}
+
+// billShare returns the user's or group's allocation of a bill, or nil when the bill
+// no longer exists or no longer charges them
+func (s *StatementService) billShare(ctx context.Context, billID, userID string, groupID *string, viaGroup bool) (*StatementBillShare, error) {
+	bill, err := s.bills.GetByID(ctx, billID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if bill == nil || bill.Status == "draft" {
+		return nil, nil
+	}
+
+	breakdown, err := s.allocationService.GetAllocationBreakdown(ctx, bill.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, alloc := range breakdown {
+		matches := alloc.SubjectType == "user" && alloc.SubjectID == userID
+		if viaGroup {
+			matches = alloc.SubjectType == "group" && groupID != nil && alloc.SubjectID == *groupID
+		}
+		if !matches {
+			continue
+		}
+		return &StatementBillShare{
+			BillID:         bill.ID,
+			Name:           billTypeLabel(bill),
+			PeriodStart:    bill.PeriodStart,
+			PeriodEnd:      bill.PeriodEnd,
+			BillTotal:      utils.MoneyFromString(bill.TotalAmountPLN),
+			ViaGroup:       viaGroup,
+			Weight:         alloc.Weight,
+			Amount:         alloc.Amount,
+			PersonalAmount: alloc.PersonalAmount,
+			SharedAmount:   alloc.SharedAmount,
+			Units:          alloc.Units,
+		}, nil
+	}
+	return nil, nil
+}
+
+// counterparty returns the name of the other side of a loan
+func (s *StatementService) counterparty(ctx context.Context, lenderID, borrowerID, userID string) string {
+	otherID := lenderID
+	if lenderID == userID {
+		otherID = borrowerID
+	}
+	if other, err := s.users.GetByID(ctx, otherID); err == nil && other != nil {
+		return other.Name
+	}
+	return ""
+}
@@ -18,7 +18,10 @@ type SupplyService struct {
 	supplyItems         repository.SupplyItemRepository
 	supplyContributions repository.SupplyContributionRepository
 	users               repository.UserRepository
+	ledgerEntries       repository.LedgerEntryRepository
+	currencyService     *CurrencyService
 	notificationService *NotificationService
+	txManager           repository.TxManager
 }
 
 func NewSupplyService(
@@ -26,36 +29,61 @@ func NewSupplyService(
 	supplyItems repository.SupplyItemRepository,
 	supplyContributions repository.SupplyContributionRepository,
 	users repository.UserRepository,
+	ledgerEntries repository.LedgerEntryRepository,
+	currencyService *CurrencyService,
 	notificationService *NotificationService,
+	txManager repository.TxManager,
 ) *SupplyService {
 	return &SupplyService{
 		supplySettings:      supplySettings,
 		supplyItems:         supplyItems,
 		supplyContributions: supplyContributions,
 		users:               users,
+		ledgerEntries:       ledgerEntries,
+		currencyService:     currencyService,
 		notificationService: notificationService,
+		txManager:           txManager,
 	}
 }
 
+// inTx runs fn with a copy of the service bound to the transaction's repositories, so
+// supply records, the budget and their ledger entries are written together or not at all
+func (s *SupplyService) inTx(ctx context.Context, fn func(ctx context.Context, tx *SupplyService) error) error {
+	return s.txManager.WithTx(ctx, func(ctx context.Context, repos *repository.Repositories) error {
+		tx := *s
+		tx.supplySettings = repos.SupplySettings
+		tx.supplyItems = repos.SupplyItems
+		tx.supplyContributions = repos.SupplyContributions
+		tx.users = repos.Users
+		tx.ledgerEntries = repos.LedgerEntries
+		return fn(ctx, &tx)
+	})
+}
+
 // ========== Settings Methods ==========
 
 // GetSettings retrieves the supply settings (creates default if not exists)
 func (s *SupplyService) GetSettings(ctx context.Context) (*models.SupplySettings, error) {
-	settings, err := s.supplySettings.Get(ctx)
-	if err != nil {
+	return getOrCreateSupplySettings(ctx, s.supplySettings)
+}
+
+// getOrCreateSupplySettings loads the settings singleton, creating the defaults on first use
+func getOrCreateSupplySettings(ctx context.Context, supplySettings repository.SupplySettingsRepository) (*models.SupplySettings, error) {
+	settings, err := supplySettings.Get(ctx)
+	if err != nil || settings == nil {
 		// Create default settings
 		settings = &models.SupplySettings{
 			ID:                    "singleton",
-			WeeklyContributionPLN: utils.FloatToDecimalString(10.0), // 10 PLN per person per week
+			WeeklyContributionPLN: utils.MoneyFromMinor(1000).String(), // 10 PLN per person per week
 			ContributionDay:       "monday",
-			CurrentBudgetPLN:      utils.FloatToDecimalString(0.0),
+			CurrentBudgetPLN:      utils.Money(0).String(),
 			LastContributionAt:    time.Now(),
 			IsActive:              true,
 			CreatedAt:             time.Now(),
 			UpdatedAt:             time.Now(),
 		}
 
-		if err := s.supplySettings.Upsert(ctx, settings); err != nil {
+		if err := supplySettings.Upsert(ctx, settings); err != nil {
 			return nil, fmt.Errorf("failed to create default settings: %w", err)
 		}
 
@@ -66,7 +94,7 @@ func (s *SupplyService) GetSettings(ctx context.Context) (*models.SupplySettings
 }
 
 // UpdateSettings updates supply settings (ADMIN only)
-func (s *SupplyService) UpdateSettings(ctx context.Context, weeklyContribution float64, contributionDay string) error {
+func (s *SupplyService) UpdateSettings(ctx context.Context, weeklyContribution utils.Money, contributionDay string) error {
 	if weeklyContribution <= 0 {
 		return errors.New("weekly contribution must be positive")
 	}
@@ -84,7 +112,7 @@ func (s *SupplyService) UpdateSettings(ctx context.Context, weeklyContribution f
 		return err
 	}
 
-	settings.WeeklyContributionPLN = utils.FloatToDecimalString(weeklyContribution)
+	settings.WeeklyContributionPLN = weeklyContribution.String()
 	settings.ContributionDay = contributionDay
 	settings.UpdatedAt = time.Now()
 
@@ -96,22 +124,56 @@ func (s *SupplyService) UpdateSettings(ctx context.Context, weeklyContribution f
 }
 
 // AdjustBudget manually adjusts the budget (ADMIN only)
-func (s *SupplyService) AdjustBudget(ctx context.Context, adjustment float64, notes string) error {
-	settings, err := s.GetSettings(ctx)
-	if err != nil {
+func (s *SupplyService) AdjustBudget(ctx context.Context, adjustment utils.Money, notes string) error {
+	return s.inTx(ctx, func(ctx context.Context, tx *SupplyService) error {
+		_, err := adjustSupplyBudget(ctx, tx.supplySettings, tx.ledgerEntries, adjustment, notes)
 		return err
+	})
+}
+
+// ExecuteApprovedBudgetAdjustment is the approval executor for "supply.budget_adjust" requests
+func (s *SupplyService) ExecuteApprovedBudgetAdjustment(ctx context.Context, repos *repository.Repositories, request *models.ApprovalRequest) (map[string]interface{}, error) {
+	var body struct {
+		Adjustment utils.Money `json:"adjustment"`
+		Notes      string      `json:"notes"`
+	}
+	if err := DecodeApprovalBody(request, &body); err != nil {
+		return nil, err
+	}
+
+	budget, err := adjustSupplyBudget(ctx, repos.SupplySettings, repos.LedgerEntries, body.Adjustment, body.Notes)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"adjustment":       body.Adjustment,
+		"currentBudgetPLN": budget.String(),
+	}, nil
+}
+
+// adjustSupplyBudget adds adjustment to the budget and returns the new budget
+func adjustSupplyBudget(ctx context.Context, supplySettings repository.SupplySettingsRepository, ledgerEntries repository.LedgerEntryRepository, adjustment utils.Money, notes string) (utils.Money, error) {
+	settings, err := getOrCreateSupplySettings(ctx, supplySettings)
+	if err != nil {
+		return 0, err
 	}
 
-	currentBudget := utils.DecimalStringToFloat(settings.CurrentBudgetPLN)
-	newBudget := currentBudget + adjustment
-	settings.CurrentBudgetPLN = utils.FloatToDecimalString(newBudget)
+	budget := utils.MoneyFromString(settings.CurrentBudgetPLN) + adjustment
+	settings.CurrentBudgetPLN = budget.String()
 	settings.UpdatedAt = time.Now()
 
-	if err := s.supplySettings.Upsert(ctx, settings); err != nil {
-		return fmt.Errorf("failed to adjust budget: %w", err)
+	if err := supplySettings.Upsert(ctx, settings); err != nil {
+		return 0, fmt.Errorf("failed to adjust budget: %w", err)
 	}
 
-	return nil
+	if adjustment != 0 {
+		if err := postLedger(ctx, ledgerEntries, supplyAdjustmentLedgerRecord(settings.ID, adjustment, notes, settings.UpdatedAt)); err != nil {
+			return 0, fmt.Errorf("failed to post budget adjustment to ledger: %w", err)
+		}
+	}
+
+	return budget, nil
 }
 
 // SetBudgetHolder assigns a user as the budget holder (ADMIN only)
@@ -316,8 +378,9 @@ func (s *SupplyService) UpdateItem(ctx context.Context, itemID string, name *str
 	return nil
 }
 
-// RestockItem increases quantity and optionally records amount spent for refund
-func (s *SupplyService) RestockItem(ctx context.Context, itemID, userID string, quantityToAdd int, amountPLN *float64, needsRefund bool) error {
+// RestockItem increases quantity and optionally records amount spent for refund.
+// An amount in a foreign currency is converted to the base currency at today's rate.
+func (s *SupplyService) RestockItem(ctx context.Context, itemID, userID string, quantityToAdd int, amountPLN *utils.Money, currency string, needsRefund bool) error {
 	if quantityToAdd <= 0 {
 		return errors.New("quantity to add must be positive")
 	}
@@ -337,15 +400,32 @@ func (s *SupplyService) RestockItem(ctx context.Context, itemID, userID string,
 		if *amountPLN < 0 {
 			return errors.New("amount cannot be negative")
 		}
-		amountStr := utils.FloatToDecimalString(*amountPLN)
+		converted, err := s.currencyService.ToBase(ctx, *amountPLN, currency, now)
+		if err != nil {
+			return err
+		}
+		amountPLN = &converted.Amount
+		amountStr := amountPLN.String()
 		item.LastRestockAmountPLN = &amountStr
+		item.LastRestockCurrency = &converted.Currency
+		item.LastRestockOriginalAmount = converted.OriginalAmount
+		item.LastRestockExchangeRate = converted.ExchangeRate
 	}
 
-	if err := s.supplyItems.Update(ctx, item); err != nil {
-		return fmt.Errorf("failed to restock item: %w", err)
-	}
+	return s.inTx(ctx, func(ctx context.Context, tx *SupplyService) error {
+		if err := tx.supplyItems.Update(ctx, item); err != nil {
+			return fmt.Errorf("failed to restock item: %w", err)
+		}
 
-	return nil
+		// Money paid out of pocket is owed back by the supply budget
+		if needsRefund && amountPLN != nil && *amountPLN > 0 {
+			if err := postLedger(ctx, tx.ledgerEntries, supplyPurchaseLedgerRecord(item, userID, *amountPLN, now)); err != nil {
+				return fmt.Errorf("failed to post purchase to ledger: %w", err)
+			}
+		}
+
+		return nil
+	})
 }
 
 // ConsumeItem reduces quantity (for use/consumption)
@@ -419,47 +499,79 @@ func (s *SupplyService) SetQuantity(ctx context.Context, itemID string, newQuant
 
 // MarkAsRefunded marks an item as refunded and deducts from shared budget
 func (s *SupplyService) MarkAsRefunded(ctx context.Context, itemID string) error {
-	// Get item to check refund details
-	item, err := s.supplyItems.GetByID(ctx, itemID)
+	return s.inTx(ctx, func(ctx context.Context, tx *SupplyService) error {
+		_, _, err := refundSupplyItem(ctx, tx.supplySettings, tx.supplyItems, tx.ledgerEntries, itemID)
+		return err
+	})
+}
+
+// ExecuteApprovedRefund is the approval executor for "supply.refund" requests
+func (s *SupplyService) ExecuteApprovedRefund(ctx context.Context, repos *repository.Repositories, request *models.ApprovalRequest) (map[string]interface{}, error) {
+	if request.ResourceID == nil {
+		return nil, errors.New("approval request has no item ID")
+	}
+
+	item, refunded, err := refundSupplyItem(ctx, repos.SupplySettings, repos.SupplyItems, repos.LedgerEntries, *request.ResourceID)
 	if err != nil {
-		return errors.New("item not found")
+		return nil, err
+	}
+
+	return map[string]interface{}{
+		"itemId":      item.ID,
+		"itemName":    item.Name,
+		"refundedPLN": refunded.String(),
+	}, nil
+}
+
+// refundSupplyItem pays a pending restock refund out of the budget, returning the item and refunded amount
+func refundSupplyItem(ctx context.Context, supplySettings repository.SupplySettingsRepository, supplyItems repository.SupplyItemRepository, ledgerEntries repository.LedgerEntryRepository, itemID string) (*models.SupplyItem, utils.Money, error) {
+	// Get item to check refund details
+	item, err := supplyItems.GetByID(ctx, itemID)
+	if err != nil || item == nil {
+		return nil, 0, errors.New("item not found")
 	}
 
 	if !item.NeedsRefund {
-		return errors.New("item does not need refund")
+		return nil, 0, errors.New("item does not need refund")
 	}
 
 	if item.LastRestockAmountPLN == nil {
-		return errors.New("no refund amount recorded")
+		return nil, 0, errors.New("no refund amount recorded")
 	}
 
 	// Deduct from budget
-	settings, err := s.GetSettings(ctx)
+	settings, err := getOrCreateSupplySettings(ctx, supplySettings)
 	if err != nil {
-		return err
+		return nil, 0, err
 	}
 
-	amountToRefund := utils.DecimalStringToFloat(*item.LastRestockAmountPLN)
-	currentBudget := utils.DecimalStringToFloat(settings.CurrentBudgetPLN)
+	amountToRefund := utils.MoneyFromString(*item.LastRestockAmountPLN)
+	currentBudget := utils.MoneyFromString(settings.CurrentBudgetPLN)
 
 	if currentBudget < amountToRefund {
-		return fmt.Errorf("insufficient budget: have %.2f PLN, need %.2f PLN", currentBudget, amountToRefund)
+		return nil, 0, fmt.Errorf("insufficient budget: have %s PLN, need %s PLN", currentBudget, amountToRefund)
 	}
 
 	// Update item
 	item.NeedsRefund = false
-	if err := s.supplyItems.Update(ctx, item); err != nil {
-		return fmt.Errorf("failed to update item: %w", err)
+	if err := supplyItems.Update(ctx, item); err != nil {
+		return nil, 0, fmt.Errorf("failed to update item: %w", err)
 	}
 
 	// Update budget
-	settings.CurrentBudgetPLN = utils.FloatToDecimalString(currentBudget - amountToRefund)
+	settings.CurrentBudgetPLN = (currentBudget - amountToRefund).String()
 	settings.UpdatedAt = time.Now()
-	if err := s.supplySettings.Upsert(ctx, settings); err != nil {
-		return fmt.Errorf("failed to update budget: %w", err)
+	if err := supplySettings.Upsert(ctx, settings); err != nil {
+		return nil, 0, fmt.Errorf("failed to update budget: %w", err)
 	}
 
-	return nil
+	if item.LastRestockedByUserID != nil && amountToRefund > 0 {
+		if err := postLedger(ctx, ledgerEntries, supplyRefundLedgerRecord(item, *item.LastRestockedByUserID, amountToRefund, settings.UpdatedAt)); err != nil {
+			return nil, 0, fmt.Errorf("failed to post refund to ledger: %w", err)
+		}
+	}
+
+	return item, amountToRefund, nil
 }
 
 // DeleteItem deletes an item (ADMIN or creator only - enforced at handler level)
@@ -536,7 +648,7 @@ func (s *SupplyService) getAllContributions(ctx context.Context) ([]models.Suppl
 }
 
 // CreateManualContribution adds a manual contribution
-func (s *SupplyService) CreateManualContribution(ctx context.Context, userID string, amountPLN float64, notes *string) error {
+func (s *SupplyService) CreateManualContribution(ctx context.Context, userID string, amountPLN utils.Money, notes *string) error {
 	if amountPLN <= 0 {
 		return errors.New("amount must be positive")
 	}
@@ -545,7 +657,7 @@ func (s *SupplyService) CreateManualContribution(ctx context.Context, userID str
 	contribution := models.SupplyContribution{
 		ID:          uuid.New().String(),
 		UserID:      userID,
-		AmountPLN:   utils.FloatToDecimalString(amountPLN),
+		AmountPLN:   amountPLN.String(),
 		PeriodStart: now,
 		PeriodEnd:   now,
 		Type:        "manual",
@@ -553,25 +665,30 @@ func (s *SupplyService) CreateManualContribution(ctx context.Context, userID str
 		CreatedAt:   now,
 	}
 
-	if err := s.supplyContributions.Create(ctx, &contribution); err != nil {
-		return fmt.Errorf("failed to create contribution: %w", err)
-	}
+	return s.inTx(ctx, func(ctx context.Context, tx *SupplyService) error {
+		if err := tx.supplyContributions.Create(ctx, &contribution); err != nil {
+			return fmt.Errorf("failed to create contribution: %w", err)
+		}
+		if err := syncLedger(ctx, tx.ledgerEntries, supplyContributionLedgerRecord(&contribution)); err != nil {
+			return fmt.Errorf("failed to post contribution to ledger: %w", err)
+		}
 
-	// Add to budget
-	settings, err := s.GetSettings(ctx)
-	if err != nil {
-		return err
-	}
+		// Add to budget
+		settings, err := tx.GetSettings(ctx)
+		if err != nil {
+			return err
+		}
 
-	currentBudget := utils.DecimalStringToFloat(settings.CurrentBudgetPLN)
-	settings.CurrentBudgetPLN = utils.FloatToDecimalString(currentBudget + amountPLN)
-	settings.UpdatedAt = time.Now()
+		currentBudget := utils.MoneyFromString(settings.CurrentBudgetPLN)
+		settings.CurrentBudgetPLN = (currentBudget + amountPLN).String()
+		settings.UpdatedAt = time.Now()
 
-	if err := s.supplySettings.Upsert(ctx, settings); err != nil {
-		return fmt.Errorf("failed to update budget: %w", err)
-	}
+		if err := tx.supplySettings.Upsert(ctx, settings); err != nil {
+			return fmt.Errorf("failed to update budget: %w", err)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // ProcessWeeklyContributions creates automatic weekly contributions for all active users
@@ -599,39 +716,44 @@ func (s *SupplyService) ProcessWeeklyContributions(ctx context.Context) error {
 	weekStart := now.AddDate(0, 0, -int(now.Weekday()))
 	weekEnd := weekStart.AddDate(0, 0, 6)
 
-	totalContributed := 0.0
-	weeklyContribution := utils.DecimalStringToFloat(settings.WeeklyContributionPLN)
+	var totalContributed utils.Money
+	weeklyContribution := utils.MoneyFromString(settings.WeeklyContributionPLN)
+
+	return s.inTx(ctx, func(ctx context.Context, tx *SupplyService) error {
+		// Create contribution for each active user
+		for _, user := range users {
+			contribution := models.SupplyContribution{
+				ID:          uuid.New().String(),
+				UserID:      user.ID,
+				AmountPLN:   settings.WeeklyContributionPLN,
+				PeriodStart: weekStart,
+				PeriodEnd:   weekEnd,
+				Type:        "weekly_auto",
+				CreatedAt:   now,
+			}
 
-	// Create contribution for each active user
-	for _, user := range users {
-		contribution := models.SupplyContribution{
-			ID:          uuid.New().String(),
-			UserID:      user.ID,
-			AmountPLN:   settings.WeeklyContributionPLN,
-			PeriodStart: weekStart,
-			PeriodEnd:   weekEnd,
-			Type:        "weekly_auto",
-			CreatedAt:   now,
-		}
+			if err := tx.supplyContributions.Create(ctx, &contribution); err != nil {
+				return fmt.Errorf("failed to create contribution for user %s: %w", user.Email, err)
+			}
+			if err := syncLedger(ctx, tx.ledgerEntries, supplyContributionLedgerRecord(&contribution)); err != nil {
+				return fmt.Errorf("failed to post contribution to ledger: %w", err)
+			}
 
-		if err := s.supplyContributions.Create(ctx, &contribution); err != nil {
-			return fmt.Errorf("failed to create contribution for user %s: %w", user.Email, err)
+			totalContributed += weeklyContribution
 		}
 
-		totalContributed += weeklyContribution
-	}
-
-	// Update budget
-	currentBudget := utils.DecimalStringToFloat(settings.CurrentBudgetPLN)
-	settings.CurrentBudgetPLN = utils.FloatToDecimalString(currentBudget + totalContributed)
-	settings.LastContributionAt = now
-	settings.UpdatedAt = now
+		// Update budget
+		currentBudget := utils.MoneyFromString(settings.CurrentBudgetPLN)
+		settings.CurrentBudgetPLN = (currentBudget + totalContributed).String()
+		settings.LastContributionAt = now
+		settings.UpdatedAt = now
 
-	if err := s.supplySettings.Upsert(ctx, settings); err != nil {
-		return fmt.Errorf("failed to update budget: %w", err)
-	}
+		if err := tx.supplySettings.Upsert(ctx, settings); err != nil {
+			return fmt.Errorf("failed to update budget: %w", err)
+		}
 
-	return nil
+		return nil
+	})
 }
 
 // GetStats returns spending statistics
@@ -649,12 +771,12 @@ func (s *SupplyService) GetStats(ctx context.Context) (map[string]interface{}, e
 			if _, exists := categoryStats[item.Category]; !exists {
 				categoryStats[item.Category] = map[string]interface{}{
 					"_id":        item.Category,
-					"totalSpent": 0.0,
+					"totalSpent": utils.Money(0),
 					"count":      0,
 				}
 			}
-			amount := utils.DecimalStringToFloat(*item.LastRestockAmountPLN)
-			categoryStats[item.Category]["totalSpent"] = categoryStats[item.Category]["totalSpent"].(float64) + amount
+			amount := utils.MoneyFromString(*item.LastRestockAmountPLN)
+			categoryStats[item.Category]["totalSpent"] = categoryStats[item.Category]["totalSpent"].(utils.Money) + amount
 			categoryStats[item.Category]["count"] = categoryStats[item.Category]["count"].(int) + 1
 		}
 	}
@@ -672,12 +794,12 @@ func (s *SupplyService) GetStats(ctx context.Context) (map[string]interface{}, e
 			if _, exists := userStats[userID]; !exists {
 				userStats[userID] = map[string]interface{}{
 					"_id":        userID,
-					"totalSpent": 0.0,
+					"totalSpent": utils.Money(0),
 					"count":      0,
 				}
 			}
-			amount := utils.DecimalStringToFloat(*item.LastRestockAmountPLN)
-			userStats[userID]["totalSpent"] = userStats[userID]["totalSpent"].(float64) + amount
+			amount := utils.MoneyFromString(*item.LastRestockAmountPLN)
+			userStats[userID]["totalSpent"] = userStats[userID]["totalSpent"].(utils.Money) + amount
 			userStats[userID]["count"] = userStats[userID]["count"].(int) + 1
 		}
 	}
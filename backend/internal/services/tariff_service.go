@@ -0,0 +1,249 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/sainaif/holy-home/internal/utils"
+)
+
+type TariffService struct {
+	tariffs repository.TariffRepository
+}
+
+func NewTariffService(tariffs repository.TariffRepository) *TariffService {
+	return &TariffService{tariffs: tariffs}
+}
+
+type TariffComponentRequest struct {
+	Name     string       `json:"name"`
+	Kind     string       `json:"kind"`               // fixed, variable
+	Amount   *utils.Money `json:"amount,omitempty"`   // fixed: amount per bill
+	Rate     *float64     `json:"rate,omitempty"`     // variable: price per unit
+	Register *string      `json:"register,omitempty"` // variable: only units of this register
+	TierFrom *float64     `json:"tierFrom,omitempty"` // variable: usage before the rate applies
+	TierTo   *float64     `json:"tierTo,omitempty"`   // variable: usage after which the rate stops applying
+}
+
+type CreateTariffRequest struct {
+	UtilityType string                   `json:"utilityType"` // bill type, or the custom type of "inne" bills
+	Name        string                   `json:"name"`
+	ValidFrom   time.Time                `json:"validFrom"`
+	ValidTo     *time.Time               `json:"validTo,omitempty"`
+	Components  []TariffComponentRequest `json:"components"`
+}
+
+type UpdateTariffRequest struct {
+	Name       *string                   `json:"name,omitempty"`
+	ValidFrom  *time.Time                `json:"validFrom,omitempty"`
+	ValidTo    *time.Time                `json:"validTo,omitempty"`
+	Components *[]TariffComponentRequest `json:"components,omitempty"`
+}
+
+// CreateTariff defines a tariff for a utility
+func (s *TariffService) CreateTariff(ctx context.Context, req CreateTariffRequest) (*models.Tariff, error) {
+	utilityType := strings.ToLower(strings.TrimSpace(req.UtilityType))
+	if utilityType == "" {
+		return nil, errors.New("utility type is required")
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		return nil, errors.New("name is required")
+	}
+	if req.ValidFrom.IsZero() {
+		return nil, errors.New("validFrom is required")
+	}
+	if req.ValidTo != nil && req.ValidTo.Before(req.ValidFrom) {
+		return nil, errors.New("validTo must be after validFrom")
+	}
+	components, err := buildTariffComponents(req.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	tariff := &models.Tariff{
+		ID:          uuid.New().String(),
+		UtilityType: utilityType,
+		Name:        strings.TrimSpace(req.Name),
+		ValidFrom:   req.ValidFrom,
+		ValidTo:     req.ValidTo,
+		Components:  components,
+		CreatedAt:   time.Now(),
+	}
+	if err := s.tariffs.Create(ctx, tariff); err != nil {
+		return nil, fmt.Errorf("failed to create tariff: %w", err)
+	}
+
+	return tariff, nil
+}
+
+// GetTariffs retrieves all tariffs
+func (s *TariffService) GetTariffs(ctx context.Context) ([]models.Tariff, error) {
+	tariffs, err := s.tariffs.List(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	return tariffs, nil
+}
+
+// GetTariff retrieves a tariff by ID
+func (s *TariffService) GetTariff(ctx context.Context, tariffID string) (*models.Tariff, error) {
+	tariff, err := s.tariffs.GetByID(ctx, tariffID)
+	if err != nil {
+		return nil, fmt.Errorf("database error: %w", err)
+	}
+	if tariff == nil {
+		return nil, errors.New("tariff not found")
+	}
+	return tariff, nil
+}
+
+// UpdateTariff updates a tariff. Components are replaced as a whole.
+func (s *TariffService) UpdateTariff(ctx context.Context, tariffID string, req UpdateTariffRequest) (*models.Tariff, error) {
+	tariff, err := s.GetTariff(ctx, tariffID)
+	if err != nil {
+		return nil, err
+	}
+
+	if req.Name != nil {
+		if strings.TrimSpace(*req.Name) == "" {
+			return nil, errors.New("name cannot be empty")
+		}
+		tariff.Name = strings.TrimSpace(*req.Name)
+	}
+	if req.ValidFrom != nil {
+		tariff.ValidFrom = *req.ValidFrom
+	}
+	if req.ValidTo != nil {
+		tariff.ValidTo = req.ValidTo
+	}
+	if tariff.ValidTo != nil && tariff.ValidTo.Before(tariff.ValidFrom) {
+		return nil, errors.New("validTo must be after validFrom")
+	}
+	if req.Components != nil {
+		components, err := buildTariffComponents(*req.Components)
+		if err != nil {
+			return nil, err
+		}
+		tariff.Components = components
+	}
+
+	if err := s.tariffs.Update(ctx, tariff); err != nil {
+		return nil, fmt.Errorf("failed to update tariff: %w", err)
+	}
+
+	return tariff, nil
+}
+
+// DeleteTariff deletes a tariff. Bills it applied to fall back to another tariff or a single rate.
+func (s *TariffService) DeleteTariff(ctx context.Context, tariffID string) error {
+	if _, err := s.GetTariff(ctx, tariffID); err != nil {
+		return err
+	}
+	return s.tariffs.Delete(ctx, tariffID)
+}
+
+// buildTariffComponents validates the requested components and converts them for storage
+func buildTariffComponents(requests []TariffComponentRequest) ([]models.TariffComponent, error) {
+	if len(requests) == 0 {
+		return nil, errors.New("a tariff needs at least one component")
+	}
+
+	components := make([]models.TariffComponent, 0, len(requests))
+	for i, req := range requests {
+		name := strings.TrimSpace(req.Name)
+		if name == "" {
+			return nil, fmt.Errorf("component %d: name is required", i+1)
+		}
+		component := models.TariffComponent{Name: name, Kind: req.Kind}
+
+		switch req.Kind {
+		case "fixed":
+			if req.Amount == nil || *req.Amount < 0 {
+				return nil, fmt.Errorf("component %q: fixed components need a non-negative amount", name)
+			}
+			if req.Rate != nil || req.Register != nil || req.TierFrom != nil || req.TierTo != nil {
+				return nil, fmt.Errorf("component %q: fixed components only have an amount", name)
+			}
+			amount := req.Amount.String()
+			component.Amount = &amount
+		case "variable":
+			if req.Rate == nil || *req.Rate < 0 {
+				return nil, fmt.Errorf("component %q: variable components need a non-negative rate", name)
+			}
+			if req.Amount != nil {
+				return nil, fmt.Errorf("component %q: variable components have a rate instead of an amount", name)
+			}
+			component.Rate = tariffDecimal(req.Rate)
+			if req.Register != nil && strings.TrimSpace(*req.Register) != "" {
+				register := strings.ToLower(strings.TrimSpace(*req.Register))
+				component.Register = &register
+			}
+			if req.TierFrom != nil && *req.TierFrom < 0 {
+				return nil, fmt.Errorf("component %q: tierFrom cannot be negative", name)
+			}
+			if req.TierTo != nil && *req.TierTo <= tariffValue(tariffDecimal(req.TierFrom), 0) {
+				return nil, fmt.Errorf("component %q: tierTo must be above tierFrom", name)
+			}
+			component.TierFrom = tariffDecimal(req.TierFrom)
+			component.TierTo = tariffDecimal(req.TierTo)
+		default:
+			return nil, fmt.Errorf("component %q: kind must be fixed or variable", name)
+		}
+
+		components = append(components, component)
+	}
+	return components, nil
+}
+
+// tariffDecimal stores a rate or tier bound without rounding it to two decimals
+func tariffDecimal(value *float64) *string {
+	if value == nil {
+		return nil
+	}
+	decimal := strconv.FormatFloat(*value, 'f', -1, 64)
+	return &decimal
+}
+
+// tariffValue reads a stored rate or tier bound, fallback when it is not set
+func tariffValue(value *string, fallback float64) float64 {
+	if value == nil {
+		return fallback
+	}
+	return utils.DecimalStringToFloat(*value)
+}
+
+// billTariff returns the tariff of a bill's utility in force at the start of its period,
+// or nil when there is none. Later tariffs take precedence over overlapping earlier ones.
+func billTariff(ctx context.Context, tariffs repository.TariffRepository, bill *models.Bill) (*models.Tariff, error) {
+	utilityType := bill.Type
+	if bill.Type == "inne" && bill.CustomType != nil {
+		utilityType = *bill.CustomType
+	}
+
+	candidates, err := tariffs.ListByUtilityType(ctx, strings.ToLower(strings.TrimSpace(utilityType)))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tariffs: %w", err)
+	}
+
+	var tariff *models.Tariff
+	for i := range candidates {
+		candidate := &candidates[i]
+		if candidate.ValidFrom.After(bill.PeriodStart) {
+			continue
+		}
+		if candidate.ValidTo != nil && candidate.ValidTo.Before(bill.PeriodStart) {
+			continue
+		}
+		if tariff == nil || !candidate.ValidFrom.Before(tariff.ValidFrom) {
+			tariff = candidate
+		}
+	}
+	return tariff, nil
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/sainaif/holy-home/internal/models"
+	"github.com/sainaif/holy-home/internal/repository"
+	"github.com/sainaif/holy-home/internal/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type memoryTariffs struct {
+	repository.TariffRepository
+	tariffs []models.Tariff
+}
+
+func (m *memoryTariffs) Create(ctx context.Context, tariff *models.Tariff) error {
+	m.tariffs = append(m.tariffs, *tariff)
+	return nil
+}
+
+func (m *memoryTariffs) ListByUtilityType(ctx context.Context, utilityType string) ([]models.Tariff, error) {
+	var tariffs []models.Tariff
+	for _, tariff := range m.tariffs {
+		if tariff.UtilityType == utilityType {
+			tariffs = append(tariffs, tariff)
+		}
+	}
+	return tariffs, nil
+}
+
+type memoryGroups struct {
+	repository.GroupRepository
+	groups []models.Group
+}
+
+func (m *memoryGroups) List(ctx context.Context) ([]models.Group, error) {
+	return m.groups, nil
+}
+
+func (m *memoryBills) GetByID(ctx context.Context, id string) (*models.Bill, error) {
+	for _, bill := range m.bills {
+		if bill.ID == id {
+			return &bill, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestTariffAllocation(t *testing.T) {
+	ctx := context.Background()
+	groupID := "flat"
+	money := func(s string) *utils.Money { m := utils.MoneyFromString(s); return &m }
+	float := func(f float64) *float64 { return &f }
+	register := func(r string) *string { return &r }
+	reading := func(subjectType, subjectID, r, units string) models.Consumption {
+		return models.Consumption{BillID: "b1", SubjectType: subjectType, SubjectID: subjectID, Register: register(r), Units: units}
+	}
+
+	users := &memoryUsers{users: []models.User{
+		{ID: "anna", Name: "Anna", GroupID: &groupID, IsActive: true},
+		{ID: "ola", Name: "Ola", GroupID: &groupID, IsActive: true},
+		{ID: "piotr", Name: "Piotr", IsActive: true},
+	}}
+	groups := &memoryGroups{groups: []models.Group{{ID: groupID, Name: "Flat", Weight: 1}}}
+	consumptions := &memoryConsumptions{consumptions: []models.Consumption{
+		reading("group", groupID, "day", "100.00"),
+		reading("group", groupID, "night", "50.00"),
+		reading("user", "piotr", "day", "20.00"),
+		reading("user", "piotr", "night", "10.00"),
+	}}
+	tariffs := &memoryTariffs{}
+	bills := &memoryBills{bills: []models.Bill{{
+		ID: "b1", Type: "electricity", TotalAmountPLN: "200.00", TotalUnits: "180",
+		PeriodStart: time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC),
+	}}}
+
+	_, err := NewTariffService(tariffs).CreateTariff(ctx, CreateTariffRequest{
+		UtilityType: "electricity", Name: "G12", ValidFrom: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Components: []TariffComponentRequest{{Name: "Day", Kind: "fixed", Rate: float(0.8)}},
+	})
+	assert.Error(t, err, "fixed components only have an amount")
+
+	_, err = NewTariffService(tariffs).CreateTariff(ctx, CreateTariffRequest{
+		UtilityType: "Electricity", Name: "G12", ValidFrom: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		Components: []TariffComponentRequest{
+			{Name: "Distribution", Kind: "fixed", Amount: money("40.00")},
+			{Name: "Day", Kind: "variable", Rate: float(0.8), Register: register("Day")},
+			{Name: "Night", Kind: "variable", Rate: float(0.5), Register: register("night")},
+		},
+	})
+	require.NoError(t, err)
+
+	allocations := NewAllocationService(users, groups, &memoryResidencies{}, &memoryGroupWeightChanges{}, &memoryMembershipChanges{}, consumptions, nil, nil, tariffs, &memoryAllocations{}, bills)
+	breakdown, err := allocations.GetAllocationBreakdown(ctx, "b1")
+	require.NoError(t, err)
+	require.Len(t, breakdown, 2)
+
+	// Fixed 40.00 split 2:1, usage at the day and night rates, the other 34.00 split 2:1 as well
+	flat, piotr := breakdown[0], breakdown[1]
+	assert.Equal(t, "154.34", flat.Amount.String())
+	assert.Equal(t, "105.00", flat.PersonalAmount.String())
+	assert.Equal(t, "45.66", piotr.Amount.String())
+	assert.Equal(t, []string{"26.67", "80.00", "25.00", "22.67"}, componentAmounts(flat.Components))
+	assert.Equal(t, []string{"13.33", "16.00", "5.00", "11.33"}, componentAmounts(piotr.Components))
+	assert.Equal(t, "remainder", piotr.Components[3].Kind)
+
+	// Usage tiers are charged on each subject's own usage
+	gas := &models.Tariff{Components: []models.TariffComponent{
+		{Name: "Up to 100", Kind: "variable", Rate: register("2"), TierTo: register("100")},
+		{Name: "Above 100", Kind: "variable", Rate: register("2.5"), TierFrom: register("100")},
+	}}
+	breakdown, err = allocations.CalculateTariffAllocation(ctx, "b1", utils.MoneyFromString("400.00"), gas)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"200.00", "125.00", "10.00"}, componentAmounts(breakdown[0].Components))
+	assert.Equal(t, []string{"60.00", "0.00", "5.00"}, componentAmounts(breakdown[1].Components))
+	assert.Equal(t, "400.00", (breakdown[0].Amount + breakdown[1].Amount).String())
+
+	// Bills before the tariff took effect keep the single rate split
+	bills.bills[0].PeriodStart = time.Date(2025, 12, 1, 0, 0, 0, 0, time.UTC)
+	breakdown, err = allocations.GetAllocationBreakdown(ctx, "b1")
+	require.NoError(t, err)
+	assert.Empty(t, breakdown[0].Components)
+}
+
+func componentAmounts(components []AllocationComponent) []string {
+	amounts := make([]string, len(components))
+	for i, component := range components {
+		amounts[i] = component.Amount.String()
+	}
+	return amounts
+}
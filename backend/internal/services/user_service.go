@@ -18,6 +18,8 @@ import (
 type UserService struct {
 	users               repository.UserRepository
 	groups              repository.GroupRepository
+	residencies         repository.ResidencyRepository
+	membershipChanges   repository.GroupMembershipChangeRepository
 	roles               repository.RoleRepository
 	passwordResetTokens repository.PasswordResetTokenRepository
 	config              *config.Config
@@ -26,6 +28,8 @@ type UserService struct {
 func NewUserService(
 	users repository.UserRepository,
 	groups repository.GroupRepository,
+	residencies repository.ResidencyRepository,
+	membershipChanges repository.GroupMembershipChangeRepository,
 	roles repository.RoleRepository,
 	passwordResetTokens repository.PasswordResetTokenRepository,
 	cfg *config.Config,
@@ -33,6 +37,8 @@ func NewUserService(
 	return &UserService{
 		users:               users,
 		groups:              groups,
+		residencies:         residencies,
+		membershipChanges:   membershipChanges,
 		roles:               roles,
 		passwordResetTokens: passwordResetTokens,
 		config:              cfg,
@@ -55,6 +61,8 @@ type UpdateUserRequest struct {
 	Role     *string `json:"role,omitempty"`
 	GroupID  *string `json:"groupId,omitempty"`
 	IsActive *bool   `json:"isActive,omitempty"`
+	// Day the group change applies from, today when not set. Bill periods before it keep the old group.
+	GroupEffectiveFrom *time.Time `json:"groupEffectiveFrom,omitempty"`
 }
 
 // CreateUser creates a new user (ADMIN only)
@@ -223,17 +231,22 @@ func (s *UserService) UpdateUser(ctx context.Context, userID string, req UpdateU
 	}
 
 	if req.GroupID != nil {
-		if *req.GroupID == "" {
-			// Empty string means remove the group
-			user.GroupID = nil
-		} else {
+		var groupID *string
+		if *req.GroupID != "" {
 			// Verify group exists before assigning
-			_, err := s.groups.GetByID(ctx, *req.GroupID)
-			if err != nil {
+			group, err := s.groups.GetByID(ctx, *req.GroupID)
+			if err != nil || group == nil {
 				return errors.New("invalid group: group does not exist")
 			}
-			user.GroupID = req.GroupID
+			groupID = req.GroupID
+		}
+		// Empty string means remove the group
+		if !sameOptional(user.GroupID, groupID) {
+			if err := s.recordMembershipChange(ctx, user, groupID, req.GroupEffectiveFrom); err != nil {
+				return err
+			}
 		}
+		user.GroupID = groupID
 	}
 
 	if req.IsActive != nil {
@@ -249,6 +262,225 @@ func (s *UserService) UpdateUser(ctx context.Context, userID string, req UpdateU
 	return nil
 }
 
+// recordMembershipChange records the user moving to groupID from the effective day. An open
+// residency is split there, so the stay before the day keeps the old group.
+func (s *UserService) recordMembershipChange(ctx context.Context, user *models.User, groupID *string, effectiveFrom *time.Time) error {
+	day := residencyDay(time.Now())
+	if effectiveFrom != nil {
+		day = residencyDay(*effectiveFrom)
+	}
+
+	// Changes are chained by their old group, so they can only be added in order
+	changes, err := s.membershipChanges.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get group history: %w", err)
+	}
+	if len(changes) > 0 && day.Before(changes[len(changes)-1].EffectiveFrom) {
+		return fmt.Errorf("group change cannot take effect before the last one on %s",
+			changes[len(changes)-1].EffectiveFrom.Format("2006-01-02"))
+	}
+
+	residencies, err := s.residencies.ListByUserID(ctx, user.ID)
+	if err != nil {
+		return fmt.Errorf("failed to get residencies: %w", err)
+	}
+	for _, residency := range residencies {
+		if residency.EndDate != nil {
+			continue
+		}
+		if !day.After(residency.StartDate) {
+			residency.GroupID = groupID
+			if err := s.residencies.Update(ctx, &residency); err != nil {
+				return fmt.Errorf("failed to update residency: %w", err)
+			}
+			continue
+		}
+
+		next := models.Residency{
+			ID:        uuid.New().String(),
+			UserID:    user.ID,
+			GroupID:   groupID,
+			StartDate: day,
+			CreatedAt: time.Now(),
+		}
+		lastDay := day.AddDate(0, 0, -1)
+		residency.EndDate = &lastDay
+		if err := s.residencies.Update(ctx, &residency); err != nil {
+			return fmt.Errorf("failed to update residency: %w", err)
+		}
+		if err := s.residencies.Create(ctx, &next); err != nil {
+			return fmt.Errorf("failed to create residency: %w", err)
+		}
+	}
+
+	change := models.GroupMembershipChange{
+		ID:            uuid.New().String(),
+		UserID:        user.ID,
+		OldGroupID:    user.GroupID,
+		NewGroupID:    groupID,
+		EffectiveFrom: day,
+		CreatedAt:     time.Now(),
+	}
+	if err := s.membershipChanges.Create(ctx, &change); err != nil {
+		return fmt.Errorf("failed to record group change: %w", err)
+	}
+
+	log.Printf("[USER] Group of %s changes on %s (ID: %s)", user.ID, day.Format("2006-01-02"), change.ID)
+	return nil
+}
+
+// GetGroupHistory lists the group changes of a user, oldest first
+func (s *UserService) GetGroupHistory(ctx context.Context, userID string) ([]models.GroupMembershipChange, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	return s.membershipChanges.ListByUserID(ctx, userID)
+}
+
+type CreateResidencyRequest struct {
+	GroupID   *string    `json:"groupId,omitempty"`
+	StartDate time.Time  `json:"startDate"`
+	EndDate   *time.Time `json:"endDate,omitempty"` // last day of the stay
+}
+
+type UpdateResidencyRequest struct {
+	GroupID   *string    `json:"groupId,omitempty"` // empty string: on their own
+	StartDate *time.Time `json:"startDate,omitempty"`
+	EndDate   *time.Time `json:"endDate,omitempty"`
+}
+
+// GetResidencies lists the periods a user lived in the household, oldest first
+func (s *UserService) GetResidencies(ctx context.Context, userID string) ([]models.Residency, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+	return s.residencies.ListByUserID(ctx, userID)
+}
+
+// CreateResidency records a period the user lived in the household. Once a user has
+// residencies, bills only charge them for the days of the bill period they lived here.
+func (s *UserService) CreateResidency(ctx context.Context, userID string, req CreateResidencyRequest) (*models.Residency, error) {
+	user, err := s.users.GetByID(ctx, userID)
+	if err != nil || user == nil {
+		return nil, errors.New("user not found")
+	}
+
+	residency := models.Residency{
+		ID:        uuid.New().String(),
+		UserID:    user.ID,
+		GroupID:   req.GroupID,
+		StartDate: req.StartDate,
+		EndDate:   req.EndDate,
+		CreatedAt: time.Now(),
+	}
+	if residency.GroupID != nil && *residency.GroupID == "" {
+		residency.GroupID = nil
+	}
+	if err := s.validateResidency(ctx, &residency); err != nil {
+		return nil, err
+	}
+
+	if err := s.residencies.Create(ctx, &residency); err != nil {
+		return nil, fmt.Errorf("failed to create residency: %w", err)
+	}
+
+	log.Printf("[USER] Residency recorded: user %s from %s (ID: %s)", user.ID, residency.StartDate.Format("2006-01-02"), residency.ID)
+
+	return &residency, nil
+}
+
+// UpdateResidency changes the dates or group of a user's residency
+func (s *UserService) UpdateResidency(ctx context.Context, userID, residencyID string, req UpdateResidencyRequest) (*models.Residency, error) {
+	residency, err := s.residencies.GetByID(ctx, residencyID)
+	if err != nil || residency == nil || residency.UserID != userID {
+		return nil, errors.New("residency not found")
+	}
+
+	if req.GroupID != nil {
+		if *req.GroupID == "" {
+			residency.GroupID = nil
+		} else {
+			residency.GroupID = req.GroupID
+		}
+	}
+	if req.StartDate != nil {
+		residency.StartDate = *req.StartDate
+	}
+	if req.EndDate != nil {
+		residency.EndDate = req.EndDate
+	}
+	if err := s.validateResidency(ctx, residency); err != nil {
+		return nil, err
+	}
+
+	if err := s.residencies.Update(ctx, residency); err != nil {
+		return nil, fmt.Errorf("failed to update residency: %w", err)
+	}
+
+	return residency, nil
+}
+
+// DeleteResidency deletes a user's residency
+func (s *UserService) DeleteResidency(ctx context.Context, userID, residencyID string) error {
+	residency, err := s.residencies.GetByID(ctx, residencyID)
+	if err != nil || residency == nil || residency.UserID != userID {
+		return errors.New("residency not found")
+	}
+	if err := s.residencies.Delete(ctx, residencyID); err != nil {
+		return fmt.Errorf("failed to delete residency: %w", err)
+	}
+	return nil
+}
+
+// validateResidency truncates the residency to whole days and checks its group and that it
+// does not overlap another stay of the same user
+func (s *UserService) validateResidency(ctx context.Context, residency *models.Residency) error {
+	if residency.StartDate.IsZero() {
+		return errors.New("start date is required")
+	}
+	residency.StartDate = residencyDay(residency.StartDate)
+	if residency.EndDate != nil {
+		end := residencyDay(*residency.EndDate)
+		if end.Before(residency.StartDate) {
+			return errors.New("end date must not be before start date")
+		}
+		residency.EndDate = &end
+	}
+
+	if residency.GroupID != nil {
+		group, err := s.groups.GetByID(ctx, *residency.GroupID)
+		if err != nil || group == nil {
+			return errors.New("invalid group: group does not exist")
+		}
+	}
+
+	others, err := s.residencies.ListByUserID(ctx, residency.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to get residencies: %w", err)
+	}
+	for _, other := range others {
+		if other.ID != residency.ID && residenciesOverlap(other, *residency) {
+			return errors.New("residency overlaps another stay of the user")
+		}
+	}
+	return nil
+}
+
+// residencyDay truncates a time to its day in UTC
+func residencyDay(t time.Time) time.Time {
+	t = t.UTC()
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// residenciesOverlap reports whether two stays share at least one day
+func residenciesOverlap(a, b models.Residency) bool {
+	aEndsBeforeB := a.EndDate != nil && a.EndDate.Before(b.StartDate)
+	bEndsBeforeA := b.EndDate != nil && b.EndDate.Before(a.StartDate)
+	return !aEndsBeforeB && !bEndsBeforeA
+}
+
 // ChangePassword allows users to change their own password
 // Returns new JWT tokens for automatic re-login after password change
 func (s *UserService) ChangePassword(ctx context.Context, userID string, oldPassword, newPassword string) (map[string]string, error) {
@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// Passphrase-encrypted data starts with a header holding the Argon2id parameters, salt
+// and nonce, followed by the AES-256-GCM ciphertext. The header is authenticated too.
+//
+//	magic (8) | iterations (4) | memory KiB (4) | threads (1) | salt (16) | nonce (12) | ciphertext
+var encryptionMagic = []byte("HHENC\x00\x00\x01")
+
+const (
+	encryptionHeaderLen = 8 + 4 + 4 + 1 + saltLen + 12
+
+	// MinPassphraseLength is the shortest passphrase accepted for encryption
+	MinPassphraseLength = 12
+)
+
+var (
+	// ErrWrongPassphrase is returned when decryption fails authentication
+	ErrWrongPassphrase = errors.New("wrong passphrase or corrupted data")
+	// ErrPassphraseTooShort is returned when encrypting with a passphrase shorter than MinPassphraseLength
+	ErrPassphraseTooShort = fmt.Errorf("passphrase must be at least %d characters long", MinPassphraseLength)
+	// ErrUnsupportedKeyDerivation is returned when encrypted data asks for costlier Argon2id parameters than EncryptWithPassphrase uses
+	ErrUnsupportedKeyDerivation = errors.New("unsupported key derivation parameters")
+)
+
+// IsPassphraseEncrypted reports whether data was produced by EncryptWithPassphrase
+func IsPassphraseEncrypted(data []byte) bool {
+	return bytes.HasPrefix(data, encryptionMagic)
+}
+
+// EncryptWithPassphrase encrypts data with a key derived from passphrase with Argon2id
+func EncryptWithPassphrase(data []byte, passphrase string) ([]byte, error) {
+	if len([]rune(passphrase)) < MinPassphraseLength {
+		return nil, ErrPassphraseTooShort
+	}
+
+	header := make([]byte, 0, encryptionHeaderLen)
+	header = append(header, encryptionMagic...)
+	header = binary.BigEndian.AppendUint32(header, argon2Time)
+	header = binary.BigEndian.AppendUint32(header, argon2Memory)
+	header = append(header, argon2Threads)
+
+	random := make([]byte, saltLen+12)
+	if _, err := rand.Read(random); err != nil {
+		return nil, fmt.Errorf("failed to generate salt: %w", err)
+	}
+	header = append(header, random...)
+
+	gcm, err := passphraseCipher(passphrase, header)
+	if err != nil {
+		return nil, err
+	}
+	nonce := header[encryptionHeaderLen-gcm.NonceSize():]
+	return gcm.Seal(header, nonce, data, header), nil
+}
+
+// DecryptWithPassphrase decrypts data produced by EncryptWithPassphrase
+func DecryptWithPassphrase(data []byte, passphrase string) ([]byte, error) {
+	if !IsPassphraseEncrypted(data) {
+		return nil, errors.New("data is not passphrase encrypted")
+	}
+	if len(data) < encryptionHeaderLen {
+		return nil, ErrWrongPassphrase
+	}
+
+	header := data[:encryptionHeaderLen]
+	gcm, err := passphraseCipher(passphrase, header)
+	if err != nil {
+		return nil, err
+	}
+	nonce := header[encryptionHeaderLen-gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, data[encryptionHeaderLen:], header)
+	if err != nil {
+		return nil, ErrWrongPassphrase
+	}
+	return plaintext, nil
+}
+
+// passphraseCipher derives the AES-GCM cipher for a header's key derivation parameters
+func passphraseCipher(passphrase string, header []byte) (cipher.AEAD, error) {
+	params := header[len(encryptionMagic):]
+	iterations := binary.BigEndian.Uint32(params[0:4])
+	memory := binary.BigEndian.Uint32(params[4:8])
+	threads := params[8]
+	salt := params[9 : 9+saltLen]
+
+	// Refuse parameters costlier than EncryptWithPassphrase writes, so a crafted file cannot exhaust memory or CPU
+	if iterations == 0 || iterations > argon2Time || memory == 0 || memory > argon2Memory || threads == 0 || threads > argon2Threads {
+		return nil, ErrUnsupportedKeyDerivation
+	}
+
+	key := argon2.IDKey([]byte(passphrase), salt, iterations, memory, threads, argon2KeyLen)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
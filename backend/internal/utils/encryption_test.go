@@ -0,0 +1,62 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"testing"
+)
+
+func TestEncryptWithPassphrase(t *testing.T) {
+	plaintext := []byte(`{"version":"2.0","users":[]}`)
+	passphrase := "correct horse battery staple"
+
+	encrypted, err := EncryptWithPassphrase(plaintext, passphrase)
+	if err != nil {
+		t.Fatalf("EncryptWithPassphrase() error = %v", err)
+	}
+	if !IsPassphraseEncrypted(encrypted) {
+		t.Error("IsPassphraseEncrypted() = false for encrypted data")
+	}
+	if IsPassphraseEncrypted(plaintext) {
+		t.Error("IsPassphraseEncrypted() = true for plain data")
+	}
+	if bytes.Contains(encrypted, []byte("users")) {
+		t.Error("encrypted data contains plaintext")
+	}
+
+	decrypted, err := DecryptWithPassphrase(encrypted, passphrase)
+	if err != nil {
+		t.Fatalf("DecryptWithPassphrase() error = %v", err)
+	}
+	if !bytes.Equal(decrypted, plaintext) {
+		t.Errorf("DecryptWithPassphrase() = %q, want %q", decrypted, plaintext)
+	}
+
+	if _, err := DecryptWithPassphrase(encrypted, "wrong passphrase!"); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("DecryptWithPassphrase() with wrong passphrase error = %v, want ErrWrongPassphrase", err)
+	}
+
+	// The header is authenticated, so tampering with it fails like a wrong passphrase
+	tampered := bytes.Clone(encrypted)
+	tampered[len(tampered)-1] ^= 1
+	if _, err := DecryptWithPassphrase(tampered, passphrase); !errors.Is(err, ErrWrongPassphrase) {
+		t.Errorf("DecryptWithPassphrase() of tampered data error = %v, want ErrWrongPassphrase", err)
+	}
+
+	// Headers asking for more work than encryption uses are refused before deriving a key
+	costly := bytes.Clone(encrypted)
+	binary.BigEndian.PutUint32(costly[len(encryptionMagic)+4:], argon2Memory+1)
+	if _, err := DecryptWithPassphrase(costly, passphrase); !errors.Is(err, ErrUnsupportedKeyDerivation) {
+		t.Errorf("DecryptWithPassphrase() with raised memory error = %v, want ErrUnsupportedKeyDerivation", err)
+	}
+	costly = bytes.Clone(encrypted)
+	binary.BigEndian.PutUint32(costly[len(encryptionMagic):], argon2Time+1)
+	if _, err := DecryptWithPassphrase(costly, passphrase); !errors.Is(err, ErrUnsupportedKeyDerivation) {
+		t.Errorf("DecryptWithPassphrase() with raised iterations error = %v, want ErrUnsupportedKeyDerivation", err)
+	}
+
+	if _, err := EncryptWithPassphrase(plaintext, "short"); !errors.Is(err, ErrPassphraseTooShort) {
+		t.Errorf("EncryptWithPassphrase() with short passphrase error = %v, want ErrPassphraseTooShort", err)
+	}
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"bytes"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+// CalendarEvent is an all-day event in an iCalendar feed
+type CalendarEvent struct {
+	UID         string // Must stay the same across feed refreshes
+	Date        time.Time
+	Summary     string
+	Description string
+}
+
+// icalLineLimit is the maximum line length in octets before folding (RFC 5545 section 3.1)
+const icalLineLimit = 75
+
+var icalEscaper = strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+
+// WriteCalendar renders events as an RFC 5545 VCALENDAR named name. stamp is the
+// DTSTAMP of every event, normally the time the feed is generated.
+func WriteCalendar(name string, events []CalendarEvent, stamp time.Time) []byte {
+	var buf bytes.Buffer
+	writeICalLine(&buf, "BEGIN:VCALENDAR")
+	writeICalLine(&buf, "VERSION:2.0")
+	writeICalLine(&buf, "PRODID:-//Holy Home//Calendar//PL")
+	writeICalLine(&buf, "CALSCALE:GREGORIAN")
+	writeICalLine(&buf, "METHOD:PUBLISH")
+	writeICalLine(&buf, "X-WR-CALNAME:"+icalEscaper.Replace(name))
+	writeICalLine(&buf, "REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	writeICalLine(&buf, "X-PUBLISHED-TTL:PT1H")
+
+	dtstamp := stamp.UTC().Format("20060102T150405Z")
+	for _, event := range events {
+		day := event.Date.UTC()
+		writeICalLine(&buf, "BEGIN:VEVENT")
+		writeICalLine(&buf, "UID:"+event.UID)
+		writeICalLine(&buf, "DTSTAMP:"+dtstamp)
+		writeICalLine(&buf, "DTSTART;VALUE=DATE:"+day.Format("20060102"))
+		writeICalLine(&buf, "DTEND;VALUE=DATE:"+day.AddDate(0, 0, 1).Format("20060102"))
+		writeICalLine(&buf, "SUMMARY:"+icalEscaper.Replace(event.Summary))
+		if event.Description != "" {
+			writeICalLine(&buf, "DESCRIPTION:"+icalEscaper.Replace(event.Description))
+		}
+		writeICalLine(&buf, "TRANSP:TRANSPARENT")
+		writeICalLine(&buf, "END:VEVENT")
+	}
+
+	writeICalLine(&buf, "END:VCALENDAR")
+	return buf.Bytes()
+}
+
+// writeICalLine writes a content line ending in CRLF, folding it into continuation lines
+// that start with a space so no line exceeds the limit. UTF-8 characters are never split.
+func writeICalLine(buf *bytes.Buffer, line string) {
+	limit := icalLineLimit
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		buf.WriteString(line[:cut])
+		buf.WriteString("\r\n ")
+		line = line[cut:]
+		limit = icalLineLimit - 1 // The leading space counts towards the limit
+	}
+	buf.WriteString(line)
+	buf.WriteString("\r\n")
+}
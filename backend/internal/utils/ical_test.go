@@ -0,0 +1,55 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"unicode/utf8"
+)
+
+func TestWriteCalendar(t *testing.T) {
+	stamp := time.Date(2026, 3, 10, 12, 30, 0, 0, time.UTC)
+	events := []CalendarEvent{
+		{UID: "chore-1@holy-home", Date: time.Date(2026, 3, 31, 0, 0, 0, 0, time.UTC), Summary: "Zadanie: Łazienka, kuchnia; balkon"},
+		{UID: "bill-1@holy-home", Date: time.Date(2026, 4, 10, 0, 0, 0, 0, time.UTC), Summary: "Rachunek", Description: strings.Repeat("żółć ", 30) + "\nkoniec"},
+	}
+
+	ics := string(WriteCalendar("Holy Home – Anna", events, stamp))
+
+	for _, want := range []string{
+		"BEGIN:VCALENDAR\r\n",
+		"X-WR-CALNAME:Holy Home – Anna\r\n",
+		"UID:chore-1@holy-home\r\n",
+		"DTSTAMP:20260310T123000Z\r\n",
+		"DTSTART;VALUE=DATE:20260331\r\n",
+		"DTEND;VALUE=DATE:20260401\r\n",
+		`SUMMARY:Zadanie: Łazienka\, kuchnia\; balkon` + "\r\n",
+		"END:VCALENDAR\r\n",
+	} {
+		if !strings.Contains(ics, want) {
+			t.Errorf("calendar does not contain %q", want)
+		}
+	}
+	if strings.Count(ics, "BEGIN:VEVENT") != 2 {
+		t.Errorf("calendar should contain 2 events")
+	}
+
+	lines := strings.Split(strings.TrimSuffix(ics, "\r\n"), "\r\n")
+	for _, line := range lines {
+		if len(line) > icalLineLimit {
+			t.Errorf("line longer than %d octets: %q", icalLineLimit, line)
+		}
+		if !utf8.ValidString(line) {
+			t.Errorf("folding split a UTF-8 character: %q", line)
+		}
+		if strings.Contains(line, "\n") {
+			t.Errorf("line contains a bare newline: %q", line)
+		}
+	}
+
+	// Unfolding restores the escaped description
+	unfolded := strings.ReplaceAll(ics, "\r\n ", "")
+	if !strings.Contains(unfolded, "DESCRIPTION:"+strings.Repeat("żółć ", 30)+`\nkoniec`+"\r\n") {
+		t.Error("unfolded description does not match")
+	}
+}
@@ -0,0 +1,246 @@
+package utils
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strconv"
+)
+
+// Money is an exact fixed-point amount stored in minor units (1/100 PLN).
+// Plain integer arithmetic (+, -, comparisons) on Money values is exact.
+type Money int64
+
+const moneyScale = 100
+
+// ErrInvalidMoney is returned when a value cannot be parsed as an amount
+var ErrInvalidMoney = errors.New("invalid money amount")
+
+// ParseMoney parses a decimal string ("12.34", "-5", "1e2") into Money.
+// Values with more than two decimals are rounded half to even.
+func ParseMoney(s string) (Money, error) {
+	if s == "" {
+		return 0, nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidMoney, s)
+	}
+	return moneyFromRat(r), nil
+}
+
+// MoneyFromString parses a stored decimal string, treating invalid values as zero
+func MoneyFromString(s string) Money {
+	m, _ := ParseMoney(s)
+	return m
+}
+
+// MoneyFromFloat converts a float amount to Money using its shortest decimal representation
+func MoneyFromFloat(f float64) Money {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return 0
+	}
+	return MoneyFromString(strconv.FormatFloat(f, 'f', -1, 64))
+}
+
+// MoneyFromMinor creates Money from an amount in minor units (grosze)
+func MoneyFromMinor(minor int64) Money {
+	return Money(minor)
+}
+
+// Minor returns the amount in minor units (grosze)
+func (m Money) Minor() int64 {
+	return int64(m)
+}
+
+// String formats the amount as a decimal string with two places, e.g. "-12.05"
+func (m Money) String() string {
+	sign := ""
+	v := int64(m)
+	if v < 0 {
+		sign = "-"
+		v = -v
+	}
+	return fmt.Sprintf("%s%d.%02d", sign, v/moneyScale, v%moneyScale)
+}
+
+// Float64 returns the amount as a float, for display and statistics only
+func (m Money) Float64() float64 {
+	return float64(m) / moneyScale
+}
+
+// Abs returns the absolute value of the amount
+func (m Money) Abs() Money {
+	if m < 0 {
+		return -m
+	}
+	return m
+}
+
+// MulRat multiplies the amount by num/den, rounding half to even
+func (m Money) MulRat(num, den int64) Money {
+	if den == 0 {
+		return 0
+	}
+	r := new(big.Rat).SetFrac64(int64(m), moneyScale)
+	r.Mul(r, big.NewRat(num, den))
+	return moneyFromRat(r)
+}
+
+// Convert multiplies the amount by a decimal exchange rate such as "4.2735",
+// rounding the result half to even
+func (m Money) Convert(rate string) (Money, error) {
+	r, ok := new(big.Rat).SetString(rate)
+	if !ok {
+		return 0, fmt.Errorf("invalid exchange rate: %q", rate)
+	}
+	amount := new(big.Rat).SetFrac64(int64(m), moneyScale)
+	return moneyFromRat(amount.Mul(amount, r)), nil
+}
+
+// MulFloat multiplies the amount by an (exactly represented) float factor, rounding half to even
+func (m Money) MulFloat(f float64) Money {
+	factor := new(big.Rat)
+	if factor.SetFloat64(f) == nil {
+		return 0
+	}
+	r := new(big.Rat).SetFrac64(int64(m), moneyScale)
+	r.Mul(r, factor)
+	return moneyFromRat(r)
+}
+
+// Allocate splits the amount proportionally to weights using the largest remainder
+// method, so the returned parts always sum exactly to m. Negative weights are treated
+// as zero; if every weight is zero the amount is split evenly.
+func (m Money) Allocate(weights []float64) []Money {
+	parts := make([]Money, len(weights))
+	if len(weights) == 0 {
+		return parts
+	}
+
+	rats := make([]*big.Rat, len(weights))
+	total := new(big.Rat)
+	for i, w := range weights {
+		r := new(big.Rat)
+		if w > 0 && r.SetFloat64(w) != nil {
+			total.Add(total, r)
+		} else {
+			r.SetInt64(0)
+		}
+		rats[i] = r
+	}
+	if total.Sign() == 0 {
+		for i := range rats {
+			rats[i].SetInt64(1)
+		}
+		total.SetInt64(int64(len(rats)))
+	}
+
+	// Work on the absolute value so flooring always moves towards zero
+	negative := m < 0
+	amount := big.NewInt(int64(m.Abs()))
+
+	type remainder struct {
+		index int
+		frac  *big.Rat
+	}
+	remainders := make([]remainder, len(weights))
+	var distributed int64
+	for i, w := range rats {
+		share := new(big.Rat).SetInt(amount)
+		share.Mul(share, w)
+		share.Quo(share, total)
+
+		floor := new(big.Int).Quo(share.Num(), share.Denom())
+		parts[i] = Money(floor.Int64())
+		distributed += floor.Int64()
+
+		frac := new(big.Rat).Sub(share, new(big.Rat).SetInt(floor))
+		remainders[i] = remainder{index: i, frac: frac}
+	}
+
+	sort.SliceStable(remainders, func(a, b int) bool {
+		return remainders[a].frac.Cmp(remainders[b].frac) > 0
+	})
+	left := amount.Int64() - distributed
+	for i := 0; left > 0; i = (i + 1) % len(remainders) {
+		parts[remainders[i].index]++
+		left--
+	}
+
+	if negative {
+		for i := range parts {
+			parts[i] = -parts[i]
+		}
+	}
+	return parts
+}
+
+// SumMoney adds up a list of amounts
+func SumMoney(amounts ...Money) Money {
+	var total Money
+	for _, a := range amounts {
+		total += a
+	}
+	return total
+}
+
+// MinMoney returns the smaller of two amounts
+func MinMoney(a, b Money) Money {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// MarshalJSON encodes the amount as a JSON number with two decimals
+func (m Money) MarshalJSON() ([]byte, error) {
+	return []byte(m.String()), nil
+}
+
+// UnmarshalJSON accepts either a JSON number or a decimal string
+func (m *Money) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	s := string(data)
+	if len(data) >= 2 && data[0] == '"' && data[len(data)-1] == '"' {
+		unquoted, err := strconv.Unquote(s)
+		if err != nil {
+			return fmt.Errorf("%w: %s", ErrInvalidMoney, s)
+		}
+		s = unquoted
+	}
+	parsed, err := ParseMoney(s)
+	if err != nil {
+		return err
+	}
+	*m = parsed
+	return nil
+}
+
+// moneyFromRat rounds a rational PLN amount to minor units, half to even
+func moneyFromRat(r *big.Rat) Money {
+	scaled := new(big.Rat).Mul(r, big.NewRat(moneyScale, 1))
+	num := scaled.Num()
+	den := scaled.Denom()
+
+	quo, rem := new(big.Int).QuoRem(num, den, new(big.Int))
+	if rem.Sign() != 0 {
+		// Compare 2*|rem| with den to decide rounding direction
+		twice := new(big.Int).Mul(new(big.Int).Abs(rem), big.NewInt(2))
+		cmp := twice.Cmp(den)
+		if cmp > 0 || (cmp == 0 && quo.Bit(0) == 1) {
+			if num.Sign() < 0 {
+				quo.Sub(quo, big.NewInt(1))
+			} else {
+				quo.Add(quo, big.NewInt(1))
+			}
+		}
+	}
+	return Money(quo.Int64())
+}
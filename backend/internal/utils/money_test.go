@@ -0,0 +1,144 @@
+package utils
+
+import (
+	"encoding/json"
+	"testing"
+)
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    Money
+		wantErr bool
+	}{
+		{"Two decimals", "123.45", 12345, false},
+		{"Integer", "10", 1000, false},
+		{"Negative", "-0.05", -5, false},
+		{"Empty is zero", "", 0, false},
+		{"Half to even down", "0.125", 12, false},
+		{"Half to even up", "0.135", 14, false},
+		{"Negative half to even", "-0.135", -14, false},
+		{"Float artifact", "0.30000000000000004", 30, false},
+		{"Invalid", "abc", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMoney(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseMoney(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseMoney(%q) = %d, want %d", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyString(t *testing.T) {
+	tests := []struct {
+		amount Money
+		want   string
+	}{
+		{0, "0.00"},
+		{5, "0.05"},
+		{12345, "123.45"},
+		{-1205, "-12.05"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.amount.String(); got != tt.want {
+			t.Errorf("Money(%d).String() = %q, want %q", tt.amount, got, tt.want)
+		}
+	}
+}
+
+func TestMoneyAllocate(t *testing.T) {
+	tests := []struct {
+		name    string
+		amount  Money
+		weights []float64
+		want    []Money
+	}{
+		{"Even thirds", 10000, []float64{1, 1, 1}, []Money{3334, 3333, 3333}},
+		{"Weighted", 10001, []float64{2, 1}, []Money{6667, 3334}},
+		{"Fractional weights", 25050, []float64{0.5, 0.25, 0.25}, []Money{12525, 6263, 6262}},
+		{"Negative amount", -100, []float64{1, 1, 1}, []Money{-34, -33, -33}},
+		{"Zero weights split evenly", 101, []float64{0, 0}, []Money{51, 50}},
+		{"Zero weight gets nothing", 100, []float64{1, 0, 1}, []Money{50, 0, 50}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.amount.Allocate(tt.weights)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Allocate() returned %d parts, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Errorf("Allocate()[%d] = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+			if sum := SumMoney(got...); sum != tt.amount {
+				t.Errorf("Allocate() parts sum to %s, want %s", sum, tt.amount)
+			}
+		})
+	}
+}
+
+func TestMoneyAllocateAlwaysSumsToTotal(t *testing.T) {
+	weights := []float64{1.5, 1, 1, 0.7, 2.3}
+	for minor := int64(0); minor < 5000; minor += 7 {
+		amount := MoneyFromMinor(minor)
+		if sum := SumMoney(amount.Allocate(weights)...); sum != amount {
+			t.Fatalf("Allocate(%s) parts sum to %s", amount, sum)
+		}
+	}
+}
+
+func TestMoneyMul(t *testing.T) {
+	if got := Money(10000).MulRat(1, 3); got != 3333 {
+		t.Errorf("MulRat(1, 3) = %s, want 33.33", got)
+	}
+	if got := Money(10000).MulFloat(0.125); got != 1250 {
+		t.Errorf("MulFloat(0.125) = %s, want 12.50", got)
+	}
+	if got := Money(101).MulFloat(0.5); got != 50 {
+		t.Errorf("MulFloat(0.5) = %s, want 0.50 (half to even)", got)
+	}
+}
+
+func TestMoneyConvert(t *testing.T) {
+	if got, err := Money(1999).Convert("4.2735"); err != nil || got != 8543 {
+		t.Errorf("Convert(4.2735) = %s, %v, want 85.43", got, err)
+	}
+	if got, err := Money(-1000).Convert("0.23405"); err != nil || got != -234 {
+		t.Errorf("Convert(0.23405) = %s, %v, want -2.34", got, err)
+	}
+	if _, err := Money(100).Convert("abc"); err == nil {
+		t.Error("Convert(abc) should fail")
+	}
+}
+
+func TestMoneyJSON(t *testing.T) {
+	var payload struct {
+		Number Money  `json:"number"`
+		Text   Money  `json:"text"`
+		Ptr    *Money `json:"ptr"`
+	}
+	if err := json.Unmarshal([]byte(`{"number": 250.5, "text": "0.1", "ptr": null}`), &payload); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+	if payload.Number != 25050 || payload.Text != 10 || payload.Ptr != nil {
+		t.Errorf("Unmarshal() = %+v", payload)
+	}
+
+	out, err := json.Marshal(payload.Number)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if string(out) != "250.50" {
+		t.Errorf("Marshal() = %s, want 250.50", out)
+	}
+}
@@ -0,0 +1,218 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+)
+
+// A4 page layout in points
+const (
+	pdfPageWidth  = 595.0
+	pdfPageHeight = 842.0
+	pdfMargin     = 50.0
+	pdfFontSize   = 9.0
+	pdfLineHeight = 12.0
+	pdfCharWidth  = 0.6 // Courier advance width per point of font size
+)
+
+// PDFLineWidth is the number of characters that fit in a line of body text
+// ((pdfPageWidth - 2*pdfMargin) / (pdfFontSize * pdfCharWidth), rounded down)
+const PDFLineWidth = 91
+
+// pdfCentralEuropean maps letters missing from WinAnsiEncoding onto byte codes
+// that the font encoding redefines with the matching Courier glyphs
+var pdfCentralEuropean = []struct {
+	r     rune
+	glyph string
+}{
+	{'Ą', "Aogonek"}, {'ą', "aogonek"},
+	{'Ć', "Cacute"}, {'ć', "cacute"},
+	{'Ę', "Eogonek"}, {'ę', "eogonek"},
+	{'Ł', "Lslash"}, {'ł', "lslash"},
+	{'Ń', "Nacute"}, {'ń', "nacute"},
+	{'Ś', "Sacute"}, {'ś', "sacute"},
+	{'Ź', "Zacute"}, {'ź', "zacute"},
+	{'Ż', "Zdotaccent"}, {'ż', "zdotaccent"},
+}
+
+// PDF builds a simple multi-page A4 text document with the built-in Courier fonts,
+// so documents can be rendered without external tools or embedded fonts
+type PDF struct {
+	title string
+	pages []*bytes.Buffer
+	y     float64
+}
+
+// NewPDF creates an empty document
+func NewPDF(title string) *PDF {
+	return &PDF{title: title}
+}
+
+// Heading writes a line of large bold text
+func (p *PDF) Heading(text string) {
+	p.write(text, true, 14, 20)
+}
+
+// Text writes body text, wrapping long lines
+func (p *PDF) Text(text string) {
+	for _, line := range wrapPDFText(text, PDFLineWidth) {
+		p.write(line, false, pdfFontSize, pdfLineHeight)
+	}
+}
+
+// BoldText writes bold body text, wrapping long lines
+func (p *PDF) BoldText(text string) {
+	for _, line := range wrapPDFText(text, PDFLineWidth) {
+		p.write(line, true, pdfFontSize, pdfLineHeight)
+	}
+}
+
+// Space adds vertical space of half a line
+func (p *PDF) Space() {
+	p.ensureSpace(pdfLineHeight / 2)
+	p.y -= pdfLineHeight / 2
+}
+
+// Rule draws a horizontal line across the page
+func (p *PDF) Rule() {
+	p.ensureSpace(pdfLineHeight / 2)
+	y := p.y - 3
+	fmt.Fprintf(p.current(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", pdfMargin, y, pdfPageWidth-pdfMargin, y)
+	p.y -= pdfLineHeight / 2
+}
+
+// Bytes renders the document, numbering the pages
+func (p *PDF) Bytes() []byte {
+	if len(p.pages) == 0 {
+		p.newPage()
+	}
+
+	var buf bytes.Buffer
+	offsets := []int{0}
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets)-1, body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+
+	// 1: catalog, 2: page tree, 3-4: fonts, 5: encoding, 6: info, then page and content pairs
+	const firstPage = 7
+	kids := make([]string, len(p.pages))
+	for i := range p.pages {
+		kids[i] = fmt.Sprintf("%d 0 R", firstPage+2*i)
+	}
+
+	var differences strings.Builder
+	for i, letter := range pdfCentralEuropean {
+		if i == 0 {
+			fmt.Fprintf(&differences, "%d", 128)
+		}
+		fmt.Fprintf(&differences, " /%s", letter.glyph)
+	}
+
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(p.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier /Encoding 5 0 R >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Courier-Bold /Encoding 5 0 R >>")
+	object(fmt.Sprintf("<< /Type /Encoding /BaseEncoding /WinAnsiEncoding /Differences [%s] >>", differences.String()))
+	object(fmt.Sprintf("<< /Title (%s) /Producer (Holy Home) >>", pdfString(p.title)))
+
+	for i, page := range p.pages {
+		footer := fmt.Sprintf("%d / %d", i+1, len(p.pages))
+		content := page.String() + fmt.Sprintf("BT /F1 8 Tf %.2f %.2f Td (%s) Tj ET\n",
+			pdfPageWidth-pdfMargin-float64(len(footer))*8*pdfCharWidth, pdfMargin/2, footer)
+
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.0f %.0f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			pdfPageWidth, pdfPageHeight, firstPage+2*i+1))
+		object(fmt.Sprintf("<< /Length %d >>\nstream\n%sendstream", len(content), content))
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets))
+	for _, offset := range offsets[1:] {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R /Info 6 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets), xref)
+
+	return buf.Bytes()
+}
+
+func (p *PDF) write(text string, bold bool, size, lineHeight float64) {
+	p.ensureSpace(lineHeight)
+	p.y -= lineHeight
+
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(p.current(), "BT /%s %.1f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, pdfMargin, p.y, pdfString(text))
+}
+
+func (p *PDF) ensureSpace(height float64) {
+	if len(p.pages) == 0 || p.y-height < pdfMargin {
+		p.newPage()
+	}
+}
+
+func (p *PDF) newPage() {
+	p.pages = append(p.pages, &bytes.Buffer{})
+	p.y = pdfPageHeight - pdfMargin
+}
+
+func (p *PDF) current() *bytes.Buffer {
+	return p.pages[len(p.pages)-1]
+}
+
+// pdfString encodes text for a PDF string literal in the document's font encoding.
+// Characters the fonts cannot show are replaced with '?'.
+func pdfString(text string) string {
+	var b strings.Builder
+	for _, r := range text {
+		switch {
+		case r == '\\' || r == '(' || r == ')':
+			b.WriteByte('\\')
+			b.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			b.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&b, "\\%03o", r)
+		default:
+			code := -1
+			for i, letter := range pdfCentralEuropean {
+				if letter.r == r {
+					code = 128 + i
+					break
+				}
+			}
+			if code < 0 {
+				b.WriteByte('?')
+				continue
+			}
+			fmt.Fprintf(&b, "\\%03o", code)
+		}
+	}
+	return b.String()
+}
+
+// wrapPDFText splits text into lines of at most width characters, breaking at spaces where possible
+func wrapPDFText(text string, width int) []string {
+	var lines []string
+	for _, paragraph := range strings.Split(text, "\n") {
+		runes := []rune(paragraph)
+		for len(runes) > width {
+			cut := width
+			for i := width; i > width/2; i-- {
+				if runes[i] == ' ' {
+					cut = i
+					break
+				}
+			}
+			lines = append(lines, strings.TrimRight(string(runes[:cut]), " "))
+			runes = []rune(strings.TrimLeft(string(runes[cut:]), " "))
+		}
+		lines = append(lines, string(runes))
+	}
+	return lines
+}
@@ -0,0 +1,79 @@
+package utils
+
+import (
+	"bytes"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestPDFString(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{"ASCII", "Rachunek 12", "Rachunek 12"},
+		{"Escapes delimiters", `a(b)\c`, `a\(b\)\\c`},
+		{"Latin-1 letter", "ó", `\363`},
+		{"Polish letters", "Łąż", `\206\201\217`},
+		{"Unsupported character", "€ 🏠", "? ?"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := pdfString(tt.input); got != tt.want {
+				t.Errorf("pdfString(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWrapPDFText(t *testing.T) {
+	lines := wrapPDFText("alpha beta gamma delta", 11)
+	want := []string{"alpha beta", "gamma delta"}
+	if strings.Join(lines, "|") != strings.Join(want, "|") {
+		t.Errorf("wrapPDFText = %q, want %q", lines, want)
+	}
+
+	lines = wrapPDFText("abcdefghijkl", 5)
+	if strings.Join(lines, "|") != "abcde|fghij|kl" {
+		t.Errorf("wrapPDFText without spaces = %q", lines)
+	}
+}
+
+func TestPDFBytes(t *testing.T) {
+	doc := NewPDF("Zestawienie")
+	doc.Heading("Zestawienie")
+	for i := 0; i < 100; i++ {
+		doc.Text(fmt.Sprintf("Linia %d", i))
+	}
+	out := doc.Bytes()
+
+	if !bytes.HasPrefix(out, []byte("%PDF-1.4")) || !bytes.HasSuffix(out, []byte("%%EOF\n")) {
+		t.Fatal("missing PDF header or trailer")
+	}
+	if !bytes.Contains(out, []byte("/Count 2")) {
+		t.Error("expected the text to flow onto a second page")
+	}
+	if !bytes.Contains(out, []byte("(2 / 2)")) {
+		t.Error("expected page numbers in the footer")
+	}
+
+	// Every xref entry must point at the start of its object
+	match := regexp.MustCompile(`startxref\n(\d+)`).FindSubmatch(out)
+	if match == nil {
+		t.Fatal("missing startxref")
+	}
+	xref, _ := strconv.Atoi(string(match[1]))
+	lines := strings.Split(string(out[xref:]), "\n")
+	count, _ := strconv.Atoi(strings.Fields(lines[1])[1])
+	for i := 1; i < count; i++ {
+		offset, _ := strconv.Atoi(strings.Fields(lines[2+i])[0])
+		if prefix := fmt.Sprintf("%d 0 obj", i); !bytes.HasPrefix(out[offset:], []byte(prefix)) {
+			t.Errorf("xref entry %d points at %q", i, out[offset:offset+10])
+		}
+	}
+}
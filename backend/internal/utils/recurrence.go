@@ -0,0 +1,486 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Recurrence is a parsed RFC 5545 recurrence: an RRULE plus optional DTSTART and EXDATE
+// lines. Supported rule parts are FREQ (DAILY, WEEKLY, MONTHLY, YEARLY), INTERVAL, COUNT,
+// UNTIL, BYDAY (with ordinals such as 2TU or -1FR in monthly and yearly rules), BYMONTHDAY,
+// BYMONTH, BYSETPOS and WKST.
+//
+// As in most RRULE implementations, DTSTART is only an occurrence when it matches the rule.
+// COUNT counts occurrences before EXDATE removes any.
+type Recurrence struct {
+	Freq       string
+	Interval   int
+	Count      int
+	Until      *time.Time
+	ByDay      []RecurrenceDay
+	ByMonthDay []int
+	ByMonth    []time.Month
+	BySetPos   []int
+	WeekStart  time.Weekday
+	Start      time.Time
+	ExDates    []time.Time
+
+	exDays map[string]bool // EXDATE values given as dates exclude the whole day
+}
+
+// RecurrenceDay is a BYDAY entry. Ordinal 0 means every such weekday in the period.
+type RecurrenceDay struct {
+	Ordinal int
+	Weekday time.Weekday
+}
+
+// maxRecurrencePeriods bounds the search for a rule that can never match, like BYMONTHDAY=30;BYMONTH=2
+const maxRecurrencePeriods = 50000
+
+var recurrenceWeekdays = map[string]time.Weekday{
+	"MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday, "TH": time.Thursday,
+	"FR": time.Friday, "SA": time.Saturday, "SU": time.Sunday,
+}
+
+// ParseRecurrence parses a recurrence rule. The rule is either a bare RRULE value
+// ("FREQ=WEEKLY;BYDAY=TU") or content lines separated by newlines: RRULE, and optionally
+// DTSTART and EXDATE. Without a DTSTART line the series starts at start. Dates without a
+// time are taken as midnight UTC.
+func ParseRecurrence(rule string, start time.Time) (*Recurrence, error) {
+	r := &Recurrence{Interval: 1, WeekStart: time.Monday, Start: start, exDays: map[string]bool{}}
+
+	var rrule string
+	for _, line := range strings.Split(strings.ReplaceAll(rule, "\r\n", "\n"), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
+		}
+		name, value, found := strings.Cut(line, ":")
+		if !found {
+			name, value = "RRULE", line
+		}
+		// Parameters such as DTSTART;VALUE=DATE are accepted and ignored
+		name, _, _ = strings.Cut(strings.ToUpper(name), ";")
+
+		switch name {
+		case "RRULE":
+			if rrule != "" {
+				return nil, errors.New("only one RRULE is supported")
+			}
+			rrule = value
+		case "DTSTART":
+			t, _, err := parseRecurrenceTime(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid DTSTART: %w", err)
+			}
+			r.Start = t
+		case "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				t, isDate, err := parseRecurrenceTime(v)
+				if err != nil {
+					return nil, fmt.Errorf("invalid EXDATE: %w", err)
+				}
+				r.ExDates = append(r.ExDates, t)
+				if isDate {
+					r.exDays[t.Format("2006-01-02")] = true
+				}
+			}
+		default:
+			return nil, fmt.Errorf("unsupported recurrence property %s", name)
+		}
+	}
+	if rrule == "" {
+		return nil, errors.New("recurrence rule is empty")
+	}
+	if r.Start.IsZero() {
+		return nil, errors.New("recurrence has no start date")
+	}
+
+	for _, part := range strings.Split(rrule, ";") {
+		if part == "" {
+			continue
+		}
+		key, value, found := strings.Cut(part, "=")
+		if !found || value == "" {
+			return nil, fmt.Errorf("invalid rule part %q", part)
+		}
+		var err error
+		switch strings.ToUpper(key) {
+		case "FREQ":
+			r.Freq = strings.ToUpper(value)
+		case "INTERVAL":
+			r.Interval, err = strconv.Atoi(value)
+			if err == nil && r.Interval < 1 {
+				err = errors.New("must be positive")
+			}
+		case "COUNT":
+			r.Count, err = strconv.Atoi(value)
+			if err == nil && r.Count < 1 {
+				err = errors.New("must be positive")
+			}
+		case "UNTIL":
+			var until time.Time
+			var isDate bool
+			until, isDate, err = parseRecurrenceTime(value)
+			if isDate {
+				// A date includes every occurrence on that day
+				until = until.AddDate(0, 0, 1).Add(-time.Nanosecond)
+			}
+			r.Until = &until
+		case "BYDAY":
+			r.ByDay, err = parseRecurrenceDays(value)
+		case "BYMONTHDAY":
+			r.ByMonthDay, err = parseRecurrenceInts(value, 31)
+		case "BYMONTH":
+			var months []int
+			months, err = parseRecurrenceInts(value, 12)
+			for _, m := range months {
+				if m < 0 {
+					err = errors.New("months must be between 1 and 12")
+				}
+				r.ByMonth = append(r.ByMonth, time.Month(m))
+			}
+		case "BYSETPOS":
+			r.BySetPos, err = parseRecurrenceInts(value, 366)
+		case "WKST":
+			day, ok := recurrenceWeekdays[strings.ToUpper(value)]
+			if !ok {
+				err = errors.New("unknown weekday")
+			}
+			r.WeekStart = day
+		default:
+			return nil, fmt.Errorf("unsupported rule part %s", strings.ToUpper(key))
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", strings.ToUpper(key), err)
+		}
+	}
+
+	switch r.Freq {
+	case "DAILY", "WEEKLY", "MONTHLY", "YEARLY":
+	case "":
+		return nil, errors.New("FREQ is required")
+	default:
+		return nil, fmt.Errorf("unsupported FREQ %s", r.Freq)
+	}
+	if r.Count > 0 && r.Until != nil {
+		return nil, errors.New("COUNT and UNTIL cannot be combined")
+	}
+	for _, day := range r.ByDay {
+		if day.Ordinal == 0 {
+			continue
+		}
+		if r.Freq != "MONTHLY" && r.Freq != "YEARLY" {
+			return nil, errors.New("BYDAY ordinals are only allowed in MONTHLY and YEARLY rules")
+		}
+		if r.Freq == "YEARLY" && len(r.ByMonth) == 0 {
+			return nil, errors.New("BYDAY ordinals in YEARLY rules need BYMONTH")
+		}
+	}
+	if r.Freq == "WEEKLY" && len(r.ByMonthDay) > 0 {
+		return nil, errors.New("BYMONTHDAY is not allowed in WEEKLY rules")
+	}
+
+	return r, nil
+}
+
+// After returns the first occurrence after t, or at t when inclusive is set.
+// It returns false when the series has no further occurrences.
+func (r *Recurrence) After(t time.Time, inclusive bool) (time.Time, bool) {
+	var found time.Time
+	ok := false
+	r.iterate(func(occurrence time.Time) bool {
+		if occurrence.After(t) || (inclusive && occurrence.Equal(t)) {
+			found, ok = occurrence, true
+			return false
+		}
+		return true
+	})
+	return found, ok
+}
+
+// Before returns the last occurrence before t, or at t when inclusive is set
+func (r *Recurrence) Before(t time.Time, inclusive bool) (time.Time, bool) {
+	var found time.Time
+	ok := false
+	r.iterate(func(occurrence time.Time) bool {
+		if occurrence.Before(t) || (inclusive && occurrence.Equal(t)) {
+			found, ok = occurrence, true
+			return true
+		}
+		return false
+	})
+	return found, ok
+}
+
+// Between returns the occurrences from from up to, but not including, to
+func (r *Recurrence) Between(from, to time.Time) []time.Time {
+	var occurrences []time.Time
+	r.iterate(func(occurrence time.Time) bool {
+		if !occurrence.Before(to) {
+			return false
+		}
+		if !occurrence.Before(from) {
+			occurrences = append(occurrences, occurrence)
+		}
+		return true
+	})
+	return occurrences
+}
+
+// iterate calls yield with each occurrence in order until it returns false or the series ends
+func (r *Recurrence) iterate(yield func(time.Time) bool) {
+	count := 0
+	period := r.periodStart(r.Start)
+	for i := 0; i < maxRecurrencePeriods; i++ {
+		for _, candidate := range r.expand(period) {
+			if candidate.Before(r.Start) {
+				continue
+			}
+			if r.Until != nil && candidate.After(*r.Until) {
+				return
+			}
+			count++
+			if !r.excluded(candidate) && !yield(candidate) {
+				return
+			}
+			if r.Count > 0 && count >= r.Count {
+				return
+			}
+		}
+		period = r.nextPeriod(period)
+	}
+}
+
+// periodStart returns the start of the DAILY, WEEKLY, MONTHLY or YEARLY period containing t
+func (r *Recurrence) periodStart(t time.Time) time.Time {
+	year, month, day := t.Date()
+	switch r.Freq {
+	case "WEEKLY":
+		offset := (int(t.Weekday()) - int(r.WeekStart) + 7) % 7
+		return time.Date(year, month, day-offset, 0, 0, 0, 0, t.Location())
+	case "MONTHLY":
+		return time.Date(year, month, 1, 0, 0, 0, 0, t.Location())
+	case "YEARLY":
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, t.Location())
+	default:
+		return time.Date(year, month, day, 0, 0, 0, 0, t.Location())
+	}
+}
+
+func (r *Recurrence) nextPeriod(period time.Time) time.Time {
+	switch r.Freq {
+	case "WEEKLY":
+		return period.AddDate(0, 0, 7*r.Interval)
+	case "MONTHLY":
+		return period.AddDate(0, r.Interval, 0)
+	case "YEARLY":
+		return period.AddDate(r.Interval, 0, 0)
+	default:
+		return period.AddDate(0, 0, r.Interval)
+	}
+}
+
+// expand returns the sorted candidate occurrences within one period, at the start's time of day
+func (r *Recurrence) expand(period time.Time) []time.Time {
+	var days []time.Time
+	switch r.Freq {
+	case "DAILY":
+		if r.matchesDay(period, false) {
+			days = append(days, period)
+		}
+	case "WEEKLY":
+		for i := 0; i < 7; i++ {
+			day := period.AddDate(0, 0, i)
+			if len(r.ByDay) == 0 && day.Weekday() != r.Start.Weekday() {
+				continue
+			}
+			if r.matchesDay(day, false) {
+				days = append(days, day)
+			}
+		}
+	case "MONTHLY":
+		days = r.expandMonth(period)
+	case "YEARLY":
+		months := r.ByMonth
+		if len(months) == 0 && len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+			months = []time.Month{r.Start.Month()}
+		} else if len(months) == 0 {
+			months = []time.Month{1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12}
+		}
+		for _, month := range months {
+			days = append(days, r.expandMonth(time.Date(period.Year(), month, 1, 0, 0, 0, 0, period.Location()))...)
+		}
+		sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+	}
+
+	days = r.applySetPos(days)
+
+	hour, minute, second := r.Start.Clock()
+	occurrences := make([]time.Time, len(days))
+	for i, day := range days {
+		occurrences[i] = time.Date(day.Year(), day.Month(), day.Day(), hour, minute, second, 0, day.Location())
+	}
+	return occurrences
+}
+
+// expandMonth returns the days of the month starting at first that match the rule.
+// Without BYDAY or BYMONTHDAY it is the start's day of the month, when the month has it.
+func (r *Recurrence) expandMonth(first time.Time) []time.Time {
+	if len(r.ByMonth) > 0 && !containsMonth(r.ByMonth, first.Month()) {
+		return nil
+	}
+	var days []time.Time
+	daysInMonth := first.AddDate(0, 1, -1).Day()
+	for d := 1; d <= daysInMonth; d++ {
+		day := first.AddDate(0, 0, d-1)
+		if len(r.ByDay) == 0 && len(r.ByMonthDay) == 0 {
+			if d == r.Start.Day() {
+				days = append(days, day)
+			}
+			continue
+		}
+		if r.matchesDay(day, true) {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// matchesDay checks BYMONTH, BYMONTHDAY and BYDAY. Ordinals are counted within the month.
+func (r *Recurrence) matchesDay(day time.Time, ordinals bool) bool {
+	if len(r.ByMonth) > 0 && !containsMonth(r.ByMonth, day.Month()) {
+		return false
+	}
+	daysInMonth := time.Date(day.Year(), day.Month()+1, 0, 0, 0, 0, 0, day.Location()).Day()
+	if len(r.ByMonthDay) > 0 {
+		matched := false
+		for _, md := range r.ByMonthDay {
+			if md == day.Day() || (md < 0 && daysInMonth+md+1 == day.Day()) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if len(r.ByDay) > 0 {
+		matched := false
+		for _, bd := range r.ByDay {
+			if bd.Weekday != day.Weekday() {
+				continue
+			}
+			if bd.Ordinal == 0 || !ordinals {
+				matched = true
+				break
+			}
+			nth := (day.Day()-1)/7 + 1
+			nthFromEnd := -((daysInMonth-day.Day())/7 + 1)
+			if bd.Ordinal == nth || bd.Ordinal == nthFromEnd {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// applySetPos keeps the BYSETPOS positions of a period's sorted candidates
+func (r *Recurrence) applySetPos(days []time.Time) []time.Time {
+	if len(r.BySetPos) == 0 || len(days) == 0 {
+		return days
+	}
+	var selected []time.Time
+	for i, day := range days {
+		for _, pos := range r.BySetPos {
+			if pos == i+1 || pos == i-len(days) {
+				selected = append(selected, day)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+func (r *Recurrence) excluded(t time.Time) bool {
+	if r.exDays[t.UTC().Format("2006-01-02")] {
+		return true
+	}
+	for _, ex := range r.ExDates {
+		if ex.Equal(t) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsMonth(months []time.Month, month time.Month) bool {
+	for _, m := range months {
+		if m == month {
+			return true
+		}
+	}
+	return false
+}
+
+// parseRecurrenceTime parses a DATE (20260310) or UTC DATE-TIME (20260310T090000Z) value.
+// It also reports whether the value was a date.
+func parseRecurrenceTime(value string) (time.Time, bool, error) {
+	value = strings.TrimSpace(value)
+	if t, err := time.Parse("20060102", value); err == nil {
+		return t, true, nil
+	}
+	for _, layout := range []string{"20060102T150405Z", "20060102T150405"} {
+		if t, err := time.Parse(layout, value); err == nil {
+			return t, false, nil
+		}
+	}
+	return time.Time{}, false, fmt.Errorf("%q is not a date or UTC date-time", value)
+}
+
+func parseRecurrenceDays(value string) ([]RecurrenceDay, error) {
+	var days []RecurrenceDay
+	for _, item := range strings.Split(strings.ToUpper(value), ",") {
+		if len(item) < 2 {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+		weekday, ok := recurrenceWeekdays[item[len(item)-2:]]
+		if !ok {
+			return nil, fmt.Errorf("invalid weekday %q", item)
+		}
+		day := RecurrenceDay{Weekday: weekday}
+		if prefix := item[:len(item)-2]; prefix != "" {
+			n, err := strconv.Atoi(prefix)
+			if err != nil || n == 0 || n < -5 || n > 5 {
+				return nil, fmt.Errorf("invalid weekday ordinal %q", item)
+			}
+			day.Ordinal = n
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
+
+// parseRecurrenceInts parses a list of integers between 1 and max or -max and -1
+func parseRecurrenceInts(value string, max int) ([]int, error) {
+	var values []int
+	for _, item := range strings.Split(value, ",") {
+		n, err := strconv.Atoi(item)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number %q", item)
+		}
+		if n == 0 || n > max || n < -max {
+			return nil, fmt.Errorf("%d is out of range", n)
+		}
+		values = append(values, n)
+	}
+	return values, nil
+}
@@ -0,0 +1,162 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func date(year int, month time.Month, day int) time.Time {
+	return time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+}
+
+func TestRecurrenceBetween(t *testing.T) {
+	start := date(2026, 3, 3) // a Tuesday
+
+	tests := []struct {
+		name string
+		rule string
+		to   time.Time
+		want []time.Time
+	}{
+		{
+			"every second Tuesday",
+			"FREQ=WEEKLY;INTERVAL=2;BYDAY=TU",
+			date(2026, 4, 15),
+			[]time.Time{date(2026, 3, 3), date(2026, 3, 17), date(2026, 3, 31), date(2026, 4, 14)},
+		},
+		{
+			"last weekday of the month",
+			"RRULE:FREQ=MONTHLY;BYDAY=MO,TU,WE,TH,FR;BYSETPOS=-1",
+			date(2026, 7, 1),
+			[]time.Time{date(2026, 3, 31), date(2026, 4, 30), date(2026, 5, 29), date(2026, 6, 30)},
+		},
+		{
+			"BYDAY list",
+			"FREQ=WEEKLY;BYDAY=MO,TH",
+			date(2026, 3, 13),
+			[]time.Time{date(2026, 3, 5), date(2026, 3, 9), date(2026, 3, 12)},
+		},
+		{
+			"first Monday and last Friday",
+			"FREQ=MONTHLY;BYDAY=1MO,-1FR",
+			date(2026, 5, 1),
+			[]time.Time{date(2026, 3, 27), date(2026, 4, 6), date(2026, 4, 24)},
+		},
+		{
+			"month days that do not exist are skipped",
+			"FREQ=MONTHLY;BYMONTHDAY=31",
+			date(2026, 8, 1),
+			[]time.Time{date(2026, 3, 31), date(2026, 5, 31), date(2026, 7, 31)},
+		},
+		{
+			"last day of the month",
+			"FREQ=MONTHLY;BYMONTHDAY=-1;COUNT=3",
+			date(2027, 1, 1),
+			[]time.Time{date(2026, 3, 31), date(2026, 4, 30), date(2026, 5, 31)},
+		},
+		{
+			"COUNT",
+			"FREQ=DAILY;COUNT=3",
+			date(2027, 1, 1),
+			[]time.Time{date(2026, 3, 3), date(2026, 3, 4), date(2026, 3, 5)},
+		},
+		{
+			"UNTIL is inclusive",
+			"FREQ=WEEKLY;UNTIL=20260317",
+			date(2027, 1, 1),
+			[]time.Time{date(2026, 3, 3), date(2026, 3, 10), date(2026, 3, 17)},
+		},
+		{
+			"EXDATE",
+			"RRULE:FREQ=WEEKLY;COUNT=4\nEXDATE:20260310,20260324T000000Z",
+			date(2027, 1, 1),
+			[]time.Time{date(2026, 3, 3), date(2026, 3, 17)},
+		},
+		{
+			"DTSTART overrides the default start",
+			"DTSTART:20260101\nRRULE:FREQ=YEARLY;BYMONTH=1,7;BYMONTHDAY=1",
+			date(2027, 7, 2),
+			[]time.Time{date(2026, 1, 1), date(2026, 7, 1), date(2027, 1, 1), date(2027, 7, 1)},
+		},
+		{
+			"Thanksgiving",
+			"FREQ=YEARLY;BYMONTH=11;BYDAY=4TH",
+			date(2028, 1, 1),
+			[]time.Time{date(2026, 11, 26), date(2027, 11, 25)},
+		},
+		{
+			"rule that never matches",
+			"FREQ=YEARLY;BYMONTH=2;BYMONTHDAY=30",
+			date(2030, 1, 1),
+			nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := ParseRecurrence(tt.rule, start)
+			if err != nil {
+				t.Fatalf("ParseRecurrence() error = %v", err)
+			}
+			got := r.Between(date(2026, 1, 1), tt.to)
+			if len(got) != len(tt.want) {
+				t.Fatalf("Between() = %v, want %v", got, tt.want)
+			}
+			for i := range got {
+				if !got[i].Equal(tt.want[i]) {
+					t.Errorf("Between()[%d] = %s, want %s", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecurrenceAfterAndBefore(t *testing.T) {
+	r, err := ParseRecurrence("FREQ=MONTHLY;BYMONTHDAY=15;COUNT=3", date(2026, 1, 1))
+	if err != nil {
+		t.Fatalf("ParseRecurrence() error = %v", err)
+	}
+
+	if got, ok := r.After(date(2026, 1, 15), false); !ok || !got.Equal(date(2026, 2, 15)) {
+		t.Errorf("After() = %s, %v, want 2026-02-15", got, ok)
+	}
+	if got, ok := r.After(date(2026, 1, 15), true); !ok || !got.Equal(date(2026, 1, 15)) {
+		t.Errorf("After(inclusive) = %s, %v, want 2026-01-15", got, ok)
+	}
+	if _, ok := r.After(date(2026, 3, 15), false); ok {
+		t.Error("After() past the last occurrence should report no occurrence")
+	}
+	if got, ok := r.Before(date(2026, 3, 1), false); !ok || !got.Equal(date(2026, 2, 15)) {
+		t.Errorf("Before() = %s, %v, want 2026-02-15", got, ok)
+	}
+	if _, ok := r.Before(date(2026, 1, 15), false); ok {
+		t.Error("Before() the first occurrence should report no occurrence")
+	}
+}
+
+func TestParseRecurrenceErrors(t *testing.T) {
+	start := date(2026, 3, 3)
+	for _, rule := range []string{
+		"",
+		"INTERVAL=2",
+		"FREQ=HOURLY",
+		"FREQ=WEEKLY;INTERVAL=0",
+		"FREQ=WEEKLY;BYDAY=XX",
+		"FREQ=WEEKLY;BYDAY=2TU",
+		"FREQ=YEARLY;BYDAY=-1FR",
+		"FREQ=MONTHLY;BYMONTHDAY=32",
+		"FREQ=MONTHLY;BYMONTH=-1",
+		"FREQ=DAILY;COUNT=2;UNTIL=20260401",
+		"FREQ=DAILY;BYWEEKNO=3",
+		"RRULE:FREQ=DAILY\nRDATE:20260401",
+		"RRULE:FREQ=DAILY\nEXDATE:tomorrow",
+	} {
+		if _, err := ParseRecurrence(rule, start); err == nil {
+			t.Errorf("ParseRecurrence(%q) should fail", rule)
+		}
+	}
+
+	if _, err := ParseRecurrence("FREQ=DAILY", time.Time{}); err == nil {
+		t.Error("ParseRecurrence() without a start date should fail")
+	}
+}